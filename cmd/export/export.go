@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/analyzer"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+)
+
+// Exports raw page views, sessions, or events for a client.
+// Pass -cursor to resume an interrupted export. The cursor file is updated after each run.
+// NDJSON and CSV exports are appended to the output file when resuming. Parquet exports must be resumed into a new file.
+//
+// Example: go run cmd/export/export.go -client 1 -from 2024-01-01 -to 2024-01-31 -data page_views -format ndjson -out page_views.ndjson -cursor page_views.cursor
+func main() {
+	hostname := flag.String("hostname", "127.0.0.1", "database hostname")
+	port := flag.Int("port", 9000, "database port")
+	database := flag.String("database", "pirsch", "database name")
+	username := flag.String("username", "default", "database user")
+	password := flag.String("password", "", "database password")
+	secure := flag.Bool("secure", false, "use TLS to connect to the database")
+	clientID := flag.Int64("client", 0, "client ID")
+	from := flag.String("from", "", "start date (YYYY-MM-DD)")
+	to := flag.String("to", "", "end date (YYYY-MM-DD)")
+	timezone := flag.String("timezone", "UTC", "timezone used for the date range")
+	data := flag.String("data", "page_views", "data to export (page_views, sessions, events)")
+	format := flag.String("format", string(analyzer.ExportNDJSON), "output format (ndjson, csv, parquet)")
+	out := flag.String("out", "", "output file (defaults to stdout)")
+	cursorFile := flag.String("cursor", "", "cursor file to resume from and update after the export")
+	flag.Parse()
+	tz, err := time.LoadLocation(*timezone)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	filter := &analyzer.Filter{
+		Ctx:      context.Background(),
+		ClientID: *clientID,
+		Timezone: tz,
+		From:     parseDate(*from, tz),
+		To:       parseDate(*to, tz),
+	}
+	client, err := db.NewClient(&db.ClientConfig{
+		Hostnames: []string{*hostname},
+		Port:      *port,
+		Database:  *database,
+		Username:  *username,
+		Password:  *password,
+		Secure:    *secure,
+	})
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cursor := loadCursor(*cursorFile)
+	w := io.Writer(os.Stdout)
+
+	if *out != "" {
+		flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+
+		if cursor != nil {
+			if analyzer.ExportFormat(*format) == analyzer.ExportParquet {
+				// Parquet files cannot be appended to, so the remaining rows must go to a new file
+				flags = os.O_CREATE | os.O_WRONLY | os.O_EXCL
+			} else {
+				flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+			}
+		}
+
+		f, err := os.OpenFile(*out, flags, 0644)
+
+		if errors.Is(err, os.ErrExist) {
+			log.Fatal("cannot resume a parquet export into an existing file, use a different output file")
+		} else if err != nil {
+			log.Fatal(err)
+		}
+
+		defer func() {
+			if err := f.Close(); err != nil {
+				log.Fatal(err)
+			}
+		}()
+		w = f
+	}
+
+	a := analyzer.NewAnalyzer(client)
+
+	switch *data {
+	case "page_views":
+		cursor, err = a.Export.PageViews(filter, w, analyzer.ExportFormat(*format), cursor)
+	case "sessions":
+		cursor, err = a.Export.Sessions(filter, w, analyzer.ExportFormat(*format), cursor)
+	case "events":
+		cursor, err = a.Export.Events(filter, w, analyzer.ExportFormat(*format), cursor)
+	default:
+		err = errors.New("unknown data type")
+	}
+
+	saveCursor(*cursorFile, cursor)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parseDate(date string, tz *time.Location) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+
+	t, err := time.ParseInLocation(time.DateOnly, date, tz)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return t
+}
+
+func loadCursor(path string) *analyzer.ExportCursor {
+	if path == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		log.Fatal(err)
+	}
+
+	cursor := new(analyzer.ExportCursor)
+
+	if err := json.Unmarshal(content, cursor); err != nil {
+		log.Fatal(err)
+	}
+
+	return cursor
+}
+
+func saveCursor(path string, cursor *analyzer.ExportCursor) {
+	if path == "" || cursor == nil {
+		return
+	}
+
+	content, err := json.Marshal(cursor)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+)
+
+const usage = `Usage: go run cmd/migrate/migrate.go [flags] <command>
+
+Commands:
+  up                 run all pending migrations
+  status             print the current version and pending migrations
+  dry-run [version]  print the statements that would be run (from the current or given version)
+  down <version>     roll back to the given version using the down migrations
+  force <version>    set the version and clear the dirty flag without running any migrations
+
+Flags:
+`
+
+// Runs and inspects the database schema migrations.
+//
+// Example: go run cmd/migrate/migrate.go -database pirsch -cluster pirsch status
+func main() {
+	hostname := flag.String("hostname", "127.0.0.1", "database hostname")
+	port := flag.Int("port", 9000, "database port")
+	database := flag.String("database", "pirsch", "database name")
+	username := flag.String("username", "default", "database user")
+	password := flag.String("password", "", "database password")
+	secure := flag.Bool("secure", false, "use TLS to connect to the database")
+	cluster := flag.String("cluster", "", "database cluster")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	config := &db.ClientConfig{
+		Hostnames: []string{*hostname},
+		Port:      *port,
+		Cluster:   *cluster,
+		Database:  *database,
+		Username:  *username,
+		Password:  *password,
+		Secure:    *secure,
+	}
+
+	switch flag.Arg(0) {
+	case "up":
+		if err := db.Migrate(config); err != nil {
+			log.Fatal(err)
+		}
+	case "status":
+		status, err := db.GetMigrationStatus(config)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("version: %d\n", status.Version)
+		fmt.Printf("dirty: %t\n", status.Dirty)
+		fmt.Printf("pending: %d\n", len(status.Pending))
+
+		for _, m := range status.Pending {
+			fmt.Printf("  %s\n", m.Name)
+		}
+	case "dry-run":
+		var version int
+
+		if flag.NArg() > 1 {
+			version = parseVersion(flag.Arg(1))
+		} else {
+			status, err := db.GetMigrationStatus(config)
+
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			version = status.Version
+		}
+
+		migrations, err := db.PendingMigrations(version, *cluster)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, m := range migrations {
+			fmt.Printf("-- %s\n", m.Name)
+
+			for _, s := range m.Statements {
+				fmt.Printf("%s;\n\n", s)
+			}
+		}
+	case "down":
+		if flag.NArg() < 2 {
+			log.Fatal("version missing")
+		}
+
+		if err := db.MigrateDown(config, parseVersion(flag.Arg(1))); err != nil {
+			log.Fatal(err)
+		}
+	case "force":
+		if flag.NArg() < 2 {
+			log.Fatal("version missing")
+		}
+
+		if err := db.ForceMigrationVersion(config, parseVersion(flag.Arg(1))); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func parseVersion(version string) int {
+	v, err := strconv.Atoi(version)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return v
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// config is the server configuration.
+// It's loaded from an optional JSON file and can be overridden using environment variables.
+type config struct {
+	// Host and Port the server listens on (PIRSCH_HOST, PIRSCH_PORT).
+	Host string `json:"host"`
+	Port int    `json:"port"`
+
+	// ShutdownTimeout is the time in seconds to wait for open requests before shutting down (PIRSCH_SHUTDOWN_TIMEOUT).
+	ShutdownTimeout int `json:"shutdown_timeout"`
+
+	// Database connection (PIRSCH_DB_HOSTNAMES, PIRSCH_DB_PORT, PIRSCH_DB_CLUSTER, PIRSCH_DB_DATABASE, PIRSCH_DB_USERNAME, PIRSCH_DB_PASSWORD, PIRSCH_DB_SECURE, PIRSCH_DB_SSL_SKIP_VERIFY).
+	DBHostnames     []string `json:"db_hostnames"`
+	DBPort          int      `json:"db_port"`
+	DBCluster       string   `json:"db_cluster"`
+	DBDatabase      string   `json:"db_database"`
+	DBUsername      string   `json:"db_username"`
+	DBPassword      string   `json:"db_password"`
+	DBSecure        bool     `json:"db_secure"`
+	DBSSLSkipVerify bool     `json:"db_ssl_skip_verify"`
+
+	// Migrate runs the database migrations on startup (PIRSCH_MIGRATE).
+	Migrate bool `json:"migrate"`
+
+	// Salt is used to generate visitor fingerprints (PIRSCH_SALT).
+	// Set it to a random, fixed value to keep visitors consistent across restarts.
+	Salt string `json:"salt"`
+
+	// Worker and WorkerBufferSize configure the Tracker (PIRSCH_WORKER, PIRSCH_WORKER_BUFFER_SIZE).
+	Worker           int `json:"worker"`
+	WorkerBufferSize int `json:"worker_buffer_size"`
+
+	// EngagementMinDuration (in seconds), EngagementMinPageViews, and EngagementEvents define when a session counts as engaged
+	// (PIRSCH_ENGAGEMENT_MIN_DURATION, PIRSCH_ENGAGEMENT_MIN_PAGE_VIEWS, PIRSCH_ENGAGEMENT_EVENTS as a comma separated list).
+	// The Tracker defaults are used if not set.
+	EngagementMinDuration  int      `json:"engagement_min_duration"`
+	EngagementMinPageViews int      `json:"engagement_min_page_views"`
+	EngagementEvents       []string `json:"engagement_events"`
+
+	// RealtimeWindow is the time in seconds a session is considered active for the real-time API (PIRSCH_REALTIME_WINDOW).
+	// Defaults to 5 minutes.
+	RealtimeWindow int `json:"realtime_window"`
+
+	// GeoDBLicenseKey and GeoDBPath enable the GeoLite2 database to look up countries and cities (PIRSCH_GEODB_LICENSE_KEY, PIRSCH_GEODB_PATH).
+	GeoDBLicenseKey string `json:"geodb_license_key"`
+	GeoDBPath       string `json:"geodb_path"`
+
+	// Clients are the clients and their API tokens (PIRSCH_CLIENTS as a comma separated list of id:token).
+	Clients []clientConfig `json:"clients"`
+}
+
+type clientConfig struct {
+	ID    int64  `json:"id"`
+	Token string `json:"token"`
+}
+
+func loadConfig(path string) (*config, error) {
+	cfg := &config{
+		Port:            8080,
+		ShutdownTimeout: 30,
+		DBHostnames:     []string{"127.0.0.1"},
+		DBPort:          9000,
+		DBDatabase:      "pirsch",
+		DBUsername:      "default",
+	}
+
+	if path != "" {
+		content, err := os.ReadFile(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(content, cfg); err != nil {
+			return nil, fmt.Errorf("error parsing configuration file: %w", err)
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (cfg *config) loadEnv() error {
+	envString("PIRSCH_HOST", &cfg.Host)
+	envString("PIRSCH_DB_CLUSTER", &cfg.DBCluster)
+	envString("PIRSCH_DB_DATABASE", &cfg.DBDatabase)
+	envString("PIRSCH_DB_USERNAME", &cfg.DBUsername)
+	envString("PIRSCH_DB_PASSWORD", &cfg.DBPassword)
+	envString("PIRSCH_SALT", &cfg.Salt)
+	envString("PIRSCH_GEODB_LICENSE_KEY", &cfg.GeoDBLicenseKey)
+	envString("PIRSCH_GEODB_PATH", &cfg.GeoDBPath)
+
+	if hostnames := os.Getenv("PIRSCH_DB_HOSTNAMES"); hostnames != "" {
+		cfg.DBHostnames = strings.Split(hostnames, ",")
+	}
+
+	if events := os.Getenv("PIRSCH_ENGAGEMENT_EVENTS"); events != "" {
+		cfg.EngagementEvents = strings.Split(events, ",")
+	}
+
+	for key, value := range map[string]*int{
+		"PIRSCH_PORT":                      &cfg.Port,
+		"PIRSCH_SHUTDOWN_TIMEOUT":          &cfg.ShutdownTimeout,
+		"PIRSCH_DB_PORT":                   &cfg.DBPort,
+		"PIRSCH_WORKER":                    &cfg.Worker,
+		"PIRSCH_WORKER_BUFFER_SIZE":        &cfg.WorkerBufferSize,
+		"PIRSCH_ENGAGEMENT_MIN_DURATION":   &cfg.EngagementMinDuration,
+		"PIRSCH_ENGAGEMENT_MIN_PAGE_VIEWS": &cfg.EngagementMinPageViews,
+		"PIRSCH_REALTIME_WINDOW":           &cfg.RealtimeWindow,
+	} {
+		if err := envInt(key, value); err != nil {
+			return err
+		}
+	}
+
+	for key, value := range map[string]*bool{
+		"PIRSCH_DB_SECURE":          &cfg.DBSecure,
+		"PIRSCH_DB_SSL_SKIP_VERIFY": &cfg.DBSSLSkipVerify,
+		"PIRSCH_MIGRATE":            &cfg.Migrate,
+	} {
+		if err := envBool(key, value); err != nil {
+			return err
+		}
+	}
+
+	if clients := os.Getenv("PIRSCH_CLIENTS"); clients != "" {
+		cfg.Clients = cfg.Clients[:0]
+
+		for _, client := range strings.Split(clients, ",") {
+			id, token, found := strings.Cut(client, ":")
+
+			if !found {
+				return errors.New("PIRSCH_CLIENTS must be a comma separated list of id:token")
+			}
+
+			clientID, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+
+			if err != nil {
+				return fmt.Errorf("invalid client ID in PIRSCH_CLIENTS: %w", err)
+			}
+
+			cfg.Clients = append(cfg.Clients, clientConfig{
+				ID:    clientID,
+				Token: strings.TrimSpace(token),
+			})
+		}
+	}
+
+	return nil
+}
+
+func (cfg *config) validate() error {
+	if len(cfg.Clients) == 0 {
+		return errors.New("no clients configured")
+	}
+
+	if cfg.EngagementMinDuration < 0 {
+		return errors.New("engagement minimum duration must not be negative")
+	}
+
+	if cfg.EngagementMinPageViews < 0 || cfg.EngagementMinPageViews > math.MaxUint16 {
+		return fmt.Errorf("engagement minimum page views must be between 0 and %d", math.MaxUint16)
+	}
+
+	if cfg.RealtimeWindow < 0 {
+		return errors.New("real-time window must not be negative")
+	}
+
+	tokens := make(map[string]struct{})
+
+	for _, client := range cfg.Clients {
+		if client.ID <= 0 {
+			return errors.New("client IDs must be greater than zero")
+		}
+
+		if len(client.Token) < 16 {
+			return fmt.Errorf("token for client %d must be at least 16 characters long", client.ID)
+		}
+
+		if _, found := tokens[client.Token]; found {
+			return fmt.Errorf("token for client %d is not unique", client.ID)
+		}
+
+		tokens[client.Token] = struct{}{}
+	}
+
+	return nil
+}
+
+func envString(key string, value *string) {
+	if v := os.Getenv(key); v != "" {
+		*value = v
+	}
+}
+
+func envInt(key string, value *int) error {
+	if v := os.Getenv(key); v != "" {
+		i, err := strconv.Atoi(v)
+
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+
+		*value = i
+	}
+
+	return nil
+}
+
+func envBool(key string, value *bool) error {
+	if v := os.Getenv(key); v != "" {
+		b, err := strconv.ParseBool(v)
+
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+
+		*value = b
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"net/url"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/analyzer"
+)
+
+// parseFilter reads the filter from the query parameters.
+// See analyzer.FilterFromValues for the format. The client ID is always set to the authenticated client.
+func parseFilter(query url.Values, clientID int64) (*analyzer.Filter, error) {
+	filter, err := analyzer.FilterFromValues(query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	filter.ClientID = clientID
+	return filter, nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/analyzer"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+)
+
+func (s *server) listGoals(w http.ResponseWriter, r *http.Request, clientID int64) {
+	goals, err := s.analyzer.Goals.List(r.Context(), uint64(clientID))
+
+	if err != nil {
+		s.writeStatisticsError(w, err)
+		return
+	}
+
+	if goals == nil {
+		goals = []model.Goal{}
+	}
+
+	writeJSON(w, http.StatusOK, goals)
+}
+
+func (s *server) saveGoal(w http.ResponseWriter, r *http.Request, clientID int64) {
+	goal := new(model.Goal)
+
+	if !decodeBody(w, r, goal) {
+		return
+	}
+
+	goal.ClientID = uint64(clientID)
+
+	if err := s.analyzer.Goals.Save(r.Context(), goal); err != nil {
+		if errors.Is(err, analyzer.ErrInvalidGoal) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		s.logger.Error("error saving goal", "err", err)
+		writeError(w, http.StatusInternalServerError, "error saving goal")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, goal)
+}
+
+func (s *server) deleteGoal(w http.ResponseWriter, r *http.Request, clientID int64) {
+	id, err := parseGoalID(r.PathValue("id"))
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.analyzer.Goals.Delete(r.Context(), uint64(clientID), id); err != nil {
+		s.logger.Error("error deleting goal", "err", err)
+		writeError(w, http.StatusInternalServerError, "error deleting goal")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseGoalID(value string) (uint64, error) {
+	id, err := strconv.ParseUint(value, 10, 64)
+
+	if err != nil || id == 0 {
+		return 0, parameterError("goal_id: must be a positive number")
+	}
+
+	return id, nil
+}
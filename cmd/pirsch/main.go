@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/analyzer"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker/geodb"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker/realtime"
+)
+
+// Runs a standalone server providing the tracking and statistics API.
+// The configuration is read from an optional JSON file and environment variables (see config.go).
+//
+// Example: PIRSCH_CLIENTS=1:secret-token-of-16-chars go run ./cmd/pirsch -config pirsch.json
+func main() {
+	configFile := flag.String("config", "", "path to the JSON configuration file")
+	flag.Parse()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	if err := run(*configFile, logger); err != nil {
+		logger.Error("error running server", "err", err)
+		os.Exit(1)
+	}
+}
+
+func run(configFile string, logger *slog.Logger) error {
+	cfg, err := loadConfig(configFile)
+
+	if err != nil {
+		return err
+	}
+
+	dbConfig := &db.ClientConfig{
+		Hostnames:     cfg.DBHostnames,
+		Port:          cfg.DBPort,
+		Cluster:       cfg.DBCluster,
+		Database:      cfg.DBDatabase,
+		Username:      cfg.DBUsername,
+		Password:      cfg.DBPassword,
+		Secure:        cfg.DBSecure,
+		SSLSkipVerify: cfg.DBSSLSkipVerify,
+		Logger:        logger,
+	}
+
+	if cfg.Migrate {
+		if err := db.Migrate(dbConfig); err != nil {
+			return fmt.Errorf("error migrating database: %w", err)
+		}
+	}
+
+	client, err := db.NewClient(dbConfig)
+
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %w", err)
+	}
+
+	defer client.Close()
+	var geoDB *geodb.GeoDB
+
+	if cfg.GeoDBLicenseKey != "" {
+		geoDB, err = geodb.NewGeoDB(cfg.GeoDBLicenseKey, cfg.GeoDBPath, "")
+
+		if err != nil {
+			return fmt.Errorf("error loading GeoDB: %w", err)
+		}
+	}
+
+	hub := realtime.NewHub(time.Second * time.Duration(cfg.RealtimeWindow))
+	defer hub.Stop()
+	t := tracker.NewTracker(tracker.Config{
+		Store:            client,
+		Salt:             cfg.Salt,
+		Worker:           cfg.Worker,
+		WorkerBufferSize: cfg.WorkerBufferSize,
+		GeoDB:            geoDB,
+		Realtime:         hub,
+		Logger:           logger,
+		Engagement: tracker.Engagement{
+			MinDuration:  time.Second * time.Duration(cfg.EngagementMinDuration),
+			MinPageViews: uint16(cfg.EngagementMinPageViews),
+			Events:       cfg.EngagementEvents,
+		},
+	})
+	s := &server{
+		tracker:  t,
+		analyzer: analyzer.NewAnalyzer(client),
+		realtime: hub,
+		clients:  cfg.Clients,
+		logger:   logger,
+	}
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Handler:           s.routes(),
+		ReadHeaderTimeout: time.Second * 10,
+	}
+
+	// close open real-time streams, as they would otherwise keep the server from shutting down
+	httpServer.RegisterOnShutdown(hub.Stop)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	serverErr := make(chan error, 1)
+
+	go func() {
+		logger.Info("starting server", "addr", httpServer.Addr)
+		serverErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		t.Stop()
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(cfg.ShutdownTimeout))
+	defer cancel()
+	err = httpServer.Shutdown(shutdownCtx)
+
+	// stop the tracker after the server has been shut down to save all remaining data
+	t.Stop()
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
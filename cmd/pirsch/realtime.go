@@ -0,0 +1,14 @@
+package main
+
+import (
+	"net/http"
+)
+
+func (s *server) realtimeStats(w http.ResponseWriter, _ *http.Request, clientID int64) {
+	writeJSON(w, http.StatusOK, s.realtime.Active(uint64(clientID)))
+}
+
+// realtimeStream streams the real-time statistics as Server-Sent Events.
+func (s *server) realtimeStream(w http.ResponseWriter, r *http.Request, clientID int64) {
+	s.realtime.ServeSSE(w, r, uint64(clientID))
+}
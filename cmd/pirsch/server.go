@@ -0,0 +1,72 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/analyzer"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker/realtime"
+)
+
+type handlerFunc func(http.ResponseWriter, *http.Request, int64)
+
+type server struct {
+	tracker  *tracker.Tracker
+	analyzer *analyzer.Analyzer
+	realtime *realtime.Hub
+	clients  []clientConfig
+	logger   *slog.Logger
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("POST /api/v1/hit", s.auth(s.pageView))
+	mux.HandleFunc("POST /api/v1/event", s.auth(s.event))
+	mux.HandleFunc("POST /api/v1/session", s.auth(s.extendSession))
+	mux.HandleFunc("GET /api/v1/realtime", s.auth(s.realtimeStats))
+	mux.HandleFunc("GET /api/v1/realtime/stream", s.auth(s.realtimeStream))
+	mux.HandleFunc("GET /api/v1/statistics/funnel", s.auth(s.funnel))
+	mux.HandleFunc("GET /api/v1/statistics/{component}/{method}", s.auth(s.statistics))
+	mux.HandleFunc("GET /api/v1/export/{data}", s.auth(s.export))
+	mux.HandleFunc("GET /api/v1/goals", s.auth(s.listGoals))
+	mux.HandleFunc("POST /api/v1/goals", s.auth(s.saveGoal))
+	mux.HandleFunc("DELETE /api/v1/goals/{id}", s.auth(s.deleteGoal))
+	return mux
+}
+
+// auth looks up the client for the bearer token in the Authorization header.
+func (s *server) auth(next handlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		if found {
+			for _, client := range s.clients {
+				if subtle.ConstantTimeCompare([]byte(token), []byte(client.Token)) == 1 {
+					next(w, r, client.ID)
+					return
+				}
+			}
+		}
+
+		writeError(w, http.StatusUnauthorized, "invalid token")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{message})
+}
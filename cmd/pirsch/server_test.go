@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/analyzer"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker/realtime"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testToken      = "token-of-client-1"
+	testOtherToken = "token-of-client-2"
+)
+
+// statsStore records the arguments of the country statistics query.
+type statsStore struct {
+	*db.ClientMock
+	args []any
+}
+
+func (store *statsStore) SelectCountryStats(_ context.Context, _ string, args ...any) ([]model.CountryStats, error) {
+	store.args = args
+	return []model.CountryStats{{MetaStats: model.MetaStats{Visitors: 42}, CountryCode: "de"}}, nil
+}
+
+func TestServer_Auth(t *testing.T) {
+	handler := newTestServer(t, db.NewClientMock()).routes()
+	resp := request(handler, http.MethodGet, "/health", "", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	for _, header := range []string{"", "Bearer ", "Bearer unknown-token", "Basic " + testToken, testToken} {
+		resp = request(handler, http.MethodGet, "/api/v1/realtime", header, "")
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		assert.JSONEq(t, `{"error": "invalid token"}`, resp.Body.String())
+	}
+
+	resp = request(handler, http.MethodGet, "/api/v1/realtime", "Bearer "+testToken, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+}
+
+func TestServer_Statistics(t *testing.T) {
+	store := &statsStore{ClientMock: db.NewClientMock()}
+	handler := newTestServer(t, store).routes()
+	resp := request(handler, http.MethodGet, "/api/v1/statistics/demographics/countries?from=2025-01-01&to=2025-01-31", "Bearer "+testToken, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	var stats []model.CountryStats
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &stats))
+	assert.Len(t, stats, 1)
+	assert.Equal(t, "de", stats[0].CountryCode)
+	assert.Equal(t, 42, stats[0].Visitors)
+	assert.Contains(t, store.args, int64(1))
+
+	// the client ID is always set to the authenticated client
+	resp = request(handler, http.MethodGet, "/api/v1/statistics/demographics/countries?client_id=2", "Bearer "+testToken, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, store.args, int64(1))
+	assert.NotContains(t, store.args, int64(2))
+	resp = request(handler, http.MethodGet, "/api/v1/statistics/demographics/countries", "Bearer "+testOtherToken, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, store.args, int64(2))
+	assert.NotContains(t, store.args, int64(1))
+
+	resp = request(handler, http.MethodGet, "/api/v1/statistics/demographics/countries?from=invalid", "Bearer "+testToken, "")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = request(handler, http.MethodGet, "/api/v1/statistics/unknown/countries", "Bearer "+testToken, "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	resp = request(handler, http.MethodGet, "/api/v1/statistics/demographics/unknown", "Bearer "+testToken, "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestServer_Goals(t *testing.T) {
+	handler := newTestServer(t, db.NewClientMock()).routes()
+	resp := request(handler, http.MethodGet, "/api/v1/goals", "Bearer "+testToken, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `[]`, resp.Body.String())
+	resp = request(handler, http.MethodPost, "/api/v1/goals", "Bearer "+testToken, `{"name": "Pricing", "path_pattern": "^/pricing", "client_id": 2}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var goal model.Goal
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &goal))
+	assert.Equal(t, uint64(1), goal.ClientID)
+	assert.Equal(t, "Pricing", goal.Name)
+	resp = request(handler, http.MethodPost, "/api/v1/goals", "Bearer "+testToken, `{}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = request(handler, http.MethodPost, "/api/v1/goals", "Bearer "+testToken, `invalid`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = request(handler, http.MethodDelete, "/api/v1/goals/1", "Bearer "+testToken, "")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	resp = request(handler, http.MethodDelete, "/api/v1/goals/invalid", "Bearer "+testToken, "")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestServer_PageView(t *testing.T) {
+	store := db.NewClientMock()
+	s := newTestServer(t, store)
+	handler := s.routes()
+	resp := request(handler, http.MethodPost, "/api/v1/hit", "Bearer "+testToken, `{
+		"url": "https://example.com/foo",
+		"ip": "81.2.69.142",
+		"user_agent": "Mozilla/5.0 AppleWebKit/537.36 Chrome/121.0.0.0 Safari/537.36"
+	}`)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	resp = request(handler, http.MethodPost, "/api/v1/event", "Bearer "+testToken, `{"url": "https://example.com/foo"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	s.tracker.Stop()
+	pageViews := store.GetPageViews()
+	assert.Len(t, pageViews, 1)
+	assert.Equal(t, uint64(1), pageViews[0].ClientID)
+	assert.Equal(t, "/foo", pageViews[0].Path)
+}
+
+func TestServer_RealtimeStream(t *testing.T) {
+	s := newTestServer(t, db.NewClientMock())
+	s.realtime.Update(&model.Session{ClientID: 1, VisitorID: 1, SessionID: 1, Time: time.Now().UTC(), ExitPath: "/"})
+	server := httptest.NewServer(s.routes())
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/realtime/stream", nil)
+	assert.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.NoError(t, resp.Body.Close())
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "event: active\n", line)
+	line, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	data, found := strings.CutPrefix(strings.TrimSpace(line), "data: ")
+	assert.True(t, found)
+	var stats model.RealtimeStats
+	assert.NoError(t, json.Unmarshal([]byte(data), &stats))
+	assert.Equal(t, 1, stats.Visitors)
+}
+
+func newTestServer(t *testing.T, store db.Store) *server {
+	hub := realtime.NewHub(time.Minute)
+	t.Cleanup(hub.Stop)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := &server{
+		tracker: tracker.NewTracker(tracker.Config{
+			Store:    store,
+			Realtime: hub,
+			Logger:   logger,
+		}),
+		analyzer: analyzer.NewAnalyzer(store),
+		realtime: hub,
+		clients: []clientConfig{
+			{ID: 1, Token: testToken},
+			{ID: 2, Token: testOtherToken},
+		},
+		logger: logger,
+	}
+	t.Cleanup(s.tracker.Stop)
+	return s
+}
+
+func request(handler http.Handler, method, path, authorization, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	return resp
+}
@@ -0,0 +1,565 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/analyzer"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+)
+
+type statisticsFunc func(*analyzer.Analyzer, *analyzer.Filter, url.Values) (any, error)
+
+// parameterError is returned by a statisticsFunc for invalid query parameters.
+type parameterError string
+
+func (err parameterError) Error() string {
+	return string(err)
+}
+
+// statistics maps the component and method to the Analyzer function.
+var statistics = map[string]map[string]statisticsFunc{
+	"visitors": {
+		"active": func(a *analyzer.Analyzer, f *analyzer.Filter, query url.Values) (any, error) {
+			duration := time.Minute * 10
+
+			if v := query.Get("duration"); v != "" {
+				seconds, err := strconv.Atoi(v)
+
+				if err != nil || seconds <= 0 {
+					return nil, parameterError("duration: must be a positive number of seconds")
+				}
+
+				duration = time.Second * time.Duration(seconds)
+			}
+
+			stats, visitors, err := a.Visitors.Active(f, duration)
+
+			if err != nil {
+				return nil, err
+			}
+
+			return struct {
+				Stats    any `json:"stats"`
+				Visitors int `json:"visitors"`
+			}{stats, visitors}, nil
+		},
+		"total": compareTotal(func(a *analyzer.Analyzer, f *analyzer.Filter) (*model.TotalVisitorStats, error) {
+			return a.Visitors.Total(f)
+		}),
+		"total_visitors":   stats(func(a *analyzer.Analyzer, f *analyzer.Filter) (any, error) { return a.Visitors.TotalVisitors(f) }),
+		"total_page_views": stats(func(a *analyzer.Analyzer, f *analyzer.Filter) (any, error) { return a.Visitors.TotalPageViews(f) }),
+		"total_sessions":   stats(func(a *analyzer.Analyzer, f *analyzer.Filter) (any, error) { return a.Visitors.TotalSessions(f) }),
+		"by_period": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.VisitorStats, error) {
+			return a.Visitors.ByPeriod(f)
+		}),
+		"by_hour": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.VisitorHourStats, error) {
+			return a.Visitors.ByHour(f)
+		}),
+		"by_minute": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.VisitorMinuteStats, error) {
+			return a.Visitors.ByMinute(f)
+		}),
+		"by_weekday_and_hour": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.VisitorWeekdayHourStats, error) {
+			return a.Visitors.ByWeekdayAndHour(f)
+		}),
+		"growth": stats(func(a *analyzer.Analyzer, f *analyzer.Filter) (any, error) { return a.Visitors.Growth(f) }),
+		"referrer": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.ReferrerStats, error) {
+			return a.Visitors.Referrer(f)
+		}),
+		"channel": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.ChannelStats, error) {
+			return a.Visitors.Channel(f)
+		}),
+		"total_visitors_views": stats(func(a *analyzer.Analyzer, f *analyzer.Filter) (any, error) {
+			return a.Visitors.TotalVisitorsPageViews(f)
+		}),
+	},
+	"pages": {
+		"hostname": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.HostnameStats, error) {
+			return a.Pages.Hostname(f)
+		}),
+		"path": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.PageStats, error) { return a.Pages.ByPath(f) }),
+		"event_path": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.PageStats, error) {
+			return a.Pages.ByEventPath(f)
+		}),
+		"entry": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.EntryStats, error) { return a.Pages.Entry(f) }),
+		"exit":  compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.ExitStats, error) { return a.Pages.Exit(f) }),
+		"conversions": compareTotal(func(a *analyzer.Analyzer, f *analyzer.Filter) (*model.ConversionsStats, error) {
+			return a.Pages.Conversions(f)
+		}),
+		"transitions": func(a *analyzer.Analyzer, f *analyzer.Filter, query url.Values) (any, error) {
+			return a.Pages.Transitions(f, query.Get("target"))
+		},
+		"flow": func(a *analyzer.Analyzer, f *analyzer.Filter, query url.Values) (any, error) {
+			options := analyzer.PageFlowOptions{
+				Path:      query.Get("start_path"),
+				EventName: query.Get("start_event"),
+				Limit:     f.Limit,
+			}
+
+			if v := query.Get("steps"); v != "" {
+				steps, err := strconv.Atoi(v)
+
+				if err != nil || steps <= 0 {
+					return nil, parameterError("steps: must be a positive number")
+				}
+
+				options.Steps = steps
+			}
+
+			return a.Pages.Flow(f, options)
+		},
+	},
+	"demographics": {
+		"languages": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.LanguageStats, error) {
+			return a.Demographics.Languages(f)
+		}),
+		"countries": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.CountryStats, error) {
+			return a.Demographics.Countries(f)
+		}),
+		"regions": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.RegionStats, error) {
+			return a.Demographics.Regions(f)
+		}),
+		"cities": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.CityStats, error) {
+			return a.Demographics.Cities(f)
+		}),
+	},
+	"device": {
+		"platform": compareTotal(func(a *analyzer.Analyzer, f *analyzer.Filter) (*model.PlatformStats, error) {
+			return a.Device.Platform(f)
+		}),
+		"browser": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.BrowserStats, error) {
+			return a.Device.Browser(f)
+		}),
+		"os": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.OSStats, error) { return a.Device.OS(f) }),
+		"browser_version": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.BrowserVersionStats, error) {
+			return a.Device.BrowserVersion(f)
+		}),
+		"os_version": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.OSVersionStats, error) {
+			return a.Device.OSVersion(f)
+		}),
+		"screen_class": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.ScreenClassStats, error) {
+			return a.Device.ScreenClass(f)
+		}),
+	},
+	"utm": {
+		"source": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.UTMSourceStats, error) { return a.UTM.Source(f) }),
+		"medium": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.UTMMediumStats, error) { return a.UTM.Medium(f) }),
+		"campaign": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.UTMCampaignStats, error) {
+			return a.UTM.Campaign(f)
+		}),
+		"content": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.UTMContentStats, error) {
+			return a.UTM.Content(f)
+		}),
+		"term": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.UTMTermStats, error) { return a.UTM.Term(f) }),
+	},
+	"events": {
+		"events": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.EventStats, error) { return a.Events.Events(f) }),
+		"breakdown": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.EventStats, error) {
+			return a.Events.Breakdown(f)
+		}),
+		"list": stats(func(a *analyzer.Analyzer, f *analyzer.Filter) (any, error) { return a.Events.List(f) }),
+	},
+	"time": {
+		"session_duration": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.TimeSpentStats, error) {
+			return a.Time.AvgSessionDuration(f)
+		}),
+		"time_on_page": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.TimeSpentStats, error) {
+			return a.Time.AvgTimeOnPage(f)
+		}),
+	},
+	"distribution": {
+		"percentiles": func(a *analyzer.Analyzer, f *analyzer.Filter, query url.Values) (any, error) {
+			return a.Distribution.Percentiles(f, analyzer.Metric(query.Get("metric")))
+		},
+		"histogram": func(a *analyzer.Analyzer, f *analyzer.Filter, query url.Values) (any, error) {
+			buckets, err := parseBuckets(query.Get("buckets"))
+
+			if err != nil {
+				return nil, err
+			}
+
+			return a.Distribution.Histogram(f, analyzer.Metric(query.Get("metric")), buckets)
+		},
+	},
+	"goals": {
+		"conversions": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.GoalStats, error) {
+			return a.Goals.Conversions(f)
+		}),
+		"breakdown": func(a *analyzer.Analyzer, f *analyzer.Filter, query url.Values) (any, error) {
+			id, err := parseGoalID(query.Get("goal_id"))
+
+			if err != nil {
+				return nil, err
+			}
+
+			field, found := breakdownFields[query.Get("breakdown")]
+
+			if !found {
+				return nil, parameterError("breakdown: unsupported field")
+			}
+
+			return compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.GoalBreakdownStats, error) {
+				return a.Goals.Breakdown(f, id, field, query.Get("tag_key"))
+			})(a, f, query)
+		},
+		"by_period": func(a *analyzer.Analyzer, f *analyzer.Filter, query url.Values) (any, error) {
+			id, err := parseGoalID(query.Get("goal_id"))
+
+			if err != nil {
+				return nil, err
+			}
+
+			return compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.GoalPeriodStats, error) {
+				return a.Goals.ByPeriod(f, id)
+			})(a, f, query)
+		},
+	},
+	"tags": {
+		"keys":      compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.TagStats, error) { return a.Tags.Keys(f) }),
+		"breakdown": compare(func(a *analyzer.Analyzer, f *analyzer.Filter) ([]model.TagStats, error) { return a.Tags.Breakdown(f) }),
+	},
+	"sessions": {
+		"list":      stats(func(a *analyzer.Analyzer, f *analyzer.Filter) (any, error) { return a.Sessions.List(f) }),
+		"breakdown": stats(func(a *analyzer.Analyzer, f *analyzer.Filter) (any, error) { return a.Sessions.Breakdown(f) }),
+	},
+	"options": {
+		"hostnames":             options((*analyzer.FilterOptions).Hostnames),
+		"pages":                 options((*analyzer.FilterOptions).Pages),
+		"referrer":              options((*analyzer.FilterOptions).Referrer),
+		"referrer_name":         options((*analyzer.FilterOptions).ReferrerName),
+		"utm_source":            options((*analyzer.FilterOptions).UTMSource),
+		"utm_medium":            options((*analyzer.FilterOptions).UTMMedium),
+		"utm_campaign":          options((*analyzer.FilterOptions).UTMCampaign),
+		"utm_content":           options((*analyzer.FilterOptions).UTMContent),
+		"utm_term":              options((*analyzer.FilterOptions).UTMTerm),
+		"channel":               options((*analyzer.FilterOptions).Channel),
+		"events":                options((*analyzer.FilterOptions).Events),
+		"countries":             options((*analyzer.FilterOptions).Countries),
+		"regions":               options((*analyzer.FilterOptions).Regions),
+		"cities":                options((*analyzer.FilterOptions).Cities),
+		"languages":             options((*analyzer.FilterOptions).Languages),
+		"os":                    options((*analyzer.FilterOptions).OS),
+		"browser":               options((*analyzer.FilterOptions).Browser),
+		"event_metadata_keys":   options((*analyzer.FilterOptions).EventMetadataKeys),
+		"event_metadata_values": options((*analyzer.FilterOptions).EventMetadataValues),
+		"tag_keys":              options((*analyzer.FilterOptions).TagKeys),
+		"tag_values":            options((*analyzer.FilterOptions).TagValues),
+	},
+}
+
+func stats(f func(*analyzer.Analyzer, *analyzer.Filter) (any, error)) statisticsFunc {
+	return func(a *analyzer.Analyzer, filter *analyzer.Filter, _ url.Values) (any, error) {
+		return f(a, filter)
+	}
+}
+
+// compare returns the statistics compared to the time range set by the compare parameter, if present.
+func compare[T any](f func(*analyzer.Analyzer, *analyzer.Filter) ([]T, error)) statisticsFunc {
+	return func(a *analyzer.Analyzer, filter *analyzer.Filter, query url.Values) (any, error) {
+		stats := func(filter *analyzer.Filter) ([]T, error) {
+			return f(a, filter)
+		}
+
+		if query.Has("compare") {
+			return analyzer.Compare(filter, stats)
+		}
+
+		return stats(filter)
+	}
+}
+
+// compareTotal returns the statistics compared to the time range set by the compare parameter, if present.
+func compareTotal[T any](f func(*analyzer.Analyzer, *analyzer.Filter) (*T, error)) statisticsFunc {
+	return func(a *analyzer.Analyzer, filter *analyzer.Filter, query url.Values) (any, error) {
+		stats := func(filter *analyzer.Filter) (*T, error) {
+			return f(a, filter)
+		}
+
+		if query.Has("compare") {
+			return analyzer.CompareTotal(filter, stats)
+		}
+
+		return stats(filter)
+	}
+}
+
+func options(f func(*analyzer.FilterOptions, *analyzer.Filter, string) ([]string, error)) statisticsFunc {
+	return func(a *analyzer.Analyzer, filter *analyzer.Filter, query url.Values) (any, error) {
+		return f(&a.Options, filter, query.Get("search"))
+	}
+}
+
+func (s *server) statistics(w http.ResponseWriter, r *http.Request, clientID int64) {
+	component, found := statistics[r.PathValue("component")]
+
+	if !found {
+		writeError(w, http.StatusNotFound, "component not found")
+		return
+	}
+
+	f, found := component[r.PathValue("method")]
+
+	if !found {
+		writeError(w, http.StatusNotFound, "method not found")
+		return
+	}
+
+	query := r.URL.Query()
+	filter, err := parseFilter(query, clientID)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter.Ctx = r.Context()
+	result, err := f(s.analyzer, filter, query)
+
+	if err != nil {
+		s.writeStatisticsError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *server) funnel(w http.ResponseWriter, r *http.Request, clientID int64) {
+	query := r.URL.Query()
+	steps := query["step"]
+
+	if len(steps) < 2 {
+		writeError(w, http.StatusBadRequest, "step: at least two steps are required")
+		return
+	}
+
+	filter := make([]analyzer.Filter, 0, len(steps))
+
+	for i, step := range steps {
+		values, err := url.ParseQuery(step)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "step "+strconv.Itoa(i+1)+": "+err.Error())
+			return
+		}
+
+		for _, key := range []string{"from", "to", "tz", "imported_until"} {
+			if !values.Has(key) && query.Has(key) {
+				values.Set(key, query.Get(key))
+			}
+		}
+
+		f, err := parseFilter(values, clientID)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "step "+strconv.Itoa(i+1)+": "+err.Error())
+			return
+		}
+
+		filter = append(filter, *f)
+	}
+
+	if query.Has("breakdown") {
+		s.funnelBreakdown(w, r, filter)
+		return
+	}
+
+	var result []model.FunnelStep
+	var err error
+
+	if query.Get("ordered") == "true" {
+		options, optionsErr := parseFunnelOptions(query)
+
+		if optionsErr != nil {
+			writeError(w, http.StatusBadRequest, optionsErr.Error())
+			return
+		}
+
+		result, err = s.analyzer.Funnel.Ordered(r.Context(), filter, options)
+	} else {
+		result, err = s.analyzer.Funnel.Steps(r.Context(), filter)
+	}
+
+	if err != nil {
+		s.writeStatisticsError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// breakdownFields maps the breakdown parameter to the field to break down a funnel or goal by.
+var breakdownFields = map[string]analyzer.Field{
+	"channel":       analyzer.FieldChannel,
+	"referrer_name": analyzer.FieldReferrerName,
+	"utm_source":    analyzer.FieldUTMSource,
+	"utm_medium":    analyzer.FieldUTMMedium,
+	"utm_campaign":  analyzer.FieldUTMCampaign,
+	"country":       analyzer.FieldCountry,
+	"language":      analyzer.FieldLanguage,
+	"browser":       analyzer.FieldBrowser,
+	"os":            analyzer.FieldOS,
+	"platform":      analyzer.FieldPlatform,
+	"screen_class":  analyzer.FieldScreenClass,
+	"tag":           analyzer.FieldTagValue,
+}
+
+// parseBuckets parses a comma separated list of histogram bucket lower bounds.
+func parseBuckets(value string) ([]float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(value, ",")
+	buckets := make([]float64, 0, len(parts))
+
+	for _, part := range parts {
+		bucket, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+
+		if err != nil {
+			return nil, parameterError("buckets: must be a comma separated list of numbers")
+		}
+
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets, nil
+}
+
+func (s *server) funnelBreakdown(w http.ResponseWriter, r *http.Request, filter []analyzer.Filter) {
+	query := r.URL.Query()
+	field, found := breakdownFields[query.Get("breakdown")]
+
+	if !found {
+		writeError(w, http.StatusBadRequest, "breakdown: unsupported field")
+		return
+	}
+
+	breakdown := analyzer.FunnelBreakdown{
+		Field:  field,
+		TagKey: query.Get("tag_key"),
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+
+		if err != nil || limit <= 0 {
+			writeError(w, http.StatusBadRequest, "limit: must be a positive number")
+			return
+		}
+
+		breakdown.Limit = limit
+	}
+
+	result, err := s.analyzer.Funnel.Breakdown(r.Context(), filter, breakdown)
+
+	if err != nil {
+		if errors.Is(err, analyzer.ErrNoTagKey) {
+			writeError(w, http.StatusBadRequest, "tag_key: "+err.Error())
+			return
+		}
+
+		s.writeStatisticsError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// parseFunnelOptions reads the options for ordered funnels from the query parameters.
+// The window is passed in seconds.
+func parseFunnelOptions(query url.Values) (*analyzer.FunnelOptions, error) {
+	options := &analyzer.FunnelOptions{
+		Scope:       analyzer.FunnelScope(query.Get("scope")),
+		StrictOrder: query.Get("strict_order") == "true",
+		NoRepeats:   query.Get("no_repeats") == "true",
+	}
+
+	if options.Scope != "" && options.Scope != analyzer.FunnelScopeSession && options.Scope != analyzer.FunnelScopeVisitor {
+		return nil, errors.New("scope: must be session or visitor")
+	}
+
+	if v := query.Get("window"); v != "" {
+		seconds, err := strconv.Atoi(v)
+
+		if err != nil || seconds <= 0 {
+			return nil, errors.New("window: must be a positive number of seconds")
+		}
+
+		options.Window = time.Second * time.Duration(seconds)
+	}
+
+	return options, nil
+}
+
+func (s *server) export(w http.ResponseWriter, r *http.Request, clientID int64) {
+	query := r.URL.Query()
+	filter, err := parseFilter(query, clientID)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter.Ctx = r.Context()
+	format := analyzer.ExportFormat(query.Get("format"))
+
+	switch format {
+	case "", analyzer.ExportNDJSON:
+		format = analyzer.ExportNDJSON
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	case analyzer.ExportCSV:
+		w.Header().Set("Content-Type", "text/csv")
+	case analyzer.ExportParquet:
+		w.Header().Set("Content-Type", "application/vnd.apache.parquet")
+	default:
+		writeError(w, http.StatusBadRequest, "format: unknown format")
+		return
+	}
+
+	switch r.PathValue("data") {
+	case "page_views":
+		_, err = s.analyzer.Export.PageViews(filter, w, format, nil)
+	case "sessions":
+		_, err = s.analyzer.Export.Sessions(filter, w, format, nil)
+	case "events":
+		_, err = s.analyzer.Export.Events(filter, w, format, nil)
+	default:
+		writeError(w, http.StatusNotFound, "data not found")
+		return
+	}
+
+	if err != nil {
+		s.logger.Error("error exporting data", "err", err, "client_id", clientID)
+	}
+}
+
+func (s *server) writeStatisticsError(w http.ResponseWriter, err error) {
+	if errors.Is(err, db.ErrQueryTooExpensive) {
+		writeError(w, http.StatusUnprocessableEntity, "the query is too expensive, please narrow down your filter")
+		return
+	}
+
+	var paramErr parameterError
+
+	if errors.Is(err, analyzer.ErrNoPeriodOrDay) || errors.Is(err, analyzer.ErrNoComparisonPeriod) ||
+		errors.Is(err, analyzer.ErrNoFlowStart) || errors.Is(err, analyzer.ErrNoTagKey) ||
+		errors.Is(err, analyzer.ErrUnsupportedBreakdownField) || errors.Is(err, analyzer.ErrUnsupportedMetric) ||
+		errors.Is(err, analyzer.ErrNoCustomMetric) || errors.Is(err, analyzer.ErrNoHistogramBuckets) ||
+		errors.As(err, &paramErr) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, analyzer.ErrGoalNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	s.logger.Error("error reading statistics", "err", err)
+	writeError(w, http.StatusInternalServerError, "error reading statistics")
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker"
+)
+
+// hit is the request body for page views and session extensions.
+// The request data (IP, User-Agent, ...) of the visitor must be passed, as the request is usually sent by a backend.
+type hit struct {
+	URL            string            `json:"url"`
+	IP             string            `json:"ip"`
+	UserAgent      string            `json:"user_agent"`
+	AcceptLanguage string            `json:"accept_language"`
+	Title          string            `json:"title"`
+	Referrer       string            `json:"referrer"`
+	ScreenWidth    uint16            `json:"screen_width"`
+	ScreenHeight   uint16            `json:"screen_height"`
+	Tags           map[string]string `json:"tags"`
+}
+
+// event is the request body for events.
+type event struct {
+	hit
+
+	Name           string            `json:"event_name"`
+	Duration       uint32            `json:"event_duration"`
+	Meta           map[string]string `json:"event_meta"`
+	NonInteractive bool              `json:"non_interactive"`
+}
+
+func (s *server) pageView(w http.ResponseWriter, r *http.Request, clientID int64) {
+	var body hit
+
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	s.tracker.PageView(body.request(r), uint64(clientID), body.options())
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *server) event(w http.ResponseWriter, r *http.Request, clientID int64) {
+	var body event
+
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	if body.Name == "" {
+		writeError(w, http.StatusBadRequest, "event_name missing")
+		return
+	}
+
+	s.tracker.Event(body.request(r), uint64(clientID), tracker.EventOptions{
+		Name:           body.Name,
+		Duration:       body.Duration,
+		Meta:           body.Meta,
+		NonInteractive: body.NonInteractive,
+	}, body.options())
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *server) extendSession(w http.ResponseWriter, r *http.Request, clientID int64) {
+	var body hit
+
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	s.tracker.ExtendSession(body.request(r), uint64(clientID), body.options())
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// request creates a copy of the request using the visitor data.
+func (h *hit) request(r *http.Request) *http.Request {
+	req := r.Clone(r.Context())
+
+	if h.IP != "" {
+		req.RemoteAddr = net.JoinHostPort(h.IP, "0")
+	}
+
+	req.Header.Set("User-Agent", h.UserAgent)
+	req.Header.Set("Accept-Language", h.AcceptLanguage)
+	req.Header.Del("Referer")
+	return req
+}
+
+func (h *hit) options() tracker.Options {
+	return tracker.Options{
+		URL:          h.URL,
+		Title:        h.Title,
+		Referrer:     h.Referrer,
+		ScreenWidth:  h.ScreenWidth,
+		ScreenHeight: h.ScreenHeight,
+		Tags:         h.Tags,
+	}
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, body any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024*64)).Decode(body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+
+	return true
+}
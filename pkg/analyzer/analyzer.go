@@ -2,7 +2,9 @@ package analyzer
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/pirsch-analytics/pirsch/v6/pkg"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
@@ -10,22 +12,28 @@ import (
 
 // Analyzer provides an interface to analyze statistics.
 type Analyzer struct {
-	Visitors     Visitors
-	Pages        Pages
-	Demographics Demographics
-	Device       Device
-	UTM          UTM
-	Events       Events
-	Time         Time
-	Tags         Tags
-	Sessions     Sessions
-	Options      FilterOptions
-	Funnel       Funnel
+	Visitors      Visitors
+	Pages         Pages
+	Demographics  Demographics
+	Device        Device
+	UTM           UTM
+	Events        Events
+	Time          Time
+	Tags          Tags
+	Sessions      Sessions
+	Options       FilterOptions
+	Funnel        Funnel
+	Export        Export
+	Goals         Goals
+	Distribution  Distribution
+	Segments      Segments
+	ContentGroups ContentGroups
+	store         db.Store
 }
 
 // NewAnalyzer returns a new Analyzer for a given Store.
 func NewAnalyzer(store db.Store) *Analyzer {
-	analyzer := new(Analyzer)
+	analyzer := &Analyzer{store: store}
 	analyzer.Visitors = Visitors{
 		analyzer: analyzer,
 		store:    store,
@@ -70,6 +78,24 @@ func NewAnalyzer(store db.Store) *Analyzer {
 		analyzer: analyzer,
 		store:    store,
 	}
+	analyzer.Export = Export{
+		analyzer: analyzer,
+		store:    store,
+	}
+	analyzer.Goals = Goals{
+		analyzer: analyzer,
+		store:    store,
+	}
+	analyzer.Distribution = Distribution{
+		analyzer: analyzer,
+		store:    store,
+	}
+	analyzer.Segments = Segments{
+		store: store,
+	}
+	analyzer.ContentGroups = ContentGroups{
+		store: store,
+	}
 	return analyzer
 }
 
@@ -83,24 +109,71 @@ func (analyzer *Analyzer) timeOnPageQuery(filter *Filter) string {
 	return timeOnPage
 }
 
-func (analyzer *Analyzer) selectByAttribute(filter *Filter, fromImported string, attr ...Field) (context.Context, string, []any) {
-	fields := make([]Field, 0, len(attr)+2)
+// metaFields are the metrics selected for meta-result types in the order of model.MetaStats.
+var metaFields = []Field{
+	FieldVisitors,
+	FieldRelativeVisitors,
+	FieldSessions,
+	FieldViews,
+	FieldEngagedSessions,
+	FieldEngagementRate,
+	FieldPagesPerSession,
+}
+
+func (analyzer *Analyzer) selectByAttribute(filter *Filter, fromImported string, attr ...Field) (context.Context, string, []any, *rowCount, error) {
+	fields := make([]Field, 0, len(attr)+len(metaFields))
 	fields = append(fields, attr...)
-	fields = append(fields, FieldVisitors, FieldRelativeVisitors)
+	fields = append(fields, metaFields...)
 	orderBy := make([]Field, 0, len(attr)+1)
 	orderBy = append(orderBy, FieldVisitors)
 	orderBy = append(orderBy, attr...)
-	filter = analyzer.getFilter(filter)
-	query, args := filter.buildQuery(fields, attr, orderBy, []Field{attr[0], FieldVisitors}, fromImported)
-	return filter.Ctx, query, args
+	filter, err := analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, "", nil, nil, err
+	}
+
+	query, args, count := filter.buildListQuery(analyzer.store, fields, attr, orderBy, []Field{attr[0], FieldVisitors}, fromImported)
+	return filter.Ctx, query, args, count, nil
 }
 
-func (analyzer *Analyzer) getFilter(filter *Filter) *Filter {
+func (analyzer *Analyzer) getFilter(filter *Filter) (*Filter, error) {
 	if filter == nil {
 		filter = NewFilter(pkg.NullClient)
 	}
 
 	filter.validate()
+
+	if filter.Expression != nil {
+		if err := filter.Expression.validate(); err != nil {
+			return nil, &FilterError{Field: "expr", Err: err}
+		}
+	}
+
+	if err := filter.validateRanges(); err != nil {
+		return nil, err
+	}
+
+	for i := range filter.SegmentConditions {
+		if !filter.SegmentConditions[i].valid() {
+			return nil, &FilterError{Field: "segment_condition", Err: ErrInvalidSegment}
+		}
+	}
+
 	filterCopy := *filter
-	return &filterCopy
+	filterCopy.Ctx = db.WithQuerySettings(filterCopy.Ctx, filterCopy.QuerySettings)
+
+	if filterCopy.Segment != "" {
+		segment, err := analyzer.Segments.Get(filterCopy.Ctx, uint64(filterCopy.ClientID), filterCopy.Segment)
+
+		// unknown segments are left in place to match no sessions
+		if err == nil {
+			filterCopy.SegmentConditions = append(slices.Clone(filterCopy.SegmentConditions), segment.Conditions...)
+			filterCopy.Segment = ""
+		} else if !errors.Is(err, ErrSegmentNotFound) {
+			return nil, err
+		}
+	}
+
+	return &filterCopy, nil
 }
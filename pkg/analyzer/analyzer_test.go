@@ -2,6 +2,7 @@ package analyzer
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -71,6 +72,37 @@ func TestAnalyzer_NoData(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestAnalyzer_GetFilter(t *testing.T) {
+	analyzer := NewAnalyzer(db.NewClientMock())
+	var filterErr *FilterError
+	_, err := analyzer.getFilter(&Filter{Expression: &Expression{
+		And: []Expression{{Field: FieldCountry, Value: []string{"de"}}},
+		Or:  []Expression{{Field: FieldChannel, Value: []string{"Direct"}}},
+	}})
+	assert.True(t, errors.As(err, &filterErr))
+	assert.Equal(t, "expr", filterErr.Field)
+	_, err = analyzer.getFilter(&Filter{Expression: &Expression{And: []Expression{
+		{Field: FieldEntryPath, Value: []string{"/"}},
+		{Field: FieldPath, Value: []string{"/foo"}},
+	}}})
+	assert.True(t, errors.As(err, &filterErr))
+	_, err = analyzer.Visitors.Total(&Filter{Expression: &Expression{Field: FieldCountry}})
+	assert.True(t, errors.As(err, &filterErr))
+	_, err = analyzer.getFilter(&Filter{SessionDuration: &Range{Operator: "=", Value: 60}})
+	assert.True(t, errors.As(err, &filterErr))
+	assert.Equal(t, "session_duration", filterErr.Field)
+	_, err = analyzer.getFilter(&Filter{EventMetaRange: map[string]Range{"amount": {Value: 10}}})
+	assert.True(t, errors.As(err, &filterErr))
+	assert.Equal(t, "meta_range.amount", filterErr.Field)
+	_, err = analyzer.getFilter(&Filter{SegmentConditions: []SegmentCondition{{Path: "/"}, {Event: "Sale", Path: "/"}}})
+	assert.True(t, errors.As(err, &filterErr))
+	assert.Equal(t, "segment_condition", filterErr.Field)
+	assert.ErrorIs(t, err, ErrInvalidSegment)
+	filter, err := analyzer.getFilter(&Filter{Expression: &Expression{Field: FieldCountry, Value: []string{"de"}}, TimeOnPage: &Range{Operator: RangeBetween, Value: 10, To: 20}})
+	assert.NoError(t, err)
+	assert.NotNil(t, filter.Expression)
+}
+
 func getMaxFilter(eventName string) *Filter {
 	var events []string
 
@@ -112,6 +144,12 @@ func getMaxFilter(eventName string) *Filter {
 	}
 }
 
+func getValidFilter(t *testing.T, analyzer *Analyzer, filter *Filter) *Filter {
+	f, err := analyzer.getFilter(filter)
+	assert.NoError(t, err)
+	return f
+}
+
 func saveSessions(t *testing.T, sessions [][]model.Session) {
 	for _, entries := range sessions {
 		assert.NoError(t, dbClient.SaveSessions(entries))
@@ -0,0 +1,197 @@
+package analyzer
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+)
+
+const (
+	// MetricVisitors is the number of unique visitors.
+	MetricVisitors = Metric("visitors")
+
+	// MetricSessions is the number of sessions.
+	MetricSessions = Metric("sessions")
+
+	// MetricViews is the number of page views.
+	MetricViews = Metric("views")
+
+	// MetricBounceRate is the share of sessions that bounced.
+	MetricBounceRate = Metric("bounce_rate")
+
+	// MetricCR is the conversion rate, the visitors relative to all visitors in the selected period.
+	MetricCR = Metric("cr")
+
+	// MetricCustomMetricAvg is the average of the event metadata field set by Filter.CustomMetricKey and Filter.CustomMetricType.
+	MetricCustomMetricAvg = Metric("custom_metric_avg")
+
+	// MetricCustomMetricTotal is the sum of the event metadata field set by Filter.CustomMetricKey and Filter.CustomMetricType.
+	MetricCustomMetricTotal = Metric("custom_metric_total")
+)
+
+var (
+	// ErrInvalidBreakdown is returned in case a breakdown has no dimensions or metrics, or contains duplicates.
+	ErrInvalidBreakdown = errors.New("breakdown requires at least one dimension and metric without duplicates")
+
+	// breakdownDimensions are the fields that can be used as a dimension for Analyzer.Breakdown.
+	breakdownDimensions = []Field{
+		FieldHostname,
+		FieldPath,
+		FieldLanguage,
+		FieldCountry,
+		FieldRegion,
+		FieldCity,
+		FieldReferrer,
+		FieldReferrerName,
+		FieldChannel,
+		FieldOS,
+		FieldOSVersion,
+		FieldBrowser,
+		FieldBrowserVersion,
+		FieldPlatform,
+		FieldScreenClass,
+		FieldUTMSource,
+		FieldUTMMedium,
+		FieldUTMCampaign,
+		FieldUTMContent,
+		FieldUTMTerm,
+	}
+
+	// breakdownMetrics are the fields that can be used as a metric for Analyzer.Breakdown.
+	breakdownMetrics = map[Metric]Field{
+		MetricVisitors:          FieldVisitors,
+		MetricSessions:          FieldSessions,
+		MetricViews:             FieldViews,
+		MetricBounceRate:        FieldBounceRate,
+		MetricCR:                FieldCR,
+		MetricCustomMetricAvg:   FieldEventMetaCustomMetricAvg,
+		MetricCustomMetricTotal: FieldEventMetaCustomMetricTotal,
+	}
+
+	// breakdownMetricDependencies are the fields a metric is calculated from, which must be selected as well.
+	breakdownMetricDependencies = map[Metric][]Field{
+		MetricBounceRate: {FieldSessions, FieldBounces},
+		MetricCR:         {FieldVisitors},
+	}
+)
+
+// Breakdown returns the metrics grouped by any combination of dimensions, like country, browser, and channel.
+// Supported dimensions are session attributes, the hostname, and the path.
+// The rows are sorted by the first metric by default and can be sorted by any selected dimension or metric using Filter.Sort.
+// Filter.Limit and Filter.Offset page the rows, while the totals and total number of rows are calculated for all rows.
+// Imported statistics are not included.
+func (analyzer *Analyzer) Breakdown(filter *Filter, dimensions []Field, metrics []Metric) (*model.BreakdownStats, error) {
+	if len(dimensions) == 0 || len(metrics) == 0 {
+		return nil, ErrInvalidBreakdown
+	}
+
+	stats := &model.BreakdownStats{
+		Dimensions: make([]string, 0, len(dimensions)),
+		Metrics:    make([]string, 0, len(metrics)),
+	}
+
+	for i, dimension := range dimensions {
+		if !slices.Contains(breakdownDimensions, dimension) {
+			return nil, ErrUnsupportedBreakdownField
+		}
+
+		if slices.Contains(dimensions[:i], dimension) {
+			return nil, ErrInvalidBreakdown
+		}
+
+		stats.Dimensions = append(stats.Dimensions, dimension.Name)
+	}
+
+	filter, err := analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	metricFields := make([]Field, 0, len(metrics))
+
+	for i, metric := range metrics {
+		field, found := breakdownMetrics[metric]
+
+		if !found {
+			return nil, ErrUnsupportedMetric
+		}
+
+		if slices.Contains(metrics[:i], metric) {
+			return nil, ErrInvalidBreakdown
+		}
+
+		if (metric == MetricCustomMetricAvg || metric == MetricCustomMetricTotal) &&
+			(len(filter.EventName) == 0 || filter.CustomMetricKey == "" || filter.CustomMetricType == "") {
+			return nil, ErrNoCustomMetric
+		}
+
+		for _, f := range append(slices.Clone(breakdownMetricDependencies[metric]), field) {
+			if !slices.Contains(metricFields, f) {
+				metricFields = append(metricFields, f)
+			}
+		}
+
+		stats.Metrics = append(stats.Metrics, string(metric))
+	}
+
+	fields := append(slices.Clone(dimensions), metricFields...)
+	orderBy := append([]Field{breakdownMetrics[metrics[0]]}, dimensions...)
+	filter.Sort = slices.DeleteFunc(slices.Clone(filter.Sort), func(sort Sort) bool {
+		return !slices.ContainsFunc(fields, func(field Field) bool {
+			return field.Name == sort.Field.Name
+		})
+	})
+	q, args := filter.buildQuery(fields, dimensions, orderBy, nil, "")
+	rows, err := analyzer.store.SelectBreakdownRows(filter.Ctx, len(dimensions), q, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q, args = filter.buildCountQuery(fields, dimensions, nil, "")
+	stats.TotalRows, err = countRows(analyzer.store, filter, len(rows), q, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	totalsFilter := *filter
+	totalsFilter.Sort = nil
+	totalsFilter.Offset = 0
+	totalsFilter.Limit = 0
+	q, args = totalsFilter.buildQuery(metricFields, nil, nil, nil, "")
+	totals, err := analyzer.store.SelectBreakdownRows(filter.Ctx, 0, q, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Rows = make([]model.BreakdownRow, 0, len(rows))
+
+	for _, row := range rows {
+		row.Metrics = selectBreakdownMetrics(row.Metrics, stats.Metrics)
+		stats.Rows = append(stats.Rows, row)
+	}
+
+	var totalMetrics map[string]float64
+
+	if len(totals) > 0 {
+		totalMetrics = totals[0].Metrics
+	}
+
+	stats.Totals = selectBreakdownMetrics(totalMetrics, stats.Metrics)
+	return stats, nil
+}
+
+// selectBreakdownMetrics returns the requested metrics and drops the columns they depend on.
+func selectBreakdownMetrics(values map[string]float64, metrics []string) map[string]float64 {
+	result := make(map[string]float64, len(metrics))
+
+	for _, metric := range metrics {
+		result[metric] = values[metric]
+	}
+
+	return result
+}
@@ -0,0 +1,73 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzer_Breakdown(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, VisitorID: 1, SessionID: 1, Time: time.Now(), Start: time.Now(), CountryCode: "de", Browser: pkg.BrowserChrome, Channel: "Direct", IsBounce: true, PageViews: 1},
+			{Sign: 1, VisitorID: 2, SessionID: 2, Time: time.Now(), Start: time.Now(), CountryCode: "de", Browser: pkg.BrowserChrome, Channel: "Direct", PageViews: 3},
+			{Sign: 1, VisitorID: 3, SessionID: 3, Time: time.Now(), Start: time.Now(), CountryCode: "de", Browser: pkg.BrowserFirefox, Channel: "Organic Search", PageViews: 2},
+			{Sign: 1, VisitorID: 4, SessionID: 4, Time: time.Now(), Start: time.Now(), CountryCode: "us", Browser: pkg.BrowserChrome, Channel: "Direct", IsBounce: true, PageViews: 1},
+		},
+	})
+	analyzer := NewAnalyzer(dbClient)
+	stats, err := analyzer.Breakdown(nil, []Field{FieldCountry, FieldBrowser}, []Metric{MetricVisitors, MetricViews, MetricBounceRate, MetricCR})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"country_code", "browser"}, stats.Dimensions)
+	assert.Equal(t, []string{"visitors", "views", "bounce_rate", "cr"}, stats.Metrics)
+	assert.Len(t, stats.Rows, 3)
+	assert.Equal(t, []string{"de", pkg.BrowserChrome}, stats.Rows[0].Dimensions)
+	assert.Equal(t, []string{"de", pkg.BrowserFirefox}, stats.Rows[1].Dimensions)
+	assert.Equal(t, []string{"us", pkg.BrowserChrome}, stats.Rows[2].Dimensions)
+	assert.Equal(t, 2.0, stats.Rows[0].Metrics["visitors"])
+	assert.Equal(t, 4.0, stats.Rows[0].Metrics["views"])
+	assert.InDelta(t, 0.5, stats.Rows[0].Metrics["bounce_rate"], 0.01)
+	assert.InDelta(t, 0.5, stats.Rows[0].Metrics["cr"], 0.01)
+	assert.Len(t, stats.Rows[0].Metrics, 4)
+	assert.Equal(t, 4.0, stats.Totals["visitors"])
+	assert.Equal(t, 7.0, stats.Totals["views"])
+	assert.InDelta(t, 0.5, stats.Totals["bounce_rate"], 0.01)
+	assert.Len(t, stats.Totals, 4)
+	stats, err = analyzer.Breakdown(&Filter{
+		Country: []string{"de"},
+		Sort:    []Sort{{Field: FieldChannel, Direction: pkg.DirectionDESC}},
+		Limit:   1,
+	}, []Field{FieldChannel}, []Metric{MetricSessions})
+	assert.NoError(t, err)
+	assert.Len(t, stats.Rows, 1)
+	assert.Equal(t, []string{"Organic Search"}, stats.Rows[0].Dimensions)
+	assert.Equal(t, 1.0, stats.Rows[0].Metrics["sessions"])
+	assert.Equal(t, 3.0, stats.Totals["sessions"])
+}
+
+func TestAnalyzer_BreakdownInvalid(t *testing.T) {
+	analyzer := NewAnalyzer(db.NewClientMock())
+	_, err := analyzer.Breakdown(nil, nil, []Metric{MetricVisitors})
+	assert.ErrorIs(t, err, ErrInvalidBreakdown)
+	_, err = analyzer.Breakdown(nil, []Field{FieldCountry}, nil)
+	assert.ErrorIs(t, err, ErrInvalidBreakdown)
+	_, err = analyzer.Breakdown(nil, []Field{FieldCountry, FieldCountry}, []Metric{MetricVisitors})
+	assert.ErrorIs(t, err, ErrInvalidBreakdown)
+	_, err = analyzer.Breakdown(nil, []Field{FieldCountry}, []Metric{MetricVisitors, MetricVisitors})
+	assert.ErrorIs(t, err, ErrInvalidBreakdown)
+	_, err = analyzer.Breakdown(nil, []Field{FieldVisitors}, []Metric{MetricVisitors})
+	assert.ErrorIs(t, err, ErrUnsupportedBreakdownField)
+	_, err = analyzer.Breakdown(nil, []Field{FieldCountry}, []Metric{MetricTimeOnPage})
+	assert.ErrorIs(t, err, ErrUnsupportedMetric)
+	_, err = analyzer.Breakdown(&Filter{EventName: []string{"Sale"}}, []Field{FieldCountry}, []Metric{MetricCustomMetricTotal})
+	assert.ErrorIs(t, err, ErrNoCustomMetric)
+	stats, err := analyzer.Breakdown(nil, []Field{FieldCountry}, []Metric{MetricVisitors, MetricBounceRate})
+	assert.NoError(t, err)
+	assert.Empty(t, stats.Rows)
+	assert.Equal(t, map[string]float64{"visitors": 0, "bounce_rate": 0}, stats.Totals)
+}
@@ -0,0 +1,294 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/emvi/null"
+	"github.com/pirsch-analytics/pirsch/v6/pkg"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/util"
+)
+
+const (
+	// ComparePrevious compares to the previous period of equal length.
+	// For a single day, the same day of the previous week is used.
+	ComparePrevious = Comparison("previous")
+
+	// CompareYear compares to the same period of the previous year.
+	CompareYear = Comparison("year")
+
+	// CompareCustom compares to the period set by Filter.CompareFrom and Filter.CompareTo.
+	CompareCustom = Comparison("custom")
+)
+
+var (
+	// ErrNoComparisonPeriod is returned in case CompareCustom is used without setting the comparison period.
+	ErrNoComparisonPeriod = errors.New("no comparison period specified")
+)
+
+// Comparison sets the time range to compare statistics to.
+type Comparison string
+
+// comparisonKeyFields are numeric fields that identify a row instead of being a metric.
+var comparisonKeyFields = []string{"hour", "minute", "weekday", "step"}
+
+// Compare returns the statistics for the filter together with the statistics for the comparison time range
+// set by Filter.Compare, and the growth for each metric.
+// Rows are matched by their dimensions (like the path or country code). Time series (rows grouped by hour, day, week, month,
+// quarter, or year) are matched by position instead. Rows that only exist for the comparison time range are dropped.
+// Offset and Limit are ignored for the comparison time range, so that all rows can be matched.
+//
+//	stats, err := analyzer.Compare(filter, a.Pages.ByPath)
+func Compare[T any](filter *Filter, stats func(*Filter) ([]T, error)) ([]model.ComparisonStats[T], error) {
+	previousFilter, err := filter.comparison()
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields := getComparisonFields(reflect.TypeFor[T]())
+
+	if !fields.timeSeries {
+		previousFilter.Offset = 0
+		previousFilter.Limit = 0
+	}
+
+	current, err := stats(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := stats(previousFilter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var previousByKey map[string]T
+
+	if !fields.timeSeries {
+		previousByKey = make(map[string]T, len(previous))
+
+		for _, row := range previous {
+			previousByKey[fields.key(reflect.ValueOf(row))] = row
+		}
+	}
+
+	results := make([]model.ComparisonStats[T], 0, len(current))
+
+	for i, row := range current {
+		var previousRow T
+
+		if fields.timeSeries {
+			if i < len(previous) {
+				previousRow = previous[i]
+			}
+		} else {
+			previousRow = previousByKey[fields.key(reflect.ValueOf(row))]
+		}
+
+		results = append(results, model.ComparisonStats[T]{
+			Stats:    row,
+			Previous: previousRow,
+			Growth:   fields.growth(reflect.ValueOf(row), reflect.ValueOf(previousRow)),
+		})
+	}
+
+	return results, nil
+}
+
+// CompareTotal returns the statistics for the filter together with the statistics for the comparison time range
+// set by Filter.Compare, and the growth for each metric.
+//
+//	total, err := analyzer.CompareTotal(filter, a.Visitors.Total)
+func CompareTotal[T any](filter *Filter, stats func(*Filter) (*T, error)) (*model.ComparisonStats[T], error) {
+	previousFilter, err := filter.comparison()
+
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := stats(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := stats(previousFilter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields := getComparisonFields(reflect.TypeFor[T]())
+	return &model.ComparisonStats[T]{
+		Stats:    *current,
+		Previous: *previous,
+		Growth:   fields.growth(reflect.ValueOf(*current), reflect.ValueOf(*previous)),
+	}, nil
+}
+
+// comparison returns a copy of the filter for the comparison time range.
+func (filter *Filter) comparison() (*Filter, error) {
+	if filter == nil {
+		filter = NewFilter(pkg.NullClient)
+	}
+
+	filter.validate()
+
+	if err := filter.validateComparison(); err != nil {
+		return nil, err
+	}
+
+	filterCopy := *filter
+	filterCopy.comparisonPeriod()
+	return &filterCopy, nil
+}
+
+// validateComparison returns an error if the time range or the comparison time range is not set.
+func (filter *Filter) validateComparison() error {
+	if filter.From.IsZero() || filter.To.IsZero() {
+		return ErrNoPeriodOrDay
+	}
+
+	if filter.Compare == CompareCustom && (filter.CompareFrom.IsZero() || filter.CompareTo.IsZero()) {
+		return ErrNoComparisonPeriod
+	}
+
+	return nil
+}
+
+// comparisonPeriod sets the time range of the filter to the comparison time range.
+func (filter *Filter) comparisonPeriod() {
+	from := filter.From
+	to := filter.To
+
+	if !filter.importedFrom.IsZero() && filter.importedFrom.Before(from) {
+		from = filter.importedFrom
+	}
+
+	switch filter.Compare {
+	case CompareYear:
+		from = previousYear(from)
+		to = previousYear(to)
+	case CompareCustom:
+		from = filter.CompareFrom
+		to = filter.CompareTo
+	default:
+		if from.Equal(to) {
+			if to.Equal(util.Today()) {
+				from = from.Add(-time.Hour * 24 * 7)
+				to = time.Now().UTC().Add(-time.Hour * 24 * 7)
+				filter.IncludeTime = true
+			} else {
+				from = from.Add(-time.Hour * 24 * 7)
+				to = to.Add(-time.Hour * 24 * 7)
+			}
+		} else {
+			days := to.Sub(from)
+
+			if days >= time.Hour*24 {
+				to = from.Add(-time.Hour * 24)
+				from = to.Add(-days)
+			} else {
+				from = from.Add(-time.Hour * 24)
+				to = to.Add(-time.Hour * 24)
+			}
+		}
+	}
+
+	filter.From = from
+	filter.To = to
+	filter.importedFrom = time.Time{}
+	filter.importedTo = time.Time{}
+
+	if !filter.ImportedUntil.IsZero() {
+		filter.validate()
+	}
+}
+
+// previousYear returns the same day of the previous year or the 28th of February for leap days.
+func previousYear(t time.Time) time.Time {
+	year := t.AddDate(-1, 0, 0)
+
+	if year.Month() != t.Month() {
+		return year.AddDate(0, 0, -year.Day())
+	}
+
+	return year
+}
+
+type comparisonFields struct {
+	timeSeries bool
+	keys       [][]int
+	metrics    [][]int
+	names      []string
+}
+
+func getComparisonFields(t reflect.Type) *comparisonFields {
+	fields := new(comparisonFields)
+	nullTime := reflect.TypeFor[null.Time]()
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Int, reflect.Int64, reflect.Float64:
+			if slices.Contains(comparisonKeyFields, name) {
+				fields.keys = append(fields.keys, field.Index)
+			} else {
+				fields.metrics = append(fields.metrics, field.Index)
+				fields.names = append(fields.names, name)
+			}
+		default:
+			if field.Type == nullTime {
+				fields.timeSeries = true
+			}
+
+			fields.keys = append(fields.keys, field.Index)
+		}
+	}
+
+	return fields
+}
+
+func (fields *comparisonFields) key(row reflect.Value) string {
+	var key strings.Builder
+
+	for _, index := range fields.keys {
+		key.WriteString(fmt.Sprint(row.FieldByIndex(index).Interface()))
+		key.WriteByte(0)
+	}
+
+	return key.String()
+}
+
+func (fields *comparisonFields) growth(current, previous reflect.Value) map[string]float64 {
+	growth := make(map[string]float64, len(fields.metrics))
+
+	for i, index := range fields.metrics {
+		c, p := current.FieldByIndex(index), previous.FieldByIndex(index)
+
+		if c.CanInt() {
+			growth[fields.names[i]] = calculateGrowth(int(c.Int()), int(p.Int()))
+		} else {
+			growth[fields.names[i]] = calculateGrowth(c.Float(), p.Float())
+		}
+	}
+
+	return growth
+}
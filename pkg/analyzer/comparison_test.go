@@ -0,0 +1,110 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/emvi/null"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC)
+	var filters []Filter
+	pages := func(filter *Filter) ([]model.PageStats, error) {
+		filters = append(filters, *filter)
+
+		if filter.From.Equal(from) {
+			return []model.PageStats{
+				{Path: "/", Visitors: 20, Views: 30, BounceRate: 0.5},
+				{Path: "/new", Visitors: 5, Views: 5},
+			}, nil
+		}
+
+		return []model.PageStats{
+			{Path: "/old", Visitors: 99},
+			{Path: "/", Visitors: 10, Views: 30, BounceRate: 0.25},
+		}, nil
+	}
+	stats, err := Compare(&Filter{From: from, To: to, Compare: CompareYear, Limit: 2}, pages)
+	assert.NoError(t, err)
+	assert.Len(t, filters, 2)
+	assert.Equal(t, 2, filters[0].Limit)
+	assert.Zero(t, filters[1].Limit)
+	assert.Equal(t, time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), filters[1].From)
+	assert.Equal(t, time.Date(2023, 5, 31, 0, 0, 0, 0, time.UTC), filters[1].To)
+	assert.Len(t, stats, 2)
+	assert.Equal(t, "/", stats[0].Stats.Path)
+	assert.Equal(t, 10, stats[0].Previous.Visitors)
+	assert.InDelta(t, 1, stats[0].Growth["visitors"], 0.001)
+	assert.InDelta(t, 0, stats[0].Growth["views"], 0.001)
+	assert.InDelta(t, 1, stats[0].Growth["bounce_rate"], 0.001)
+	assert.Equal(t, "/new", stats[1].Stats.Path)
+	assert.Zero(t, stats[1].Previous.Visitors)
+	assert.InDelta(t, 1, stats[1].Growth["visitors"], 0.001)
+	assert.NotContains(t, stats[0].Growth, "path")
+
+	days := func(filter *Filter) ([]model.VisitorStats, error) {
+		if filter.From.Equal(from) {
+			return []model.VisitorStats{
+				{Day: null.NewTime(from, true), Visitors: 4},
+				{Day: null.NewTime(from.Add(time.Hour*24), true), Visitors: 2},
+			}, nil
+		}
+
+		return []model.VisitorStats{
+			{Day: null.NewTime(filter.From, true), Visitors: 2},
+		}, nil
+	}
+	series, err := Compare(&Filter{From: from, To: to}, days)
+	assert.NoError(t, err)
+	assert.Len(t, series, 2)
+	assert.Equal(t, 2, series[0].Previous.Visitors)
+	assert.InDelta(t, 1, series[0].Growth["visitors"], 0.001)
+	assert.Zero(t, series[1].Previous.Visitors)
+
+	hours := func(filter *Filter) ([]model.VisitorHourStats, error) {
+		if filter.From.Equal(from) {
+			return []model.VisitorHourStats{{Hour: 1, Visitors: 3}, {Hour: 2, Visitors: 6}}, nil
+		}
+
+		return []model.VisitorHourStats{{Hour: 2, Visitors: 3}}, nil
+	}
+	byHour, err := Compare(&Filter{From: from, To: to}, hours)
+	assert.NoError(t, err)
+	assert.Zero(t, byHour[0].Previous.Visitors)
+	assert.Equal(t, 3, byHour[1].Previous.Visitors)
+	assert.NotContains(t, byHour[1].Growth, "hour")
+
+	_, err = Compare(&Filter{}, pages)
+	assert.ErrorIs(t, err, ErrNoPeriodOrDay)
+	_, err = Compare(&Filter{From: from, To: to, Compare: CompareCustom}, pages)
+	assert.ErrorIs(t, err, ErrNoComparisonPeriod)
+}
+
+func TestCompareTotal(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC)
+	total := func(filter *Filter) (*model.TotalVisitorStats, error) {
+		if filter.From.Equal(from) {
+			return &model.TotalVisitorStats{Visitors: 30, Sessions: 40, CR: 0.1}, nil
+		}
+
+		return &model.TotalVisitorStats{Visitors: 60, Sessions: 40, CR: 0.2}, nil
+	}
+	stats, err := CompareTotal(&Filter{
+		From:        from,
+		To:          to,
+		Compare:     CompareCustom,
+		CompareFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		CompareTo:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+	}, total)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, stats.Stats.Visitors)
+	assert.Equal(t, 60, stats.Previous.Visitors)
+	assert.InDelta(t, -0.5, stats.Growth["visitors"], 0.001)
+	assert.InDelta(t, 0, stats.Growth["sessions"], 0.001)
+	assert.InDelta(t, -0.5, stats.Growth["cr"], 0.001)
+}
@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+)
+
+var (
+	// ErrInvalidContentGroup is returned in case a content group has no name or doesn't set exactly one of path prefix, path pattern, or tag.
+	ErrInvalidContentGroup = errors.New("content group requires a name and exactly one of path prefix, path pattern, or tag key and value")
+)
+
+// ContentGroup maps pages to a named group, like all pages starting with /blog to "Blog".
+// Exactly one of PathPrefix, PathPattern, or TagKey and TagValue must be set.
+type ContentGroup struct {
+	Name string
+
+	// PathPrefix matches all paths starting with the prefix.
+	PathPrefix string
+
+	// PathPattern matches all paths matching the regular expression.
+	PathPattern string
+
+	// TagKey and TagValue match all page views with the tag.
+	TagKey   string
+	TagValue string
+}
+
+// ContentGroups manages the content groups for each client used by Pages.ContentGroups.
+type ContentGroups struct {
+	store db.Store
+}
+
+// Set replaces the content groups for given client.
+// Pages are assigned to the first matching group, so the order matters. Pass an empty list to remove all groups.
+func (contentGroups *ContentGroups) Set(ctx context.Context, clientID uint64, groups []ContentGroup) error {
+	list := make([]model.ContentGroup, 0, len(groups))
+
+	for i := range groups {
+		if err := groups[i].validate(); err != nil {
+			return err
+		}
+
+		list = append(list, model.ContentGroup(groups[i]))
+	}
+
+	return contentGroups.store.SaveContentGroups(ctx, []model.ContentGroups{
+		{
+			ClientID: clientID,
+			Groups:   list,
+			Version:  time.Now().UTC(),
+		},
+	})
+}
+
+// Get returns the content groups for given client in the order they are matched.
+func (contentGroups *ContentGroups) Get(ctx context.Context, clientID uint64) ([]ContentGroup, error) {
+	query := `SELECT client_id, names, path_prefixes, path_patterns, tag_keys, tag_values, version
+		FROM "content_group" FINAL
+		WHERE client_id = ?`
+	list, err := contentGroups.store.SelectContentGroups(ctx, query, clientID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	groups := make([]ContentGroup, 0, len(list[0].Groups))
+
+	for _, group := range list[0].Groups {
+		groups = append(groups, ContentGroup(group))
+	}
+
+	return groups, nil
+}
+
+func (group *ContentGroup) validate() error {
+	conditions := 0
+
+	if group.PathPrefix != "" {
+		conditions++
+	}
+
+	if group.PathPattern != "" {
+		if _, err := regexp.Compile(group.PathPattern); err != nil {
+			return ErrInvalidContentGroup
+		}
+
+		conditions++
+	}
+
+	if group.TagKey != "" || group.TagValue != "" {
+		if group.TagKey == "" || group.TagValue == "" {
+			return ErrInvalidContentGroup
+		}
+
+		conditions++
+	}
+
+	if strings.TrimSpace(group.Name) == "" || conditions != 1 {
+		return ErrInvalidContentGroup
+	}
+
+	return nil
+}
+
+// pathGroup is the query selecting the content group or directory for a page view (FieldPathGroup),
+// and the optional condition to drill down into a single group.
+type pathGroup struct {
+	query     string
+	args      []any
+	where     string
+	whereArgs []any
+}
+
+// contentGroupQuery returns the query selecting the name of the first matching content group or an empty string.
+func contentGroupQuery(groups []ContentGroup) (string, []any) {
+	if len(groups) == 0 {
+		return "''", nil
+	}
+
+	var q strings.Builder
+	args := make([]any, 0, len(groups)*3)
+	q.WriteString("multiIf(")
+
+	for _, group := range groups {
+		if group.PathPrefix != "" {
+			q.WriteString("startsWith(path, ?), ?, ")
+			args = append(args, group.PathPrefix, group.Name)
+		} else if group.PathPattern != "" {
+			q.WriteString("match(path, ?), ?, ")
+			args = append(args, group.PathPattern, group.Name)
+		} else {
+			q.WriteString("tag_values[indexOf(tag_keys, ?)] = ?, ?, ")
+			args = append(args, group.TagKey, group.TagValue, group.Name)
+		}
+	}
+
+	q.WriteString("'')")
+	return q.String(), args
+}
+
+// directoryQuery returns the query selecting the directory of the path at given depth, like /blog for /blog/2025/post at depth 1.
+// Trailing slashes are removed, so that /blog/ and /blog are the same directory. Paths with less segments than the depth are returned as is.
+func directoryQuery(depth int) (string, []any) {
+	return `arrayStringConcat(arraySlice(splitByChar('/', replaceRegexpOne(path, '(.)/+$', '\\1')), 1, ?), '/')`, []any{depth + 1}
+}
@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentGroups(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	ctx := context.Background()
+	groups := NewAnalyzer(dbClient).ContentGroups
+	assert.ErrorIs(t, groups.Set(ctx, 1, []ContentGroup{{PathPrefix: "/blog"}}), ErrInvalidContentGroup)
+	assert.ErrorIs(t, groups.Set(ctx, 1, []ContentGroup{{Name: "Blog"}}), ErrInvalidContentGroup)
+	assert.ErrorIs(t, groups.Set(ctx, 1, []ContentGroup{{Name: "Blog", PathPrefix: "/blog", PathPattern: "^/blog"}}), ErrInvalidContentGroup)
+	assert.ErrorIs(t, groups.Set(ctx, 1, []ContentGroup{{Name: "Blog", PathPattern: "(/blog"}}), ErrInvalidContentGroup)
+	assert.ErrorIs(t, groups.Set(ctx, 1, []ContentGroup{{Name: "Blog", TagKey: "type"}}), ErrInvalidContentGroup)
+	assert.NoError(t, groups.Set(ctx, 1, []ContentGroup{
+		{Name: "Blog", PathPrefix: "/blog"},
+		{Name: "Docs", PathPattern: "^/(docs|help)/"},
+		{Name: "Products", TagKey: "type", TagValue: "product"},
+	}))
+	time.Sleep(time.Millisecond * 100)
+	list, err := groups.Get(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []ContentGroup{
+		{Name: "Blog", PathPrefix: "/blog"},
+		{Name: "Docs", PathPattern: "^/(docs|help)/"},
+		{Name: "Products", TagKey: "type", TagValue: "product"},
+	}, list)
+	list, err = groups.Get(ctx, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+	assert.NoError(t, groups.Set(ctx, 1, nil))
+	time.Sleep(time.Millisecond * 100)
+	list, err = groups.Get(ctx, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestContentGroupQuery(t *testing.T) {
+	query, args := contentGroupQuery(nil)
+	assert.Equal(t, "''", query)
+	assert.Empty(t, args)
+	query, args = contentGroupQuery([]ContentGroup{
+		{Name: "Blog", PathPrefix: "/blog"},
+		{Name: "Docs", PathPattern: "^/docs/"},
+		{Name: "Products", TagKey: "type", TagValue: "product"},
+	})
+	assert.Equal(t, "multiIf(startsWith(path, ?), ?, match(path, ?), ?, tag_values[indexOf(tag_keys, ?)] = ?, ?, '')", query)
+	assert.Equal(t, []any{"/blog", "Blog", "^/docs/", "Docs", "type", "product", "Products"}, args)
+}
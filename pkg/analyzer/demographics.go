@@ -11,26 +11,110 @@ type Demographics struct {
 	store    db.Store
 }
 
-// Languages return the visitor count grouped by language.
+// Languages return the visitor count and engagement grouped by language.
 func (demographics *Demographics) Languages(filter *Filter) ([]model.LanguageStats, error) {
-	ctx, q, args := demographics.analyzer.selectByAttribute(filter, "imported_language", FieldLanguage)
-	return demographics.store.SelectLanguageStats(ctx, q, args...)
+	stats, _, err := demographics.languages(filter)
+	return stats, err
 }
 
-// Countries return the visitor count grouped by country.
+// LanguagesPage returns a page of Demographics.Languages together with the total number of rows.
+func (demographics *Demographics) LanguagesPage(filter *Filter) (*model.PaginatedStats[model.LanguageStats], error) {
+	return paginate(demographics.languages(filter))
+}
+
+func (demographics *Demographics) languages(filter *Filter) ([]model.LanguageStats, *rowCount, error) {
+	ctx, q, args, count, err := demographics.analyzer.selectByAttribute(filter, "imported_language", FieldLanguage)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := demographics.store.SelectLanguageStats(ctx, q, args...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, count, nil
+}
+
+// Countries return the visitor count and engagement grouped by country.
 func (demographics *Demographics) Countries(filter *Filter) ([]model.CountryStats, error) {
-	ctx, q, args := demographics.analyzer.selectByAttribute(filter, "imported_country", FieldCountry)
-	return demographics.store.SelectCountryStats(ctx, q, args...)
+	stats, _, err := demographics.countries(filter)
+	return stats, err
+}
+
+// CountriesPage returns a page of Demographics.Countries together with the total number of rows.
+func (demographics *Demographics) CountriesPage(filter *Filter) (*model.PaginatedStats[model.CountryStats], error) {
+	return paginate(demographics.countries(filter))
+}
+
+func (demographics *Demographics) countries(filter *Filter) ([]model.CountryStats, *rowCount, error) {
+	ctx, q, args, count, err := demographics.analyzer.selectByAttribute(filter, "imported_country", FieldCountry)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := demographics.store.SelectCountryStats(ctx, q, args...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, count, nil
 }
 
-// Regions return the visitor count grouped by region.
+// Regions return the visitor count and engagement grouped by region.
 func (demographics *Demographics) Regions(filter *Filter) ([]model.RegionStats, error) {
-	ctx, q, args := demographics.analyzer.selectByAttribute(filter, "imported_region", FieldRegion, FieldCountryRegion)
-	return demographics.store.SelectRegionStats(ctx, q, args...)
+	stats, _, err := demographics.regions(filter)
+	return stats, err
 }
 
-// Cities return the visitor count grouped by city.
+// RegionsPage returns a page of Demographics.Regions together with the total number of rows.
+func (demographics *Demographics) RegionsPage(filter *Filter) (*model.PaginatedStats[model.RegionStats], error) {
+	return paginate(demographics.regions(filter))
+}
+
+func (demographics *Demographics) regions(filter *Filter) ([]model.RegionStats, *rowCount, error) {
+	ctx, q, args, count, err := demographics.analyzer.selectByAttribute(filter, "imported_region", FieldRegion, FieldCountryRegion)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := demographics.store.SelectRegionStats(ctx, q, args...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, count, nil
+}
+
+// Cities return the visitor count and engagement grouped by city.
 func (demographics *Demographics) Cities(filter *Filter) ([]model.CityStats, error) {
-	ctx, q, args := demographics.analyzer.selectByAttribute(filter, "imported_city", FieldCity, FieldRegionCity, FieldCountryCity)
-	return demographics.store.SelectCityStats(ctx, q, args...)
+	stats, _, err := demographics.cities(filter)
+	return stats, err
+}
+
+// CitiesPage returns a page of Demographics.Cities together with the total number of rows.
+func (demographics *Demographics) CitiesPage(filter *Filter) (*model.PaginatedStats[model.CityStats], error) {
+	return paginate(demographics.cities(filter))
+}
+
+func (demographics *Demographics) cities(filter *Filter) ([]model.CityStats, *rowCount, error) {
+	ctx, q, args, count, err := demographics.analyzer.selectByAttribute(filter, "imported_city", FieldCity, FieldRegionCity, FieldCountryCity)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := demographics.store.SelectCityStats(ctx, q, args...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, count, nil
 }
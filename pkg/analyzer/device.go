@@ -1,6 +1,9 @@
 package analyzer
 
 import (
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
 )
@@ -11,9 +14,16 @@ type Device struct {
 	store    db.Store
 }
 
-// Platform returns the visitor count grouped by platform.
+// Platform returns the visitor count and engagement grouped by platform.
+// Imported statistics don't contain the platform of sessions and are therefore not included in the engagement.
 func (device *Device) Platform(filter *Filter) (*model.PlatformStats, error) {
-	filter = device.analyzer.getFilter(filter)
+	filter, err := device.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	engagementFilter := *filter
 
 	if !filter.ImportedUntil.IsZero() && (filter.From.Before(filter.ImportedUntil) || filter.From.Equal(filter.ImportedUntil)) {
 		filter.Path = nil
@@ -44,73 +54,216 @@ func (device *Device) Platform(filter *Filter) (*model.PlatformStats, error) {
 		return nil, err
 	}
 
+	desktop, err := device.platformEngagement(&engagementFilter, pkg.PlatformDesktop)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mobile, err := device.platformEngagement(&engagementFilter, pkg.PlatformMobile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	unknown, err := device.platformEngagement(&engagementFilter, pkg.PlatformUnknown)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stats.EngagedSessionsDesktop = desktop.EngagedSessions
+	stats.EngagedSessionsMobile = mobile.EngagedSessions
+	stats.EngagedSessionsUnknown = unknown.EngagedSessions
+	stats.EngagementRateDesktop = desktop.EngagementRate
+	stats.EngagementRateMobile = mobile.EngagementRate
+	stats.EngagementRateUnknown = unknown.EngagementRate
+	stats.PagesPerSessionDesktop = desktop.PagesPerSession
+	stats.PagesPerSessionMobile = mobile.PagesPerSession
+	stats.PagesPerSessionUnknown = unknown.PagesPerSession
 	return stats, nil
 }
 
-// Browser returns the visitor count grouped by browser.
+func (device *Device) platformEngagement(filter *Filter, platform string) (*model.TotalVisitorStats, error) {
+	filterCopy := *filter
+	filterCopy.Platform = platform
+	filterCopy.ImportedUntil = time.Time{}
+	q, args := filterCopy.buildQuery([]Field{
+		FieldVisitors,
+		FieldSessions,
+		FieldViews,
+		FieldBounces,
+		FieldBounceRate,
+		FieldEngagedSessions,
+		FieldEngagementRate,
+		FieldPagesPerSession,
+	}, nil, nil, nil, "")
+	return device.store.GetTotalVisitorStats(filterCopy.Ctx, q, false, false, args...)
+}
+
+// Browser returns the visitor count and engagement grouped by browser.
 func (device *Device) Browser(filter *Filter) ([]model.BrowserStats, error) {
-	ctx, q, args := device.analyzer.selectByAttribute(filter, "imported_browser", FieldBrowser)
-	return device.store.SelectBrowserStats(ctx, q, args...)
+	stats, _, err := device.browser(filter)
+	return stats, err
 }
 
-// OS returns the visitor count grouped by operating system.
+// BrowserPage returns a page of Device.Browser together with the total number of rows.
+func (device *Device) BrowserPage(filter *Filter) (*model.PaginatedStats[model.BrowserStats], error) {
+	return paginate(device.browser(filter))
+}
+
+func (device *Device) browser(filter *Filter) ([]model.BrowserStats, *rowCount, error) {
+	ctx, q, args, count, err := device.analyzer.selectByAttribute(filter, "imported_browser", FieldBrowser)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := device.store.SelectBrowserStats(ctx, q, args...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, count, nil
+}
+
+// OS returns the visitor count and engagement grouped by operating system.
 func (device *Device) OS(filter *Filter) ([]model.OSStats, error) {
-	ctx, q, args := device.analyzer.selectByAttribute(filter, "imported_os", FieldOS)
-	return device.store.SelectOSStats(ctx, q, args...)
+	stats, _, err := device.os(filter)
+	return stats, err
+}
+
+// OSPage returns a page of Device.OS together with the total number of rows.
+func (device *Device) OSPage(filter *Filter) (*model.PaginatedStats[model.OSStats], error) {
+	return paginate(device.os(filter))
+}
+
+func (device *Device) os(filter *Filter) ([]model.OSStats, *rowCount, error) {
+	ctx, q, args, count, err := device.analyzer.selectByAttribute(filter, "imported_os", FieldOS)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := device.store.SelectOSStats(ctx, q, args...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, count, nil
 }
 
-// OSVersion returns the visitor count grouped by operating systems and version.
+// OSVersion returns the visitor count and engagement grouped by operating systems and version.
 func (device *Device) OSVersion(filter *Filter) ([]model.OSVersionStats, error) {
-	filter = device.analyzer.getFilter(filter)
-	q, args := filter.buildQuery([]Field{
+	stats, _, err := device.osVersion(filter)
+	return stats, err
+}
+
+// OSVersionPage returns a page of Device.OSVersion together with the total number of rows.
+func (device *Device) OSVersionPage(filter *Filter) (*model.PaginatedStats[model.OSVersionStats], error) {
+	return paginate(device.osVersion(filter))
+}
+
+func (device *Device) osVersion(filter *Filter) ([]model.OSVersionStats, *rowCount, error) {
+	filter, err := device.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := append([]Field{
 		FieldOS,
 		FieldOSVersion,
-		FieldVisitors,
-		FieldRelativeVisitors,
-	}, []Field{
+	}, metaFields...)
+	q, args, count := filter.buildListQuery(device.store, fields, []Field{
 		FieldOS,
 		FieldOSVersion,
 	}, []Field{
 		FieldVisitors,
 		FieldOS,
 		FieldOSVersion,
-	}, nil, "")
+	}, []Field{
+		FieldOS,
+		FieldOSVersion,
+		FieldVisitors,
+	}, "imported_os_version")
 	stats, err := device.store.SelectOSVersionStats(filter.Ctx, q, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return stats, nil
+	return stats, count, nil
 }
 
-// BrowserVersion returns the visitor count grouped by browser and version.
+// BrowserVersion returns the visitor count and engagement grouped by browser and version.
 func (device *Device) BrowserVersion(filter *Filter) ([]model.BrowserVersionStats, error) {
-	filter = device.analyzer.getFilter(filter)
-	q, args := filter.buildQuery([]Field{
+	stats, _, err := device.browserVersion(filter)
+	return stats, err
+}
+
+// BrowserVersionPage returns a page of Device.BrowserVersion together with the total number of rows.
+func (device *Device) BrowserVersionPage(filter *Filter) (*model.PaginatedStats[model.BrowserVersionStats], error) {
+	return paginate(device.browserVersion(filter))
+}
+
+func (device *Device) browserVersion(filter *Filter) ([]model.BrowserVersionStats, *rowCount, error) {
+	filter, err := device.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := append([]Field{
 		FieldBrowser,
 		FieldBrowserVersion,
-		FieldVisitors,
-		FieldRelativeVisitors,
-	}, []Field{
+	}, metaFields...)
+	q, args, count := filter.buildListQuery(device.store, fields, []Field{
 		FieldBrowser,
 		FieldBrowserVersion,
 	}, []Field{
 		FieldVisitors,
 		FieldBrowser,
 		FieldBrowserVersion,
-	}, nil, "")
+	}, []Field{
+		FieldBrowser,
+		FieldBrowserVersion,
+		FieldVisitors,
+	}, "imported_browser_version")
 	stats, err := device.store.SelectBrowserVersionStats(filter.Ctx, q, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return stats, nil
+	return stats, count, nil
 }
 
-// ScreenClass returns the visitor count grouped by screen class.
+// ScreenClass returns the visitor count and engagement grouped by screen class.
 func (device *Device) ScreenClass(filter *Filter) ([]model.ScreenClassStats, error) {
-	ctx, q, args := device.analyzer.selectByAttribute(filter, "", FieldScreenClass)
-	return device.store.SelectScreenClassStats(ctx, q, args...)
+	stats, _, err := device.screenClass(filter)
+	return stats, err
+}
+
+// ScreenClassPage returns a page of Device.ScreenClass together with the total number of rows.
+func (device *Device) ScreenClassPage(filter *Filter) (*model.PaginatedStats[model.ScreenClassStats], error) {
+	return paginate(device.screenClass(filter))
+}
+
+func (device *Device) screenClass(filter *Filter) ([]model.ScreenClassStats, *rowCount, error) {
+	ctx, q, args, count, err := device.analyzer.selectByAttribute(filter, "", FieldScreenClass)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := device.store.SelectScreenClassStats(ctx, q, args...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, count, nil
 }
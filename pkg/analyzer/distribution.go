@@ -0,0 +1,246 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/emvi/null"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+)
+
+const (
+	// MetricSessionDuration is the duration of a session in seconds. Bounced sessions without a duration are ignored.
+	MetricSessionDuration = Metric("session_duration")
+
+	// MetricTimeOnPage is the time on a page in seconds. Page views without a time on the page are ignored.
+	MetricTimeOnPage = Metric("time_on_page")
+
+	// MetricPageViews is the number of page views per session.
+	MetricPageViews = Metric("page_views")
+
+	// MetricCustomMetric is the event metadata field set by Filter.CustomMetricKey and Filter.CustomMetricType.
+	MetricCustomMetric = Metric("custom_metric")
+)
+
+var (
+	// ErrUnsupportedMetric is returned in case the distribution or breakdown for an unknown metric is requested.
+	ErrUnsupportedMetric = errors.New("unsupported metric")
+
+	// ErrNoCustomMetric is returned in case MetricCustomMetric is used without setting the event name, custom metric key, and type.
+	ErrNoCustomMetric = errors.New("no event name, custom metric key, or custom metric type specified")
+
+	// ErrNoHistogramBuckets is returned in case a histogram is requested without buckets for a metric that has no default buckets.
+	ErrNoHistogramBuckets = errors.New("no histogram buckets specified")
+
+	// defaultHistogramBuckets are the lower bounds of the histogram buckets used if none are passed.
+	defaultHistogramBuckets = map[Metric][]float64{
+		MetricSessionDuration: {0, 10, 30, 60, 180, 600, 1800},
+		MetricTimeOnPage:      {0, 10, 30, 60, 180, 600, 1800},
+		MetricPageViews:       {1, 2, 3, 4, 5, 6, 11, 21},
+	}
+)
+
+// Metric is a value that can be aggregated into a distribution.
+type Metric string
+
+// Distribution aggregates percentiles and histograms.
+type Distribution struct {
+	analyzer *Analyzer
+	store    db.Store
+}
+
+// Percentiles returns the count, minimum, maximum, average, median, 75th, 90th, and 95th percentile for a metric.
+func (distribution *Distribution) Percentiles(filter *Filter, metric Metric) (*model.PercentileStats, error) {
+	filter, err := distribution.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	values, args, err := distribution.valuesQuery(filter, metric)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`WITH distribution_values AS ( %s ),
+		quantiles(0.5, 0.75, 0.9, 0.95)(value) AS q
+		SELECT count(*) count,
+		min(value) min,
+		max(value) max,
+		ifNotFinite(avg(value), 0) average,
+		ifNotFinite(q[1], 0) median,
+		ifNotFinite(q[2], 0) p75,
+		ifNotFinite(q[3], 0) p90,
+		ifNotFinite(q[4], 0) p95
+		FROM distribution_values`, values)
+	stats, err := distribution.store.GetPercentileStats(filter.Ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// Histogram returns the number of values and visitors for a metric grouped into buckets.
+// Buckets are the lower bounds of each bucket in ascending order, the last bucket is open-ended.
+// Values below the first bound are counted in the first bucket.
+// Default buckets are used in case none are passed, except for MetricCustomMetric.
+func (distribution *Distribution) Histogram(filter *Filter, metric Metric, buckets []float64) ([]model.HistogramBucket, error) {
+	if len(buckets) == 0 {
+		buckets = defaultHistogramBuckets[metric]
+
+		if len(buckets) == 0 && metric == MetricCustomMetric {
+			return nil, ErrNoHistogramBuckets
+		}
+	}
+
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	buckets = slices.Compact(buckets)
+	filter, err := distribution.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	values, args, err := distribution.valuesQuery(filter, metric)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := make([]string, 0, len(buckets))
+
+	for _, bucket := range buckets {
+		bounds = append(bounds, fmt.Sprint(bucket))
+	}
+
+	query := fmt.Sprintf(`WITH distribution_values AS ( %s ),
+		[%s] AS bounds
+		SELECT toFloat64(bounds[greatest(arrayCount(b -> b <= value, bounds), 1)]) bucket,
+		count(*) count,
+		uniq(visitor_id) visitors,
+		count / (SELECT count(*) FROM distribution_values) relative_count
+		FROM distribution_values
+		GROUP BY bucket
+		ORDER BY bucket`, values, strings.Join(bounds, ","))
+	stats, err := distribution.store.SelectHistogramBuckets(filter.Ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]model.HistogramBucket, 0, len(buckets))
+
+	for i, bucket := range buckets {
+		result := model.HistogramBucket{From: bucket}
+
+		if i < len(buckets)-1 {
+			result.To = null.NewFloat64(buckets[i+1], true)
+		}
+
+		for _, s := range stats {
+			if s.From == bucket {
+				result.Count = s.Count
+				result.Visitors = s.Visitors
+				result.RelativeCount = s.RelativeCount
+				break
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// valuesQuery returns a query selecting the visitor_id and value for each session, page view, or event of a metric.
+func (distribution *Distribution) valuesQuery(filter *Filter, metric Metric) (string, []any, error) {
+	if metric == MetricCustomMetric && (len(filter.EventName) == 0 || filter.CustomMetricKey == "" || filter.CustomMetricType == "") {
+		return "", nil, ErrNoCustomMetric
+	}
+
+	matching, args := filter.buildSessionsQuery()
+	var query string
+
+	switch metric {
+	case MetricSessionDuration, MetricPageViews:
+		timeQuery, timeArgs := filter.buildTimeQuery()
+		args = append(args, timeArgs...)
+		value := "sum(duration_seconds*sign)/sum(sign)"
+		having := "AND value > 0"
+
+		if metric == MetricPageViews {
+			value = "sum(page_views*sign)"
+			having = ""
+		}
+
+		query = fmt.Sprintf(`SELECT visitor_id, toFloat64(%s) value
+			FROM "session"
+			%s
+			AND (visitor_id, session_id) IN (SELECT visitor_id, session_id FROM matching)
+			GROUP BY visitor_id, session_id
+			HAVING sum(sign) > 0 %s`, value, timeQuery, having)
+	case MetricTimeOnPage:
+		q := queryBuilder{
+			filter:     filter,
+			from:       pageViews,
+			search:     filter.Search,
+			timeOnPage: true,
+		}
+		fields := slices.DeleteFunc(q.getFields(), func(field string) bool {
+			return field == FieldVisitorID.Name // already selected
+		})
+		filterFields := strings.Join(fields, ",")
+
+		if filterFields != "" {
+			filterFields = "," + filterFields
+		}
+
+		timeQuery := q.whereTime()
+		q.whereFields()
+		args = append(args, q.args...)
+		query = fmt.Sprintf(`SELECT visitor_id, toFloat64(time_on_page) value
+			FROM (
+				SELECT visitor_id,
+				nth_value(%s, 2) OVER (PARTITION BY visitor_id, session_id ORDER BY "time" ASC Rows BETWEEN CURRENT ROW AND 1 FOLLOWING) AS time_on_page
+				%s
+				FROM "page_view"
+				%s
+				AND (visitor_id, session_id) IN (SELECT visitor_id, session_id FROM matching)
+			)
+			WHERE time_on_page > 0 %s`, distribution.analyzer.timeOnPageQuery(filter), filterFields, timeQuery, q.q.String())
+	case MetricCustomMetric:
+		q := queryBuilder{
+			filter: &Filter{
+				EventName:        filter.EventName,
+				EventMeta:        filter.EventMeta,
+				EventMetaRange:   filter.EventMetaRange,
+				CustomMetricType: filter.CustomMetricType,
+			},
+			from: events,
+		}
+		q.whereFields()
+		timeQuery, timeArgs := filter.buildTimeQuery()
+		args = append(args, filter.CustomMetricKey)
+		args = append(args, timeArgs...)
+		args = append(args, filter.CustomMetricKey)
+		args = append(args, q.args...)
+		query = fmt.Sprintf(`SELECT visitor_id, toFloat64(%s(event_meta_values[indexOf(event_meta_keys, ?)])) value
+			FROM "event"
+			%s
+			AND has(event_meta_keys, ?)
+			%s
+			AND (visitor_id, session_id) IN (SELECT visitor_id, session_id FROM matching)`,
+			filter.CustomMetricType, timeQuery, q.q.String())
+	default:
+		return "", nil, ErrUnsupportedMetric
+	}
+
+	return fmt.Sprintf("WITH matching AS ( %s ) %s", matching, query), args, nil
+}
@@ -0,0 +1,162 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistribution_Percentiles(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	saveDistributionTestData(t)
+	analyzer := NewAnalyzer(dbClient)
+	stats, err := analyzer.Distribution.Percentiles(nil, MetricSessionDuration)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, stats.Count) // bounced sessions are ignored
+	assert.InDelta(t, 2, stats.Min, 0.001)
+	assert.InDelta(t, 35, stats.Max, 0.001)
+	assert.InDelta(t, 17.5, stats.Average, 0.001)
+	assert.InDelta(t, 16.5, stats.Median, 0.001)
+	stats, err = analyzer.Distribution.Percentiles(&Filter{Path: []string{"/foo"}}, MetricSessionDuration)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stats.Count)
+	assert.InDelta(t, 5, stats.Min, 0.001)
+	stats, err = analyzer.Distribution.Percentiles(nil, MetricPageViews)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, stats.Count)
+	assert.InDelta(t, 1, stats.Min, 0.001)
+	assert.InDelta(t, 3, stats.Max, 0.001)
+	assert.InDelta(t, 2, stats.Median, 0.001)
+	stats, err = analyzer.Distribution.Percentiles(nil, MetricTimeOnPage)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, stats.Count)
+	assert.InDelta(t, 2, stats.Min, 0.001)
+	assert.InDelta(t, 35, stats.Max, 0.001)
+	assert.InDelta(t, 5, stats.Median, 0.001)
+	stats, err = analyzer.Distribution.Percentiles(&Filter{Path: []string{"/foo"}}, MetricTimeOnPage)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.Count)
+	assert.InDelta(t, 3, stats.Max, 0.001)
+	stats, err = analyzer.Distribution.Percentiles(&Filter{
+		EventName:        []string{"Sale"},
+		CustomMetricKey:  "amount",
+		CustomMetricType: pkg.CustomMetricTypeInteger,
+	}, MetricCustomMetric)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stats.Count)
+	assert.InDelta(t, 177, stats.Min, 0.001)
+	assert.InDelta(t, 312, stats.Max, 0.001)
+	assert.InDelta(t, 226, stats.Average, 0.001)
+	assert.InDelta(t, 189, stats.Median, 0.001)
+	stats, err = analyzer.Distribution.Percentiles(&Filter{
+		EventName:        []string{"Sale"},
+		EventMeta:        map[string]string{"currency": "EUR"},
+		CustomMetricKey:  "amount",
+		CustomMetricType: pkg.CustomMetricTypeInteger,
+	}, MetricCustomMetric)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.Count)
+	assert.InDelta(t, 250.5, stats.Average, 0.001)
+	_, err = analyzer.Distribution.Percentiles(nil, MetricCustomMetric)
+	assert.ErrorIs(t, err, ErrNoCustomMetric)
+	_, err = analyzer.Distribution.Percentiles(nil, "foo")
+	assert.ErrorIs(t, err, ErrUnsupportedMetric)
+
+	for _, metric := range []Metric{MetricSessionDuration, MetricTimeOnPage, MetricPageViews} {
+		_, err = analyzer.Distribution.Percentiles(getMaxFilter(""), metric)
+		assert.NoError(t, err)
+		_, err = analyzer.Distribution.Percentiles(getMaxFilter("event"), metric)
+		assert.NoError(t, err)
+	}
+}
+
+func TestDistribution_Histogram(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	saveDistributionTestData(t)
+	analyzer := NewAnalyzer(dbClient)
+	buckets, err := analyzer.Distribution.Histogram(nil, MetricSessionDuration, nil)
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 7)
+	assert.InDelta(t, 0, buckets[0].From, 0.001)
+	assert.InDelta(t, 10, buckets[0].To.Float64, 0.001)
+	assert.Equal(t, 2, buckets[0].Count)
+	assert.Equal(t, 2, buckets[0].Visitors)
+	assert.InDelta(t, 0.5, buckets[0].RelativeCount, 0.001)
+	assert.Equal(t, 1, buckets[1].Count)
+	assert.Equal(t, 1, buckets[2].Count)
+	assert.Zero(t, buckets[3].Count)
+	assert.InDelta(t, 1800, buckets[6].From, 0.001)
+	assert.False(t, buckets[6].To.Valid)
+	buckets, err = analyzer.Distribution.Histogram(nil, MetricPageViews, []float64{3, 1, 2, 2})
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 3)
+	assert.InDelta(t, 1, buckets[0].From, 0.001)
+	assert.Equal(t, 2, buckets[0].Count)
+	assert.InDelta(t, 2, buckets[1].From, 0.001)
+	assert.Equal(t, 3, buckets[1].Count)
+	assert.InDelta(t, 3, buckets[2].From, 0.001)
+	assert.Equal(t, 1, buckets[2].Count)
+	buckets, err = analyzer.Distribution.Histogram(&Filter{Path: []string{"/bar"}}, MetricPageViews, []float64{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 3)
+	assert.Zero(t, buckets[0].Count)
+	assert.Equal(t, 1, buckets[1].Count)
+	assert.Equal(t, 1, buckets[2].Count)
+	buckets, err = analyzer.Distribution.Histogram(&Filter{
+		EventName:        []string{"Sale"},
+		CustomMetricKey:  "amount",
+		CustomMetricType: pkg.CustomMetricTypeInteger,
+	}, MetricCustomMetric, []float64{0, 200})
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 2)
+	assert.Equal(t, 2, buckets[0].Count)
+	assert.Equal(t, 1, buckets[1].Count)
+	_, err = analyzer.Distribution.Histogram(nil, MetricCustomMetric, nil)
+	assert.ErrorIs(t, err, ErrNoHistogramBuckets)
+	_, err = analyzer.Distribution.Histogram(nil, "foo", nil)
+	assert.ErrorIs(t, err, ErrUnsupportedMetric)
+
+	for _, metric := range []Metric{MetricSessionDuration, MetricTimeOnPage, MetricPageViews} {
+		_, err = analyzer.Distribution.Histogram(getMaxFilter(""), metric, nil)
+		assert.NoError(t, err)
+		_, err = analyzer.Distribution.Histogram(getMaxFilter("event"), metric, nil)
+		assert.NoError(t, err)
+	}
+}
+
+func saveDistributionTestData(t *testing.T) {
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, VisitorID: 1, Time: util.Today(), Start: time.Now(), SessionID: 1, DurationSeconds: 28, PageViews: 3},
+			{Sign: 1, VisitorID: 2, Time: util.Today(), Start: time.Now(), SessionID: 2, DurationSeconds: 5, PageViews: 2},
+			{Sign: 1, VisitorID: 3, Time: util.Today(), Start: time.Now(), SessionID: 3, DurationSeconds: 0, PageViews: 1},
+			{Sign: 1, VisitorID: 4, Time: util.Today(), Start: time.Now(), SessionID: 4, DurationSeconds: 35, PageViews: 2},
+			{Sign: 1, VisitorID: 5, Time: util.Today(), Start: time.Now(), SessionID: 5, DurationSeconds: 2, PageViews: 2},
+			{Sign: 1, VisitorID: 6, Time: util.Today(), Start: time.Now(), SessionID: 6, DurationSeconds: 0, PageViews: 1},
+		},
+	})
+	assert.NoError(t, dbClient.SavePageViews([]model.PageView{
+		{VisitorID: 1, SessionID: 1, Time: util.Today(), Path: "/"},
+		{VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Second * 25), Path: "/foo", DurationSeconds: 25},
+		{VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Second * 28), Path: "/bar", DurationSeconds: 3},
+		{VisitorID: 2, SessionID: 2, Time: util.Today(), Path: "/"},
+		{VisitorID: 2, SessionID: 2, Time: util.Today().Add(time.Second * 5), Path: "/foo", DurationSeconds: 5},
+		{VisitorID: 3, SessionID: 3, Time: util.Today(), Path: "/"},
+		{VisitorID: 4, SessionID: 4, Time: util.Today(), Path: "/"},
+		{VisitorID: 4, SessionID: 4, Time: util.Today().Add(time.Second * 35), Path: "/foo", DurationSeconds: 35},
+		{VisitorID: 5, SessionID: 5, Time: util.Today(), Path: "/"},
+		{VisitorID: 5, SessionID: 5, Time: util.Today().Add(time.Second * 2), Path: "/bar", DurationSeconds: 2},
+		{VisitorID: 6, SessionID: 6, Time: util.Today(), Path: "/"},
+	}))
+	assert.NoError(t, dbClient.SaveEvents([]model.Event{
+		{Name: "Sale", VisitorID: 1, SessionID: 1, Time: util.Today(), Path: "/bar", MetaKeys: []string{"amount", "currency"}, MetaValues: []string{"189", "EUR"}},
+		{Name: "Sale", VisitorID: 2, SessionID: 2, Time: util.Today(), Path: "/foo", MetaKeys: []string{"amount", "currency"}, MetaValues: []string{"312", "EUR"}},
+		{Name: "Sale", VisitorID: 4, SessionID: 4, Time: util.Today(), Path: "/foo", MetaKeys: []string{"amount", "currency"}, MetaValues: []string{"177", "USD"}},
+	}))
+	time.Sleep(time.Millisecond * 100)
+}
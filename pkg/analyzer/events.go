@@ -13,8 +13,23 @@ type Events struct {
 
 // Events return the visitor count, views, and conversion rate for custom events.
 func (events *Events) Events(filter *Filter) ([]model.EventStats, error) {
-	filter = events.analyzer.getFilter(filter)
-	q, args := filter.buildQuery([]Field{
+	stats, _, err := events.events(filter)
+	return stats, err
+}
+
+// EventsPage returns a page of Events.Events together with the total number of rows.
+func (events *Events) EventsPage(filter *Filter) (*model.PaginatedStats[model.EventStats], error) {
+	return paginate(events.events(filter))
+}
+
+func (events *Events) events(filter *Filter) ([]model.EventStats, *rowCount, error) {
+	filter, err := events.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q, args, count := filter.buildListQuery(events.store, []Field{
 		FieldEventName,
 		FieldCount,
 		FieldVisitors,
@@ -27,26 +42,45 @@ func (events *Events) Events(filter *Filter) ([]model.EventStats, error) {
 	}, []Field{
 		FieldVisitors,
 		FieldEventName,
-	}, nil, "")
+	}, []Field{
+		FieldEventName,
+		FieldVisitors,
+		FieldViews,
+		FieldCount,
+	}, "imported_event")
 	stats, err := events.store.SelectEventStats(filter.Ctx, false, q, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return stats, nil
+	return stats, count, nil
 }
 
 // Breakdown returns the visitor count, views, and conversion rate for a custom event grouping them by a meta-value for a given key.
 // The Filter.EventName and Filter.EventMetaKey must be set, or otherwise the result set will be empty.
 func (events *Events) Breakdown(filter *Filter) ([]model.EventStats, error) {
-	filter = events.analyzer.getFilter(filter)
+	stats, _, err := events.breakdown(filter)
+	return stats, err
+}
+
+// BreakdownPage returns a page of Events.Breakdown together with the total number of rows.
+func (events *Events) BreakdownPage(filter *Filter) (*model.PaginatedStats[model.EventStats], error) {
+	return paginate(events.breakdown(filter))
+}
+
+func (events *Events) breakdown(filter *Filter) ([]model.EventStats, *rowCount, error) {
+	filter, err := events.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
 
 	if len(filter.EventName) == 0 || len(filter.EventMetaKey) == 0 {
-		return []model.EventStats{}, nil
+		return []model.EventStats{}, &rowCount{filter: filter}, nil
 	}
 
-	q, args := filter.buildQuery([]Field{
+	q, args, count := filter.buildListQuery(events.store, []Field{
 		FieldEventName,
 		FieldCount,
 		FieldVisitors,
@@ -64,16 +98,31 @@ func (events *Events) Breakdown(filter *Filter) ([]model.EventStats, error) {
 	stats, err := events.store.SelectEventStats(filter.Ctx, true, q, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return stats, nil
+	return stats, count, nil
 }
 
 // List returns events as a list. The metadata is grouped as key-value pairs.
 func (events *Events) List(filter *Filter) ([]model.EventListStats, error) {
-	filter = events.analyzer.getFilter(filter)
-	q, args := filter.buildQuery([]Field{
+	stats, _, err := events.list(filter)
+	return stats, err
+}
+
+// ListPage returns a page of Events.List together with the total number of rows.
+func (events *Events) ListPage(filter *Filter) (*model.PaginatedStats[model.EventListStats], error) {
+	return paginate(events.list(filter))
+}
+
+func (events *Events) list(filter *Filter) ([]model.EventListStats, *rowCount, error) {
+	filter, err := events.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q, args, count := filter.buildListQuery(events.store, []Field{
 		FieldEventName,
 		FieldEventMeta,
 		FieldVisitors,
@@ -88,8 +137,8 @@ func (events *Events) List(filter *Filter) ([]model.EventListStats, error) {
 	stats, err := events.store.SelectEventListStats(filter.Ctx, q, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return stats, nil
+	return stats, count, nil
 }
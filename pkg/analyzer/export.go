@@ -0,0 +1,325 @@
+package analyzer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+)
+
+const (
+	// ExportNDJSON exports one JSON object per line.
+	ExportNDJSON = ExportFormat("ndjson")
+
+	// ExportCSV exports comma separated values including a header.
+	ExportCSV = ExportFormat("csv")
+
+	// ExportParquet exports a single Parquet file.
+	ExportParquet = ExportFormat("parquet")
+
+	exportBatchSize         = 10_000
+	exportCSVTimeFormat     = "2006-01-02T15:04:05.000Z07:00"
+	exportPageViewColumns   = "client_id, visitor_id, session_id, time, duration_seconds, hostname, path, title, language, country_code, region, city, referrer, referrer_name, referrer_icon, os, os_version, browser, browser_version, desktop, mobile, screen_class, utm_source, utm_medium, utm_campaign, utm_content, utm_term, channel, tag_keys, tag_values"
+	exportSessionColumns    = "sign, version, client_id, visitor_id, session_id, time, start, duration_seconds, hostname, entry_path, exit_path, page_views, is_bounce, is_engaged, entry_title, exit_title, language, country_code, region, city, referrer, referrer_name, referrer_icon, os, os_version, browser, browser_version, desktop, mobile, screen_class, utm_source, utm_medium, utm_campaign, utm_content, utm_term, channel, extended"
+	exportEventColumns      = "client_id, visitor_id, time, session_id, event_name, event_meta_keys, event_meta_values, duration_seconds, hostname, path, title, language, country_code, region, city, referrer, referrer_name, referrer_icon, os, os_version, browser, browser_version, desktop, mobile, screen_class, utm_source, utm_medium, utm_campaign, utm_content, utm_term, channel"
+	exportCursorOrder       = "time, visitor_id, session_id"
+	exportCursorWhereClause = "AND (time, visitor_id, session_id) >= (fromUnixTimestamp64Milli(?, 'UTC'), ?, ?) "
+)
+
+// ErrUnknownExportFormat is returned if the export format is not supported.
+var ErrUnknownExportFormat = errors.New("unknown export format")
+
+// ExportFormat is the output format for raw data exports.
+type ExportFormat string
+
+// ExportCursor is the position of the last row written by an export.
+// Rows are exported in order of time, visitor ID, and session ID.
+// As these don't identify a row, rows sharing them are ordered by a hash of all columns,
+// and the export continues after the rows that have already been written.
+type ExportCursor struct {
+	Time      time.Time `json:"time"`
+	VisitorID uint64    `json:"visitor_id"`
+	SessionID uint32    `json:"session_id"`
+
+	// Duplicates is the number of rows written after the first one sharing the time, visitor ID, and session ID.
+	Duplicates int `json:"duplicates,omitempty"`
+}
+
+// Export streams raw page views, sessions, and events.
+type Export struct {
+	analyzer *Analyzer
+	store    db.Store
+}
+
+// PageViews writes all page views matching the filter to w.
+// Pass a cursor to resume a previous export or nil to start from the beginning.
+// The returned cursor points to the last row written, even if an error occurred.
+func (export *Export) PageViews(filter *Filter, w io.Writer, format ExportFormat, cursor *ExportCursor) (*ExportCursor, error) {
+	filter, err := export.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return exportRows(w, format, cursor, func(cursor *ExportCursor) ([]model.PageView, error) {
+		q, args := export.buildQuery(filter, pageViews, exportPageViewColumns, cursor)
+		return export.store.ExportPageViews(filter.Ctx, q, args...)
+	}, func(pageView *model.PageView) ExportCursor {
+		return ExportCursor{Time: pageView.Time, VisitorID: pageView.VisitorID, SessionID: pageView.SessionID}
+	})
+}
+
+// Sessions writes all sessions matching the filter to w.
+// Sessions are collapsed, so only the latest state of each session is exported.
+// Pass a cursor to resume a previous export or nil to start from the beginning.
+// The returned cursor points to the last row written, even if an error occurred.
+func (export *Export) Sessions(filter *Filter, w io.Writer, format ExportFormat, cursor *ExportCursor) (*ExportCursor, error) {
+	filter, err := export.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return exportRows(w, format, cursor, func(cursor *ExportCursor) ([]model.Session, error) {
+		q, args := export.buildQuery(filter, sessions, exportSessionColumns, cursor)
+		return export.store.ExportSessions(filter.Ctx, q, args...)
+	}, func(session *model.Session) ExportCursor {
+		return ExportCursor{Time: session.Time, VisitorID: session.VisitorID, SessionID: session.SessionID}
+	})
+}
+
+// Events writes all events matching the filter to w.
+// Pass a cursor to resume a previous export or nil to start from the beginning.
+// The returned cursor points to the last row written, even if an error occurred.
+func (export *Export) Events(filter *Filter, w io.Writer, format ExportFormat, cursor *ExportCursor) (*ExportCursor, error) {
+	filter, err := export.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return exportRows(w, format, cursor, func(cursor *ExportCursor) ([]model.Event, error) {
+		q, args := export.buildQuery(filter, events, exportEventColumns, cursor)
+		return export.store.ExportEvents(filter.Ctx, q, args...)
+	}, func(event *model.Event) ExportCursor {
+		return ExportCursor{Time: event.Time, VisitorID: event.VisitorID, SessionID: event.SessionID}
+	})
+}
+
+func (export *Export) buildQuery(filter *Filter, from table, columns string, cursor *ExportCursor) (string, []any) {
+	builder := queryBuilder{
+		filter: filter,
+		from:   from,
+		final:  true,
+	}
+	builder.q.WriteString(fmt.Sprintf("SELECT %s ", columns))
+	builder.fromTable()
+	builder.q.WriteString(builder.whereTime())
+	builder.whereFields()
+
+	if cursor != nil {
+		builder.q.WriteString(exportCursorWhereClause)
+		builder.args = append(builder.args, cursor.Time.UnixMilli(), cursor.VisitorID, cursor.SessionID)
+	}
+
+	builder.q.WriteString(fmt.Sprintf("ORDER BY %s, cityHash64(%s) LIMIT %d", exportCursorOrder, columns, exportBatchSize+cursor.skip()))
+	return builder.q.String(), builder.args
+}
+
+// skip returns the number of rows at the start of the next batch that have already been written.
+func (cursor *ExportCursor) skip() int {
+	if cursor == nil {
+		return 0
+	}
+
+	return cursor.Duplicates + 1
+}
+
+func (cursor *ExportCursor) equal(other *ExportCursor) bool {
+	return cursor != nil &&
+		cursor.Time.Equal(other.Time) &&
+		cursor.VisitorID == other.VisitorID &&
+		cursor.SessionID == other.SessionID
+}
+
+type exportWriter[T any] interface {
+	write([]T) error
+	close() error
+}
+
+func exportRows[T any](w io.Writer, format ExportFormat, cursor *ExportCursor, next func(*ExportCursor) ([]T, error), cursorOf func(*T) ExportCursor) (*ExportCursor, error) {
+	var writer exportWriter[T]
+
+	switch format {
+	case ExportNDJSON:
+		writer = &ndjsonWriter[T]{encoder: json.NewEncoder(w)}
+	case ExportCSV:
+		writer = &csvWriter[T]{writer: csv.NewWriter(w), header: cursor == nil}
+	case ExportParquet:
+		writer = &parquetWriter[T]{writer: parquet.NewGenericWriter[T](w)}
+	default:
+		return cursor, ErrUnknownExportFormat
+	}
+
+	if cursor != nil {
+		c := *cursor
+		cursor = &c
+	}
+
+	for {
+		limit := exportBatchSize + cursor.skip()
+		rows, err := next(cursor)
+
+		if err != nil {
+			return cursor, errors.Join(err, writer.close())
+		}
+
+		n := len(rows)
+		rows = skipExportedRows(rows, cursor, cursorOf)
+
+		if len(rows) > 0 {
+			if err := writer.write(rows); err != nil {
+				return cursor, errors.Join(err, writer.close())
+			}
+
+			for i := range rows {
+				c := cursorOf(&rows[i])
+
+				if cursor.equal(&c) {
+					cursor.Duplicates++
+				} else {
+					cursor = &c
+				}
+			}
+		}
+
+		if n < limit {
+			break
+		}
+	}
+
+	return cursor, writer.close()
+}
+
+// skipExportedRows removes the rows at the start of a batch that have been written already.
+func skipExportedRows[T any](rows []T, cursor *ExportCursor, cursorOf func(*T) ExportCursor) []T {
+	skip := cursor.skip()
+	i := 0
+
+	for i < len(rows) && i < skip {
+		c := cursorOf(&rows[i])
+
+		if !cursor.equal(&c) {
+			break
+		}
+
+		i++
+	}
+
+	return rows[i:]
+}
+
+type ndjsonWriter[T any] struct {
+	encoder *json.Encoder
+}
+
+func (writer *ndjsonWriter[T]) write(rows []T) error {
+	for i := range rows {
+		if err := writer.encoder.Encode(&rows[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (writer *ndjsonWriter[T]) close() error {
+	return nil
+}
+
+type csvWriter[T any] struct {
+	writer *csv.Writer
+	header bool
+}
+
+func (writer *csvWriter[T]) write(rows []T) error {
+	if writer.header {
+		writer.header = false
+		var row T
+		t := reflect.TypeOf(row)
+		header := make([]string, 0, t.NumField())
+
+		for i := 0; i < t.NumField(); i++ {
+			header = append(header, strings.Split(t.Field(i).Tag.Get("json"), ",")[0])
+		}
+
+		if err := writer.writer.Write(header); err != nil {
+			return err
+		}
+	}
+
+	for i := range rows {
+		v := reflect.ValueOf(rows[i])
+		record := make([]string, 0, v.NumField())
+
+		for j := 0; j < v.NumField(); j++ {
+			value, err := csvValue(v.Field(j))
+
+			if err != nil {
+				return err
+			}
+
+			record = append(record, value)
+		}
+
+		if err := writer.writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.writer.Flush()
+	return writer.writer.Error()
+}
+
+func (writer *csvWriter[T]) close() error {
+	writer.writer.Flush()
+	return writer.writer.Error()
+}
+
+func csvValue(v reflect.Value) (string, error) {
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.UTC().Format(exportCSVTimeFormat), nil
+	case string:
+		return value, nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case []string:
+		out, err := json.Marshal(value)
+		return string(out), err
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
+
+type parquetWriter[T any] struct {
+	writer *parquet.GenericWriter[T]
+}
+
+func (writer *parquetWriter[T]) write(rows []T) error {
+	_, err := writer.writer.Write(rows)
+	return err
+}
+
+func (writer *parquetWriter[T]) close() error {
+	return writer.writer.Close()
+}
@@ -0,0 +1,162 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/pirsch-analytics/pirsch/v6/pkg"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExport_PageViews(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	now := util.Today().Add(time.Hour)
+	assert.NoError(t, dbClient.SavePageViews([]model.PageView{
+		{ClientID: 1, VisitorID: 1, SessionID: 1, Time: now, Path: "/"},
+		{ClientID: 1, VisitorID: 1, SessionID: 1, Time: now.Add(time.Second), Path: "/foo"},
+		{ClientID: 1, VisitorID: 2, SessionID: 1, Time: now.Add(time.Second), Path: "/bar", TagKeys: []string{"author"}, TagValues: []string{"John"}},
+		{ClientID: 2, VisitorID: 3, SessionID: 1, Time: now, Path: "/"},
+	}))
+	analyzer := NewAnalyzer(dbClient)
+	var out bytes.Buffer
+	cursor, err := analyzer.Export.PageViews(&Filter{ClientID: 1, From: util.Today(), To: util.Today()}, &out, ExportNDJSON, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), cursor.VisitorID)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"path":"/"`)
+	assert.Contains(t, lines[2], `"tag_keys":["author"]`)
+	out.Reset()
+	cursor, err = analyzer.Export.PageViews(&Filter{ClientID: 1, From: util.Today(), To: util.Today()}, &out, ExportCSV, &ExportCursor{
+		Time:      now,
+		VisitorID: 1,
+		SessionID: 1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), cursor.VisitorID)
+	records, err := csv.NewReader(&out).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "/foo", records[0][6])
+	assert.Equal(t, "/bar", records[1][6])
+	assert.Equal(t, `["John"]`, records[1][29])
+	out.Reset()
+	_, err = analyzer.Export.PageViews(&Filter{ClientID: 1, From: util.Today(), To: util.Today(), Path: []string{"/foo"}}, &out, ExportParquet, nil)
+	assert.NoError(t, err)
+	rows, err := parquet.Read[model.PageView](bytes.NewReader(out.Bytes()), int64(out.Len()))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "/foo", rows[0].Path)
+	cursor, err = analyzer.Export.PageViews(&Filter{ClientID: 3}, &out, ExportNDJSON, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, cursor)
+	_, err = analyzer.Export.PageViews(nil, &out, "xml", nil)
+	assert.ErrorIs(t, err, ErrUnknownExportFormat)
+}
+
+func TestExport_PageViewsSameKey(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	now := util.Today().Add(time.Hour)
+	assert.NoError(t, dbClient.SavePageViews([]model.PageView{
+		{ClientID: 1, VisitorID: 1, SessionID: 1, Time: now, Path: "/"},
+		{ClientID: 1, VisitorID: 1, SessionID: 1, Time: now, Path: "/foo"},
+		{ClientID: 1, VisitorID: 1, SessionID: 1, Time: now, Path: "/bar"},
+		{ClientID: 1, VisitorID: 2, SessionID: 1, Time: now, Path: "/"},
+	}))
+	analyzer := NewAnalyzer(dbClient)
+	var out bytes.Buffer
+	cursor, err := analyzer.Export.PageViews(&Filter{ClientID: 1, From: util.Today(), To: util.Today()}, &out, ExportNDJSON, nil)
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(out.String()), "\n"), 4)
+	assert.Equal(t, uint64(2), cursor.VisitorID)
+	assert.Equal(t, 0, cursor.Duplicates)
+	all := strings.Split(strings.TrimSpace(out.String()), "\n")
+
+	for i := range 3 {
+		out.Reset()
+		cursor, err = analyzer.Export.PageViews(&Filter{ClientID: 1, From: util.Today(), To: util.Today()}, &out, ExportNDJSON, &ExportCursor{
+			Time:       now,
+			VisitorID:  1,
+			SessionID:  1,
+			Duplicates: i,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, all[i+1:], strings.Split(strings.TrimSpace(out.String()), "\n"))
+		assert.Equal(t, uint64(2), cursor.VisitorID)
+	}
+}
+
+func TestExport_SkipExportedRows(t *testing.T) {
+	now := time.Now()
+	rows := []ExportCursor{
+		{Time: now, VisitorID: 1, SessionID: 1},
+		{Time: now, VisitorID: 1, SessionID: 1},
+		{Time: now, VisitorID: 2, SessionID: 1},
+	}
+	cursorOf := func(c *ExportCursor) ExportCursor {
+		return *c
+	}
+	assert.Len(t, skipExportedRows(rows, nil, cursorOf), 3)
+	assert.Len(t, skipExportedRows(rows, &ExportCursor{Time: now, VisitorID: 1, SessionID: 1}, cursorOf), 2)
+	assert.Len(t, skipExportedRows(rows, &ExportCursor{Time: now, VisitorID: 1, SessionID: 1, Duplicates: 1}, cursorOf), 1)
+	assert.Len(t, skipExportedRows(rows, &ExportCursor{Time: now, VisitorID: 1, SessionID: 1, Duplicates: 5}, cursorOf), 1)
+	assert.Len(t, skipExportedRows(rows, &ExportCursor{Time: now, VisitorID: 3, SessionID: 1}, cursorOf), 3)
+}
+
+func TestExport_SessionsEvents(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	now := util.Today().Add(time.Hour)
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, Version: 1, ClientID: 1, VisitorID: 1, SessionID: 1, Time: now, Start: now, EntryPath: "/", ExitPath: "/", PageViews: 1, IsBounce: true},
+		},
+		{
+			{Sign: -1, Version: 1, ClientID: 1, VisitorID: 1, SessionID: 1, Time: now, Start: now, EntryPath: "/", ExitPath: "/", PageViews: 1, IsBounce: true},
+			{Sign: 1, Version: 2, ClientID: 1, VisitorID: 1, SessionID: 1, Time: now.Add(time.Second), Start: now, EntryPath: "/", ExitPath: "/foo", PageViews: 2},
+		},
+	})
+	assert.NoError(t, dbClient.SaveEvents([]model.Event{
+		{ClientID: 1, VisitorID: 1, SessionID: 1, Time: now, Name: "event", MetaKeys: []string{"key"}, MetaValues: []string{"value"}},
+	}))
+	analyzer := NewAnalyzer(dbClient)
+	var out bytes.Buffer
+	_, err := analyzer.Export.Sessions(&Filter{ClientID: 1, From: util.Today(), To: util.Today()}, &out, ExportCSV, nil)
+	assert.NoError(t, err)
+	records, err := csv.NewReader(&out).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "exit_path", records[0][10])
+	assert.Equal(t, "/foo", records[1][10])
+	out.Reset()
+	cursor, err := analyzer.Export.Events(&Filter{ClientID: 1, From: util.Today(), To: util.Today(), EventName: []string{"event"}}, &out, ExportNDJSON, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, cursor)
+	assert.Contains(t, out.String(), `"meta_keys":["key"]`)
+}
+
+func TestExport_BuildQuery(t *testing.T) {
+	analyzer := NewAnalyzer(db.NewClientMock())
+	filter := getValidFilter(t, analyzer, &Filter{
+		ClientID: 42,
+		From:     util.PastDay(7),
+		To:       util.Today(),
+		Country:  []string{"de"},
+		Platform: pkg.PlatformDesktop,
+	})
+	q, args := analyzer.Export.buildQuery(filter, events, "event_name", &ExportCursor{
+		Time:      time.UnixMilli(1700000000000),
+		VisitorID: 7,
+		SessionID: 3,
+	})
+	assert.Equal(t, `SELECT event_name FROM "event" t WHERE client_id = ? AND toDate(time, 'UTC') >= toDate(?) AND toDate(time, 'UTC') <= toDate(?) AND country_code = ? AND desktop = 1 AND (time, visitor_id, session_id) >= (fromUnixTimestamp64Milli(?, 'UTC'), ?, ?) ORDER BY time, visitor_id, session_id, cityHash64(event_name) LIMIT 10001`, q)
+	assert.Equal(t, []any{int64(42), util.PastDay(7).Format(dateFormat), util.Today().Format(dateFormat), "de", int64(1700000000000), uint64(7), uint32(3)}, args)
+	q, _ = analyzer.Export.buildQuery(filter, sessions, "session_id", nil)
+	assert.True(t, strings.HasPrefix(q, `SELECT session_id FROM "session" t FINAL WHERE client_id = ? `))
+}
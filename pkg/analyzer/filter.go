@@ -3,11 +3,13 @@ package analyzer
 import (
 	"context"
 	"maps"
+	"reflect"
 	"slices"
 	"strings"
 	"time"
 
 	"github.com/pirsch-analytics/pirsch/v6/pkg"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/util"
 )
 
@@ -18,6 +20,9 @@ type Filter struct {
 	// Ctx can be used to set a timeout or to cancel queries.
 	Ctx context.Context
 
+	// QuerySettings optionally overrides the resource limits configured for the db.Client.
+	QuerySettings db.QuerySettings
+
 	// ClientID is optional.
 	ClientID int64
 
@@ -33,11 +38,23 @@ type Filter struct {
 
 	// ImportedUntil is the date until which the imported statistics should be used.
 	// Set to zero to ignore imported statistics.
+	// Hourly statistics use the imported visitor statistics at hour 0 for days without imported hourly statistics,
+	// as imports created before hourly statistics were available would otherwise be missing from the totals.
 	ImportedUntil time.Time
 
 	// Period sets the period to group results.
 	Period pkg.Period
 
+	// Compare sets the time range used by Compare, CompareTotal, Visitors.Growth, and Visitors.TotalVisitorsPageViews.
+	// The previous period of equal length (ComparePrevious) is used by default.
+	Compare Comparison
+
+	// CompareFrom is the start date of the comparison time range for CompareCustom.
+	CompareFrom time.Time
+
+	// CompareTo is the end date of the comparison time range for CompareCustom.
+	CompareTo time.Time
+
 	// Hostname filters for the hostname.
 	Hostname []string
 
@@ -141,6 +158,36 @@ type Filter struct {
 	// Must be used together with VisitorID.
 	SessionID uint32
 
+	// SessionDuration filters for sessions by their duration in seconds.
+	SessionDuration *Range
+
+	// SessionPageViews filters for sessions by the number of page views.
+	SessionPageViews *Range
+
+	// TimeOnPage filters for page views by the time spent on the page in seconds.
+	// Page views without a following page view in the session are excluded. MaxTimeOnPageSeconds is applied.
+	// Other tables are filtered for sessions containing a matching page view.
+	TimeOnPage *Range
+
+	// EventDuration filters for events by their duration in seconds.
+	EventDuration *Range
+
+	// EventMetaRange filters for events by numeric metadata values.
+	// The values are cast using the CustomMetricType (float by default). Events without the key do not match.
+	EventMetaRange map[string]Range
+
+	// Segment is the name of a saved segment (see Segments) the sessions must match.
+	// It's resolved by the Analyzer and combined with the SegmentConditions. Unknown segments match no sessions.
+	Segment string
+
+	// SegmentConditions filters for sessions by what happened in them (see SegmentCondition).
+	// The conditions are combined using AND. Imported statistics are not filtered by segments.
+	SegmentConditions []SegmentCondition
+
+	// Expression filters using a boolean expression of field predicates (see Expression).
+	// It's combined with all other fields using AND. Imported statistics are not filtered by the expression.
+	Expression *Expression
+
 	// Search searches the results for given fields and inputs.
 	Search []Search
 
@@ -187,6 +234,7 @@ type Filter struct {
 	funnelStep   int
 	importedFrom time.Time
 	importedTo   time.Time
+	pathGroup    *pathGroup
 }
 
 // Search filters results by searching for the given input for a given field.
@@ -250,6 +298,14 @@ func (filter *Filter) Empty() bool {
 		len(filter.EventMeta) == 0 &&
 		filter.VisitorID == 0 &&
 		filter.SessionID == 0 &&
+		filter.SessionDuration == nil &&
+		filter.SessionPageViews == nil &&
+		filter.TimeOnPage == nil &&
+		filter.EventDuration == nil &&
+		len(filter.EventMetaRange) == 0 &&
+		filter.Segment == "" &&
+		len(filter.SegmentConditions) == 0 &&
+		filter.Expression == nil &&
 		len(filter.Search) == 0
 }
 
@@ -261,9 +317,13 @@ func (filter *Filter) Equal(other *Filter) bool {
 		filter.To.Equal(other.To) &&
 		filter.ImportedUntil.Equal(other.ImportedUntil) &&
 		filter.Period == other.Period &&
+		filter.Compare == other.Compare &&
+		filter.CompareFrom.Equal(other.CompareFrom) &&
+		filter.CompareTo.Equal(other.CompareTo) &&
 		filter.Platform == other.Platform &&
 		filter.VisitorID == other.VisitorID &&
 		filter.SessionID == other.SessionID &&
+		filter.Segment == other.Segment &&
 		filter.Offset == other.Offset &&
 		filter.Limit == other.Limit &&
 		filter.CustomMetricKey == other.CustomMetricKey &&
@@ -530,6 +590,25 @@ func (filter *Filter) Equal(other *Filter) bool {
 		return false
 	}
 
+	if !reflect.DeepEqual(filter.SessionDuration, other.SessionDuration) ||
+		!reflect.DeepEqual(filter.SessionPageViews, other.SessionPageViews) ||
+		!reflect.DeepEqual(filter.TimeOnPage, other.TimeOnPage) ||
+		!reflect.DeepEqual(filter.EventDuration, other.EventDuration) {
+		return false
+	}
+
+	if !maps.Equal(filter.EventMetaRange, other.EventMetaRange) {
+		return false
+	}
+
+	if !slices.Equal(filter.SegmentConditions, other.SegmentConditions) {
+		return false
+	}
+
+	if !reflect.DeepEqual(filter.Expression, other.Expression) {
+		return false
+	}
+
 	return true
 }
 
@@ -562,6 +641,19 @@ func (filter *Filter) validate() {
 		filter.From, filter.To = filter.To, filter.From
 	}
 
+	if filter.Compare != ComparePrevious && filter.Compare != CompareYear && filter.Compare != CompareCustom {
+		filter.Compare = ""
+	}
+
+	if filter.Compare == CompareCustom {
+		filter.CompareFrom = filter.toDate(filter.CompareFrom)
+		filter.CompareTo = filter.toDate(filter.CompareTo)
+
+		if filter.CompareFrom.After(filter.CompareTo) {
+			filter.CompareFrom, filter.CompareTo = filter.CompareTo, filter.CompareFrom
+		}
+	}
+
 	if !filter.ImportedUntil.IsZero() {
 		if filter.From.Before(filter.ImportedUntil) {
 			filter.importedFrom = filter.From
@@ -719,10 +811,73 @@ func (filter *Filter) buildQuery(fields, groupBy, orderBy, fieldsImported []Fiel
 		filter.joinOrLeftJoinEvents(&q, fields)
 	}
 
+	// the expression is evaluated by the main query if possible and by the joined queries otherwise
+	if q.leftJoin != nil {
+		q.leftJoin.filter.Expression = nil
+	}
+
+	if q.expressionApplies() {
+		if q.join != nil {
+			q.join.filter.Expression = nil
+		}
+
+		if q.joinSecond != nil {
+			q.joinSecond.filter.Expression = nil
+		}
+	}
+
+	// the numeric ranges, segments, and path groups are always evaluated by the main query
+	for _, join := range []*queryBuilder{q.join, q.joinSecond, q.leftJoin} {
+		if join != nil {
+			join.filter.pathGroup = nil
+			join.filter.Segment = ""
+			join.filter.SegmentConditions = nil
+			join.filter.SessionDuration = nil
+			join.filter.SessionPageViews = nil
+			join.filter.TimeOnPage = nil
+			join.filter.EventDuration = nil
+			join.filter.EventMetaRange = nil
+		}
+	}
+
 	q.joinThird = filter.joinUniqueVisitorsByPeriod(fields)
 	return q.query()
 }
 
+// buildSessionsQuery returns a query selecting the visitor and session IDs of all sessions matching the filter.
+func (filter *Filter) buildSessionsQuery() (string, []any) {
+	filterCopy := *filter
+	filterCopy.funnelStep = 1
+	filterCopy.Search = nil
+	filterCopy.Sort = nil
+	filterCopy.Offset = 0
+	filterCopy.Limit = 0
+	return filterCopy.buildQuery([]Field{
+		FieldVisitorID,
+		FieldSessionID,
+	}, nil, nil, nil, "")
+}
+
+// periodFilter returns a filter for the selected period only, used to build subqueries for the numeric ranges and segments.
+func (filter *Filter) periodFilter() *Filter {
+	return &Filter{
+		ClientID:             filter.ClientID,
+		Timezone:             filter.Timezone,
+		From:                 filter.From,
+		To:                   filter.To,
+		IncludeTime:          filter.IncludeTime,
+		MaxTimeOnPageSeconds: filter.MaxTimeOnPageSeconds,
+	}
+}
+
+// hasSessionSubquery returns whether the numeric ranges or segment conditions for given table are matched by session using a subquery.
+func (filter *Filter) hasSessionSubquery(table table) bool {
+	return filter.Segment != "" ||
+		len(filter.SegmentConditions) > 0 ||
+		(table != sessions && (filter.SessionDuration != nil || filter.SessionPageViews != nil)) ||
+		(table != events && (filter.EventDuration != nil || len(filter.EventMetaRange) > 0))
+}
+
 func (filter *Filter) buildTimeQuery() (string, []any) {
 	q := queryBuilder{filter: filter}
 	return q.whereTime(), q.args
@@ -735,10 +890,12 @@ func (filter *Filter) table(fields []Field) table {
 
 	if (len(filter.Path) > 0 ||
 		len(filter.PathPattern) > 0 ||
+		filter.Expression.contains(FieldPath) ||
 		len(filter.Tags) > 0 ||
 		len(filter.Tag) > 0 ||
 		filter.fieldsContain(fields, FieldPageViewsAll) ||
 		filter.fieldsContain(fields, FieldPath) ||
+		filter.fieldsContain(fields, FieldPathGroup) ||
 		filter.fieldsContain(fields, FieldEntries) ||
 		filter.fieldsContain(fields, FieldExits) ||
 		filter.fieldsContain(fields, FieldHour) ||
@@ -759,6 +916,7 @@ func (filter *Filter) table(fields []Field) table {
 	}
 
 	if (len(filter.EventName) > 0 ||
+		filter.Expression.contains(FieldEventName) ||
 		filter.fieldsContain(fields, FieldEventName) ||
 		filter.fieldsContain(fields, FieldEventsAll) ||
 		filter.CustomMetricType != "" && filter.CustomMetricKey != "") &&
@@ -772,7 +930,10 @@ func (filter *Filter) table(fields []Field) table {
 func (filter *Filter) joinSessions(table table, fields []Field) *queryBuilder {
 	if len(filter.EntryPath) > 0 ||
 		len(filter.ExitPath) > 0 ||
+		filter.Expression.contains(FieldEntryPath) ||
+		filter.Expression.contains(FieldExitPath) ||
 		filter.fieldsContain(fields, FieldBounces) ||
+		filter.fieldsContain(fields, FieldEngagedSessions) ||
 		(table == events && filter.fieldsContain(fields, FieldViews)) ||
 		filter.fieldsContain(fields, FieldEntryPath) ||
 		filter.fieldsContain(fields, FieldExitPath) {
@@ -803,6 +964,10 @@ func (filter *Filter) joinSessions(table table, fields []Field) *queryBuilder {
 			sessionFields = append(sessionFields, FieldBounces)
 		}
 
+		if filter.fieldsContain(fields, FieldEngagedSessions) {
+			sessionFields = append(sessionFields, FieldEngagedSessions)
+		}
+
 		if filter.fieldsContain(fields, FieldViews) {
 			sessionFields = append(sessionFields, FieldViews)
 		}
@@ -824,6 +989,7 @@ func (filter *Filter) joinSessions(table table, fields []Field) *queryBuilder {
 
 func (filter *Filter) joinPageViews(fields []Field) *queryBuilder {
 	if len(filter.Path) > 0 || len(filter.PathPattern) > 0 || len(filter.Tag) > 0 || len(filter.Tags) > 0 || filter.searchContains(FieldPath) ||
+		filter.Expression.contains(FieldPath) ||
 		filter.fieldsContain(fields, FieldTagKey) || filter.fieldsContain(fields, FieldTagValue) ||
 		filter.fieldsContain(fields, FieldTagKeysRaw) || filter.fieldsContain(fields, FieldTagValuesRaw) {
 		pageViewFields := []Field{FieldVisitorID, FieldSessionID}
@@ -865,7 +1031,7 @@ func (filter *Filter) joinOrLeftJoinEvents(q *queryBuilder, fields []Field) {
 }
 
 func (filter *Filter) joinEvents(fields []Field) *queryBuilder {
-	if len(filter.EventName) > 0 || filter.fieldsContain(fields, FieldEventName) {
+	if len(filter.EventName) > 0 || filter.Expression.contains(FieldEventName) || filter.fieldsContain(fields, FieldEventName) {
 		eventFields := []Field{FieldVisitorID, FieldSessionID}
 
 		if filter.fieldsContain(fields, FieldHour) {
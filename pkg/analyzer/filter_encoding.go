@@ -0,0 +1,966 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg"
+)
+
+// searchSortFields are the fields that can be used for Filter.Search and Filter.Sort when encoding and decoding filters.
+var searchSortFields = map[string]Field{
+	"hostname":          FieldHostname,
+	"path":              FieldPath,
+	"entry_path":        FieldEntryPath,
+	"exit_path":         FieldExitPath,
+	"title":             FieldTitle,
+	"entries":           FieldEntries,
+	"entry_rate":        FieldEntryRate,
+	"exits":             FieldExits,
+	"exit_rate":         FieldExitRate,
+	"visitors":          FieldVisitors,
+	"relative_visitors": FieldRelativeVisitors,
+	"sessions":          FieldSessions,
+	"views":             FieldViews,
+	"relative_views":    FieldRelativeViews,
+	"bounces":           FieldBounces,
+	"bounce_rate":       FieldBounceRate,
+	"engaged_sessions":  FieldEngagedSessions,
+	"engagement_rate":   FieldEngagementRate,
+	"pages_per_session": FieldPagesPerSession,
+	"cr":                FieldCR,
+	"referrer":          FieldReferrer,
+	"referrer_name":     FieldReferrerName,
+	"language":          FieldLanguage,
+	"country":           FieldCountry,
+	"region":            FieldRegion,
+	"city":              FieldCity,
+	"browser":           FieldBrowser,
+	"browser_version":   FieldBrowserVersion,
+	"os":                FieldOS,
+	"os_version":        FieldOSVersion,
+	"screen_class":      FieldScreenClass,
+	"utm_source":        FieldUTMSource,
+	"utm_medium":        FieldUTMMedium,
+	"utm_campaign":      FieldUTMCampaign,
+	"utm_content":       FieldUTMContent,
+	"utm_term":          FieldUTMTerm,
+	"channel":           FieldChannel,
+	"event_name":        FieldEventName,
+	"count":             FieldCount,
+	"tag_key":           FieldTagKey,
+	"tag_value":         FieldTagValue,
+	"day":               FieldDay,
+	"hour":              FieldHour,
+	"minute":            FieldMinute,
+	"weekday":           FieldWeekday,
+}
+
+// FilterError is returned when decoding or validating a Filter fails.
+// Field is the name of the field as used for the URL query and JSON encoding.
+type FilterError struct {
+	Field string
+	Err   error
+}
+
+// Error implements the error interface.
+func (err *FilterError) Error() string {
+	return fmt.Sprintf("invalid filter field %s: %s", err.Field, err.Err)
+}
+
+// Unwrap returns the underlying error.
+func (err *FilterError) Unwrap() error {
+	return err.Err
+}
+
+// filterData is the canonical representation of a Filter used for encoding.
+// The json tags are used for the URL query parameters as well.
+// Maps are encoded as <name>.<key>=<value> in URL queries.
+type filterData struct {
+	ClientID             int64             `json:"client_id,omitempty"`
+	Timezone             string            `json:"tz,omitempty"`
+	From                 string            `json:"from,omitempty"`
+	To                   string            `json:"to,omitempty"`
+	ImportedUntil        string            `json:"imported_until,omitempty"`
+	Period               string            `json:"period,omitempty"`
+	Compare              string            `json:"compare,omitempty"`
+	CompareFrom          string            `json:"compare_from,omitempty"`
+	CompareTo            string            `json:"compare_to,omitempty"`
+	Hostname             []string          `json:"hostname,omitempty"`
+	Path                 []string          `json:"path,omitempty"`
+	AnyPath              []string          `json:"any_path,omitempty"`
+	EntryPath            []string          `json:"entry_path,omitempty"`
+	ExitPath             []string          `json:"exit_path,omitempty"`
+	PathPattern          []string          `json:"pattern,omitempty"`
+	Language             []string          `json:"language,omitempty"`
+	Country              []string          `json:"country,omitempty"`
+	Region               []string          `json:"region,omitempty"`
+	City                 []string          `json:"city,omitempty"`
+	Referrer             []string          `json:"referrer,omitempty"`
+	ReferrerName         []string          `json:"referrer_name,omitempty"`
+	Channel              []string          `json:"channel,omitempty"`
+	OS                   []string          `json:"os,omitempty"`
+	OSVersion            []string          `json:"os_version,omitempty"`
+	Browser              []string          `json:"browser,omitempty"`
+	BrowserVersion       []string          `json:"browser_version,omitempty"`
+	Platform             string            `json:"platform,omitempty"`
+	ScreenClass          []string          `json:"screen_class,omitempty"`
+	UTMSource            []string          `json:"utm_source,omitempty"`
+	UTMMedium            []string          `json:"utm_medium,omitempty"`
+	UTMCampaign          []string          `json:"utm_campaign,omitempty"`
+	UTMContent           []string          `json:"utm_content,omitempty"`
+	UTMTerm              []string          `json:"utm_term,omitempty"`
+	Tags                 map[string]string `json:"tags,omitempty"`
+	Tag                  []string          `json:"tag,omitempty"`
+	EventName            []string          `json:"event,omitempty"`
+	EventMetaKey         []string          `json:"event_meta_key,omitempty"`
+	EventMeta            map[string]string `json:"meta,omitempty"`
+	VisitorID            uint64            `json:"visitor_id,omitempty"`
+	SessionID            uint32            `json:"session_id,omitempty"`
+	SessionDuration      string            `json:"session_duration,omitempty"`
+	SessionPageViews     string            `json:"session_page_views,omitempty"`
+	TimeOnPage           string            `json:"time_on_page,omitempty"`
+	EventDuration        string            `json:"event_duration,omitempty"`
+	EventMetaRange       map[string]string `json:"meta_range,omitempty"`
+	Segment              string            `json:"segment,omitempty"`
+	SegmentConditions    []*filterSegment  `json:"segment_condition,omitempty"`
+	Expression           *filterExpression `json:"expr,omitempty"`
+	Search               []filterSearch    `json:"search,omitempty"`
+	Sort                 []filterSort      `json:"sort,omitempty"`
+	Offset               int               `json:"offset,omitempty"`
+	Limit                int               `json:"limit,omitempty"`
+	CustomMetricKey      string            `json:"custom_metric_key,omitempty"`
+	CustomMetricType     string            `json:"custom_metric_type,omitempty"`
+	IncludeTime          bool              `json:"include_time,omitempty"`
+	IncludeTitle         bool              `json:"include_title,omitempty"`
+	IncludeTimeOnPage    bool              `json:"include_time_on_page,omitempty"`
+	IncludeCR            bool              `json:"include_cr,omitempty"`
+	WeekdayMode          string            `json:"weekday_mode,omitempty"`
+	MaxTimeOnPageSeconds int               `json:"max_time_on_page_seconds,omitempty"`
+	Sample               uint              `json:"sample,omitempty"`
+}
+
+// filterExpression is the encoded form of an Expression.
+// It's encoded as JSON in URL queries.
+type filterExpression struct {
+	And   []filterExpression `json:"and,omitempty"`
+	Or    []filterExpression `json:"or,omitempty"`
+	Not   *filterExpression  `json:"not,omitempty"`
+	Field string             `json:"field,omitempty"`
+	Value []string           `json:"value,omitempty"`
+}
+
+// filterSegment is the encoded form of a SegmentCondition.
+// It's encoded as JSON in URL queries.
+type filterSegment struct {
+	Event    string `json:"event,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Not      bool   `json:"not,omitempty"`
+	Visitor  bool   `json:"visitor,omitempty"`
+	MinCount int    `json:"min_count,omitempty"`
+	MaxCount int    `json:"max_count,omitempty"`
+}
+
+// filterSearch is encoded as <field>:<input>.
+type filterSearch struct {
+	field string
+	input string
+}
+
+// filterSort is encoded as <field>:<asc|desc>.
+type filterSort struct {
+	field     string
+	direction string
+}
+
+// FilterFromValues decodes a Filter from URL query parameters.
+// Lists can be passed multiple times (path=/a&path=/b), maps are passed as tags.<key>=<value> and meta.<key>=<value>.
+// Ranges are passed as >n, >=n, <n, <=n, or n..m (see ParseRange), like session_duration=>60 or meta_range.amount=100..200.
+// Search and Sort are passed as search=<field>:<input> and sort=<field>:<asc|desc>.
+// Segment conditions are passed as JSON, like segment_condition={"event":"signup","not":true}.
+// The Expression is passed as JSON, like expr={"or":[{"field":"country","value":["de"]},{"field":"event","value":["signup"]}]}.
+// Unknown parameters are ignored.
+func FilterFromValues(values url.Values) (*Filter, error) {
+	data := new(filterData)
+
+	if err := data.decodeValues(values); err != nil {
+		return nil, err
+	}
+
+	return data.filter()
+}
+
+// Values encodes the filter as URL query parameters.
+// The result can be decoded using FilterFromValues. Ctx and QuerySettings are not encoded.
+func (filter *Filter) Values() url.Values {
+	return newFilterData(filter).encodeValues()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The field names are the same as for the URL query parameters. Ctx and QuerySettings are not encoded.
+func (filter *Filter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newFilterData(filter))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Unknown fields are rejected.
+func (filter *Filter) UnmarshalJSON(data []byte) error {
+	var fd filterData
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&fd); err != nil {
+		var typeErr *json.UnmarshalTypeError
+
+		if errors.As(err, &typeErr) {
+			return &FilterError{Field: typeErr.Field, Err: fmt.Errorf("expected %s", typeErr.Type)}
+		}
+
+		var textErr *FilterError
+
+		if errors.As(err, &textErr) {
+			return textErr
+		}
+
+		return err
+	}
+
+	f, err := fd.filter()
+
+	if err != nil {
+		return err
+	}
+
+	ctx, settings := filter.Ctx, filter.QuerySettings
+	*filter = *f
+	filter.Ctx, filter.QuerySettings = ctx, settings
+	return nil
+}
+
+// orderedFilterLists are the lists in filterData whose order changes the query, like the first Tag being used for tag values.
+var orderedFilterLists = map[string]bool{
+	"Tag":          true,
+	"EventMetaKey": true,
+}
+
+// Hash returns a stable hash for the filter that can be used as a cache key.
+// Lists are treated as sets, so the order of values doesn't change the hash, except for orderedFilterLists.
+// Ctx and QuerySettings are ignored.
+func (filter *Filter) Hash() string {
+	data := newFilterData(filter)
+	v := reflect.ValueOf(data).Elem()
+
+	for i := range v.NumField() {
+		if orderedFilterLists[v.Type().Field(i).Name] {
+			continue
+		}
+
+		if list, ok := v.Field(i).Interface().([]string); ok {
+			slices.Sort(list)
+			v.Field(i).Set(reflect.ValueOf(slices.Compact(list)))
+		}
+	}
+
+	slices.SortFunc(data.Search, func(a, b filterSearch) int {
+		return strings.Compare(a.field+":"+a.input, b.field+":"+b.input)
+	})
+	hash := sha256.Sum256([]byte(data.encodeValues().Encode()))
+	return hex.EncodeToString(hash[:])
+}
+
+func newFilterData(filter *Filter) *filterData {
+	data := &filterData{
+		ClientID:             filter.ClientID,
+		From:                 encodeFilterTime(filter.From, filter.IncludeTime),
+		To:                   encodeFilterTime(filter.To, filter.IncludeTime),
+		ImportedUntil:        encodeFilterTime(filter.ImportedUntil, false),
+		Compare:              string(filter.Compare),
+		CompareFrom:          encodeFilterTime(filter.CompareFrom, false),
+		CompareTo:            encodeFilterTime(filter.CompareTo, false),
+		Hostname:             slices.Clone(filter.Hostname),
+		Path:                 slices.Clone(filter.Path),
+		AnyPath:              slices.Clone(filter.AnyPath),
+		EntryPath:            slices.Clone(filter.EntryPath),
+		ExitPath:             slices.Clone(filter.ExitPath),
+		PathPattern:          slices.Clone(filter.PathPattern),
+		Language:             slices.Clone(filter.Language),
+		Country:              slices.Clone(filter.Country),
+		Region:               slices.Clone(filter.Region),
+		City:                 slices.Clone(filter.City),
+		Referrer:             slices.Clone(filter.Referrer),
+		ReferrerName:         slices.Clone(filter.ReferrerName),
+		Channel:              slices.Clone(filter.Channel),
+		OS:                   slices.Clone(filter.OS),
+		OSVersion:            slices.Clone(filter.OSVersion),
+		Browser:              slices.Clone(filter.Browser),
+		BrowserVersion:       slices.Clone(filter.BrowserVersion),
+		Platform:             filter.Platform,
+		ScreenClass:          slices.Clone(filter.ScreenClass),
+		UTMSource:            slices.Clone(filter.UTMSource),
+		UTMMedium:            slices.Clone(filter.UTMMedium),
+		UTMCampaign:          slices.Clone(filter.UTMCampaign),
+		UTMContent:           slices.Clone(filter.UTMContent),
+		UTMTerm:              slices.Clone(filter.UTMTerm),
+		Tags:                 filter.Tags,
+		Tag:                  slices.Clone(filter.Tag),
+		EventName:            slices.Clone(filter.EventName),
+		EventMetaKey:         slices.Clone(filter.EventMetaKey),
+		EventMeta:            filter.EventMeta,
+		VisitorID:            filter.VisitorID,
+		SessionID:            filter.SessionID,
+		Segment:              filter.Segment,
+		Offset:               filter.Offset,
+		Limit:                filter.Limit,
+		CustomMetricKey:      filter.CustomMetricKey,
+		IncludeTime:          filter.IncludeTime,
+		IncludeTitle:         filter.IncludeTitle,
+		IncludeTimeOnPage:    filter.IncludeTimeOnPage,
+		IncludeCR:            filter.IncludeCR,
+		MaxTimeOnPageSeconds: filter.MaxTimeOnPageSeconds,
+		Sample:               filter.Sample,
+	}
+
+	if filter.Timezone != nil && filter.Timezone != time.UTC {
+		data.Timezone = filter.Timezone.String()
+	}
+
+	if filter.Expression != nil {
+		data.Expression = newFilterExpression(filter.Expression)
+	}
+
+	for _, condition := range filter.SegmentConditions {
+		data.SegmentConditions = append(data.SegmentConditions, &filterSegment{
+			Event:    condition.Event,
+			Path:     condition.Path,
+			Not:      condition.Not,
+			Visitor:  condition.Visitor,
+			MinCount: condition.MinCount,
+			MaxCount: condition.MaxCount,
+		})
+	}
+
+	data.SessionDuration = encodeFilterRange(filter.SessionDuration)
+	data.SessionPageViews = encodeFilterRange(filter.SessionPageViews)
+	data.TimeOnPage = encodeFilterRange(filter.TimeOnPage)
+	data.EventDuration = encodeFilterRange(filter.EventDuration)
+
+	if len(filter.EventMetaRange) > 0 {
+		data.EventMetaRange = make(map[string]string, len(filter.EventMetaRange))
+
+		for k, v := range filter.EventMetaRange {
+			data.EventMetaRange[k] = v.String()
+		}
+	}
+
+	switch filter.Period {
+	case pkg.PeriodHour:
+		data.Period = "hour"
+	case pkg.PeriodWeek:
+		data.Period = "week"
+	case pkg.PeriodMonth:
+		data.Period = "month"
+	case pkg.PeriodQuarter:
+		data.Period = "quarter"
+	case pkg.PeriodYear:
+		data.Period = "year"
+	}
+
+	switch filter.CustomMetricType {
+	case pkg.CustomMetricTypeInteger:
+		data.CustomMetricType = "integer"
+	case pkg.CustomMetricTypeFloat:
+		data.CustomMetricType = "float"
+	}
+
+	if filter.WeekdayMode == WeekdaySunday {
+		data.WeekdayMode = "sunday"
+	}
+
+	for _, search := range filter.Search {
+		data.Search = append(data.Search, filterSearch{
+			field: searchSortFieldName(search.Field),
+			input: search.Input,
+		})
+	}
+
+	for _, sort := range filter.Sort {
+		data.Sort = append(data.Sort, filterSort{
+			field:     searchSortFieldName(sort.Field),
+			direction: strings.ToLower(string(sort.Direction)),
+		})
+	}
+
+	return data
+}
+
+func (data *filterData) filter() (*Filter, error) {
+	filter := &Filter{
+		ClientID:             data.ClientID,
+		Timezone:             time.UTC,
+		Hostname:             data.Hostname,
+		Path:                 data.Path,
+		AnyPath:              data.AnyPath,
+		EntryPath:            data.EntryPath,
+		ExitPath:             data.ExitPath,
+		PathPattern:          data.PathPattern,
+		Language:             data.Language,
+		Country:              data.Country,
+		Region:               data.Region,
+		City:                 data.City,
+		Referrer:             data.Referrer,
+		ReferrerName:         data.ReferrerName,
+		Channel:              data.Channel,
+		OS:                   data.OS,
+		OSVersion:            data.OSVersion,
+		Browser:              data.Browser,
+		BrowserVersion:       data.BrowserVersion,
+		Platform:             data.Platform,
+		ScreenClass:          data.ScreenClass,
+		UTMSource:            data.UTMSource,
+		UTMMedium:            data.UTMMedium,
+		UTMCampaign:          data.UTMCampaign,
+		UTMContent:           data.UTMContent,
+		UTMTerm:              data.UTMTerm,
+		Tags:                 data.Tags,
+		Tag:                  data.Tag,
+		EventName:            data.EventName,
+		EventMetaKey:         data.EventMetaKey,
+		EventMeta:            data.EventMeta,
+		VisitorID:            data.VisitorID,
+		SessionID:            data.SessionID,
+		Segment:              data.Segment,
+		Offset:               data.Offset,
+		Limit:                data.Limit,
+		CustomMetricKey:      data.CustomMetricKey,
+		IncludeTime:          data.IncludeTime,
+		IncludeTitle:         data.IncludeTitle,
+		IncludeTimeOnPage:    data.IncludeTimeOnPage,
+		IncludeCR:            data.IncludeCR,
+		MaxTimeOnPageSeconds: data.MaxTimeOnPageSeconds,
+		Sample:               data.Sample,
+	}
+	var err error
+
+	if data.Timezone != "" {
+		filter.Timezone, err = time.LoadLocation(data.Timezone)
+
+		if err != nil {
+			return nil, &FilterError{Field: "tz", Err: errors.New("unknown timezone")}
+		}
+	}
+
+	for name, t := range map[string]struct {
+		value string
+		out   *time.Time
+	}{
+		"from":           {data.From, &filter.From},
+		"to":             {data.To, &filter.To},
+		"imported_until": {data.ImportedUntil, &filter.ImportedUntil},
+		"compare_from":   {data.CompareFrom, &filter.CompareFrom},
+		"compare_to":     {data.CompareTo, &filter.CompareTo},
+	} {
+		if *t.out, err = decodeFilterTime(t.value); err != nil {
+			return nil, &FilterError{Field: name, Err: err}
+		}
+	}
+
+	switch data.Period {
+	case "", "day":
+		filter.Period = pkg.PeriodDay
+	case "week":
+		filter.Period = pkg.PeriodWeek
+	case "hour":
+		filter.Period = pkg.PeriodHour
+	case "month":
+		filter.Period = pkg.PeriodMonth
+	case "quarter":
+		filter.Period = pkg.PeriodQuarter
+	case "year":
+		filter.Period = pkg.PeriodYear
+	default:
+		return nil, &FilterError{Field: "period", Err: errors.New("must be hour, day, week, month, quarter, or year")}
+	}
+
+	switch Comparison(data.Compare) {
+	case "", ComparePrevious, CompareYear:
+		filter.Compare = Comparison(data.Compare)
+	case CompareCustom:
+		if filter.CompareFrom.IsZero() || filter.CompareTo.IsZero() {
+			return nil, &FilterError{Field: "compare", Err: errors.New("compare_from and compare_to must be set for custom comparison")}
+		}
+
+		filter.Compare = CompareCustom
+	default:
+		return nil, &FilterError{Field: "compare", Err: errors.New("must be previous, year, or custom")}
+	}
+
+	switch data.CustomMetricType {
+	case "":
+	case "integer":
+		filter.CustomMetricType = pkg.CustomMetricTypeInteger
+	case "float":
+		filter.CustomMetricType = pkg.CustomMetricTypeFloat
+	default:
+		return nil, &FilterError{Field: "custom_metric_type", Err: errors.New("must be integer or float")}
+	}
+
+	switch data.WeekdayMode {
+	case "":
+	case "monday":
+		filter.WeekdayMode = WeekdayMonday
+	case "sunday":
+		filter.WeekdayMode = WeekdaySunday
+	default:
+		return nil, &FilterError{Field: "weekday_mode", Err: errors.New("must be monday or sunday")}
+	}
+
+	if data.Platform != "" &&
+		data.Platform != pkg.PlatformDesktop &&
+		data.Platform != pkg.PlatformMobile &&
+		data.Platform != pkg.PlatformUnknown &&
+		data.Platform != "!"+pkg.PlatformDesktop &&
+		data.Platform != "!"+pkg.PlatformMobile &&
+		data.Platform != "!"+pkg.PlatformUnknown {
+		return nil, &FilterError{Field: "platform", Err: errors.New("must be desktop, mobile, or unknown")}
+	}
+
+	if data.Offset < 0 {
+		return nil, &FilterError{Field: "offset", Err: errors.New("must not be negative")}
+	}
+
+	if data.Limit < 0 {
+		return nil, &FilterError{Field: "limit", Err: errors.New("must not be negative")}
+	}
+
+	if data.MaxTimeOnPageSeconds < 0 {
+		return nil, &FilterError{Field: "max_time_on_page_seconds", Err: errors.New("must not be negative")}
+	}
+
+	if (data.VisitorID == 0) != (data.SessionID == 0) {
+		return nil, &FilterError{Field: "session_id", Err: errors.New("visitor_id and session_id must be used together")}
+	}
+
+	if len(data.EventMetaKey) > 0 && len(data.EventName) == 0 {
+		return nil, &FilterError{Field: "event_meta_key", Err: errors.New("must be used together with event")}
+	}
+
+	if data.CustomMetricKey != "" && (len(data.EventName) == 0 || data.CustomMetricType == "") {
+		return nil, &FilterError{Field: "custom_metric_key", Err: errors.New("must be used together with event and custom_metric_type")}
+	}
+
+	for _, country := range data.Country {
+		if n := len(strings.TrimPrefix(country, "!")); n != 2 && strings.ToLower(country) != "null" {
+			return nil, &FilterError{Field: "country", Err: fmt.Errorf("%q is not a two letter country code", country)}
+		}
+	}
+
+	for name, r := range map[string]struct {
+		value string
+		out   **Range
+	}{
+		"session_duration":   {data.SessionDuration, &filter.SessionDuration},
+		"session_page_views": {data.SessionPageViews, &filter.SessionPageViews},
+		"time_on_page":       {data.TimeOnPage, &filter.TimeOnPage},
+		"event_duration":     {data.EventDuration, &filter.EventDuration},
+	} {
+		if *r.out, err = decodeFilterRange(r.value); err != nil {
+			return nil, &FilterError{Field: name, Err: err}
+		}
+	}
+
+	if len(data.EventMetaRange) > 0 {
+		filter.EventMetaRange = make(map[string]Range, len(data.EventMetaRange))
+
+		for k, v := range data.EventMetaRange {
+			if filter.EventMetaRange[k], err = ParseRange(v); err != nil {
+				return nil, &FilterError{Field: "meta_range." + k, Err: err}
+			}
+		}
+	}
+
+	for _, condition := range data.SegmentConditions {
+		if condition == nil {
+			continue
+		}
+
+		c := SegmentCondition{
+			Event:    condition.Event,
+			Path:     condition.Path,
+			Not:      condition.Not,
+			Visitor:  condition.Visitor,
+			MinCount: condition.MinCount,
+			MaxCount: condition.MaxCount,
+		}
+
+		if !c.valid() {
+			return nil, &FilterError{Field: "segment_condition", Err: ErrInvalidSegment}
+		}
+
+		filter.SegmentConditions = append(filter.SegmentConditions, c)
+	}
+
+	if data.Expression != nil {
+		filter.Expression, err = data.Expression.expression()
+
+		if err != nil {
+			return nil, &FilterError{Field: "expr", Err: err}
+		}
+
+		if err := filter.Expression.validate(); err != nil {
+			return nil, &FilterError{Field: "expr", Err: err}
+		}
+	}
+
+	for _, search := range data.Search {
+		filter.Search = append(filter.Search, Search{
+			Field: searchSortFields[search.field],
+			Input: search.input,
+		})
+	}
+
+	for _, sort := range data.Sort {
+		filter.Sort = append(filter.Sort, Sort{
+			Field:     searchSortFields[sort.field],
+			Direction: pkg.Direction(strings.ToUpper(sort.direction)),
+		})
+	}
+
+	return filter, nil
+}
+
+func (data *filterData) encodeValues() url.Values {
+	values := make(url.Values)
+	v := reflect.ValueOf(data).Elem()
+	t := v.Type()
+
+	for i := range t.NumField() {
+		name := filterDataFieldName(t.Field(i))
+		field := v.Field(i)
+
+		if field.IsZero() {
+			continue
+		}
+
+		switch value := field.Interface().(type) {
+		case []string:
+			values[name] = value
+		case map[string]string:
+			for k, val := range value {
+				values.Set(name+"."+k, val)
+			}
+		case []filterSearch:
+			for _, s := range value {
+				text, _ := s.MarshalText()
+				values.Add(name, string(text))
+			}
+		case []filterSort:
+			for _, s := range value {
+				text, _ := s.MarshalText()
+				values.Add(name, string(text))
+			}
+		case []*filterSegment:
+			for _, c := range value {
+				condition, _ := json.Marshal(c)
+				values.Add(name, string(condition))
+			}
+		case *filterExpression:
+			expr, _ := json.Marshal(value)
+			values.Set(name, string(expr))
+		default:
+			values.Set(name, fmt.Sprint(value))
+		}
+	}
+
+	return values
+}
+
+func (data *filterData) decodeValues(values url.Values) error {
+	v := reflect.ValueOf(data).Elem()
+	t := v.Type()
+
+	for i := range t.NumField() {
+		name := filterDataFieldName(t.Field(i))
+		field := v.Field(i)
+
+		if field.Kind() == reflect.Map {
+			m := make(map[string]string)
+
+			for key, val := range values {
+				if k, found := strings.CutPrefix(key, name+"."); found && k != "" && len(val) > 0 {
+					m[k] = val[0]
+				}
+			}
+
+			if len(m) > 0 {
+				field.Set(reflect.ValueOf(m))
+			}
+
+			continue
+		}
+
+		list := values[name]
+
+		if len(list) == 0 {
+			continue
+		}
+
+		if field.Kind() == reflect.Slice {
+			slice := reflect.MakeSlice(field.Type(), len(list), len(list))
+
+			for j, val := range list {
+				if err := decodeFilterValue(name, slice.Index(j), val); err != nil {
+					return err
+				}
+			}
+
+			field.Set(slice)
+		} else if err := decodeFilterValue(name, field, list[0]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodeFilterValue(name string, field reflect.Value, value string) error {
+	if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+
+	switch field.Kind() {
+	case reflect.Pointer:
+		v := reflect.New(field.Type().Elem())
+
+		if err := json.Unmarshal([]byte(value), v.Interface()); err != nil {
+			return &FilterError{Field: name, Err: errors.New("must be valid JSON")}
+		}
+
+		field.Set(v)
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+
+		if err != nil {
+			return &FilterError{Field: name, Err: errors.New("must be true or false")}
+		}
+
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+
+		if err != nil {
+			return &FilterError{Field: name, Err: errors.New("must be a number")}
+		}
+
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(value, 10, field.Type().Bits())
+
+		if err != nil {
+			return &FilterError{Field: name, Err: errors.New("must be a positive number")}
+		}
+
+		field.SetUint(i)
+	}
+
+	return nil
+}
+
+func filterDataFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return name
+}
+
+func newFilterExpression(expression *Expression) *filterExpression {
+	data := &filterExpression{
+		Value: slices.Clone(expression.Value),
+	}
+
+	if expression.Field.Name != "" {
+		data.Field = expressionFieldName(expression.Field)
+	}
+
+	for i := range expression.And {
+		data.And = append(data.And, *newFilterExpression(&expression.And[i]))
+	}
+
+	for i := range expression.Or {
+		data.Or = append(data.Or, *newFilterExpression(&expression.Or[i]))
+	}
+
+	if expression.Not != nil {
+		data.Not = newFilterExpression(expression.Not)
+	}
+
+	return data
+}
+
+func (data *filterExpression) expression() (*Expression, error) {
+	expression := &Expression{
+		Value: data.Value,
+	}
+
+	if data.Field != "" {
+		field, found := expressionFields[data.Field]
+
+		if !found {
+			return nil, fmt.Errorf("unknown field %q", data.Field)
+		}
+
+		expression.Field = field
+	}
+
+	for i := range data.And {
+		and, err := data.And[i].expression()
+
+		if err != nil {
+			return nil, err
+		}
+
+		expression.And = append(expression.And, *and)
+	}
+
+	for i := range data.Or {
+		or, err := data.Or[i].expression()
+
+		if err != nil {
+			return nil, err
+		}
+
+		expression.Or = append(expression.Or, *or)
+	}
+
+	if data.Not != nil {
+		not, err := data.Not.expression()
+
+		if err != nil {
+			return nil, err
+		}
+
+		expression.Not = not
+	}
+
+	return expression, nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (search filterSearch) MarshalText() ([]byte, error) {
+	return []byte(search.field + ":" + search.input), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (search *filterSearch) UnmarshalText(text []byte) error {
+	field, input, _ := strings.Cut(string(text), ":")
+
+	if _, found := searchSortFields[field]; !found {
+		return &FilterError{Field: "search", Err: fmt.Errorf("unknown field %q", field)}
+	}
+
+	search.field, search.input = field, input
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (sort filterSort) MarshalText() ([]byte, error) {
+	return []byte(sort.field + ":" + sort.direction), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (sort *filterSort) UnmarshalText(text []byte) error {
+	field, direction, _ := strings.Cut(string(text), ":")
+
+	if _, found := searchSortFields[field]; !found {
+		return &FilterError{Field: "sort", Err: fmt.Errorf("unknown field %q", field)}
+	}
+
+	direction = strings.ToLower(direction)
+
+	if direction == "" {
+		direction = "asc"
+	} else if direction != "asc" && direction != "desc" {
+		return &FilterError{Field: "sort", Err: fmt.Errorf("unknown direction %q", direction)}
+	}
+
+	sort.field, sort.direction = field, direction
+	return nil
+}
+
+// searchSortFieldName returns the encoded name for a search or sort field.
+func searchSortFieldName(field Field) string {
+	// the direction is set on the field when building the query
+	field.queryDirection = ""
+
+	for name, f := range searchSortFields {
+		f.queryDirection = ""
+
+		if f == field {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+// encodeFilterTime encodes dates as YYYY-MM-DD and times (including a date that is not at midnight UTC) as RFC 3339.
+func encodeFilterTime(t time.Time, includeTime bool) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	if !includeTime && t.Equal(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)) {
+		return t.Format(time.DateOnly)
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
+
+func encodeFilterRange(r *Range) string {
+	if r == nil {
+		return ""
+	}
+
+	return r.String()
+}
+
+func decodeFilterRange(value string) (*Range, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	r, err := ParseRange(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+func decodeFilterTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+
+	if err != nil {
+		return time.Time{}, errors.New("must be a date (YYYY-MM-DD) or time (RFC 3339)")
+	}
+
+	return t, nil
+}
@@ -0,0 +1,274 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_Values(t *testing.T) {
+	tz, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	filters := []*Filter{
+		{},
+		{
+			ClientID:      42,
+			Timezone:      tz,
+			From:          time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			To:            time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+			ImportedUntil: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			Period:        pkg.PeriodMonth,
+			Compare:       CompareCustom,
+			CompareFrom:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			CompareTo:     time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			Hostname:      []string{"example.com"},
+			Path:          []string{"/", "!/blog", "~foo", "^bar", "null"},
+			EntryPath:     []string{"/entry"},
+			Country:       []string{"de", "!us"},
+			Platform:      "!" + pkg.PlatformMobile,
+			Tags:          map[string]string{"author": "John", "type": "!post"},
+			EventName:     []string{"signup"},
+			EventMetaKey:  []string{"plan"},
+			EventMeta:     map[string]string{"plan": "pro"},
+			Expression: &Expression{Or: []Expression{
+				{And: []Expression{
+					{Field: FieldCountry, Value: []string{"de"}},
+					{Field: FieldChannel, Value: []string{"Paid Search"}},
+				}},
+				{Not: &Expression{Field: FieldUTMCampaign, Value: []string{"~spring"}}},
+			}},
+			SessionDuration: &Range{Operator: RangeGreater, Value: 60},
+			TimeOnPage:      &Range{Operator: RangeBetween, Value: 1.5, To: 30},
+			EventMetaRange:  map[string]Range{"amount": {Operator: RangeGreaterOrEqual, Value: 100}},
+			Search: []Search{
+				{Field: FieldPath, Input: "/blog:2024"},
+				{Field: FieldCountry, Input: "de"},
+			},
+			Sort: []Sort{
+				{Field: FieldVisitors, Direction: pkg.DirectionDESC},
+				{Field: FieldPath, Direction: pkg.DirectionASC},
+			},
+			Segment:              "buyers",
+			SegmentConditions:    []SegmentCondition{{Path: "/pricing"}, {Event: "signup", Not: true, MaxCount: 2}},
+			Offset:               10,
+			Limit:                20,
+			CustomMetricKey:      "amount",
+			CustomMetricType:     pkg.CustomMetricTypeFloat,
+			IncludeTitle:         true,
+			IncludeCR:            true,
+			WeekdayMode:          WeekdaySunday,
+			MaxTimeOnPageSeconds: 600,
+			Sample:               10_000,
+		},
+		{
+			From:        time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC),
+			To:          time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+			IncludeTime: true,
+			VisitorID:   123,
+			SessionID:   456,
+		},
+	}
+
+	for _, filter := range filters {
+		values := filter.Values()
+		decoded, err := FilterFromValues(values)
+		assert.NoError(t, err)
+		assert.True(t, filter.Equal(decoded), values.Encode())
+		assert.Equal(t, filter.Sort, decoded.Sort)
+		assert.Equal(t, filter.Search, decoded.Search)
+		assert.Equal(t, filter.WeekdayMode, decoded.WeekdayMode)
+		assert.Equal(t, filter.Values(), decoded.Values())
+	}
+
+	values := filters[1].Values()
+	assert.Equal(t, "2024-05-01", values.Get("from"))
+	assert.Equal(t, "Europe/Berlin", values.Get("tz"))
+	assert.Equal(t, "month", values.Get("period"))
+	assert.Equal(t, "John", values.Get("tags.author"))
+	assert.Equal(t, []string{"visitors:desc", "path:asc"}, values["sort"])
+	assert.Equal(t, "2024-05-01T08:30:00Z", filters[2].Values().Get("from"))
+	assert.JSONEq(t, `{"or":[{"and":[{"field":"country","value":["de"]},{"field":"channel","value":["Paid Search"]}]},{"not":{"field":"utm_campaign","value":["~spring"]}}]}`, values.Get("expr"))
+	assert.Len(t, values["segment_condition"], 2)
+	assert.JSONEq(t, `{"event":"signup","not":true,"max_count":2}`, values["segment_condition"][1])
+}
+
+func TestFilterFromValues(t *testing.T) {
+	values, err := url.ParseQuery("path=/&path=!/blog&tz=Europe/Berlin&from=2024-05-01&to=2024-05-31T12:00:00Z&period=week&tags.author=John&meta.plan=pro&event=signup&sort=views&include_cr=true&limit=5&unknown=1")
+	assert.NoError(t, err)
+	filter, err := FilterFromValues(values)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/", "!/blog"}, filter.Path)
+	assert.Equal(t, "Europe/Berlin", filter.Timezone.String())
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), filter.From)
+	assert.Equal(t, time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC), filter.To)
+	assert.Equal(t, pkg.PeriodWeek, filter.Period)
+	assert.Equal(t, map[string]string{"author": "John"}, filter.Tags)
+	assert.Equal(t, map[string]string{"plan": "pro"}, filter.EventMeta)
+	assert.Equal(t, []Sort{{Field: FieldViews, Direction: pkg.DirectionASC}}, filter.Sort)
+	assert.True(t, filter.IncludeCR)
+	assert.Equal(t, 5, filter.Limit)
+
+	for query, field := range map[string]string{
+		"tz=Mars/Olympus":                        "tz",
+		"from=yesterday":                         "from",
+		"to=2024-13-01":                          "to",
+		"period=decade":                          "period",
+		"compare=week":                           "compare",
+		"compare=custom&compare_from=2024-01-01": "compare",
+		"compare_to=2024-01-32":                  "compare_to",
+		"platform=tablet":                        "platform",
+		"custom_metric_type=string":              "custom_metric_type",
+		"custom_metric_key=amount":               "custom_metric_key",
+		"weekday_mode=friday":                    "weekday_mode",
+		"search=foo:bar":                         "search",
+		"sort=foo:asc":                           "sort",
+		"sort=path:up":                           "sort",
+		"offset=-1":                              "offset",
+		"limit=ten":                              "limit",
+		"include_cr=maybe":                       "include_cr",
+		"visitor_id=1":                           "session_id",
+		"session_id=-1":                          "session_id",
+		"event_meta_key=plan":                    "event_meta_key",
+		"country=germany":                        "country",
+		"max_time_on_page_seconds=-5":            "max_time_on_page_seconds",
+		"client_id=1&sample=-1&limit=10":         "sample",
+		"from=2024-05-01&imported_until=x":       "imported_until",
+		"session_duration=60":                    "session_duration",
+		"session_page_views=>three":              "session_page_views",
+		"time_on_page=1..":                       "time_on_page",
+		"event_duration=<=":                      "event_duration",
+		"meta_range.amount=100":                  "meta_range.amount",
+		"segment_condition=signup":               "segment_condition",
+		`segment_condition={"min_count":1}`:      "segment_condition",
+	} {
+		values, err := url.ParseQuery(query)
+		assert.NoError(t, err)
+		_, err = FilterFromValues(values)
+		var filterErr *FilterError
+		assert.True(t, errors.As(err, &filterErr), query)
+
+		if filterErr != nil {
+			assert.Equal(t, field, filterErr.Field, query)
+		}
+	}
+
+	for _, query := range []string{
+		"expr=country:de",
+		`expr={"field":"visitors","value":["1"]}`,
+		`expr={"field":"country"}`,
+		`expr={"field":"country","value":["de"],"not":{"field":"city","value":["Berlin"]}}`,
+		`expr={"and":[{"field":"entry_path","value":["/"]},{"field":"path","value":["/foo"]}]}`,
+		`expr={"or":[{"field":"city","value":["Berlin"]},{"and":[{"field":"os","value":["Linux"]},{}]}]}`,
+	} {
+		values, err := url.ParseQuery(query)
+		assert.NoError(t, err)
+		_, err = FilterFromValues(values)
+		var filterErr *FilterError
+		assert.True(t, errors.As(err, &filterErr), query)
+
+		if filterErr != nil {
+			assert.Equal(t, "expr", filterErr.Field, query)
+		}
+	}
+
+	for period, expected := range map[string]pkg.Period{
+		"hour":    pkg.PeriodHour,
+		"quarter": pkg.PeriodQuarter,
+	} {
+		filter, err := FilterFromValues(url.Values{"period": {period}})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, filter.Period)
+		assert.Equal(t, []string{period}, filter.Values()["period"])
+	}
+}
+
+func TestFilter_JSON(t *testing.T) {
+	filter := &Filter{
+		ClientID:  42,
+		From:      time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+		Period:    pkg.PeriodYear,
+		Path:      []string{"/", "~blog"},
+		Tags:      map[string]string{"author": "John"},
+		EventName: []string{"signup"},
+		Expression: &Expression{Or: []Expression{
+			{Field: FieldCountry, Value: []string{"de"}},
+			{Field: FieldEventName, Value: []string{"!signup"}},
+		}},
+		Search: []Search{{Field: FieldPath, Input: "blog"}},
+		Sort:   []Sort{{Field: FieldViews, Direction: pkg.DirectionDESC}},
+		Limit:  10,
+	}
+	data, err := json.Marshal(filter)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"client_id":42,"from":"2024-05-01","to":"2024-05-31","period":"year","path":["/","~blog"],"tags":{"author":"John"},"event":["signup"],"expr":{"or":[{"field":"country","value":["de"]},{"field":"event","value":["!signup"]}]},"search":["path:blog"],"sort":["views:desc"],"limit":10}`, string(data))
+	var decoded Filter
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, filter.Equal(&decoded))
+	assert.Equal(t, filter.Sort, decoded.Sort)
+	assert.Equal(t, filter.Search, decoded.Search)
+	filter = &Filter{SessionPageViews: &Range{Operator: RangeGreaterOrEqual, Value: 3}}
+	data, err = json.Marshal(filter)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"session_page_views":">=3"}`, string(data))
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, filter.SessionPageViews, decoded.SessionPageViews)
+	filter = &Filter{SegmentConditions: []SegmentCondition{{Path: "/pricing", Visitor: true, MinCount: 2}}}
+	data, err = json.Marshal(filter)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"segment_condition":[{"path":"/pricing","visitor":true,"min_count":2}]}`, string(data))
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, filter.SegmentConditions, decoded.SegmentConditions)
+
+	var filterErr *FilterError
+	assert.True(t, errors.As(json.Unmarshal([]byte(`{"period":"decade"}`), &decoded), &filterErr))
+	assert.Equal(t, "period", filterErr.Field)
+	assert.True(t, errors.As(json.Unmarshal([]byte(`{"limit":"ten"}`), &decoded), &filterErr))
+	assert.Equal(t, "limit", filterErr.Field)
+	assert.True(t, errors.As(json.Unmarshal([]byte(`{"sort":["foo:asc"]}`), &decoded), &filterErr))
+	assert.Equal(t, "sort", filterErr.Field)
+	assert.True(t, errors.As(json.Unmarshal([]byte(`{"expr":{"field":"foo","value":["bar"]}}`), &decoded), &filterErr))
+	assert.Equal(t, "expr", filterErr.Field)
+	assert.Error(t, json.Unmarshal([]byte(`{"expr":{"field":"country","values":["de"]}}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"unknown":true}`), &decoded))
+}
+
+func TestFilter_Hash(t *testing.T) {
+	a := &Filter{
+		ClientID: 1,
+		From:     time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Path:     []string{"/foo", "/bar"},
+		Tags:     map[string]string{"a": "1", "b": "2"},
+		Search:   []Search{{Field: FieldPath, Input: "foo"}, {Field: FieldCountry, Input: "de"}},
+		Sort:     []Sort{{Field: FieldVisitors, Direction: pkg.DirectionDESC}, {Field: FieldPath, Direction: pkg.DirectionASC}},
+	}
+	b := &Filter{
+		ClientID: 1,
+		From:     time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Path:     []string{"/bar", "/foo", "/bar"},
+		Tags:     map[string]string{"b": "2", "a": "1"},
+		Search:   []Search{{Field: FieldCountry, Input: "de"}, {Field: FieldPath, Input: "foo"}},
+		Sort:     []Sort{{Field: FieldVisitors, Direction: pkg.DirectionDESC}, {Field: FieldPath, Direction: pkg.DirectionASC}},
+	}
+	assert.Len(t, a.Hash(), 64)
+	assert.Equal(t, a.Hash(), a.Hash())
+	assert.Equal(t, a.Hash(), b.Hash())
+	assert.Equal(t, []string{"/foo", "/bar"}, a.Path)
+	assert.Equal(t, FieldPath, a.Search[0].Field)
+	b.Sort[0], b.Sort[1] = b.Sort[1], b.Sort[0]
+	assert.NotEqual(t, a.Hash(), b.Hash())
+	b.Sort = a.Sort
+	b.Limit = 10
+	assert.NotEqual(t, a.Hash(), b.Hash())
+	a = &Filter{ClientID: 1, Tag: []string{"author", "type"}}
+	b = &Filter{ClientID: 1, Tag: []string{"type", "author"}}
+	assert.NotEqual(t, a.Hash(), b.Hash())
+	a = &Filter{ClientID: 1, EventMetaKey: []string{"a", "b"}}
+	b = &Filter{ClientID: 1, EventMetaKey: []string{"b", "a"}}
+	assert.NotEqual(t, a.Hash(), b.Hash())
+}
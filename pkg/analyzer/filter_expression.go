@@ -0,0 +1,194 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// expressionFields are the fields that can be used in an Expression, by the name used for encoding.
+var expressionFields = map[string]Field{
+	"hostname":        FieldHostname,
+	"path":            FieldPath,
+	"entry_path":      FieldEntryPath,
+	"exit_path":       FieldExitPath,
+	"language":        FieldLanguage,
+	"country":         FieldCountry,
+	"region":          FieldRegion,
+	"city":            FieldCity,
+	"referrer":        FieldReferrer,
+	"referrer_name":   FieldReferrerName,
+	"channel":         FieldChannel,
+	"os":              FieldOS,
+	"os_version":      FieldOSVersion,
+	"browser":         FieldBrowser,
+	"browser_version": FieldBrowserVersion,
+	"screen_class":    FieldScreenClass,
+	"utm_source":      FieldUTMSource,
+	"utm_medium":      FieldUTMMedium,
+	"utm_campaign":    FieldUTMCampaign,
+	"utm_content":     FieldUTMContent,
+	"utm_term":        FieldUTMTerm,
+	"event":           FieldEventName,
+}
+
+// Expression is a boolean filter expression.
+// Each node is either a group of expressions combined using And or Or, a negated expression (Not),
+// or a predicate comparing a Field to a list of values.
+// The values of a predicate use the same syntax as the Filter fields (like "!" to invert a value or "~" for contains),
+// so a predicate behaves exactly like setting the corresponding Filter field.
+//
+// Example for (country = de AND channel = Paid Search) OR utm_campaign = spring:
+//
+//	&Expression{Or: []Expression{
+//		{And: []Expression{
+//			{Field: FieldCountry, Value: []string{"de"}},
+//			{Field: FieldChannel, Value: []string{"Paid Search"}},
+//		}},
+//		{Field: FieldUTMCampaign, Value: []string{"spring"}},
+//	}}
+//
+// Supported fields are the hostname, path, entry and exit path, language, country, region, city, referrer, referrer name,
+// channel, OS, browser (and their versions), screen class, UTM parameters, and the event name.
+// Predicates on the entry or exit path cannot be combined with predicates on the path or event name within one expression.
+type Expression struct {
+	And   []Expression
+	Or    []Expression
+	Not   *Expression
+	Field Field
+	Value []string
+}
+
+// contains returns whether the expression contains a predicate for given field.
+func (expression *Expression) contains(field Field) bool {
+	if expression == nil {
+		return false
+	}
+
+	if expression.Field.Name != "" && expression.Field.Name == field.Name {
+		return true
+	}
+
+	for i := range expression.And {
+		if expression.And[i].contains(field) {
+			return true
+		}
+	}
+
+	for i := range expression.Or {
+		if expression.Or[i].contains(field) {
+			return true
+		}
+	}
+
+	return expression.Not.contains(field)
+}
+
+// fields returns the distinct column names used by the expression in the order they appear.
+func (expression *Expression) fields() []string {
+	fields := make([]string, 0)
+	expression.collectFields(&fields)
+	return fields
+}
+
+func (expression *Expression) collectFields(fields *[]string) {
+	if expression == nil {
+		return
+	}
+
+	if isExpressionField(expression.Field) && !slices.Contains(*fields, expression.Field.Name) {
+		*fields = append(*fields, expression.Field.Name)
+	}
+
+	for i := range expression.And {
+		expression.And[i].collectFields(fields)
+	}
+
+	for i := range expression.Or {
+		expression.Or[i].collectFields(fields)
+	}
+
+	expression.Not.collectFields(fields)
+}
+
+// validate returns an error if the expression cannot be evaluated.
+func (expression *Expression) validate() error {
+	if expression.contains(FieldEntryPath) || expression.contains(FieldExitPath) {
+		if expression.contains(FieldPath) || expression.contains(FieldEventName) {
+			return errors.New("entry_path and exit_path cannot be combined with path or event")
+		}
+	}
+
+	return expression.validateNode()
+}
+
+func (expression *Expression) validateNode() error {
+	n := 0
+
+	if len(expression.And) > 0 {
+		n++
+	}
+
+	if len(expression.Or) > 0 {
+		n++
+	}
+
+	if expression.Not != nil {
+		n++
+	}
+
+	if expression.Field.Name != "" {
+		n++
+
+		if !isExpressionField(expression.Field) {
+			return fmt.Errorf("unsupported field %q", expression.Field.Name)
+		}
+
+		if len(expression.Value) == 0 {
+			return fmt.Errorf("missing value for field %q", expression.Field.Name)
+		}
+	}
+
+	if n != 1 {
+		return errors.New("each node must have exactly one of and, or, not, or field")
+	}
+
+	for i := range expression.And {
+		if err := expression.And[i].validateNode(); err != nil {
+			return err
+		}
+	}
+
+	for i := range expression.Or {
+		if err := expression.Or[i].validateNode(); err != nil {
+			return err
+		}
+	}
+
+	if expression.Not != nil {
+		return expression.Not.validateNode()
+	}
+
+	return nil
+}
+
+func isExpressionField(field Field) bool {
+	for _, f := range expressionFields {
+		if f.Name == field.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expressionFieldName returns the encoded name for an expression field.
+func expressionFieldName(field Field) string {
+	for name, f := range expressionFields {
+		if f.Name == field.Name {
+			return name
+		}
+	}
+
+	return field.Name
+}
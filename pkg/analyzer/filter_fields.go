@@ -46,17 +46,20 @@ var (
 
 	// FieldCount is a query result column.
 	FieldCount = Field{
-		querySessions:  "count(*)",
-		queryPageViews: "count(*)",
-		Name:           "count",
-		queryDirection: "DESC",
-		sampleType:     sampleTypeInt,
+		querySessions:    "count(*)",
+		queryPageViews:   "count(*)",
+		queryImported:    "sum(t.count + imp.count)",
+		subqueryImported: "sum(events)",
+		Name:             "count",
+		queryDirection:   "DESC",
+		sampleType:       sampleTypeInt,
 	}
 
 	// FieldHostname is a query result column.
 	FieldHostname = Field{
 		querySessions:  "t.hostname",
 		queryPageViews: "t.hostname",
+		queryImported:  "coalesce(nullif(t.hostname, ''), imp.hostname)",
 		queryDirection: "ASC",
 		Name:           "hostname",
 	}
@@ -244,6 +247,38 @@ var (
 		Name:           "bounce_rate",
 	}
 
+	// FieldEngagedSessions is a query result column.
+	// Imported statistics don't contain engaged sessions.
+	FieldEngagedSessions = Field{
+		querySessions:  "sum(is_engaged*sign)",
+		queryPageViews: "uniqIf((t.visitor_id, t.session_id), engaged_sessions = 1)",
+		queryImported:  "sum(t.engaged_sessions)",
+		queryPeriod:    "sum(engaged_sessions)",
+		queryDirection: "DESC",
+		sampleType:     sampleTypeInt,
+		Name:           "engaged_sessions",
+	}
+
+	// FieldEngagementRate is a query result column.
+	FieldEngagementRate = Field{
+		querySessions:  "engaged_sessions / IF(sessions = 0, 1, sessions)",
+		queryPageViews: "engaged_sessions / IF(sessions = 0, 1, sessions)",
+		queryImported:  "engaged_sessions / IF(sum(t.sessions) = 0, 1, sum(t.sessions))",
+		queryPeriod:    "avg(engagement_rate)",
+		queryDirection: "DESC",
+		Name:           "engagement_rate",
+	}
+
+	// FieldPagesPerSession is a query result column.
+	FieldPagesPerSession = Field{
+		querySessions:  "views / IF(sessions = 0, 1, sessions)",
+		queryPageViews: "views / IF(sessions = 0, 1, sessions)",
+		queryImported:  "views / IF(sessions = 0, 1, sessions)",
+		queryPeriod:    "avg(pages_per_session)",
+		queryDirection: "DESC",
+		Name:           "pages_per_session",
+	}
+
 	// FieldReferrer is a query result column.
 	FieldReferrer = Field{
 		querySessions:  "referrer",
@@ -387,6 +422,7 @@ var (
 	FieldBrowserVersion = Field{
 		querySessions:  "browser_version",
 		queryPageViews: "browser_version",
+		queryImported:  "coalesce(nullif(t.browser_version, ''), imp.browser_version)",
 		queryDirection: "ASC",
 		Name:           "browser_version",
 	}
@@ -404,6 +440,7 @@ var (
 	FieldOSVersion = Field{
 		querySessions:  "os_version",
 		queryPageViews: "os_version",
+		queryImported:  "coalesce(nullif(t.os_version, ''), imp.os_version)",
 		queryDirection: "ASC",
 		Name:           "os_version",
 	}
@@ -447,6 +484,7 @@ var (
 	FieldUTMContent = Field{
 		querySessions:  "utm_content",
 		queryPageViews: "utm_content",
+		queryImported:  "coalesce(nullif(t.utm_content, ''), imp.utm_content)",
 		queryDirection: "ASC",
 		Name:           "utm_content",
 	}
@@ -455,6 +493,7 @@ var (
 	FieldUTMTerm = Field{
 		querySessions:  "utm_term",
 		queryPageViews: "utm_term",
+		queryImported:  "coalesce(nullif(t.utm_term, ''), imp.utm_term)",
 		queryDirection: "ASC",
 		Name:           "utm_term",
 	}
@@ -463,6 +502,7 @@ var (
 	FieldChannel = Field{
 		querySessions:  "t.channel",
 		queryPageViews: "t.channel",
+		queryImported:  "coalesce(nullif(t.channel, ''), imp.channel)",
 		queryDirection: "ASC",
 		Name:           "channel",
 	}
@@ -483,16 +523,20 @@ var (
 
 	// FieldTagKey is a query result column.
 	FieldTagKey = Field{
-		querySessions:  "arrayJoin(tag_keys)",
-		queryPageViews: "arrayJoin(tag_keys)",
-		Name:           "key",
+		querySessions:    "arrayJoin(tag_keys)",
+		queryPageViews:   "arrayJoin(tag_keys)",
+		queryImported:    "coalesce(nullif(t.key, ''), imp.key)",
+		subqueryImported: "tag_key",
+		Name:             "key",
 	}
 
 	// FieldTagValue is a query result column.
 	FieldTagValue = Field{
-		querySessions:  "tag_values[indexOf(tag_keys, ?)]",
-		queryPageViews: "tag_values[indexOf(tag_keys, ?)]",
-		Name:           "value",
+		querySessions:    "tag_values[indexOf(tag_keys, ?)]",
+		queryPageViews:   "tag_values[indexOf(tag_keys, ?)]",
+		queryImported:    "coalesce(nullif(t.value, ''), imp.value)",
+		subqueryImported: "tag_value",
+		Name:             "value",
 	}
 
 	// FieldTitle is a query result column.
@@ -568,7 +612,8 @@ var (
 	FieldHour = Field{
 		querySessions:    "toHour(time, '%s')",
 		queryPageViews:   "toHour(time, '%s')",
-		subqueryImported: "0",
+		queryImported:    "greatest(t.hour, imp.hour)",
+		subqueryImported: "hour",
 		queryDirection:   "ASC",
 		queryWithFill:    "WITH FILL FROM 0 TO 24",
 		timezone:         true,
@@ -599,6 +644,7 @@ var (
 	FieldEventName = Field{
 		querySessions:  "event_name",
 		queryPageViews: "event_name",
+		queryImported:  "coalesce(nullif(t.event_name, ''), imp.event_name)",
 		Name:           "event_name",
 		queryDirection: "ASC",
 	}
@@ -614,6 +660,7 @@ var (
 	FieldEventMetaKeys = Field{
 		querySessions:  "groupUniqArrayArray(event_meta_keys)",
 		queryPageViews: "groupUniqArrayArray(event_meta_keys)",
+		queryImported:  "any(t.meta_keys)",
 		Name:           "meta_keys",
 	}
 
@@ -642,6 +689,7 @@ var (
 	FieldEventTimeSpent = Field{
 		querySessions:  "toUInt64(avg(duration_seconds))",
 		queryPageViews: "toUInt64(avg(duration_seconds))",
+		queryImported:  "any(t.average_time_spent_seconds)",
 		sampleType:     sampleTypeInt,
 		Name:           "average_time_spent_seconds",
 	}
@@ -664,6 +712,14 @@ var (
 		Name:           "custom_metric_total",
 	}
 
+	// FieldPlatform is a query result column.
+	FieldPlatform = Field{
+		querySessions:  "multiIf(desktop = 1, 'desktop', mobile = 1, 'mobile', 'unknown')",
+		queryPageViews: "multiIf(desktop = 1, 'desktop', mobile = 1, 'mobile', 'unknown')",
+		queryDirection: "ASC",
+		Name:           "platform",
+	}
+
 	// FieldPlatformDesktop is a query result column.
 	FieldPlatformDesktop = Field{
 		querySessions:    "uniqIf(visitor_id, desktop = 1)",
@@ -728,6 +784,13 @@ var (
 		sampleType:     sampleTypeInt,
 		Name:           "duration_seconds",
 	}
+
+	// FieldPathGroup is a query result column.
+	// The query is set by the content group or directory the pages are grouped by.
+	FieldPathGroup = Field{
+		queryDirection: "ASC",
+		Name:           "path_group",
+	}
 )
 
 const (
@@ -744,6 +807,7 @@ const (
 		t.exit_path session_exit_path,
 		max(t.page_views) session_page_views,
 		min(t.is_bounce) session_is_bounce,
+		max(t.is_engaged) session_is_engaged,
 		any(t.entry_title) session_entry_title,
 		t.exit_title session_exit_title,
 		any(t.language) session_language,
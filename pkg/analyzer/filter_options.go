@@ -111,7 +111,12 @@ func (options *FilterOptions) EventMetadataKeys(filter *Filter, search string) (
 		return []string{}, nil
 	}
 
-	filter = options.analyzer.getFilter(filter)
+	filter, err := options.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	search = strings.TrimSpace(search)
 	timeQuery, args := filter.buildTimeQuery()
 	builder := queryBuilder{
@@ -146,7 +151,12 @@ func (options *FilterOptions) EventMetadataValues(filter *Filter, search string)
 		return []string{}, nil
 	}
 
-	filter = options.analyzer.getFilter(filter)
+	filter, err := options.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	search = strings.TrimSpace(search)
 	timeQuery, args := filter.buildTimeQuery()
 	builder := queryBuilder{
@@ -182,7 +192,12 @@ func (options *FilterOptions) TagKeys(filter *Filter, search string) ([]string,
 		return []string{}, nil
 	}
 
-	filter = options.analyzer.getFilter(filter)
+	filter, err := options.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	search = strings.TrimSpace(search)
 	timeQuery, args := filter.buildTimeQuery()
 	searchQuery := ""
@@ -208,7 +223,12 @@ func (options *FilterOptions) TagValues(filter *Filter, search string) ([]string
 		return []string{}, nil
 	}
 
-	filter = options.analyzer.getFilter(filter)
+	filter, err := options.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	search = strings.TrimSpace(search)
 	args := make([]any, 0)
 	args = append(args, filter.Tag[0])
@@ -234,7 +254,12 @@ func (options *FilterOptions) TagValues(filter *Filter, search string) ([]string
 }
 
 func (options *FilterOptions) selectFilterOptions(filter *Filter, field, table, search string) ([]string, error) {
-	filter = options.analyzer.getFilter(filter)
+	filter, err := options.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	search = strings.TrimSpace(search)
 	timeQuery, args := filter.buildTimeQuery()
 	query := fmt.Sprintf(`SELECT DISTINCT %s FROM %s %s `, field, table, timeQuery)
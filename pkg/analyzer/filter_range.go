@@ -0,0 +1,163 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// RangeGreater matches values greater than Range.Value.
+	RangeGreater = RangeOperator(">")
+
+	// RangeGreaterOrEqual matches values greater than or equal to Range.Value.
+	RangeGreaterOrEqual = RangeOperator(">=")
+
+	// RangeLess matches values less than Range.Value.
+	RangeLess = RangeOperator("<")
+
+	// RangeLessOrEqual matches values less than or equal to Range.Value.
+	RangeLessOrEqual = RangeOperator("<=")
+
+	// RangeBetween matches values between Range.Value and Range.To (both inclusive).
+	RangeBetween = RangeOperator("between")
+)
+
+// RangeOperator is the comparison used for a Range.
+type RangeOperator string
+
+// Range is a predicate for a numeric value.
+type Range struct {
+	Operator RangeOperator
+	Value    float64
+
+	// To is the upper bound for RangeBetween. The bounds are swapped if To is less than Value.
+	To float64
+}
+
+// ParseRange parses a Range from its string representation.
+// Supported formats are >60, >=60, <60, <=60, and 10..20 for values between 10 and 20.
+func ParseRange(value string) (Range, error) {
+	value = strings.TrimSpace(value)
+	var r Range
+
+	for _, operator := range []RangeOperator{RangeGreaterOrEqual, RangeLessOrEqual, RangeGreater, RangeLess} {
+		if v, found := strings.CutPrefix(value, string(operator)); found {
+			n, err := parseRangeValue(v)
+
+			if err != nil {
+				return r, err
+			}
+
+			r.Operator, r.Value = operator, n
+			return r, nil
+		}
+	}
+
+	from, to, found := strings.Cut(value, "..")
+
+	if !found {
+		return r, errors.New("must be >n, >=n, <n, <=n, or n..m")
+	}
+
+	n, err := parseRangeValue(from)
+
+	if err != nil {
+		return r, err
+	}
+
+	m, err := parseRangeValue(to)
+
+	if err != nil {
+		return r, err
+	}
+
+	r.Operator, r.Value, r.To = RangeBetween, n, m
+	return r, nil
+}
+
+// String returns the string representation of the range that can be parsed using ParseRange.
+func (r Range) String() string {
+	if r.Operator == RangeBetween {
+		return fmt.Sprintf("%s..%s", formatRangeValue(r.Value), formatRangeValue(r.To))
+	}
+
+	return string(r.Operator) + formatRangeValue(r.Value)
+}
+
+// validate returns an error if the operator is unknown or a bound is not a finite number.
+func (r Range) validate() error {
+	if !isFinite(r.Value) || (r.Operator == RangeBetween && !isFinite(r.To)) {
+		return errors.New("bounds must be finite numbers")
+	}
+
+	switch r.Operator {
+	case RangeGreater, RangeGreaterOrEqual, RangeLess, RangeLessOrEqual, RangeBetween:
+		return nil
+	}
+
+	return fmt.Errorf("unknown operator %q", r.Operator)
+}
+
+// where returns the condition for given column and its arguments.
+// An empty string is returned for unknown operators, which are rejected by validate.
+func (r Range) where(column string) (string, []any) {
+	switch r.Operator {
+	case RangeGreater, RangeGreaterOrEqual, RangeLess, RangeLessOrEqual:
+		return fmt.Sprintf("%s %s ? ", column, r.Operator), []any{r.Value}
+	case RangeBetween:
+		return fmt.Sprintf("%s BETWEEN ? AND ? ", column), []any{min(r.Value, r.To), max(r.Value, r.To)}
+	}
+
+	return "", nil
+}
+
+// validateRanges returns an error for the first invalid range of the filter.
+// The ranges are checked in a fixed order, and the event metadata ranges are checked ordered by key.
+func (filter *Filter) validateRanges() error {
+	for _, r := range []struct {
+		name  string
+		value *Range
+	}{
+		{"session_duration", filter.SessionDuration},
+		{"session_page_views", filter.SessionPageViews},
+		{"time_on_page", filter.TimeOnPage},
+		{"event_duration", filter.EventDuration},
+	} {
+		if r.value != nil {
+			if err := r.value.validate(); err != nil {
+				return &FilterError{Field: r.name, Err: err}
+			}
+		}
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(filter.EventMetaRange)) {
+		if err := filter.EventMetaRange[k].validate(); err != nil {
+			return &FilterError{Field: "meta_range." + k, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func parseRangeValue(value string) (float64, error) {
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+
+	if err != nil || !isFinite(n) {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+
+	return n, nil
+}
+
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+func formatRangeValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
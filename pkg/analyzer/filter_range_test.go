@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRange(t *testing.T) {
+	for value, expected := range map[string]Range{
+		">60":      {Operator: RangeGreater, Value: 60},
+		">=3":      {Operator: RangeGreaterOrEqual, Value: 3},
+		"<5":       {Operator: RangeLess, Value: 5},
+		"<= 2.5":   {Operator: RangeLessOrEqual, Value: 2.5},
+		"10..20":   {Operator: RangeBetween, Value: 10, To: 20},
+		"-5..-1.5": {Operator: RangeBetween, Value: -5, To: -1.5},
+	} {
+		r, err := ParseRange(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, r, value)
+		parsed, err := ParseRange(r.String())
+		assert.NoError(t, err)
+		assert.Equal(t, r, parsed)
+	}
+
+	for _, value := range []string{"", "60", "=60", ">", ">abc", "1..", "..2", "a..b", ">NaN", "<=Inf", ">-Inf", "1..+Inf", "nan..2"} {
+		_, err := ParseRange(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestFilter_ValidateRanges(t *testing.T) {
+	valid := &Range{Operator: RangeGreater, Value: 1}
+	filter := &Filter{
+		SessionDuration:  valid,
+		SessionPageViews: &Range{Operator: RangeBetween, Value: 1, To: math.Inf(1)},
+		TimeOnPage:       &Range{Operator: "="},
+		EventDuration:    &Range{Operator: RangeLess, Value: math.NaN()},
+		EventMetaRange: map[string]Range{
+			"c": {Operator: "="},
+			"a": {Operator: RangeGreater, Value: math.Inf(-1)},
+			"b": {Operator: "="},
+		},
+	}
+	var filterErr *FilterError
+
+	for _, field := range []string{"session_page_views", "time_on_page", "event_duration", "meta_range.a", "meta_range.b", "meta_range.c"} {
+		for range 10 {
+			err := filter.validateRanges()
+			assert.True(t, errors.As(err, &filterErr))
+			assert.Equal(t, field, filterErr.Field)
+		}
+
+		switch field {
+		case "session_page_views":
+			filter.SessionPageViews = valid
+		case "time_on_page":
+			filter.TimeOnPage = valid
+		case "event_duration":
+			filter.EventDuration = valid
+		default:
+			delete(filter.EventMetaRange, field[len("meta_range."):])
+		}
+	}
+
+	assert.NoError(t, filter.validateRanges())
+}
@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -163,7 +164,7 @@ func TestFilter_BuildQuery(t *testing.T) {
 
 	// no filter (from page views)
 	analyzer := NewAnalyzer(dbClient)
-	q, args := analyzer.getFilter(nil).buildQuery([]Field{FieldPath, FieldVisitors},
+	q, args := getValidFilter(t, analyzer, nil).buildQuery([]Field{FieldPath, FieldVisitors},
 		[]Field{FieldPath}, []Field{FieldVisitors, FieldPath}, nil, "")
 	var stats []model.PageStats
 	rows, err := dbClient.Query(q, args...)
@@ -184,7 +185,7 @@ func TestFilter_BuildQuery(t *testing.T) {
 	assert.Equal(t, "/foo", stats[2].Path)
 
 	// join (from page views)
-	q, args = analyzer.getFilter(&Filter{EntryPath: []string{"/"}}).buildQuery([]Field{FieldPath, FieldVisitors}, []Field{FieldPath}, []Field{FieldPath}, nil, "")
+	q, args = getValidFilter(t, analyzer, &Filter{EntryPath: []string{"/"}}).buildQuery([]Field{FieldPath, FieldVisitors}, []Field{FieldPath}, []Field{FieldPath}, nil, "")
 	stats = stats[:0]
 	rows, err = dbClient.Query(q, args...)
 	assert.NoError(t, err)
@@ -204,7 +205,7 @@ func TestFilter_BuildQuery(t *testing.T) {
 	assert.Equal(t, "/foo", stats[2].Path)
 
 	// join and filter (from page views)
-	q, args = analyzer.getFilter(&Filter{EntryPath: []string{"/"}, Path: []string{"/foo"}}).buildQuery([]Field{FieldPath, FieldVisitors}, []Field{FieldPath}, []Field{FieldPath}, nil, "")
+	q, args = getValidFilter(t, analyzer, &Filter{EntryPath: []string{"/"}, Path: []string{"/foo"}}).buildQuery([]Field{FieldPath, FieldVisitors}, []Field{FieldPath}, []Field{FieldPath}, nil, "")
 	stats = stats[:0]
 	rows, err = dbClient.Query(q, args...)
 	assert.NoError(t, err)
@@ -220,7 +221,7 @@ func TestFilter_BuildQuery(t *testing.T) {
 	assert.Equal(t, 1, stats[0].Visitors)
 
 	// filter (from page views)
-	q, args = analyzer.getFilter(&Filter{Path: []string{"/foo"}}).buildQuery([]Field{FieldPath, FieldVisitors}, []Field{FieldPath}, []Field{FieldPath}, nil, "")
+	q, args = getValidFilter(t, analyzer, &Filter{Path: []string{"/foo"}}).buildQuery([]Field{FieldPath, FieldVisitors}, []Field{FieldPath}, []Field{FieldPath}, nil, "")
 	stats = stats[:0]
 	rows, err = dbClient.Query(q, args...)
 	assert.NoError(t, err)
@@ -236,7 +237,7 @@ func TestFilter_BuildQuery(t *testing.T) {
 	assert.Equal(t, 2, stats[0].Visitors)
 
 	// no filter (from sessions)
-	q, args = analyzer.getFilter(nil).buildQuery([]Field{FieldVisitors, FieldSessions, FieldViews, FieldBounces, FieldBounceRate}, nil, nil, nil, "")
+	q, args = getValidFilter(t, analyzer, nil).buildQuery([]Field{FieldVisitors, FieldSessions, FieldViews, FieldBounces, FieldBounceRate}, nil, nil, nil, "")
 	var vstats model.PageStats
 	assert.NoError(t, dbClient.QueryRow(q, args...).Scan(&vstats.Visitors, &vstats.Sessions, &vstats.Views, &vstats.Bounces, &vstats.BounceRate))
 	assert.Equal(t, 2, vstats.Visitors)
@@ -246,7 +247,7 @@ func TestFilter_BuildQuery(t *testing.T) {
 	assert.InDelta(t, 0, vstats.BounceRate, 0.01)
 
 	// filter (from page views)
-	q, args = analyzer.getFilter(&Filter{Path: []string{"/foo"}, EntryPath: []string{"/"}}).buildQuery([]Field{FieldVisitors, FieldRelativeVisitors, FieldSessions, FieldViews, FieldRelativeViews, FieldBounces, FieldBounceRate}, nil, nil, nil, "")
+	q, args = getValidFilter(t, analyzer, &Filter{Path: []string{"/foo"}, EntryPath: []string{"/"}}).buildQuery([]Field{FieldVisitors, FieldRelativeVisitors, FieldSessions, FieldViews, FieldRelativeViews, FieldBounces, FieldBounceRate}, nil, nil, nil, "")
 	assert.NoError(t, dbClient.QueryRow(q, args...).Scan(&vstats.Visitors, &vstats.RelativeVisitors, &vstats.Sessions, &vstats.Views, &vstats.RelativeViews, &vstats.Bounces, &vstats.BounceRate))
 	assert.Equal(t, 1, vstats.Visitors)
 	assert.Equal(t, 1, vstats.Sessions)
@@ -257,7 +258,7 @@ func TestFilter_BuildQuery(t *testing.T) {
 	assert.InDelta(t, 0.3333, vstats.RelativeViews, 0.01)
 
 	// filter period
-	q, args = analyzer.getFilter(&Filter{Period: pkg.PeriodWeek}).buildQuery([]Field{FieldDay, FieldVisitors}, []Field{FieldDay}, []Field{FieldDay}, nil, "")
+	q, args = getValidFilter(t, analyzer, &Filter{Period: pkg.PeriodWeek}).buildQuery([]Field{FieldDay, FieldVisitors}, []Field{FieldDay}, []Field{FieldDay}, nil, "")
 	var visitors []model.VisitorStats
 	rows, err = dbClient.Query(q, args...)
 	assert.NoError(t, err)
@@ -271,7 +272,7 @@ func TestFilter_BuildQuery(t *testing.T) {
 	assert.Len(t, visitors, 1)
 
 	// join and filter with sampling (from page views)
-	q, args = analyzer.getFilter(&Filter{EntryPath: []string{"/"}, Path: []string{"/foo"}, Sample: 10_000_000}).buildQuery([]Field{FieldPath, FieldVisitors}, []Field{FieldPath}, []Field{FieldPath}, nil, "")
+	q, args = getValidFilter(t, analyzer, &Filter{EntryPath: []string{"/"}, Path: []string{"/foo"}, Sample: 10_000_000}).buildQuery([]Field{FieldPath, FieldVisitors}, []Field{FieldPath}, []Field{FieldPath}, nil, "")
 	stats = stats[:0]
 	rows, err = dbClient.Query(q, args...)
 	assert.NoError(t, err)
@@ -287,6 +288,164 @@ func TestFilter_BuildQuery(t *testing.T) {
 	assert.Equal(t, 1, stats[0].Visitors)
 }
 
+func TestFilter_Expression(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, VisitorID: 1, SessionID: 1, Time: util.Today(), Start: time.Now(), EntryPath: "/", ExitPath: "/", PageViews: 1, CountryCode: "de", Channel: "Paid Search"},
+			{Sign: 1, VisitorID: 2, SessionID: 2, Time: util.Today(), Start: time.Now(), EntryPath: "/", ExitPath: "/", PageViews: 1, CountryCode: "de", Channel: "Direct"},
+			{Sign: 1, VisitorID: 3, SessionID: 3, Time: util.Today(), Start: time.Now(), EntryPath: "/foo", ExitPath: "/foo", PageViews: 1, CountryCode: "gb", UTMCampaign: "spring"},
+			{Sign: 1, VisitorID: 4, SessionID: 4, Time: util.Today(), Start: time.Now(), EntryPath: "/foo", ExitPath: "/foo", PageViews: 1, CountryCode: "gb", Channel: "Paid Search"},
+		},
+	})
+	assert.NoError(t, dbClient.SavePageViews([]model.PageView{
+		{VisitorID: 1, SessionID: 1, Time: util.Today(), Path: "/", CountryCode: "de", Channel: "Paid Search"},
+		{VisitorID: 2, SessionID: 2, Time: util.Today(), Path: "/", CountryCode: "de", Channel: "Direct"},
+		{VisitorID: 3, SessionID: 3, Time: util.Today(), Path: "/foo", CountryCode: "gb", UTMCampaign: "spring"},
+		{VisitorID: 4, SessionID: 4, Time: util.Today(), Path: "/foo", CountryCode: "gb", Channel: "Paid Search"},
+	}))
+	assert.NoError(t, dbClient.SaveEvents([]model.Event{
+		{Name: "signup", VisitorID: 4, SessionID: 4, Time: util.Today(), Path: "/foo", CountryCode: "gb", Channel: "Paid Search"},
+	}))
+	time.Sleep(time.Millisecond * 100)
+	analyzer := NewAnalyzer(dbClient)
+	visitors, err := analyzer.Visitors.Total(&Filter{
+		Expression: &Expression{Or: []Expression{
+			{And: []Expression{
+				{Field: FieldCountry, Value: []string{"de"}},
+				{Field: FieldChannel, Value: []string{"Paid Search"}},
+			}},
+			{Field: FieldUTMCampaign, Value: []string{"spring"}},
+		}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, visitors.Visitors)
+	visitors, err = analyzer.Visitors.Total(&Filter{
+		Country: []string{"gb"},
+		Expression: &Expression{Not: &Expression{
+			Field: FieldUTMCampaign,
+			Value: []string{"spring"},
+		}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, visitors.Visitors)
+	pages, err := analyzer.Pages.ByPath(&Filter{
+		Expression: &Expression{And: []Expression{
+			{Field: FieldPath, Value: []string{"/"}},
+			{Field: FieldChannel, Value: []string{"!Direct"}},
+		}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, pages, 1)
+	assert.Equal(t, "/", pages[0].Path)
+	assert.Equal(t, 1, pages[0].Visitors)
+	visitors, err = analyzer.Visitors.Total(&Filter{
+		Expression: &Expression{Or: []Expression{
+			{Field: FieldEventName, Value: []string{"signup"}},
+			{Field: FieldChannel, Value: []string{"Direct"}},
+		}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, visitors.Visitors)
+	entries, err := analyzer.Pages.Entry(&Filter{
+		Expression: &Expression{Or: []Expression{
+			{Field: FieldPath, Value: []string{"/"}},
+			{Field: FieldCountry, Value: []string{"gb"}},
+		}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	pages, err = analyzer.Pages.ByPath(&Filter{
+		Expression: &Expression{And: []Expression{
+			{Field: FieldEntryPath, Value: []string{"/foo"}},
+			{Field: FieldChannel, Value: []string{"!Paid Search"}},
+		}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, pages, 1)
+	assert.Equal(t, "/foo", pages[0].Path)
+	assert.Equal(t, 1, pages[0].Visitors)
+}
+
+func TestFilter_Range(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	saveDistributionTestData(t)
+	analyzer := NewAnalyzer(dbClient)
+	visitors, err := analyzer.Visitors.Total(&Filter{SessionDuration: &Range{Operator: RangeGreater, Value: 10}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, visitors.Visitors)
+	visitors, err = analyzer.Visitors.Total(&Filter{SessionPageViews: &Range{Operator: RangeGreaterOrEqual, Value: 3}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, visitors.Visitors)
+	visitors, err = analyzer.Visitors.Total(&Filter{SessionDuration: &Range{Operator: RangeBetween, Value: 2, To: 28}})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, visitors.Visitors)
+	pages, err := analyzer.Pages.ByPath(&Filter{SessionDuration: &Range{Operator: RangeGreater, Value: 30}})
+	assert.NoError(t, err)
+	assert.Len(t, pages, 2)
+	assert.Equal(t, "/", pages[0].Path)
+	assert.Equal(t, "/foo", pages[1].Path)
+	pages, err = analyzer.Pages.ByPath(&Filter{TimeOnPage: &Range{Operator: RangeLess, Value: 5}})
+	assert.NoError(t, err)
+	assert.Len(t, pages, 2)
+	assert.Equal(t, "/", pages[0].Path)
+	assert.Equal(t, 1, pages[0].Visitors)
+	assert.Equal(t, "/foo", pages[1].Path)
+	assert.Equal(t, 1, pages[1].Visitors)
+	visitors, err = analyzer.Visitors.Total(&Filter{TimeOnPage: &Range{Operator: RangeGreaterOrEqual, Value: 25}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, visitors.Visitors)
+	visitors, err = analyzer.Visitors.Total(&Filter{
+		EventName:      []string{"Sale"},
+		EventMetaRange: map[string]Range{"amount": {Operator: RangeGreater, Value: 180}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, visitors.Visitors)
+	events, err := analyzer.Events.Events(&Filter{
+		EventName:        []string{"Sale"},
+		EventMetaRange:   map[string]Range{"amount": {Operator: RangeLessOrEqual, Value: 189}},
+		CustomMetricType: pkg.CustomMetricTypeInteger,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, 2, events[0].Visitors)
+	visitors, err = analyzer.Visitors.Total(&Filter{EventMetaRange: map[string]Range{"missing": {Operator: RangeLess, Value: 1}}})
+	assert.NoError(t, err)
+	assert.Zero(t, visitors.Visitors)
+}
+
+func TestFilter_Segment(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	saveDistributionTestData(t)
+	analyzer := NewAnalyzer(dbClient)
+	visitors, err := analyzer.Visitors.Total(&Filter{SegmentConditions: []SegmentCondition{{Event: "Sale"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, visitors.Visitors)
+	visitors, err = analyzer.Visitors.Total(&Filter{SegmentConditions: []SegmentCondition{
+		{Path: "/bar"},
+		{Event: "Sale", Not: true},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, visitors.Visitors)
+	visitors, err = analyzer.Visitors.Total(&Filter{SegmentConditions: []SegmentCondition{{Path: "/", MinCount: 2}}})
+	assert.NoError(t, err)
+	assert.Zero(t, visitors.Visitors)
+	assert.NoError(t, analyzer.Segments.Save(context.Background(), 0, Segment{Name: "Buyers", Conditions: []SegmentCondition{{Event: "Sale"}}}))
+	time.Sleep(time.Millisecond * 100)
+	pages, err := analyzer.Pages.ByPath(&Filter{Segment: "buyers"})
+	assert.NoError(t, err)
+	assert.Len(t, pages, 3)
+	assert.Equal(t, "/", pages[0].Path)
+	assert.Equal(t, 3, pages[0].Visitors)
+	assert.Equal(t, "/foo", pages[1].Path)
+	assert.Equal(t, 3, pages[1].Visitors)
+	assert.Equal(t, "/bar", pages[2].Path)
+	assert.Equal(t, 1, pages[2].Visitors)
+	visitors, err = analyzer.Visitors.Total(&Filter{Segment: "unknown"})
+	assert.NoError(t, err)
+	assert.Zero(t, visitors.Visitors)
+}
+
 func TestFilter_Empty(t *testing.T) {
 	a := Filter{
 		Path:      []string{"/foo", "/bar"},
@@ -301,6 +460,14 @@ func TestFilter_Empty(t *testing.T) {
 	}
 	assert.False(t, a.Empty())
 	assert.True(t, b.Empty())
+	b.Expression = &Expression{Field: FieldCountry, Value: []string{"de"}}
+	assert.False(t, b.Empty())
+	b.Expression = nil
+	b.EventMetaRange = map[string]Range{"amount": {Operator: RangeGreater, Value: 100}}
+	assert.False(t, b.Empty())
+	b.EventMetaRange = nil
+	b.Segment = "buyers"
+	assert.False(t, b.Empty())
 }
 
 func TestFilter_Equal(t *testing.T) {
@@ -327,4 +494,16 @@ func TestFilter_Equal(t *testing.T) {
 	assert.False(t, c.Equal(b))
 	assert.False(t, a.Equal(c))
 	assert.False(t, b.Equal(c))
+	a.Expression = &Expression{Not: &Expression{Field: FieldCountry, Value: []string{"de"}}}
+	assert.False(t, a.Equal(b))
+	b.Expression = &Expression{Not: &Expression{Field: FieldCountry, Value: []string{"de"}}}
+	assert.True(t, a.Equal(b))
+	a.SessionDuration = &Range{Operator: RangeGreater, Value: 60}
+	assert.False(t, a.Equal(b))
+	b.SessionDuration = &Range{Operator: RangeGreater, Value: 60}
+	assert.True(t, a.Equal(b))
+	a.SegmentConditions = []SegmentCondition{{Event: "signup", Not: true}}
+	assert.False(t, a.Equal(b))
+	b.SegmentConditions = []SegmentCondition{{Event: "signup", Not: true}}
+	assert.True(t, a.Equal(b))
 }
@@ -4,16 +4,70 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
 )
 
 const (
-	minFunnelSteps = 2
+	minFunnelSteps              = 2
+	defaultFunnelWindow         = time.Hour * 24
+	defaultFunnelBreakdownLimit = 10
+
+	// FunnelScopeSession requires all steps to be completed within the same session.
+	FunnelScopeSession = FunnelScope("session")
+
+	// FunnelScopeVisitor allows steps to be completed across multiple sessions of the same visitor.
+	FunnelScopeVisitor = FunnelScope("visitor")
+)
+
+var (
+	// ErrUnsupportedBreakdownField is returned in case a funnel, goal, or Analyzer.Breakdown uses a field that is not supported.
+	ErrUnsupportedBreakdownField = errors.New("unsupported breakdown field")
+
+	// ErrNoTagKey is returned in case a funnel or goal is broken down by FieldTagValue without setting the tag key.
+	ErrNoTagKey = errors.New("no tag key specified")
 )
 
+// FunnelScope sets whether the steps of an ordered funnel must be completed in a single session or across sessions.
+type FunnelScope string
+
+// FunnelOptions are the options for ordered funnels.
+type FunnelOptions struct {
+	// Window is the maximum time between the first and the last step.
+	// Defaults to 24 hours and is rounded to full seconds.
+	Window time.Duration
+
+	// Scope sets whether the steps must be completed within the same session (FunnelScopeSession, default)
+	// or by the same visitor (FunnelScopeVisitor).
+	Scope FunnelScope
+
+	// StrictOrder stops the funnel as soon as a step is completed out of order.
+	// By default, other steps may happen in between.
+	StrictOrder bool
+
+	// NoRepeats stops the funnel as soon as the same step is completed twice in a row.
+	NoRepeats bool
+}
+
+// FunnelBreakdown sets the dimension to break down a funnel by.
+type FunnelBreakdown struct {
+	// Field is the dimension of the session that entered the funnel.
+	// Supported fields are FieldChannel, FieldReferrerName, FieldUTMSource, FieldUTMMedium, FieldUTMCampaign, FieldCountry,
+	// FieldLanguage, FieldBrowser, FieldOS, FieldPlatform, FieldScreenClass, and FieldTagValue.
+	Field Field
+
+	// TagKey is the tag to break down the funnel by for FieldTagValue.
+	TagKey string
+
+	// Limit is the maximum number of values, ordered by the number of visitors entering the funnel.
+	// All other values are combined into a single group. Defaults to 10.
+	Limit int
+}
+
 // Funnel aggregates funnels.
 type Funnel struct {
 	analyzer *Analyzer
@@ -27,11 +81,255 @@ func (funnel *Funnel) Steps(ctx context.Context, filter []Filter) ([]model.Funne
 	}
 
 	for i := range filter {
-		filter[i] = *funnel.analyzer.getFilter(&filter[i])
+		f, err := funnel.analyzer.getFilter(&filter[i])
+
+		if err != nil {
+			return nil, err
+		}
+
+		filter[i] = *f
+		filter[i].funnelStep = i + 1
+	}
+
+	var query strings.Builder
+	args := funnel.stepQueries(&query, filter)
+	query.WriteString("SELECT * FROM ( ")
+
+	for i := range filter {
+		query.WriteString(fmt.Sprintf("SELECT %d step, uniq(visitor_id), 0 FROM step%d ", i+1, i+1))
+
+		if i != len(filter)-1 {
+			query.WriteString("UNION ALL ")
+		}
+	}
+
+	query.WriteString(") ORDER BY step")
+	return funnel.selectSteps(ctx, query.String(), args)
+}
+
+// Ordered returns the funnel steps for a given filter list, counting visitors only if they completed the steps in order
+// and within the conversion window.
+// The median time to convert is the time between completing the previous step and the step,
+// taken from the first sequence of steps that reaches the funnel level within the window.
+func (funnel *Funnel) Ordered(ctx context.Context, filter []Filter, options *FunnelOptions) ([]model.FunnelStep, error) {
+	if len(filter) < minFunnelSteps {
+		return nil, errors.New("not enough steps")
+	}
+
+	if options == nil {
+		options = new(FunnelOptions)
+	}
+
+	window := options.Window
+
+	if window <= 0 {
+		window = defaultFunnelWindow
+	}
+
+	groupBy := "visitor_id, session_id"
+
+	if options.Scope == FunnelScopeVisitor {
+		groupBy = "visitor_id"
+	}
+
+	var modes []string
+
+	if options.StrictOrder {
+		modes = append(modes, "'strict_order'")
+	}
+
+	if options.NoRepeats {
+		modes = append(modes, "'strict_deduplication'")
+	}
+
+	for i := range filter {
+		f, err := funnel.analyzer.getFilter(&filter[i])
+
+		if err != nil {
+			return nil, err
+		}
+
+		// steps are selected independently, the order is checked by windowFunnel
+		filter[i] = *f
+		filter[i].funnelStep = 1
+	}
+
+	var query strings.Builder
+	args := funnel.stepQueries(&query, filter)
+	query.WriteString(", funnel_events AS ( ")
+
+	for i := range filter {
+		query.WriteString(fmt.Sprintf("SELECT %d step, visitor_id, session_id, toDateTime(time) time FROM step%d ", i+1, i+1))
+
+		if i != len(filter)-1 {
+			query.WriteString("UNION ALL ")
+		}
+	}
+
+	windowSeconds := int64(window.Round(time.Second).Seconds())
+	conditions := make([]string, 0, len(filter))
+	paths := make([]string, 0, len(filter))
+
+	for i := range filter {
+		conditions = append(conditions, fmt.Sprintf("step = %d", i+1))
+
+		// each path starts at an occurrence of the first step and continues with the next occurrence of each step within the window
+		// a step that can't be found is set to 0, which also stops all following steps
+		if i == 0 {
+			paths = append(paths, "arrayMap(e -> [e.1], arrayFilter(e -> e.2 = 1, events)) paths1")
+		} else {
+			paths = append(paths, fmt.Sprintf("arrayMap(p -> arrayPushBack(p, arrayFirst(e -> e.2 = %d AND p[-1] >= p[1] AND e.1 >= p[-1] AND e.1 <= p[1] + %d, events).1), paths%d) paths%d",
+				i+1, windowSeconds, i, i+1))
+		}
+	}
+
+	query.WriteString(fmt.Sprintf(`), funnel_levels AS (
+		SELECT visitor_id,
+		windowFunnel(%d%s)(time, %s) level,
+		arraySort(groupArray((time, step))) events,
+		%s,
+		arrayFirst(p -> p[greatest(level, 1)] >= p[1], paths%d) times
+		FROM funnel_events
+		GROUP BY %s
+	)
+	SELECT arrayJoin(range(1, %d)) step,
+	uniqIf(visitor_id, level >= step) visitors,
+	toUInt64(ifNotFinite(medianIf(dateDiff('second', times[step-1], times[step]), level >= step AND step > 1), 0)) median_time_to_convert_seconds
+	FROM funnel_levels
+	GROUP BY step
+	ORDER BY step`,
+		windowSeconds,
+		strings.Join(append([]string{""}, modes...), ", "),
+		strings.Join(conditions, ", "),
+		strings.Join(paths, ",\n\t\t"),
+		len(filter),
+		groupBy,
+		len(filter)+1))
+	return funnel.selectSteps(ctx, query.String(), args)
+}
+
+// Breakdown returns the funnel steps for a given filter list grouped by a dimension.
+// The steps are calculated the same way as for Steps. The value of the dimension is taken from the session entering the funnel.
+func (funnel *Funnel) Breakdown(ctx context.Context, filter []Filter, breakdown FunnelBreakdown) ([]model.FunnelBreakdownStats, error) {
+	if len(filter) < minFunnelSteps {
+		return nil, errors.New("not enough steps")
+	}
+
+	limit := breakdown.Limit
+
+	if limit <= 0 {
+		limit = defaultFunnelBreakdownLimit
+	}
+
+	for i := range filter {
+		f, err := funnel.analyzer.getFilter(&filter[i])
+
+		if err != nil {
+			return nil, err
+		}
+
+		filter[i] = *f
 		filter[i].funnelStep = i + 1
 	}
 
+	dimension, dimensionArgs, err := breakdownDimensionQuery(breakdown.Field, breakdown.TagKey, filter[0].ClientID, "step1")
+
+	if err != nil {
+		return nil, err
+	}
+
 	var query strings.Builder
+	args := funnel.stepQueries(&query, filter)
+	args = append(args, dimensionArgs...)
+	query.WriteString(", funnel_steps AS ( ")
+
+	for i := range filter {
+		query.WriteString(fmt.Sprintf("SELECT %d step, visitor_id, session_id FROM step%d ", i+1, i+1))
+
+		if i != len(filter)-1 {
+			query.WriteString("UNION ALL ")
+		}
+	}
+
+	query.WriteString(fmt.Sprintf(`), funnel_dimension AS ( %s ),
+	funnel_values AS (
+		SELECT s.step step, s.visitor_id visitor_id, d.value value
+		FROM funnel_steps s
+		JOIN funnel_dimension d ON s.visitor_id = d.visitor_id AND s.session_id = d.session_id
+	),
+	funnel_top AS (
+		SELECT value
+		FROM funnel_values
+		WHERE step = 1
+		GROUP BY value
+		ORDER BY uniq(visitor_id) DESC, value
+		LIMIT %d
+	)
+	SELECT step,
+	if(other, '', value) group_value,
+	value NOT IN (SELECT value FROM funnel_top) other,
+	uniq(visitor_id) visitors
+	FROM funnel_values
+	GROUP BY step, group_value, other
+	ORDER BY step, visitors DESC, group_value`, dimension, limit))
+	stats, err := funnel.store.SelectFunnelBreakdownSteps(ctx, query.String(), args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	type group struct {
+		value string
+		other bool
+	}
+
+	results := make([]model.FunnelBreakdownStats, 0)
+	groups := make(map[group]int)
+
+	for _, step := range stats {
+		key := group{step.Value, step.Other}
+		i, found := groups[key]
+
+		if !found {
+			i = len(results)
+			groups[key] = i
+			result := model.FunnelBreakdownStats{
+				Value: step.Value,
+				Other: step.Other,
+				Steps: make([]model.FunnelStep, len(filter)),
+			}
+
+			for j := range result.Steps {
+				result.Steps[j].Step = j + 1
+			}
+
+			results = append(results, result)
+		}
+
+		if step.Step > 0 && step.Step <= len(filter) {
+			results[i].Steps[step.Step-1].Visitors = step.Visitors
+		}
+	}
+
+	for i := range results {
+		calculateFunnelSteps(results[i].Steps)
+	}
+
+	slices.SortStableFunc(results, func(a, b model.FunnelBreakdownStats) int {
+		if a.Other != b.Other {
+			if a.Other {
+				return 1
+			}
+
+			return -1
+		}
+
+		return b.Steps[0].Visitors - a.Steps[0].Visitors
+	})
+	return results, nil
+}
+
+func (funnel *Funnel) stepQueries(query *strings.Builder, filter []Filter) []any {
 	args := make([]any, 0)
 
 	for i := range filter {
@@ -63,23 +361,21 @@ func (funnel *Funnel) Steps(ctx context.Context, filter []Filter) ([]model.Funne
 		}
 	}
 
-	query.WriteString("SELECT * FROM ( ")
-
-	for i := range filter {
-		query.WriteString(fmt.Sprintf("SELECT %d step, uniq(visitor_id) FROM step%d ", i+1, i+1))
-
-		if i != len(filter)-1 {
-			query.WriteString("UNION ALL ")
-		}
-	}
+	return args
+}
 
-	query.WriteString(") ORDER BY step")
-	stats, err := funnel.store.SelectFunnelSteps(ctx, query.String(), args...)
+func (funnel *Funnel) selectSteps(ctx context.Context, query string, args []any) ([]model.FunnelStep, error) {
+	stats, err := funnel.store.SelectFunnelSteps(ctx, query, args...)
 
 	if err != nil {
 		return nil, err
 	}
 
+	calculateFunnelSteps(stats)
+	return stats, nil
+}
+
+func calculateFunnelSteps(stats []model.FunnelStep) {
 	for i := range stats {
 		if i > 0 {
 			if stats[0].Visitors > 0 {
@@ -97,6 +393,30 @@ func (funnel *Funnel) Steps(ctx context.Context, filter []Filter) ([]model.Funne
 			stats[i].RelativeVisitors = 1
 		}
 	}
+}
 
-	return stats, nil
+// breakdownDimensionQuery returns a query selecting the value of the field for each session selected by the CTE.
+// The CTE must select the visitor_id and session_id.
+func breakdownDimensionQuery(field Field, tagKey string, clientID int64, cte string) (string, []any, error) {
+	switch field {
+	case FieldChannel, FieldReferrerName, FieldUTMSource, FieldUTMMedium, FieldUTMCampaign, FieldCountry,
+		FieldLanguage, FieldBrowser, FieldOS, FieldPlatform, FieldScreenClass:
+		return fmt.Sprintf(`SELECT visitor_id, session_id, any(%s) value
+			FROM "session" t
+			WHERE client_id = ?
+			AND (visitor_id, session_id) IN (SELECT visitor_id, session_id FROM %s)
+			GROUP BY visitor_id, session_id`, field.querySessions, cte), []any{clientID}, nil
+	case FieldTagValue:
+		if tagKey == "" {
+			return "", nil, ErrNoTagKey
+		}
+
+		return fmt.Sprintf(`SELECT visitor_id, session_id, anyIf(tag_values[indexOf(tag_keys, ?)], has(tag_keys, ?)) value
+			FROM "page_view" t
+			WHERE client_id = ?
+			AND (visitor_id, session_id) IN (SELECT visitor_id, session_id FROM %s)
+			GROUP BY visitor_id, session_id`, cte), []any{tagKey, tagKey, clientID}, nil
+	default:
+		return "", nil, ErrUnsupportedBreakdownField
+	}
 }
@@ -245,3 +245,173 @@ func TestFunnel_EqualSteps(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, funnel, 10)
 }
+
+func TestFunnel_Ordered(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	now := time.Now()
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, ClientID: 1, VisitorID: 1, SessionID: 1, Time: util.Today(), Start: now, EntryPath: "/", ExitPath: "/cart", PageViews: 3},
+			{Sign: 1, ClientID: 1, VisitorID: 2, SessionID: 1, Time: util.Today(), Start: now, EntryPath: "/cart", ExitPath: "/", PageViews: 3},
+			{Sign: 1, ClientID: 1, VisitorID: 3, SessionID: 1, Time: util.PastDay(2), Start: now, EntryPath: "/", ExitPath: "/", PageViews: 1, IsBounce: true},
+			{Sign: 1, ClientID: 1, VisitorID: 3, SessionID: 2, Time: util.Today(), Start: now, EntryPath: "/product", ExitPath: "/product", PageViews: 1, IsBounce: true},
+			{Sign: 1, ClientID: 1, VisitorID: 4, SessionID: 1, Time: util.Today(), Start: now, EntryPath: "/", ExitPath: "/cart", PageViews: 4},
+		},
+	})
+	assert.NoError(t, dbClient.SavePageViews([]model.PageView{
+		{ClientID: 1, VisitorID: 1, SessionID: 1, Time: util.Today(), Path: "/"},
+		{ClientID: 1, VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Second * 10), Path: "/product"},
+		{ClientID: 1, VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Second * 60), Path: "/cart"},
+
+		{ClientID: 1, VisitorID: 2, SessionID: 1, Time: util.Today(), Path: "/cart"},
+		{ClientID: 1, VisitorID: 2, SessionID: 1, Time: util.Today().Add(time.Second * 5), Path: "/product"},
+		{ClientID: 1, VisitorID: 2, SessionID: 1, Time: util.Today().Add(time.Second * 10), Path: "/"},
+
+		{ClientID: 1, VisitorID: 3, SessionID: 1, Time: util.PastDay(2), Path: "/"},
+		{ClientID: 1, VisitorID: 3, SessionID: 2, Time: util.Today(), Path: "/product"},
+
+		{ClientID: 1, VisitorID: 4, SessionID: 1, Time: util.Today(), Path: "/"},
+		{ClientID: 1, VisitorID: 4, SessionID: 1, Time: util.Today().Add(time.Second * 20), Path: "/product"},
+		{ClientID: 1, VisitorID: 4, SessionID: 1, Time: util.Today().Add(time.Second * 25), Path: "/product"},
+		{ClientID: 1, VisitorID: 4, SessionID: 1, Time: util.Today().Add(time.Second * 30), Path: "/cart"},
+	}))
+	time.Sleep(time.Millisecond * 100)
+	analyzer := NewAnalyzer(dbClient)
+	steps := func() []Filter {
+		return []Filter{
+			{ClientID: 1, From: util.PastDay(3), To: util.Today(), Path: []string{"/"}},
+			{ClientID: 1, From: util.PastDay(3), To: util.Today(), Path: []string{"/product"}},
+			{ClientID: 1, From: util.PastDay(3), To: util.Today(), Path: []string{"/cart"}},
+		}
+	}
+	_, err := analyzer.Funnel.Ordered(context.Background(), []Filter{{}}, nil)
+	assert.Equal(t, "not enough steps", err.Error())
+
+	// same session, visitor 2 visited the pages in the wrong order
+	funnel, err := analyzer.Funnel.Ordered(context.Background(), steps(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, funnel, 3)
+	assert.Equal(t, 1, funnel[0].Step)
+	assert.Equal(t, 4, funnel[0].Visitors)
+	assert.Equal(t, 2, funnel[1].Visitors)
+	assert.Equal(t, 2, funnel[2].Visitors)
+	assert.InDelta(t, 0.5, funnel[2].RelativeVisitors, 0.01)
+	assert.Equal(t, 2, funnel[1].Dropped)
+	assert.Zero(t, funnel[0].MedianTimeToConvertSeconds)
+	assert.InDelta(t, 15, funnel[1].MedianTimeToConvertSeconds, 5)
+	assert.InDelta(t, 30, funnel[2].MedianTimeToConvertSeconds, 20)
+
+	// visitor 3 completed the second step in another session
+	funnel, err = analyzer.Funnel.Ordered(context.Background(), steps(), &FunnelOptions{
+		Window: time.Hour * 24 * 3,
+		Scope:  FunnelScopeVisitor,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, funnel, 3)
+	assert.Equal(t, 4, funnel[0].Visitors)
+	assert.Equal(t, 3, funnel[1].Visitors)
+	assert.Equal(t, 2, funnel[2].Visitors)
+
+	// the window is too short for visitor 1 to reach the cart
+	funnel, err = analyzer.Funnel.Ordered(context.Background(), steps(), &FunnelOptions{
+		Window: time.Second * 40,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, funnel[1].Visitors)
+	assert.Equal(t, 1, funnel[2].Visitors)
+
+	// visitor 4 visited the product page twice
+	funnel, err = analyzer.Funnel.Ordered(context.Background(), steps(), &FunnelOptions{
+		NoRepeats: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, funnel[1].Visitors)
+	assert.Equal(t, 1, funnel[2].Visitors)
+
+	funnel, err = analyzer.Funnel.Ordered(context.Background(), steps(), &FunnelOptions{
+		StrictOrder: true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, funnel, 3)
+
+	// the time to convert is taken from the steps in order, not the first occurrence of each step
+	db.CleanupDB(t, dbClient)
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, ClientID: 1, VisitorID: 5, SessionID: 1, Time: util.Today(), Start: now, EntryPath: "/product", ExitPath: "/cart", PageViews: 4},
+		},
+	})
+	assert.NoError(t, dbClient.SavePageViews([]model.PageView{
+		{ClientID: 1, VisitorID: 5, SessionID: 1, Time: util.Today(), Path: "/product"},
+		{ClientID: 1, VisitorID: 5, SessionID: 1, Time: util.Today().Add(time.Second * 10), Path: "/"},
+		{ClientID: 1, VisitorID: 5, SessionID: 1, Time: util.Today().Add(time.Second * 40), Path: "/product"},
+		{ClientID: 1, VisitorID: 5, SessionID: 1, Time: util.Today().Add(time.Second * 50), Path: "/cart"},
+	}))
+	time.Sleep(time.Millisecond * 100)
+	funnel, err = analyzer.Funnel.Ordered(context.Background(), steps(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, funnel, 3)
+	assert.Equal(t, 1, funnel[2].Visitors)
+	assert.Equal(t, 30, funnel[1].MedianTimeToConvertSeconds)
+	assert.Equal(t, 10, funnel[2].MedianTimeToConvertSeconds)
+}
+
+func TestFunnel_Breakdown(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	now := time.Now()
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, ClientID: 1, VisitorID: 1, SessionID: 1, Time: util.Today(), Start: now, EntryPath: "/", ExitPath: "/cart", PageViews: 2, CountryCode: "de", Desktop: true},
+			{Sign: 1, ClientID: 1, VisitorID: 2, SessionID: 1, Time: util.Today(), Start: now, EntryPath: "/", ExitPath: "/", PageViews: 1, CountryCode: "de", Desktop: true, IsBounce: true},
+			{Sign: 1, ClientID: 1, VisitorID: 3, SessionID: 1, Time: util.Today(), Start: now, EntryPath: "/", ExitPath: "/cart", PageViews: 2, CountryCode: "us", Mobile: true},
+			{Sign: 1, ClientID: 1, VisitorID: 4, SessionID: 1, Time: util.Today(), Start: now, EntryPath: "/", ExitPath: "/", PageViews: 1, CountryCode: "fr", Mobile: true, IsBounce: true},
+		},
+	})
+	assert.NoError(t, dbClient.SavePageViews([]model.PageView{
+		{ClientID: 1, VisitorID: 1, SessionID: 1, Time: util.Today(), Path: "/", CountryCode: "de", Desktop: true, TagKeys: []string{"plan"}, TagValues: []string{"pro"}},
+		{ClientID: 1, VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Second * 10), Path: "/cart", CountryCode: "de", Desktop: true},
+		{ClientID: 1, VisitorID: 2, SessionID: 1, Time: util.Today(), Path: "/", CountryCode: "de", Desktop: true, TagKeys: []string{"plan"}, TagValues: []string{"free"}},
+		{ClientID: 1, VisitorID: 3, SessionID: 1, Time: util.Today(), Path: "/", CountryCode: "us", Mobile: true, TagKeys: []string{"plan"}, TagValues: []string{"pro"}},
+		{ClientID: 1, VisitorID: 3, SessionID: 1, Time: util.Today().Add(time.Second * 10), Path: "/cart", CountryCode: "us", Mobile: true},
+		{ClientID: 1, VisitorID: 4, SessionID: 1, Time: util.Today(), Path: "/", CountryCode: "fr", Mobile: true},
+	}))
+	time.Sleep(time.Millisecond * 100)
+	analyzer := NewAnalyzer(dbClient)
+	steps := func() []Filter {
+		return []Filter{
+			{ClientID: 1, From: util.Today(), To: util.Today(), Path: []string{"/"}},
+			{ClientID: 1, From: util.Today(), To: util.Today(), Path: []string{"/cart"}},
+		}
+	}
+	_, err := analyzer.Funnel.Breakdown(context.Background(), steps(), FunnelBreakdown{Field: FieldPath})
+	assert.ErrorIs(t, err, ErrUnsupportedBreakdownField)
+	_, err = analyzer.Funnel.Breakdown(context.Background(), steps(), FunnelBreakdown{Field: FieldTagValue})
+	assert.ErrorIs(t, err, ErrNoTagKey)
+
+	breakdown, err := analyzer.Funnel.Breakdown(context.Background(), steps(), FunnelBreakdown{Field: FieldCountry, Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, breakdown, 3)
+	assert.Equal(t, "de", breakdown[0].Value)
+	assert.False(t, breakdown[0].Other)
+	assert.Len(t, breakdown[0].Steps, 2)
+	assert.Equal(t, 2, breakdown[0].Steps[0].Visitors)
+	assert.Equal(t, 1, breakdown[0].Steps[1].Visitors)
+	assert.InDelta(t, 0.5, breakdown[0].Steps[1].DropOff, 0.01)
+	assert.True(t, breakdown[2].Other)
+	assert.Empty(t, breakdown[2].Value)
+	assert.Equal(t, 1, breakdown[2].Steps[0].Visitors)
+
+	breakdown, err = analyzer.Funnel.Breakdown(context.Background(), steps(), FunnelBreakdown{Field: FieldPlatform})
+	assert.NoError(t, err)
+	assert.Len(t, breakdown, 2)
+	assert.Equal(t, 2, breakdown[0].Steps[0].Visitors)
+	assert.Equal(t, 2, breakdown[1].Steps[0].Visitors)
+
+	breakdown, err = analyzer.Funnel.Breakdown(context.Background(), steps(), FunnelBreakdown{Field: FieldTagValue, TagKey: "plan"})
+	assert.NoError(t, err)
+	assert.Len(t, breakdown, 3)
+	assert.Equal(t, "pro", breakdown[0].Value)
+	assert.Equal(t, 2, breakdown[0].Steps[0].Visitors)
+	assert.Equal(t, 2, breakdown[0].Steps[1].Visitors)
+	assert.InDelta(t, 1, breakdown[0].Steps[1].RelativeVisitors, 0.01)
+}
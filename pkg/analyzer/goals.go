@@ -0,0 +1,409 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/util"
+)
+
+var (
+	// ErrInvalidGoal is returned in case a goal does not set exactly one condition, or the condition is invalid.
+	ErrInvalidGoal = errors.New("goal requires exactly one of path pattern, event name, minimum session duration, or minimum page views")
+
+	// ErrGoalNotFound is returned in case a goal does not exist for the client.
+	ErrGoalNotFound = errors.New("goal not found")
+
+	// goalBreakdownDimensions are the fields that can be used as a dimension for Goals.Breakdown.
+	goalBreakdownDimensions = append(slices.Clone(breakdownDimensions), FieldEntryPath, FieldExitPath, FieldTagValue)
+)
+
+// Goals manages conversion goals and aggregates goal conversions.
+type Goals struct {
+	analyzer *Analyzer
+	store    db.Store
+}
+
+// Save creates or updates a goal.
+// A new ID is assigned in case the ID is not set.
+func (goals *Goals) Save(ctx context.Context, goal *model.Goal) error {
+	if err := validateGoal(goal); err != nil {
+		return err
+	}
+
+	if goal.ID == 0 {
+		goal.ID = util.RandUint64()
+	}
+
+	goal.Version = time.Now().UTC()
+	return goals.store.SaveGoals(ctx, []model.Goal{*goal})
+}
+
+// Delete removes a goal.
+func (goals *Goals) Delete(ctx context.Context, clientID, id uint64) error {
+	return goals.store.DeleteGoal(ctx, clientID, id)
+}
+
+// List returns all goals for a client ordered by name.
+func (goals *Goals) List(ctx context.Context, clientID uint64) ([]model.Goal, error) {
+	return goals.selectGoals(ctx, clientID, 0)
+}
+
+// Get returns a single goal or ErrGoalNotFound.
+func (goals *Goals) Get(ctx context.Context, clientID, id uint64) (*model.Goal, error) {
+	list, err := goals.selectGoals(ctx, clientID, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, ErrGoalNotFound
+	}
+
+	return &list[0], nil
+}
+
+// Conversions returns the converted visitors, conversions (sessions), conversion rate, and value for each goal of the client.
+// The filter is used to select the sessions.
+func (goals *Goals) Conversions(filter *Filter) ([]model.GoalStats, error) {
+	filter, err := goals.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := goals.selectGoals(filter.Ctx, uint64(filter.ClientID), 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return []model.GoalStats{}, nil
+	}
+
+	matching, args := filter.buildSessionsQuery()
+	conversions := make([]string, 0, len(list))
+
+	for i := range list {
+		q, a := goals.conversionsQuery(filter, &list[i])
+		conversions = append(conversions, q)
+		args = append(args, a...)
+	}
+
+	query := fmt.Sprintf(`WITH matching AS ( %s ),
+		goal_conversions AS ( %s )
+		SELECT goal_id,
+		uniq(visitor_id) visitors,
+		count(*) conversions,
+		visitors / greatest((SELECT uniq(visitor_id) FROM matching), 1) cr,
+		sum(value) conversion_value
+		FROM goal_conversions
+		WHERE (visitor_id, session_id) IN (SELECT visitor_id, session_id FROM matching)
+		GROUP BY goal_id`, matching, strings.Join(conversions, " UNION ALL "))
+	stats, err := goals.store.SelectGoalStats(filter.Ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]model.GoalStats, 0, len(list))
+
+	for _, goal := range list {
+		result := model.GoalStats{Goal: goal}
+
+		for _, s := range stats {
+			if s.Goal.ID == goal.ID {
+				result.Visitors = s.Visitors
+				result.Conversions = s.Conversions
+				result.CR = s.CR
+				result.Value = s.Value
+				break
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Breakdown returns the conversions for a goal grouped by a dimension.
+// Supported fields are the dimensions of Analyzer.Breakdown, the entry and exit path, and FieldTagValue for the tag key.
+// A session is counted for each value it has, like each path visited. The conversion rate is relative to the visitors for each value.
+// The filter is used to select the sessions, and Limit sets the maximum number of values.
+func (goals *Goals) Breakdown(filter *Filter, id uint64, field Field, tagKey string) ([]model.GoalBreakdownStats, error) {
+	if !slices.Contains(goalBreakdownDimensions, field) {
+		return nil, ErrUnsupportedBreakdownField
+	}
+
+	if field == FieldTagValue && tagKey == "" {
+		return nil, ErrNoTagKey
+	}
+
+	filter, err := goals.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	goal, err := goals.Get(filter.Ctx, uint64(filter.ClientID), id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	matching, args := filter.buildSessionsQuery()
+	conversions, conversionsArgs := goals.conversionsQuery(filter, goal)
+	args = append(args, conversionsArgs...)
+	dimensionFilter := *filter
+	dimensionFilter.Sort = nil
+	dimensionFilter.Offset = 0
+	dimensionFilter.Limit = 0
+
+	if field == FieldTagValue {
+		dimensionFilter.Tag = []string{tagKey}
+	}
+
+	fields := []Field{FieldVisitorID, FieldSessionID, field}
+	dimension, dimensionArgs := dimensionFilter.buildQuery(fields, fields, nil, nil, "")
+	args = append(args, dimensionArgs...)
+	var limit string
+
+	if filter.Limit > 0 {
+		limit = fmt.Sprintf("LIMIT %d", filter.Limit)
+	}
+
+	query := fmt.Sprintf(`WITH matching AS ( %s ),
+		goal_conversions AS ( %s ),
+		goal_dimension AS ( SELECT visitor_id, session_id, %s value FROM ( %s ) ),
+		goal_totals AS (
+			SELECT value dimension, uniq(visitor_id) total_visitors
+			FROM goal_dimension
+			GROUP BY dimension
+		),
+		goal_converted AS (
+			SELECT d.value dimension, uniq(c.visitor_id) converted_visitors, count(*) conversions, sum(c.value) conversion_value
+			FROM goal_conversions c
+			JOIN goal_dimension d ON c.visitor_id = d.visitor_id AND c.session_id = d.session_id
+			GROUP BY dimension
+		)
+		SELECT t.dimension dimension,
+		c.converted_visitors visitors,
+		c.conversions conversions,
+		if(t.total_visitors > 0, c.converted_visitors / t.total_visitors, 0) cr,
+		c.conversion_value conversion_value
+		FROM goal_totals t
+		LEFT JOIN goal_converted c ON t.dimension = c.dimension
+		ORDER BY visitors DESC, dimension
+		%s`, matching, conversions, field.Name, dimension, limit)
+	stats, err := goals.store.SelectGoalBreakdownStats(filter.Ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// ByPeriod returns the conversions for a goal grouped by hour, day, week, month, quarter, or year.
+// The conversion rate is relative to the visitors for each period.
+// The filter is used to select the sessions.
+func (goals *Goals) ByPeriod(filter *Filter, id uint64) ([]model.GoalPeriodStats, error) {
+	filter, err := goals.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	goal, err := goals.Get(filter.Ctx, uint64(filter.ClientID), id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	matching, args := filter.buildSessionsQuery()
+	conversions, conversionsArgs := goals.conversionsQuery(filter, goal)
+	args = append(args, conversionsArgs...)
+	timeQuery, timeArgs := filter.buildTimeQuery()
+	args = append(args, timeArgs...)
+	fill := queryBuilder{filter: filter}
+	withFill := fill.withFill()
+	args = append(args, fill.args...)
+	period := goalPeriodQuery(filter, "time")
+	conversionPeriod := goalPeriodQuery(filter, "conversion_time")
+	query := fmt.Sprintf(`WITH matching AS ( %s ),
+		goal_conversions AS ( %s ),
+		goal_totals AS (
+			SELECT %s period, uniq(visitor_id) total_visitors
+			FROM "session"
+			%s
+			AND (visitor_id, session_id) IN (SELECT visitor_id, session_id FROM matching)
+			GROUP BY period
+		),
+		goal_converted AS (
+			SELECT %s period, uniq(visitor_id) converted_visitors, count(*) conversions, sum(value) conversion_value
+			FROM goal_conversions
+			WHERE (visitor_id, session_id) IN (SELECT visitor_id, session_id FROM matching)
+			GROUP BY period
+		)
+		SELECT t.period period,
+		c.converted_visitors visitors,
+		c.conversions conversions,
+		if(t.total_visitors > 0, c.converted_visitors / t.total_visitors, 0) cr,
+		c.conversion_value conversion_value
+		FROM goal_totals t
+		LEFT JOIN goal_converted c ON t.period = c.period
+		ORDER BY period ASC %s`, matching, conversions, period, timeQuery, conversionPeriod, withFill)
+	stats, err := goals.store.SelectGoalPeriodStats(filter.Ctx, filter.Period, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (goals *Goals) selectGoals(ctx context.Context, clientID, id uint64) ([]model.Goal, error) {
+	args := []any{clientID}
+	var where string
+
+	if id != 0 {
+		where = "AND id = ? "
+		args = append(args, id)
+	}
+
+	query := fmt.Sprintf(`SELECT client_id, id, name, path_pattern, event_name, event_meta_keys, event_meta_values,
+		min_duration_seconds, min_page_views, value, value_meta_key, version
+		FROM "goal" FINAL
+		WHERE client_id = ? %s
+		AND deleted = 0
+		ORDER BY name, id`, where)
+	list, err := goals.store.SelectGoals(ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// conversionsQuery returns a query selecting the goal ID, visitor ID, session ID, time of the first conversion (conversion_time),
+// and value for each session converting for the goal within the time range of the filter.
+func (goals *Goals) conversionsQuery(filter *Filter, goal *model.Goal) (string, []any) {
+	timeQuery, timeArgs := filter.buildTimeQuery()
+	args := []any{goal.ID}
+	var query strings.Builder
+	query.WriteString("SELECT toUInt64(?) goal_id, visitor_id, session_id, min(time) conversion_time, ")
+
+	if goal.EventName != "" && goal.ValueMetaKey != "" {
+		query.WriteString("sum(toFloat64OrZero(event_meta_values[indexOf(event_meta_keys, ?)])) value ")
+		args = append(args, goal.ValueMetaKey)
+	} else {
+		query.WriteString("toFloat64(?) value ")
+		args = append(args, goal.Value)
+	}
+
+	switch {
+	case goal.PathPattern != "":
+		query.WriteString(`FROM "page_view" `)
+		query.WriteString(timeQuery)
+		query.WriteString("AND match(path, ?) GROUP BY visitor_id, session_id")
+		args = append(args, timeArgs...)
+		args = append(args, goal.PathPattern)
+	case goal.EventName != "":
+		query.WriteString(`FROM "event" `)
+		query.WriteString(timeQuery)
+		query.WriteString("AND event_name = ? ")
+		args = append(args, timeArgs...)
+		args = append(args, goal.EventName)
+		keys := make([]string, 0, len(goal.EventMeta))
+
+		for key := range goal.EventMeta {
+			keys = append(keys, key)
+		}
+
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			query.WriteString("AND has(event_meta_keys, ?) AND event_meta_values[indexOf(event_meta_keys, ?)] = ? ")
+			args = append(args, key, key, goal.EventMeta[key])
+		}
+
+		query.WriteString("GROUP BY visitor_id, session_id")
+	case goal.MinDurationSeconds > 0:
+		query.WriteString(`FROM "session" `)
+		query.WriteString(timeQuery)
+		query.WriteString("GROUP BY visitor_id, session_id HAVING sum(sign) > 0 AND sum(duration_seconds * sign) >= ?")
+		args = append(args, timeArgs...)
+		args = append(args, goal.MinDurationSeconds)
+	default:
+		query.WriteString(`FROM "session" `)
+		query.WriteString(timeQuery)
+		query.WriteString("GROUP BY visitor_id, session_id HAVING sum(sign) > 0 AND sum(page_views * sign) >= ?")
+		args = append(args, timeArgs...)
+		args = append(args, goal.MinPageViews)
+	}
+
+	return query.String(), args
+}
+
+func validateGoal(goal *model.Goal) error {
+	conditions := 0
+
+	if goal.PathPattern != "" {
+		if _, err := regexp.Compile(goal.PathPattern); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidGoal, err)
+		}
+
+		conditions++
+	}
+
+	if goal.EventName != "" {
+		conditions++
+	} else if len(goal.EventMeta) > 0 || goal.ValueMetaKey != "" {
+		return fmt.Errorf("%w: event meta data requires an event name", ErrInvalidGoal)
+	}
+
+	if goal.MinDurationSeconds > 0 {
+		conditions++
+	}
+
+	if goal.MinPageViews > 0 {
+		conditions++
+	}
+
+	if conditions != 1 || goal.MinDurationSeconds < 0 || goal.MinPageViews < 0 {
+		return ErrInvalidGoal
+	}
+
+	return nil
+}
+
+func goalPeriodQuery(filter *Filter, column string) string {
+	day := fmt.Sprintf("toDate(%s, '%s')", column, filter.Timezone.String())
+
+	switch filter.Period {
+	case pkg.PeriodHour:
+		return fmt.Sprintf("toStartOfHour(%s, '%s')", column, filter.Timezone.String())
+	case pkg.PeriodWeek:
+		return fmt.Sprintf("toStartOfWeek(%s, %d)", day, filter.WeekdayMode)
+	case pkg.PeriodMonth:
+		return fmt.Sprintf("toStartOfMonth(%s)", day)
+	case pkg.PeriodQuarter:
+		return fmt.Sprintf("toStartOfQuarter(%s)", day)
+	case pkg.PeriodYear:
+		return fmt.Sprintf("toStartOfYear(%s)", day)
+	default:
+		return day
+	}
+}
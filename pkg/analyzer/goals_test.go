@@ -0,0 +1,144 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoals(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	ctx := context.Background()
+	analyzer := NewAnalyzer(dbClient)
+	pricing := &model.Goal{Name: "Pricing", PathPattern: "^/pricing"}
+	signup := &model.Goal{Name: "Sign up", EventName: "Sign up", EventMeta: map[string]string{"plan": "pro"}, ValueMetaKey: "amount"}
+	engaged := &model.Goal{Name: "Engaged", MinPageViews: 3, Value: 2.5}
+	removed := &model.Goal{Name: "Removed", MinDurationSeconds: 60}
+
+	for _, goal := range []*model.Goal{pricing, signup, engaged, removed} {
+		assert.NoError(t, analyzer.Goals.Save(ctx, goal))
+		assert.NotZero(t, goal.ID)
+	}
+
+	assert.NoError(t, analyzer.Goals.Delete(ctx, 0, removed.ID))
+	pricing.Name = "Pricing page"
+	assert.NoError(t, analyzer.Goals.Save(ctx, pricing))
+	time.Sleep(time.Millisecond * 100)
+	list, err := analyzer.Goals.List(ctx, 0)
+	assert.NoError(t, err)
+	assert.Len(t, list, 3)
+	assert.Equal(t, "Engaged", list[0].Name)
+	assert.Equal(t, "Pricing page", list[1].Name)
+	assert.Equal(t, "Sign up", list[2].Name)
+	assert.Equal(t, map[string]string{"plan": "pro"}, list[2].EventMeta)
+	goal, err := analyzer.Goals.Get(ctx, 0, signup.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "amount", goal.ValueMetaKey)
+	_, err = analyzer.Goals.Get(ctx, 0, removed.ID)
+	assert.ErrorIs(t, err, ErrGoalNotFound)
+	_, err = analyzer.Goals.Get(ctx, 1, signup.ID)
+	assert.ErrorIs(t, err, ErrGoalNotFound)
+
+	assert.NoError(t, dbClient.SavePageViews([]model.PageView{
+		{VisitorID: 1, SessionID: 1, Time: util.PastDay(1), Path: "/"},
+		{VisitorID: 1, SessionID: 1, Time: util.PastDay(1).Add(time.Second), Path: "/pricing"},
+		{VisitorID: 1, SessionID: 1, Time: util.PastDay(1).Add(time.Second * 2), Path: "/signup"},
+		{VisitorID: 2, SessionID: 2, Time: util.Today(), Path: "/"},
+		{VisitorID: 2, SessionID: 2, Time: util.Today().Add(time.Second), Path: "/pricing/enterprise"},
+		{VisitorID: 3, SessionID: 3, Time: util.Today(), Path: "/blog"},
+	}))
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, VisitorID: 1, SessionID: 1, Time: util.PastDay(1), Start: time.Now(), EntryPath: "/", ExitPath: "/signup", PageViews: 3, Browser: pkg.BrowserChrome},
+			{Sign: 1, VisitorID: 2, SessionID: 2, Time: util.Today(), Start: time.Now(), EntryPath: "/", ExitPath: "/pricing/enterprise", PageViews: 2, Browser: pkg.BrowserFirefox},
+			{Sign: 1, VisitorID: 3, SessionID: 3, Time: util.Today(), Start: time.Now(), EntryPath: "/blog", ExitPath: "/blog", PageViews: 1, Browser: pkg.BrowserFirefox},
+		},
+	})
+	assert.NoError(t, dbClient.SaveEvents([]model.Event{
+		{Name: "Sign up", VisitorID: 1, SessionID: 1, Time: util.PastDay(1).Add(time.Second * 2), Path: "/signup", MetaKeys: []string{"plan", "amount"}, MetaValues: []string{"pro", "49.5"}},
+		{Name: "Sign up", VisitorID: 2, SessionID: 2, Time: util.Today(), Path: "/", MetaKeys: []string{"plan", "amount"}, MetaValues: []string{"free", "0"}},
+	}))
+	time.Sleep(time.Millisecond * 100)
+	stats, err := analyzer.Goals.Conversions(&Filter{From: util.PastDay(1), To: util.Today()})
+	assert.NoError(t, err)
+	assert.Len(t, stats, 3)
+	assert.Equal(t, "Engaged", stats[0].Goal.Name)
+	assert.Equal(t, 1, stats[0].Visitors)
+	assert.Equal(t, 1, stats[0].Conversions)
+	assert.InDelta(t, 0.3333, stats[0].CR, 0.001)
+	assert.InDelta(t, 2.5, stats[0].Value, 0.001)
+	assert.Equal(t, "Pricing page", stats[1].Goal.Name)
+	assert.Equal(t, 2, stats[1].Visitors)
+	assert.InDelta(t, 0.6666, stats[1].CR, 0.001)
+	assert.Equal(t, "Sign up", stats[2].Goal.Name)
+	assert.Equal(t, 1, stats[2].Visitors)
+	assert.InDelta(t, 49.5, stats[2].Value, 0.001)
+	stats, err = analyzer.Goals.Conversions(&Filter{From: util.Today(), To: util.Today(), Browser: []string{pkg.BrowserFirefox}})
+	assert.NoError(t, err)
+	assert.Len(t, stats, 3)
+	assert.Zero(t, stats[0].Visitors)
+	assert.Equal(t, 1, stats[1].Visitors)
+	assert.InDelta(t, 0.5, stats[1].CR, 0.001)
+	assert.Zero(t, stats[2].Visitors)
+	breakdown, err := analyzer.Goals.Breakdown(&Filter{From: util.PastDay(1), To: util.Today()}, pricing.ID, FieldBrowser, "")
+	assert.NoError(t, err)
+	assert.Len(t, breakdown, 2)
+	assert.Equal(t, pkg.BrowserChrome, breakdown[0].Dimension)
+	assert.Equal(t, 1, breakdown[0].Visitors)
+	assert.InDelta(t, 1, breakdown[0].CR, 0.001)
+	assert.Equal(t, pkg.BrowserFirefox, breakdown[1].Dimension)
+	assert.Equal(t, 1, breakdown[1].Visitors)
+	assert.InDelta(t, 0.5, breakdown[1].CR, 0.001)
+	breakdown, err = analyzer.Goals.Breakdown(&Filter{From: util.PastDay(1), To: util.Today()}, pricing.ID, FieldPath, "")
+	assert.NoError(t, err)
+	assert.Len(t, breakdown, 5)
+	assert.Equal(t, "/", breakdown[0].Dimension)
+	assert.Equal(t, 2, breakdown[0].Visitors)
+	assert.InDelta(t, 1, breakdown[0].CR, 0.001)
+	assert.Equal(t, "/blog", breakdown[4].Dimension)
+	assert.Zero(t, breakdown[4].Visitors)
+	breakdown, err = analyzer.Goals.Breakdown(&Filter{From: util.PastDay(1), To: util.Today()}, pricing.ID, FieldEntryPath, "")
+	assert.NoError(t, err)
+	assert.Len(t, breakdown, 2)
+	assert.Equal(t, "/", breakdown[0].Dimension)
+	assert.Equal(t, 2, breakdown[0].Visitors)
+	assert.Equal(t, "/blog", breakdown[1].Dimension)
+	assert.Zero(t, breakdown[1].Visitors)
+	_, err = analyzer.Goals.Breakdown(nil, pricing.ID, FieldVisitors, "")
+	assert.ErrorIs(t, err, ErrUnsupportedBreakdownField)
+	_, err = analyzer.Goals.Breakdown(nil, pricing.ID, FieldTagValue, "")
+	assert.ErrorIs(t, err, ErrNoTagKey)
+	_, err = analyzer.Goals.Breakdown(nil, removed.ID, FieldBrowser, "")
+	assert.ErrorIs(t, err, ErrGoalNotFound)
+	series, err := analyzer.Goals.ByPeriod(&Filter{From: util.PastDay(2), To: util.Today()}, pricing.ID)
+	assert.NoError(t, err)
+	assert.Len(t, series, 3)
+	assert.Equal(t, util.PastDay(2), series[0].Day.Time)
+	assert.Zero(t, series[0].Visitors)
+	assert.Equal(t, 1, series[1].Visitors)
+	assert.InDelta(t, 1, series[1].CR, 0.001)
+	assert.Equal(t, 1, series[2].Visitors)
+	assert.InDelta(t, 0.5, series[2].CR, 0.001)
+	_, err = analyzer.Goals.Conversions(getMaxFilter(""))
+	assert.NoError(t, err)
+	_, err = analyzer.Goals.ByPeriod(getMaxFilter("event"), signup.ID)
+	assert.NoError(t, err)
+}
+
+func TestValidateGoal(t *testing.T) {
+	assert.NoError(t, validateGoal(&model.Goal{PathPattern: "^/blog/.*$"}))
+	assert.NoError(t, validateGoal(&model.Goal{EventName: "Sale", EventMeta: map[string]string{"currency": "EUR"}, ValueMetaKey: "amount"}))
+	assert.NoError(t, validateGoal(&model.Goal{MinDurationSeconds: 30}))
+	assert.NoError(t, validateGoal(&model.Goal{MinPageViews: 2}))
+	assert.ErrorIs(t, validateGoal(&model.Goal{}), ErrInvalidGoal)
+	assert.ErrorIs(t, validateGoal(&model.Goal{PathPattern: "/", MinPageViews: 2}), ErrInvalidGoal)
+	assert.ErrorIs(t, validateGoal(&model.Goal{PathPattern: "(["}), ErrInvalidGoal)
+	assert.ErrorIs(t, validateGoal(&model.Goal{MinPageViews: 2, ValueMetaKey: "amount"}), ErrInvalidGoal)
+	assert.ErrorIs(t, validateGoal(&model.Goal{MinPageViews: 2, MinDurationSeconds: -1}), ErrInvalidGoal)
+}
@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"errors"
 	"fmt"
 	"slices"
 	"sort"
@@ -11,16 +12,58 @@ import (
 	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
 )
 
+const (
+	defaultPageFlowSteps = 5
+	maxPageFlowSteps     = 10
+	defaultPageFlowLimit = 10
+)
+
+var (
+	// ErrNoFlowStart is returned in case a page flow is requested without setting the path or event to start from.
+	ErrNoFlowStart = errors.New("no path or event to start the flow from specified")
+)
+
+// PageFlowOptions are the options for page flows.
+type PageFlowOptions struct {
+	// Path starts the flow at the first page view of the path in each session.
+	Path string
+
+	// EventName starts the flow at the first event with the name in each session.
+	// The first step is the path the event was triggered on. EventName takes precedence over Path.
+	EventName string
+
+	// Steps is the number of steps including the start. Defaults to 5 and is limited to 10.
+	Steps int
+
+	// Limit is the maximum number of paths for each step, ordered by the number of visitors. Defaults to 10.
+	Limit int
+}
+
 // Pages aggregates statistics regarding pages.
 type Pages struct {
 	analyzer *Analyzer
 	store    db.Store
 }
 
-// Hostname returns the visitor count, session count, bounce rate, and views grouped by hostname.
+// Hostname returns the visitor count, session count, bounce rate, engagement, and views grouped by hostname.
 func (pages *Pages) Hostname(filter *Filter) ([]model.HostnameStats, error) {
-	filter = pages.analyzer.getFilter(filter)
-	q, args := filter.buildQuery([]Field{
+	stats, _, err := pages.hostname(filter)
+	return stats, err
+}
+
+// HostnamePage returns a page of Pages.Hostname together with the total number of rows.
+func (pages *Pages) HostnamePage(filter *Filter) (*model.PaginatedStats[model.HostnameStats], error) {
+	return paginate(pages.hostname(filter))
+}
+
+func (pages *Pages) hostname(filter *Filter) ([]model.HostnameStats, *rowCount, error) {
+	filter, err := pages.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q, args, count := filter.buildListQuery(pages.store, []Field{
 		FieldHostname,
 		FieldVisitors,
 		FieldViews,
@@ -29,37 +72,67 @@ func (pages *Pages) Hostname(filter *Filter) ([]model.HostnameStats, error) {
 		FieldRelativeVisitors,
 		FieldRelativeViews,
 		FieldBounceRate,
+		FieldEngagedSessions,
+		FieldEngagementRate,
+		FieldPagesPerSession,
 	}, []Field{
 		FieldHostname,
 	}, []Field{
 		FieldVisitors,
 		FieldHostname,
-	}, nil, "")
+	}, []Field{
+		FieldHostname,
+		FieldVisitors,
+		FieldViews,
+		FieldSessions,
+		FieldBounces,
+	}, "imported_hostname")
 	stats, err := pages.store.SelectHostnameStats(filter.Ctx, q, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return stats, nil
+	return stats, count, nil
 }
 
-// ByPath returns the visitor count, session count, bounce rate, views, and average time on the page grouped by hostname, path, and (optional) page title.
+// ByPath returns the visitor count, session count, bounce rate, engagement, views, and average time on the page grouped by hostname, path, and (optional) page title.
 func (pages *Pages) ByPath(filter *Filter) ([]model.PageStats, error) {
-	return pages.byPath(filter, false)
+	stats, _, err := pages.byPath(filter, false)
+	return stats, err
 }
 
-// ByEventPath returns the visitor count, session count, bounce rate, views, and average time on the page grouped by hostname, event path, and (optional) title.
+// ByPathPage returns a page of Pages.ByPath together with the total number of rows.
+func (pages *Pages) ByPathPage(filter *Filter) (*model.PaginatedStats[model.PageStats], error) {
+	return paginate(pages.byPath(filter, false))
+}
+
+// ByEventPath returns the visitor count, session count, bounce rate, engagement, views, and average time on the page grouped by hostname, event path, and (optional) title.
 func (pages *Pages) ByEventPath(filter *Filter) ([]model.PageStats, error) {
 	if len(filter.EventName) == 0 {
 		return []model.PageStats{}, nil
 	}
 
-	return pages.byPath(filter, true)
+	stats, _, err := pages.byPath(filter, true)
+	return stats, err
+}
+
+// ByEventPathPage returns a page of Pages.ByEventPath together with the total number of rows.
+func (pages *Pages) ByEventPathPage(filter *Filter) (*model.PaginatedStats[model.PageStats], error) {
+	if len(filter.EventName) == 0 {
+		return paginate([]model.PageStats{}, &rowCount{filter: filter}, nil)
+	}
+
+	return paginate(pages.byPath(filter, true))
 }
 
-func (pages *Pages) byPath(filter *Filter, eventPath bool) ([]model.PageStats, error) {
-	filter = pages.analyzer.getFilter(filter)
+func (pages *Pages) byPath(filter *Filter, eventPath bool) ([]model.PageStats, *rowCount, error) {
+	filter, err := pages.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
 	pathField := FieldPath
 
 	if eventPath {
@@ -75,6 +148,9 @@ func (pages *Pages) byPath(filter *Filter, eventPath bool) ([]model.PageStats, e
 		FieldRelativeViews,
 		FieldBounces,
 		FieldBounceRate,
+		FieldEngagedSessions,
+		FieldEngagementRate,
+		FieldPagesPerSession,
 	}
 	groupBy := []Field{
 		pathField,
@@ -96,7 +172,7 @@ func (pages *Pages) byPath(filter *Filter, eventPath bool) ([]model.PageStats, e
 		}
 	}
 
-	q, args := filter.buildQuery(fields, groupBy, orderBy, []Field{
+	q, args, count := filter.buildListQuery(pages.store, fields, groupBy, orderBy, []Field{
 		FieldPath,
 		FieldVisitors,
 		FieldViews,
@@ -106,7 +182,7 @@ func (pages *Pages) byPath(filter *Filter, eventPath bool) ([]model.PageStats, e
 	stats, err := pages.store.SelectPageStats(filter.Ctx, filter.IncludeTitle, false, q, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if filter.IncludeTimeOnPage {
@@ -118,7 +194,7 @@ func (pages *Pages) byPath(filter *Filter, eventPath bool) ([]model.PageStats, e
 			top, err := pages.avgTimeOnPage(filter, pathList)
 
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			for i := range stats {
@@ -132,12 +208,156 @@ func (pages *Pages) byPath(filter *Filter, eventPath bool) ([]model.PageStats, e
 		}
 	}
 
-	return stats, nil
+	return stats, count, nil
+}
+
+// ContentGroups returns the visitor count, session count, bounce rate, views, and (optional) average time on the page
+// grouped by the content groups of the client (see Analyzer.ContentGroups).
+// Pages are assigned to the first matching content group, or to an empty group name otherwise.
+// Pass the name of a content group to drill down into the paths of the group.
+func (pages *Pages) ContentGroups(filter *Filter, group string) ([]model.PathGroupStats, error) {
+	stats, _, err := pages.contentGroups(filter, group)
+	return stats, err
+}
+
+// ContentGroupsPage returns a page of Pages.ContentGroups together with the total number of rows.
+func (pages *Pages) ContentGroupsPage(filter *Filter, group string) (*model.PaginatedStats[model.PathGroupStats], error) {
+	return paginate(pages.contentGroups(filter, group))
+}
+
+func (pages *Pages) contentGroups(filter *Filter, group string) ([]model.PathGroupStats, *rowCount, error) {
+	filter, err := pages.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groups, err := pages.analyzer.ContentGroups.Get(filter.Ctx, uint64(filter.ClientID))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query, args := contentGroupQuery(groups)
+
+	if group == "" {
+		filter.pathGroup = &pathGroup{
+			query: query,
+			args:  args,
+		}
+	} else {
+		filter.pathGroup = &pathGroup{
+			query:     FieldPath.Name,
+			where:     fmt.Sprintf("%s = ? ", query),
+			whereArgs: append(args, group),
+		}
+	}
+
+	return pages.byPathGroup(filter)
+}
+
+// Directories returns the visitor count, session count, bounce rate, views, and (optional) average time on the page
+// grouped by the directories directly below the parent path, like /blog and /docs for /, or /blog/2025 for /blog.
+// The statistics for a directory include all pages below it, and a page with the same path as the directory itself.
+// Pass a directory as the parent to drill down.
+func (pages *Pages) Directories(filter *Filter, parent string) ([]model.PathGroupStats, error) {
+	stats, _, err := pages.directories(filter, parent)
+	return stats, err
+}
+
+// DirectoriesPage returns a page of Pages.Directories together with the total number of rows.
+func (pages *Pages) DirectoriesPage(filter *Filter, parent string) (*model.PaginatedStats[model.PathGroupStats], error) {
+	return paginate(pages.directories(filter, parent))
+}
+
+func (pages *Pages) directories(filter *Filter, parent string) ([]model.PathGroupStats, *rowCount, error) {
+	filter, err := pages.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parent = "/" + strings.Trim(parent, "/")
+	depth := 0
+
+	if parent != "/" {
+		depth = strings.Count(parent, "/")
+	}
+
+	query, args := directoryQuery(depth + 1)
+	filter.pathGroup = &pathGroup{
+		query: query,
+		args:  args,
+	}
+
+	if depth > 0 {
+		query, args = directoryQuery(depth)
+		filter.pathGroup.where = fmt.Sprintf("%s = ? ", query)
+		filter.pathGroup.whereArgs = append(args, parent)
+	}
+
+	return pages.byPathGroup(filter)
+}
+
+func (pages *Pages) byPathGroup(filter *Filter) ([]model.PathGroupStats, *rowCount, error) {
+	q, args, count := filter.buildListQuery(pages.store, []Field{
+		FieldPathGroup,
+		FieldVisitors,
+		FieldViews,
+		FieldSessions,
+		FieldBounces,
+		FieldRelativeVisitors,
+		FieldBounceRate,
+	}, []Field{
+		FieldPathGroup,
+	}, []Field{
+		FieldVisitors,
+		FieldPathGroup,
+	}, nil, "")
+	stats, err := pages.store.SelectPathGroupStats(filter.Ctx, q, args...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if filter.IncludeTimeOnPage && len(stats) > 0 {
+		top, err := pages.selectAvgTimeOnPage(filter, "", nil)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i := range stats {
+			for j := range top {
+				if stats[i].Name == top[j].Path {
+					stats[i].AverageTimeSpentSeconds = top[j].AverageTimeSpentSeconds
+					break
+				}
+			}
+		}
+	}
+
+	return stats, count, nil
 }
 
-// Entry returns the visitor count and time on the page grouped by hostname, path, and (optional) page title for the first page visited.
+// Entry returns the visitor count, engagement, and time on the page grouped by hostname, path, and (optional) page title for the first page visited.
 func (pages *Pages) Entry(filter *Filter) ([]model.EntryStats, error) {
-	filter = pages.analyzer.getFilter(filter)
+	stats, _, err := pages.entry(filter)
+	return stats, err
+}
+
+// EntryPage returns a page of Pages.Entry together with the total number of rows.
+func (pages *Pages) EntryPage(filter *Filter) (*model.PaginatedStats[model.EntryStats], error) {
+	return paginate(pages.entry(filter))
+}
+
+func (pages *Pages) entry(filter *Filter) ([]model.EntryStats, *rowCount, error) {
+	filter, err := pages.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var sortVisitors pkg.Direction
 
 	if len(filter.Sort) > 0 && filter.Sort[0].Field == FieldVisitors {
@@ -149,6 +369,11 @@ func (pages *Pages) Entry(filter *Filter) ([]model.EntryStats, error) {
 		FieldEntryPath,
 		FieldEntries,
 		FieldEntryRate,
+		FieldSessions,
+		FieldViews,
+		FieldEngagedSessions,
+		FieldEngagementRate,
+		FieldPagesPerSession,
 	}
 	groupBy := []Field{
 		FieldEntryPath,
@@ -164,14 +389,15 @@ func (pages *Pages) Entry(filter *Filter) ([]model.EntryStats, error) {
 		orderBy = append(orderBy, FieldEntryTitle)
 	}
 
-	q, args := filter.buildQuery(fields, groupBy, orderBy, []Field{
+	q, args, count := filter.buildListQuery(pages.store, fields, groupBy, orderBy, []Field{
 		FieldEntryPath,
 		FieldVisitors,
+		FieldSessions,
 	}, "imported_entry_page")
 	stats, err := pages.store.SelectEntryStats(filter.Ctx, filter.IncludeTitle, q, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	n := len(stats)
@@ -182,7 +408,7 @@ func (pages *Pages) Entry(filter *Filter) ([]model.EntryStats, error) {
 		total, err := pages.totalVisitorsSessions(filter, pathList)
 
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		for i := range stats {
@@ -199,7 +425,7 @@ func (pages *Pages) Entry(filter *Filter) ([]model.EntryStats, error) {
 			top, err := pages.avgTimeOnPage(filter, pathList)
 
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			for i := range stats {
@@ -225,12 +451,27 @@ func (pages *Pages) Entry(filter *Filter) ([]model.EntryStats, error) {
 		}
 	}
 
-	return stats, nil
+	return stats, count, nil
 }
 
-// Exit returns the visitor count and time on the page grouped by hostname, path, and (optional) page title for the last page visited.
+// Exit returns the visitor count, engagement, and time on the page grouped by hostname, path, and (optional) page title for the last page visited.
 func (pages *Pages) Exit(filter *Filter) ([]model.ExitStats, error) {
-	filter = pages.analyzer.getFilter(filter)
+	stats, _, err := pages.exit(filter)
+	return stats, err
+}
+
+// ExitPage returns a page of Pages.Exit together with the total number of rows.
+func (pages *Pages) ExitPage(filter *Filter) (*model.PaginatedStats[model.ExitStats], error) {
+	return paginate(pages.exit(filter))
+}
+
+func (pages *Pages) exit(filter *Filter) ([]model.ExitStats, *rowCount, error) {
+	filter, err := pages.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var sortVisitors pkg.Direction
 
 	if len(filter.Sort) > 0 && filter.Sort[0].Field == FieldVisitors {
@@ -242,6 +483,11 @@ func (pages *Pages) Exit(filter *Filter) ([]model.ExitStats, error) {
 		FieldExitPath,
 		FieldExits,
 		FieldExitRate,
+		FieldSessions,
+		FieldViews,
+		FieldEngagedSessions,
+		FieldEngagementRate,
+		FieldPagesPerSession,
 	}
 	groupBy := []Field{
 		FieldExitPath,
@@ -257,14 +503,15 @@ func (pages *Pages) Exit(filter *Filter) ([]model.ExitStats, error) {
 		orderBy = append(orderBy, FieldExitTitle)
 	}
 
-	q, args := filter.buildQuery(fields, groupBy, orderBy, []Field{
+	q, args, count := filter.buildListQuery(pages.store, fields, groupBy, orderBy, []Field{
 		FieldExitPath,
 		FieldVisitors,
+		FieldSessions,
 	}, "imported_exit_page")
 	stats, err := pages.store.SelectExitStats(filter.Ctx, filter.IncludeTitle, q, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	n := len(stats)
@@ -275,7 +522,7 @@ func (pages *Pages) Exit(filter *Filter) ([]model.ExitStats, error) {
 		total, err := pages.totalVisitorsSessions(filter, pathList)
 
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		for i := range stats {
@@ -301,12 +548,17 @@ func (pages *Pages) Exit(filter *Filter) ([]model.ExitStats, error) {
 		}
 	}
 
-	return stats, nil
+	return stats, count, nil
 }
 
 // Conversions return the visitor count, views, conversion rate, and custom metric for conversion goals.
 func (pages *Pages) Conversions(filter *Filter) (*model.ConversionsStats, error) {
-	filter = pages.analyzer.getFilter(filter)
+	filter, err := pages.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	fields := []Field{
 		FieldVisitors,
 		FieldViews,
@@ -329,12 +581,174 @@ func (pages *Pages) Conversions(filter *Filter) (*model.ConversionsStats, error)
 	return stats, nil
 }
 
+// Transitions returns the pages visited directly before and after the given path.
+// Entrances (previous pages) and exits (next pages) have an empty path.
+// The filter is used to select the sessions, and Limit sets the maximum number of previous and next pages.
+func (pages *Pages) Transitions(filter *Filter, path string) (*model.PageTransitions, error) {
+	filter, err := pages.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := pages.transitions(filter, path, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := pages.transitions(filter, path, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.PageTransitions{
+		Path:     path,
+		Previous: previous,
+		Next:     next,
+	}, nil
+}
+
+// Flow returns the paths visited after a path or event as nodes and links for each step.
+// The filter is used to select the sessions.
+func (pages *Pages) Flow(filter *Filter, options PageFlowOptions) (*model.PageFlow, error) {
+	if options.Path == "" && options.EventName == "" {
+		return nil, ErrNoFlowStart
+	}
+
+	steps := options.Steps
+
+	if steps <= 0 {
+		steps = defaultPageFlowSteps
+	} else if steps > maxPageFlowSteps {
+		steps = maxPageFlowSteps
+	}
+
+	limit := options.Limit
+
+	if limit <= 0 {
+		limit = defaultPageFlowLimit
+	}
+
+	filter, err := pages.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	matching, args := filter.buildSessionsQuery()
+	timeQuery, timeArgs := filter.buildTimeQuery()
+	args = append(args, timeArgs...)
+	start := `FROM "page_view" ` + timeQuery + "AND path = ? "
+	args = append(args, options.Path)
+
+	if options.EventName != "" {
+		start = `FROM "event" ` + timeQuery + "AND event_name = ? "
+		args[len(args)-1] = options.EventName
+	}
+
+	args = append(args, timeArgs...)
+	args = append(args, steps)
+	query := fmt.Sprintf(`WITH matching AS ( %s ),
+		flow_start AS (
+			SELECT visitor_id, session_id, min(time) start, argMin(path, time) start_path
+			%s
+			AND (visitor_id, session_id) IN (SELECT visitor_id, session_id FROM matching)
+			GROUP BY visitor_id, session_id
+		),
+		flow_paths AS (
+			SELECT v.visitor_id visitor_id,
+			arrayConcat([any(s.start_path)], arrayMap(x -> x.2, arraySort(x -> x.1, groupArrayIf((v.time, v.path), v.time > s.start)))) paths
+			FROM (SELECT visitor_id, session_id, time, path FROM "page_view" %s) v
+			JOIN flow_start s ON v.visitor_id = s.visitor_id AND v.session_id = s.session_id
+			GROUP BY v.visitor_id, v.session_id
+		),
+		flow_steps AS (
+			SELECT visitor_id, arrayJoin(arrayEnumerate(arraySlice(paths, 1, ?))) step, paths[step] path, paths[step + 1] next_path
+			FROM flow_paths
+		)
+		SELECT l.step step, l.path path, l.next_path next_path, l.visitors visitors, n.visitors path_visitors
+		FROM (
+			SELECT step, path, next_path, uniq(visitor_id) visitors
+			FROM flow_steps
+			GROUP BY step, path, next_path
+		) l
+		JOIN (
+			SELECT step, path, uniq(visitor_id) visitors
+			FROM flow_steps
+			GROUP BY step, path
+		) n ON l.step = n.step AND l.path = n.path
+		ORDER BY step, visitors DESC, path, next_path`, matching, start, timeQuery)
+	stats, err := pages.store.SelectPageFlowSteps(filter.Ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return getPageFlow(stats, steps, limit), nil
+}
+
+func (pages *Pages) transitions(filter *Filter, path string, previous bool) ([]model.PageTransitionStats, error) {
+	matching, args := filter.buildSessionsQuery()
+	timeQuery, timeArgs := filter.buildTimeQuery()
+	args = append(args, timeArgs...)
+	args = append(args, path, path)
+
+	// the array index is out of bounds for entrances and exits, which returns an empty string
+	transition := "paths[i + 1]"
+
+	if previous {
+		transition = "paths[i - 1]"
+	}
+
+	var limit string
+
+	if filter.Limit > 0 {
+		limit = fmt.Sprintf("LIMIT %d", filter.Limit)
+	}
+
+	query := fmt.Sprintf(`WITH matching AS ( %s ),
+		transition_paths AS (
+			SELECT visitor_id, arrayMap(x -> x.2, arraySort(x -> x.1, groupArray((time, path)))) paths
+			FROM "page_view"
+			%s
+			AND (visitor_id, session_id) IN (SELECT visitor_id, session_id FROM matching)
+			GROUP BY visitor_id, session_id
+			HAVING has(paths, ?)
+		),
+		transition_steps AS (
+			SELECT visitor_id, arrayJoin(arrayFilter(i -> paths[i] = ?, arrayEnumerate(paths))) i, %s transition_path
+			FROM transition_paths
+		)
+		SELECT transition_path path,
+		uniq(visitor_id) visitors,
+		count(*) transitions,
+		count(*) / sum(count(*)) OVER () relative_transitions
+		FROM transition_steps
+		GROUP BY transition_path
+		ORDER BY transitions DESC, path
+		%s`, matching, timeQuery, transition, limit)
+	stats, err := pages.store.SelectPageTransitionStats(filter.Ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
 func (pages *Pages) totalVisitorsSessions(filter *Filter, paths []string) ([]model.TotalVisitorSessionStats, error) {
 	if len(paths) == 0 {
 		return []model.TotalVisitorSessionStats{}, nil
 	}
 
-	filter = pages.analyzer.getFilter(filter)
+	filter, err := pages.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	filter.Path = nil
 	filter.EntryPath = nil
 	filter.ExitPath = nil
@@ -371,27 +785,55 @@ func (pages *Pages) avgTimeOnPage(filter *Filter, paths []string) ([]model.AvgTi
 		return []model.AvgTimeSpentStats{}, nil
 	}
 
-	filter = pages.analyzer.getFilter(filter)
+	pathInQuery := queryBuilder{
+		filter: &Filter{
+			AnyPath: paths,
+		},
+	}
+	pathInQuery.whereFieldPathIn()
+	pathIn := pathInQuery.where[len(pathInQuery.where)-1].eqContains[0]
+	return pages.selectAvgTimeOnPage(filter, "AND "+pathIn, pathInQuery.args)
+}
+
+// selectAvgTimeOnPage returns the average time on the page grouped by path, or by the path group of the filter.
+// The condition is added to the query to select the pages.
+func (pages *Pages) selectAvgTimeOnPage(filter *Filter, condition string, conditionArgs []any) ([]model.AvgTimeSpentStats, error) {
+	filter, err := pages.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	filter.Sort = nil
 	filter.Search = nil
 	q := queryBuilder{
-		filter: filter,
-		from:   pageViews,
-		search: filter.Search,
+		filter:     filter,
+		from:       pageViews,
+		search:     filter.Search,
+		timeOnPage: true,
 	}
 	fields := q.getFields()
-	hasPath := slices.Contains(fields, FieldPath.Name)
+	column, groupBy := FieldPath.Name, FieldPath.Name
+	required := []string{FieldPath.Name}
 
-	if !hasPath {
-		fields = append(fields, FieldPath.Name)
+	if filter.pathGroup != nil {
+		column, groupBy = fmt.Sprintf("%s %s", filter.pathGroup.query, FieldPathGroup.Name), FieldPathGroup.Name
+		required = append(required, FieldTagKeysRaw.Name, FieldTagValuesRaw.Name)
+		q.args = append(q.args, filter.pathGroup.args...)
+	}
+
+	for _, field := range required {
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
 	}
 
 	var query strings.Builder
-	query.WriteString(fmt.Sprintf(`SELECT path, round(avg(time_on_page)) average_time_spent_seconds
+	query.WriteString(fmt.Sprintf(`SELECT %s, round(avg(time_on_page)) average_time_spent_seconds
 		FROM (
 			SELECT nth_value(%s, 2) OVER (PARTITION BY v.visitor_id, v.session_id ORDER BY v."time" ASC Rows BETWEEN CURRENT ROW AND 1 FOLLOWING) AS time_on_page,
 				%s
-			FROM page_view v `, pages.analyzer.timeOnPageQuery(filter), strings.Join(fields, ",")))
+			FROM page_view v `, column, pages.analyzer.timeOnPageQuery(filter), strings.Join(fields, ",")))
 
 	if len(filter.EntryPath) > 0 || len(filter.ExitPath) > 0 {
 		sessionsQuery := queryBuilder{
@@ -431,18 +873,11 @@ func (pages *Pages) avgTimeOnPage(filter *Filter, paths []string) ([]model.AvgTi
 
 	whereTime := q.whereTime()
 	q.whereFields()
-	pathInQuery := queryBuilder{
-		filter: &Filter{
-			AnyPath: paths,
-		},
-	}
-	pathInQuery.whereFieldPathIn()
-	pathIn := pathInQuery.where[len(pathInQuery.where)-1].eqContains[0]
-	q.args = append(q.args, pathInQuery.args...)
+	q.args = append(q.args, conditionArgs...)
 	query.WriteString(fmt.Sprintf(`%s)
 		WHERE time_on_page > 0 %s
-		AND %s
-		GROUP BY path`, whereTime, q.q.String(), pathIn))
+		%s
+		GROUP BY %s`, whereTime, q.q.String(), condition, groupBy))
 	stats, err := pages.store.SelectAvgTimeSpentStats(filter.Ctx, query.String(), q.args...)
 
 	if err != nil {
@@ -467,3 +902,83 @@ func getPathList[T interface{ GetPath() string }](stats []T) []string {
 
 	return pathList
 }
+
+func getPageFlow(stats []model.PageFlowStep, steps, limit int) *model.PageFlow {
+	type node struct {
+		step int
+		path string
+	}
+
+	nodeIndex := make(map[node]int)
+	nodes := make([]model.PageFlowNode, 0)
+
+	for _, stat := range stats {
+		key := node{stat.Step, stat.Path}
+		i, found := nodeIndex[key]
+
+		if !found {
+			i = len(nodes)
+			nodeIndex[key] = i
+			nodes = append(nodes, model.PageFlowNode{
+				Step: stat.Step,
+				Path: stat.Path,
+			})
+		}
+
+		nodes[i].Visitors = stat.PathVisitors
+
+		if stat.NextPath == "" {
+			nodes[i].Exits += stat.Visitors
+		}
+	}
+
+	slices.SortStableFunc(nodes, func(a, b model.PageFlowNode) int {
+		if a.Step != b.Step {
+			return a.Step - b.Step
+		}
+
+		if a.Visitors != b.Visitors {
+			return b.Visitors - a.Visitors
+		}
+
+		return strings.Compare(a.Path, b.Path)
+	})
+	flow := &model.PageFlow{
+		Nodes: make([]model.PageFlowNode, 0, len(nodes)),
+		Links: make([]model.PageFlowLink, 0),
+	}
+	included := make(map[node]struct{})
+	n := 0
+
+	for i := range nodes {
+		if i > 0 && nodes[i].Step != nodes[i-1].Step {
+			n = 0
+		}
+
+		if n < limit {
+			flow.Nodes = append(flow.Nodes, nodes[i])
+			included[node{nodes[i].Step, nodes[i].Path}] = struct{}{}
+			n++
+		}
+	}
+
+	for _, stat := range stats {
+		if stat.NextPath == "" || stat.Step >= steps {
+			continue
+		}
+
+		_, source := included[node{stat.Step, stat.Path}]
+		_, target := included[node{stat.Step + 1, stat.NextPath}]
+
+		if source && target {
+			flow.Links = append(flow.Links, model.PageFlowLink{
+				Step:     stat.Step,
+				Source:   stat.Path,
+				Target:   stat.NextPath,
+				Visitors: stat.Visitors,
+			})
+		}
+	}
+
+	return flow
+}
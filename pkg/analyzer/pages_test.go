@@ -1842,6 +1842,142 @@ func TestAnalyzer_totalVisitorsSessions(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestAnalyzer_Transitions(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	assert.NoError(t, dbClient.SavePageViews([]model.PageView{
+		{VisitorID: 1, SessionID: 1, Time: util.Today(), Path: "/"},
+		{VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Second), Path: "/pricing"},
+		{VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Second * 2), Path: "/signup"},
+		{VisitorID: 2, SessionID: 2, Time: util.Today(), Path: "/blog"},
+		{VisitorID: 2, SessionID: 2, Time: util.Today().Add(time.Second), Path: "/pricing"},
+		{VisitorID: 3, SessionID: 3, Time: util.Today(), Path: "/pricing"},
+		{VisitorID: 3, SessionID: 3, Time: util.Today().Add(time.Second), Path: "/"},
+		{VisitorID: 3, SessionID: 3, Time: util.Today().Add(time.Second * 2), Path: "/pricing"},
+		{VisitorID: 4, SessionID: 4, Time: util.Today(), Path: "/"},
+	}))
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, VisitorID: 1, SessionID: 1, Time: util.Today(), Start: time.Now(), EntryPath: "/", ExitPath: "/signup", PageViews: 3},
+			{Sign: 1, VisitorID: 2, SessionID: 2, Time: util.Today(), Start: time.Now(), EntryPath: "/blog", ExitPath: "/pricing", PageViews: 2},
+			{Sign: 1, VisitorID: 3, SessionID: 3, Time: util.Today(), Start: time.Now(), EntryPath: "/pricing", ExitPath: "/pricing", PageViews: 3},
+			{Sign: 1, VisitorID: 4, SessionID: 4, Time: util.Today(), Start: time.Now(), EntryPath: "/", ExitPath: "/", PageViews: 1},
+		},
+	})
+	time.Sleep(time.Millisecond * 100)
+	analyzer := NewAnalyzer(dbClient)
+	stats, err := analyzer.Pages.Transitions(nil, "/pricing")
+	assert.NoError(t, err)
+	assert.Equal(t, "/pricing", stats.Path)
+	assert.Len(t, stats.Previous, 3)
+	assert.Equal(t, "/", stats.Previous[0].Path)
+	assert.Equal(t, 2, stats.Previous[0].Visitors)
+	assert.Equal(t, 2, stats.Previous[0].Transitions)
+	assert.InDelta(t, 0.5, stats.Previous[0].RelativeTransitions, 0.001)
+	assert.Empty(t, stats.Previous[1].Path)
+	assert.Equal(t, 1, stats.Previous[1].Transitions)
+	assert.Equal(t, "/blog", stats.Previous[2].Path)
+	assert.Equal(t, 1, stats.Previous[2].Transitions)
+	assert.Len(t, stats.Next, 3)
+	assert.Empty(t, stats.Next[0].Path)
+	assert.Equal(t, 2, stats.Next[0].Transitions)
+	assert.Equal(t, "/", stats.Next[1].Path)
+	assert.Equal(t, 1, stats.Next[1].Transitions)
+	assert.Equal(t, "/signup", stats.Next[2].Path)
+	assert.Equal(t, 1, stats.Next[2].Transitions)
+	stats, err = analyzer.Pages.Transitions(&Filter{EntryPath: []string{"/"}, Limit: 1}, "/pricing")
+	assert.NoError(t, err)
+	assert.Len(t, stats.Previous, 1)
+	assert.Equal(t, "/", stats.Previous[0].Path)
+	assert.InDelta(t, 1, stats.Previous[0].RelativeTransitions, 0.001)
+	assert.Len(t, stats.Next, 1)
+	assert.Equal(t, "/signup", stats.Next[0].Path)
+	_, err = analyzer.Pages.Transitions(getMaxFilter(""), "/pricing")
+	assert.NoError(t, err)
+	_, err = analyzer.Pages.Transitions(getMaxFilter("event"), "/pricing")
+	assert.NoError(t, err)
+}
+
+func TestAnalyzer_Flow(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	assert.NoError(t, dbClient.SavePageViews([]model.PageView{
+		{VisitorID: 1, SessionID: 1, Time: util.Today(), Path: "/"},
+		{VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Second), Path: "/pricing"},
+		{VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Second * 2), Path: "/signup"},
+		{VisitorID: 2, SessionID: 2, Time: util.Today(), Path: "/"},
+		{VisitorID: 2, SessionID: 2, Time: util.Today().Add(time.Second), Path: "/pricing"},
+		{VisitorID: 3, SessionID: 3, Time: util.Today(), Path: "/blog"},
+		{VisitorID: 3, SessionID: 3, Time: util.Today().Add(time.Second), Path: "/"},
+		{VisitorID: 3, SessionID: 3, Time: util.Today().Add(time.Second * 2), Path: "/about"},
+		{VisitorID: 4, SessionID: 4, Time: util.Today(), Path: "/"},
+		{VisitorID: 4, SessionID: 4, Time: util.Today().Add(time.Second), Path: "/about"},
+		{VisitorID: 4, SessionID: 5, Time: util.Today().Add(time.Minute), Path: "/"},
+		{VisitorID: 2, SessionID: 2, Time: util.Today().Add(time.Hour * 25), Path: "/blog"},
+	}))
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, VisitorID: 1, SessionID: 1, Time: util.Today(), Start: time.Now(), EntryPath: "/", ExitPath: "/signup", PageViews: 3},
+			{Sign: 1, VisitorID: 2, SessionID: 2, Time: util.Today(), Start: time.Now(), EntryPath: "/", ExitPath: "/pricing", PageViews: 2},
+			{Sign: 1, VisitorID: 3, SessionID: 3, Time: util.Today(), Start: time.Now(), EntryPath: "/blog", ExitPath: "/about", PageViews: 3},
+			{Sign: 1, VisitorID: 4, SessionID: 4, Time: util.Today(), Start: time.Now(), EntryPath: "/", ExitPath: "/about", PageViews: 2},
+			{Sign: 1, VisitorID: 4, SessionID: 5, Time: util.Today(), Start: time.Now(), EntryPath: "/", ExitPath: "/", PageViews: 1, IsBounce: true},
+		},
+	})
+	assert.NoError(t, dbClient.SaveEvents([]model.Event{
+		{Name: "click", VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Millisecond * 1500), Path: "/pricing"},
+	}))
+	time.Sleep(time.Millisecond * 100)
+	analyzer := NewAnalyzer(dbClient)
+	_, err := analyzer.Pages.Flow(nil, PageFlowOptions{})
+	assert.ErrorIs(t, err, ErrNoFlowStart)
+	flow, err := analyzer.Pages.Flow(nil, PageFlowOptions{Path: "/", Steps: 3})
+	assert.NoError(t, err)
+	assert.Len(t, flow.Nodes, 4)
+	assert.Equal(t, model.PageFlowNode{Step: 1, Path: "/", Visitors: 4, Exits: 1}, flow.Nodes[0])
+	assert.Equal(t, model.PageFlowNode{Step: 2, Path: "/about", Visitors: 2, Exits: 2}, flow.Nodes[1])
+	assert.Equal(t, model.PageFlowNode{Step: 2, Path: "/pricing", Visitors: 2, Exits: 1}, flow.Nodes[2])
+	assert.Equal(t, model.PageFlowNode{Step: 3, Path: "/signup", Visitors: 1, Exits: 1}, flow.Nodes[3])
+	assert.Len(t, flow.Links, 3)
+	assert.Equal(t, model.PageFlowLink{Step: 1, Source: "/", Target: "/about", Visitors: 2}, flow.Links[0])
+	assert.Equal(t, model.PageFlowLink{Step: 1, Source: "/", Target: "/pricing", Visitors: 2}, flow.Links[1])
+	assert.Equal(t, model.PageFlowLink{Step: 2, Source: "/pricing", Target: "/signup", Visitors: 1}, flow.Links[2])
+	flow, err = analyzer.Pages.Flow(nil, PageFlowOptions{EventName: "click"})
+	assert.NoError(t, err)
+	assert.Len(t, flow.Nodes, 2)
+	assert.Equal(t, model.PageFlowNode{Step: 1, Path: "/pricing", Visitors: 1}, flow.Nodes[0])
+	assert.Equal(t, model.PageFlowNode{Step: 2, Path: "/signup", Visitors: 1, Exits: 1}, flow.Nodes[1])
+	assert.Len(t, flow.Links, 1)
+	_, err = analyzer.Pages.Flow(getMaxFilter(""), PageFlowOptions{Path: "/"})
+	assert.NoError(t, err)
+	_, err = analyzer.Pages.Flow(getMaxFilter("event"), PageFlowOptions{Path: "/"})
+	assert.NoError(t, err)
+}
+
+func TestGetPageFlow(t *testing.T) {
+	flow := getPageFlow([]model.PageFlowStep{
+		{Step: 1, Path: "/", NextPath: "/a", Visitors: 5, PathVisitors: 10},
+		{Step: 1, Path: "/", NextPath: "/b", Visitors: 3, PathVisitors: 10},
+		{Step: 1, Path: "/", NextPath: "/c", Visitors: 1, PathVisitors: 10},
+		{Step: 1, Path: "/", Visitors: 2, PathVisitors: 10},
+		{Step: 2, Path: "/a", NextPath: "/b", Visitors: 4, PathVisitors: 5},
+		{Step: 2, Path: "/a", Visitors: 1, PathVisitors: 5},
+		{Step: 2, Path: "/b", NextPath: "/a", Visitors: 3, PathVisitors: 3},
+		{Step: 2, Path: "/c", Visitors: 1, PathVisitors: 1},
+		{Step: 3, Path: "/b", NextPath: "/d", Visitors: 4, PathVisitors: 4},
+		{Step: 3, Path: "/a", Visitors: 3, PathVisitors: 3},
+	}, 3, 2)
+	assert.Len(t, flow.Nodes, 5)
+	assert.Equal(t, model.PageFlowNode{Step: 1, Path: "/", Visitors: 10, Exits: 2}, flow.Nodes[0])
+	assert.Equal(t, model.PageFlowNode{Step: 2, Path: "/a", Visitors: 5, Exits: 1}, flow.Nodes[1])
+	assert.Equal(t, model.PageFlowNode{Step: 2, Path: "/b", Visitors: 3}, flow.Nodes[2])
+	assert.Equal(t, model.PageFlowNode{Step: 3, Path: "/b", Visitors: 4}, flow.Nodes[3])
+	assert.Equal(t, model.PageFlowNode{Step: 3, Path: "/a", Visitors: 3, Exits: 3}, flow.Nodes[4])
+	assert.Len(t, flow.Links, 4)
+	assert.Equal(t, model.PageFlowLink{Step: 1, Source: "/", Target: "/a", Visitors: 5}, flow.Links[0])
+	assert.Equal(t, model.PageFlowLink{Step: 1, Source: "/", Target: "/b", Visitors: 3}, flow.Links[1])
+	assert.Equal(t, model.PageFlowLink{Step: 2, Source: "/a", Target: "/b", Visitors: 4}, flow.Links[2])
+	assert.Equal(t, model.PageFlowLink{Step: 2, Source: "/b", Target: "/a", Visitors: 3}, flow.Links[3])
+}
+
 func TestGetPathList(t *testing.T) {
 	paths := getPathList([]model.PageStats{
 		{Path: "/"},
@@ -1873,3 +2009,85 @@ func TestGetPathList(t *testing.T) {
 	assert.Contains(t, paths, "/foo")
 	assert.Contains(t, paths, "/bar")
 }
+
+func TestAnalyzer_ContentGroupsAndDirectories(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	assert.NoError(t, dbClient.SavePageViews([]model.PageView{
+		{VisitorID: 1, SessionID: 1, Time: util.Today(), Path: "/"},
+		{VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Second * 10), Path: "/blog", DurationSeconds: 10},
+		{VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Second * 40), Path: "/blog/2025/a", DurationSeconds: 30},
+		{VisitorID: 2, SessionID: 2, Time: util.Today(), Path: "/blog/2025/b"},
+		{VisitorID: 3, SessionID: 3, Time: util.Today(), Path: "/blog/2024/c"},
+		{VisitorID: 3, SessionID: 3, Time: util.Today().Add(time.Second * 20), Path: "/docs/install", DurationSeconds: 20},
+		{VisitorID: 4, SessionID: 4, Time: util.Today(), Path: "/shop/shoes", TagKeys: []string{"type"}, TagValues: []string{"product"}},
+		{VisitorID: 5, SessionID: 5, Time: util.Today(), Path: "/shop"},
+		{VisitorID: 5, SessionID: 5, Time: util.Today().Add(time.Second), Path: "/shop/"},
+	}))
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, VisitorID: 1, SessionID: 1, Time: util.Today(), Start: time.Now(), EntryPath: "/", ExitPath: "/blog/2025/a", PageViews: 3},
+			{Sign: 1, VisitorID: 2, SessionID: 2, Time: util.Today(), Start: time.Now(), EntryPath: "/blog/2025/b", ExitPath: "/blog/2025/b", IsBounce: true, PageViews: 1},
+			{Sign: 1, VisitorID: 3, SessionID: 3, Time: util.Today(), Start: time.Now(), EntryPath: "/blog/2024/c", ExitPath: "/docs/install", PageViews: 2},
+			{Sign: 1, VisitorID: 4, SessionID: 4, Time: util.Today(), Start: time.Now(), EntryPath: "/shop/shoes", ExitPath: "/shop/shoes", IsBounce: true, PageViews: 1},
+			{Sign: 1, VisitorID: 5, SessionID: 5, Time: util.Today(), Start: time.Now(), EntryPath: "/shop", ExitPath: "/shop/", PageViews: 2},
+		},
+	})
+	analyzer := NewAnalyzer(dbClient)
+	assert.NoError(t, analyzer.ContentGroups.Set(context.Background(), 0, []ContentGroup{
+		{Name: "Blog", PathPrefix: "/blog"},
+		{Name: "Docs", PathPattern: "^/docs/"},
+		{Name: "Products", TagKey: "type", TagValue: "product"},
+	}))
+	time.Sleep(time.Millisecond * 100)
+	groups, err := analyzer.Pages.ContentGroups(&Filter{IncludeTimeOnPage: true}, "")
+	assert.NoError(t, err)
+	assert.Len(t, groups, 4)
+	assert.Equal(t, "Blog", groups[0].Name)
+	assert.Equal(t, 3, groups[0].Visitors)
+	assert.Equal(t, 4, groups[0].Views)
+	assert.InDelta(t, 0.33, groups[0].BounceRate, 0.01)
+	assert.Equal(t, 25, groups[0].AverageTimeSpentSeconds)
+	assert.Equal(t, "", groups[1].Name)
+	assert.Equal(t, 2, groups[1].Visitors)
+	assert.Equal(t, "Docs", groups[2].Name)
+	assert.Equal(t, 1, groups[2].Visitors)
+	assert.Equal(t, "Products", groups[3].Name)
+	assert.Equal(t, 1, groups[3].Visitors)
+	paths, err := analyzer.Pages.ContentGroups(nil, "Blog")
+	assert.NoError(t, err)
+	assert.Len(t, paths, 4)
+	assert.Equal(t, "/blog", paths[0].Name)
+	assert.Equal(t, "/blog/2024/c", paths[1].Name)
+	assert.Equal(t, "/blog/2025/a", paths[2].Name)
+	assert.Equal(t, "/blog/2025/b", paths[3].Name)
+	directories, err := analyzer.Pages.Directories(nil, "/")
+	assert.NoError(t, err)
+	assert.Len(t, directories, 4)
+	assert.Equal(t, "/blog", directories[0].Name)
+	assert.Equal(t, 3, directories[0].Visitors)
+	assert.Equal(t, 4, directories[0].Views)
+	assert.Equal(t, "/shop", directories[1].Name)
+	assert.Equal(t, 2, directories[1].Visitors)
+	assert.Equal(t, "/", directories[2].Name)
+	assert.Equal(t, "/docs", directories[3].Name)
+	directories, err = analyzer.Pages.Directories(&Filter{IncludeTimeOnPage: true}, "/blog/")
+	assert.NoError(t, err)
+	assert.Len(t, directories, 3)
+	assert.Equal(t, "/blog/2025", directories[0].Name)
+	assert.Equal(t, 2, directories[0].Visitors)
+	assert.Equal(t, 30, directories[0].AverageTimeSpentSeconds)
+	assert.Equal(t, "/blog", directories[1].Name)
+	assert.Equal(t, "/blog/2024", directories[2].Name)
+	directories, err = analyzer.Pages.Directories(nil, "/blog/2025")
+	assert.NoError(t, err)
+	assert.Len(t, directories, 2)
+	assert.Equal(t, "/blog/2025/a", directories[0].Name)
+	assert.Equal(t, "/blog/2025/b", directories[1].Name)
+	directories, err = analyzer.Pages.Directories(nil, "/shop")
+	assert.NoError(t, err)
+	assert.Len(t, directories, 2)
+	assert.Equal(t, "/shop", directories[0].Name)
+	assert.Equal(t, 1, directories[0].Visitors)
+	assert.Equal(t, 2, directories[0].Views)
+	assert.Equal(t, "/shop/shoes", directories[1].Name)
+}
@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+)
+
+// rowCount is the query counting all rows of a list report, returned by the report next to the rows of a page.
+type rowCount struct {
+	store  db.Store
+	filter *Filter
+	query  string
+	args   []any
+}
+
+// paginate returns a page of a list report set by Filter.Offset and Filter.Limit, together with the total number of rows.
+// The count query is only run in case the total cannot be determined from the page itself (like for the last page).
+// A rowCount without query is used for reports that don't select any rows for the filter.
+func paginate[T any](stats []T, count *rowCount, err error) (*model.PaginatedStats[T], error) {
+	if err != nil {
+		return nil, err
+	}
+
+	total := len(stats)
+
+	if count.query != "" {
+		total, err = countRows(count.store, count.filter, len(stats), count.query, count.args)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &model.PaginatedStats[T]{
+		Stats:   stats,
+		Total:   total,
+		Offset:  count.filter.Offset,
+		Limit:   count.filter.Limit,
+		HasMore: count.filter.Limit > 0 && count.filter.Offset+len(stats) < total,
+	}, nil
+}
+
+// countRows returns the total number of rows for a page of n rows.
+// The count query is only run in case the total cannot be determined from the page.
+func countRows(store db.Store, filter *Filter, n int, query string, args []any) (int, error) {
+	if filter.Limit <= 0 {
+		return n, nil
+	}
+
+	if (n > 0 && n < filter.Limit) || (n == 0 && filter.Offset == 0) {
+		return filter.Offset + n, nil
+	}
+
+	return store.Count(filter.Ctx, query, args...)
+}
+
+// buildCountQuery returns the query counting all rows of the query built for the fields without limit, offset, and order.
+func (filter *Filter) buildCountQuery(fields, groupBy, fieldsImported []Field, fromImported string) (string, []any) {
+	countFilter := *filter
+	countFilter.Offset = 0
+	countFilter.Limit = 0
+	countFilter.Sort = nil
+	q, args := countFilter.buildQuery(fields, groupBy, nil, fieldsImported, fromImported)
+	return fmt.Sprintf("SELECT count(*) FROM (%s)", q), args
+}
+
+// buildListQuery returns the query for a page of a list report together with the rowCount counting all of its rows.
+func (filter *Filter) buildListQuery(store db.Store, fields, groupBy, orderBy, fieldsImported []Field, fromImported string) (string, []any, *rowCount) {
+	q, args := filter.buildQuery(fields, groupBy, orderBy, fieldsImported, fromImported)
+	countQuery, countArgs := filter.buildCountQuery(fields, groupBy, fieldsImported, fromImported)
+	return q, args, &rowCount{
+		store:  store,
+		filter: filter,
+		query:  countQuery,
+		args:   countArgs,
+	}
+}
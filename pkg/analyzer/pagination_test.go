@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginate(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, VisitorID: 1, Time: time.Now(), Start: time.Now(), CountryCode: "de"},
+			{Sign: 1, VisitorID: 2, Time: time.Now(), Start: time.Now(), CountryCode: "de"},
+			{Sign: 1, VisitorID: 3, Time: time.Now(), Start: time.Now(), CountryCode: "en"},
+			{Sign: 1, VisitorID: 4, Time: time.Now(), Start: time.Now(), CountryCode: "fr"},
+			{Sign: 1, VisitorID: 5, Time: time.Now(), Start: time.Now(), CountryCode: "jp"},
+			{Sign: 1, VisitorID: 6, Time: time.Now(), Start: time.Now(), CountryCode: "us"},
+		},
+	})
+	analyzer := NewAnalyzer(dbClient)
+	page, err := analyzer.Demographics.CountriesPage(&Filter{Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, page.Stats, 2)
+	assert.Equal(t, "de", page.Stats[0].CountryCode)
+	assert.Equal(t, 5, page.Total)
+	assert.Equal(t, 2, page.Limit)
+	assert.True(t, page.HasMore)
+	page, err = analyzer.Demographics.CountriesPage(&Filter{Offset: 4, Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, page.Stats, 1)
+	assert.Equal(t, 5, page.Total)
+	assert.Equal(t, 4, page.Offset)
+	assert.False(t, page.HasMore)
+	page, err = analyzer.Demographics.CountriesPage(&Filter{Offset: 3, Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, page.Stats, 2)
+	assert.Equal(t, 5, page.Total)
+	assert.False(t, page.HasMore)
+	page, err = analyzer.Demographics.CountriesPage(&Filter{Offset: 10, Limit: 2})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Stats)
+	assert.Equal(t, 5, page.Total)
+	assert.False(t, page.HasMore)
+	sessions, err := analyzer.Sessions.ListPage(&Filter{Limit: 4})
+	assert.NoError(t, err)
+	assert.Len(t, sessions.Stats, 4)
+	assert.Equal(t, 6, sessions.Total)
+	assert.True(t, sessions.HasMore)
+}
+
+func TestPaginateEmpty(t *testing.T) {
+	analyzer := NewAnalyzer(db.NewClientMock())
+	page, err := analyzer.Events.BreakdownPage(&Filter{Offset: 10, Limit: 5})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Stats)
+	assert.Zero(t, page.Total)
+	assert.Equal(t, 10, page.Offset)
+	assert.Equal(t, 5, page.Limit)
+	assert.False(t, page.HasMore)
+	page, err = analyzer.Events.BreakdownPage(&Filter{Expression: &Expression{Field: FieldCountry}})
+	assert.Error(t, err)
+	assert.Nil(t, page)
+}
+
+func TestFilter_BuildListQuery(t *testing.T) {
+	filter := &Filter{
+		ClientID: 42,
+		Sort:     []Sort{{Field: FieldCountry, Direction: "ASC"}},
+		Offset:   10,
+		Limit:    5,
+	}
+	filter.validate()
+	queryStr, _, count := filter.buildListQuery(nil, []Field{FieldCountry, FieldVisitors}, []Field{FieldCountry}, []Field{FieldVisitors, FieldCountry}, nil, "")
+	assert.Equal(t, `SELECT country_code country_code,uniq(t.visitor_id) visitors FROM "session" t WHERE client_id = ? GROUP BY country_code HAVING sum(sign) > 0 ORDER BY country_code ASC LIMIT 5 OFFSET 10 `, queryStr)
+	assert.Equal(t, `SELECT count(*) FROM (SELECT country_code country_code,uniq(t.visitor_id) visitors FROM "session" t WHERE client_id = ? GROUP BY country_code HAVING sum(sign) > 0 )`, count.query)
+	assert.Equal(t, []any{int64(42)}, count.args)
+	assert.Equal(t, filter, count.filter)
+}
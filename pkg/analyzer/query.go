@@ -2,6 +2,8 @@ package analyzer
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -43,6 +45,10 @@ type queryBuilder struct {
 	sample             uint
 	final              bool
 
+	// timeOnPage indicates that the time on page is available as the time_on_page column
+	// and that the table is not aliased, like for queries using a window function on page views.
+	timeOnPage bool
+
 	where []where
 	q     strings.Builder
 	args  []any
@@ -135,6 +141,22 @@ func (query *queryBuilder) getFields() []string {
 	query.appendField(&fields, FieldUTMContent.Name, query.filter.UTMContent)
 	query.appendField(&fields, FieldUTMTerm.Name, query.filter.UTMTerm)
 
+	if query.expressionApplies() {
+		for _, field := range query.filter.Expression.fields() {
+			if !slices.Contains(fields, field) {
+				fields = append(fields, field)
+			}
+		}
+	}
+
+	if query.timeOnPage && query.filter.hasSessionSubquery(query.from) {
+		for _, field := range []string{FieldVisitorID.Name, FieldSessionID.Name} {
+			if !slices.Contains(fields, field) {
+				fields = append(fields, field)
+			}
+		}
+	}
+
 	if query.filter.Platform != "" {
 		platform := query.filter.Platform
 
@@ -182,7 +204,7 @@ func (query *queryBuilder) selectFields() bool {
 
 				if includeImported {
 					dateQuery := query.whereTimeImported()[len("WHERE "):]
-					q.WriteString(fmt.Sprintf("%s %s,", fmt.Sprintf(query.selectField(query.fields[i]), sampleFactor, sampleQuery, timeQuery, query.fromImported, dateQuery), query.fields[i].Name))
+					q.WriteString(fmt.Sprintf("%s %s,", fmt.Sprintf(query.selectField(query.fields[i]), sampleFactor, sampleQuery, timeQuery, query.importedTotals(), dateQuery), query.fields[i].Name))
 				} else {
 					q.WriteString(fmt.Sprintf("%s %s,", fmt.Sprintf(query.selectField(query.fields[i]), sampleFactor, sampleQuery, timeQuery), query.fields[i].Name))
 				}
@@ -202,10 +224,14 @@ func (query *queryBuilder) selectFields() bool {
 				if query.filter.Period != pkg.PeriodDay && query.fields[i] == FieldDay {
 					if includeImported {
 						switch query.filter.Period {
+						case pkg.PeriodHour:
+							q.WriteString("hour,")
 						case pkg.PeriodWeek:
 							q.WriteString("week,")
 						case pkg.PeriodMonth:
 							q.WriteString("month,")
+						case pkg.PeriodQuarter:
+							q.WriteString("quarter,")
 						case pkg.PeriodYear:
 							q.WriteString("year,")
 						default:
@@ -213,10 +239,14 @@ func (query *queryBuilder) selectFields() bool {
 						}
 					} else {
 						switch query.filter.Period {
+						case pkg.PeriodHour:
+							q.WriteString(fmt.Sprintf("toStartOfHour(time, '%s') hour,", query.filter.Timezone.String()))
 						case pkg.PeriodWeek:
 							q.WriteString(fmt.Sprintf("toStartOfWeek(%s, %d) week,", withTz, query.filter.WeekdayMode))
 						case pkg.PeriodMonth:
 							q.WriteString(fmt.Sprintf("toStartOfMonth(%s) month,", withTz))
+						case pkg.PeriodQuarter:
+							q.WriteString(fmt.Sprintf("toStartOfQuarter(%s) quarter,", withTz))
 						case pkg.PeriodYear:
 							q.WriteString(fmt.Sprintf("toStartOfYear(%s) year,", withTz))
 						default:
@@ -234,9 +264,17 @@ func (query *queryBuilder) selectFields() bool {
 					query.args = append(query.args, query.filter.EventMetaKey[0])
 					q.WriteString(fmt.Sprintf("%s %s,", query.selectField(query.fields[i]), query.fields[i].Name))
 				}
+			} else if query.fields[i] == FieldPathGroup {
+				if query.filter.pathGroup != nil {
+					query.args = append(query.args, query.filter.pathGroup.args...)
+					q.WriteString(fmt.Sprintf("%s %s,", query.filter.pathGroup.query, query.fields[i].Name))
+				}
 			} else if query.fields[i] == FieldTagValue {
 				if len(query.filter.Tag) > 0 {
-					query.args = append(query.args, query.filter.Tag[0])
+					if !includeImported {
+						query.args = append(query.args, query.filter.Tag[0])
+					}
+
 					q.WriteString(fmt.Sprintf("%s %s,", query.selectField(query.fields[i]), query.fields[i].Name))
 				}
 			} else if !includeImported && (query.fields[i] == FieldEventMetaCustomMetricAvg || query.fields[i] == FieldEventMetaCustomMetricTotal) {
@@ -378,10 +416,14 @@ func (query *queryBuilder) joinQuery() {
 			query.q.WriteString(fmt.Sprintf("JOIN (%s) uvd ON minute = uvd.minute ", q))
 		} else if query.filter.Period == pkg.PeriodDay {
 			query.q.WriteString(fmt.Sprintf("JOIN (%s) uvd ON day = uvd.day ", q))
+		} else if query.filter.Period == pkg.PeriodHour {
+			query.q.WriteString(fmt.Sprintf("JOIN (%s) uvd ON hour = uvd.hour ", q))
 		} else if query.filter.Period == pkg.PeriodWeek {
 			query.q.WriteString(fmt.Sprintf("JOIN (%s) uvd ON week = uvd.week ", q))
 		} else if query.filter.Period == pkg.PeriodMonth {
 			query.q.WriteString(fmt.Sprintf("JOIN (%s) uvd ON month = uvd.month ", q))
+		} else if query.filter.Period == pkg.PeriodQuarter {
+			query.q.WriteString(fmt.Sprintf("JOIN (%s) uvd ON quarter = uvd.quarter ", q))
 		} else {
 			query.q.WriteString(fmt.Sprintf("JOIN (%s) uvd ON year = uvd.year ", q))
 		}
@@ -417,10 +459,15 @@ func (query *queryBuilder) joinImported(from string) {
 		if field.subqueryImported != "" {
 			if query.filter.Period != pkg.PeriodDay && field == FieldDay {
 				switch query.filter.Period {
+				case pkg.PeriodHour:
+					// the start of the hour is calculated from the imported hourly statistics, see importedHours
+					fields = append(fields, "time hour")
 				case pkg.PeriodWeek:
 					fields = append(fields, fmt.Sprintf("toStartOfWeek(date, %d) week", query.filter.WeekdayMode))
 				case pkg.PeriodMonth:
 					fields = append(fields, "toStartOfMonth(date) month")
+				case pkg.PeriodQuarter:
+					fields = append(fields, "toStartOfQuarter(date) quarter")
 				case pkg.PeriodYear:
 					fields = append(fields, "toStartOfYear(date) year")
 				default:
@@ -434,26 +481,53 @@ func (query *queryBuilder) joinImported(from string) {
 		}
 	}
 
+	// imported statistics that don't contain a metric (like the sessions for countries) are joined as zero
+	for _, field := range query.fields {
+		if query.joinImportedSum(field) && !slices.Contains(query.fieldsImported, field) {
+			fields = append(fields, fmt.Sprintf("0 %s", field.Name))
+		}
+	}
+
+	var table string
+
+	if query.filter.Period == pkg.PeriodHour && query.fieldsImported[0] == FieldDay {
+		table = query.importedHours()
+	} else {
+		table = query.importedTable(from)
+	}
+
 	dateQuery := query.whereTimeImported()
-	joinField := query.fieldsImported[0]
+	joinFields := query.joinFieldsImported()
+	joinField := joinFields[0]
 	query.where = make([]where, 0)
-	query.whereFieldImported(FieldHostname.Name, query.filter.Hostname, joinField.Name)
-	query.whereFieldImported(FieldEntryPath.Name, query.filter.EntryPath, joinField.Name)
-	query.whereFieldImported(FieldExitPath.Name, query.filter.ExitPath, joinField.Name)
-	query.whereFieldImported(FieldPath.Name, query.filter.Path, joinField.Name)
-	query.whereFieldImported(FieldLanguage.Name, query.filter.Language, joinField.Name)
-	query.whereFieldImported(FieldCountry.Name, query.filter.Country, joinField.Name)
-	query.whereFieldImported(FieldRegion.Name, query.filter.Region, joinField.Name)
-	query.whereFieldImported(FieldCity.Name, query.filter.City, joinField.Name)
-	query.whereFieldImported(FieldReferrer.Name, query.filter.Referrer, joinField.Name)
-	query.whereFieldImported(FieldReferrerName.Name, query.filter.Referrer, joinField.Name)
-	query.whereFieldImported(FieldReferrer.Name, query.filter.ReferrerName, joinField.Name)
-	query.whereFieldImported(FieldReferrerName.Name, query.filter.ReferrerName, joinField.Name)
-	query.whereFieldImported(FieldOS.Name, query.filter.OS, joinField.Name)
-	query.whereFieldImported(FieldBrowser.Name, query.filter.Browser, joinField.Name)
-	query.whereFieldImported(FieldUTMSource.Name, query.filter.UTMSource, joinField.Name)
-	query.whereFieldImported(FieldUTMMedium.Name, query.filter.UTMMedium, joinField.Name)
-	query.whereFieldImported(FieldUTMCampaign.Name, query.filter.UTMCampaign, joinField.Name)
+	query.whereFieldImported(FieldHostname.Name, query.filter.Hostname, joinFields)
+	query.whereFieldImported(FieldEntryPath.Name, query.filter.EntryPath, joinFields)
+	query.whereFieldImported(FieldExitPath.Name, query.filter.ExitPath, joinFields)
+	query.whereFieldImported(FieldPath.Name, query.filter.Path, joinFields)
+	query.whereFieldImported(FieldLanguage.Name, query.filter.Language, joinFields)
+	query.whereFieldImported(FieldCountry.Name, query.filter.Country, joinFields)
+	query.whereFieldImported(FieldRegion.Name, query.filter.Region, joinFields)
+	query.whereFieldImported(FieldCity.Name, query.filter.City, joinFields)
+	query.whereFieldImported(FieldReferrer.Name, query.filter.Referrer, joinFields)
+	query.whereFieldImported(FieldReferrerName.Name, query.filter.Referrer, joinFields)
+	query.whereFieldImported(FieldReferrer.Name, query.filter.ReferrerName, joinFields)
+	query.whereFieldImported(FieldReferrerName.Name, query.filter.ReferrerName, joinFields)
+	query.whereFieldImported(FieldOS.Name, query.filter.OS, joinFields)
+	query.whereFieldImported(FieldBrowser.Name, query.filter.Browser, joinFields)
+	query.whereFieldImported(FieldUTMSource.Name, query.filter.UTMSource, joinFields)
+	query.whereFieldImported(FieldUTMMedium.Name, query.filter.UTMMedium, joinFields)
+	query.whereFieldImported(FieldUTMCampaign.Name, query.filter.UTMCampaign, joinFields)
+	query.whereFieldImported(FieldUTMContent.Name, query.filter.UTMContent, joinFields)
+	query.whereFieldImported(FieldUTMTerm.Name, query.filter.UTMTerm, joinFields)
+	query.whereFieldImported(FieldChannel.Name, query.filter.Channel, joinFields)
+	query.whereFieldImported(FieldOSVersion.Name, query.filter.OSVersion, joinFields)
+	query.whereFieldImported(FieldBrowserVersion.Name, query.filter.BrowserVersion, joinFields)
+	query.whereFieldImported(FieldEventName.Name, query.filter.EventName, joinFields)
+
+	if joinField == FieldTagValue && len(query.filter.Tag) > 0 {
+		query.args = append(query.args, query.filter.Tag[0])
+		query.where = append(query.where, where{eqContains: []string{"tag_key = ? "}})
+	}
 
 	if joinField == FieldPlatformDesktop ||
 		joinField == FieldPlatformMobile ||
@@ -479,7 +553,7 @@ func (query *queryBuilder) joinImported(from string) {
 		}
 	}
 
-	query.q.WriteString(fmt.Sprintf(`FULL JOIN (SELECT %s FROM "%s" %s `, strings.Join(fields, ","), from, dateQuery))
+	query.q.WriteString(fmt.Sprintf(`FULL JOIN (SELECT %s FROM %s %s `, strings.Join(fields, ","), table, dateQuery))
 	query.whereWrite()
 
 	if joinField != FieldPlatformDesktop &&
@@ -487,17 +561,21 @@ func (query *queryBuilder) joinImported(from string) {
 		joinField != FieldPlatformUnknown &&
 		!query.joinImportedSum(joinField) {
 		groupBy := query.groupBy
-		query.groupBy = []Field{joinField}
+		query.groupBy = joinFields
 		query.groupByFields(false)
 		query.groupBy = groupBy
 	}
 
 	if query.filter.Period != pkg.PeriodDay && joinField == FieldDay {
 		switch query.filter.Period {
+		case pkg.PeriodHour:
+			query.q.WriteString(") imp ON t.hour = imp.hour ")
 		case pkg.PeriodWeek:
 			query.q.WriteString(") imp ON t.week = imp.week ")
 		case pkg.PeriodMonth:
 			query.q.WriteString(") imp ON t.month = imp.month ")
+		case pkg.PeriodQuarter:
+			query.q.WriteString(") imp ON t.quarter = imp.quarter ")
 		case pkg.PeriodYear:
 			query.q.WriteString(") imp ON t.year = imp.year ")
 		default:
@@ -510,6 +588,56 @@ func (query *queryBuilder) joinImported(from string) {
 			query.q.WriteString(fmt.Sprintf(") imp ON t.%s = imp.%s ", joinField.Name, joinField.Name))
 		}
 	}
+
+	for _, field := range joinFields[1:] {
+		query.q.WriteString(fmt.Sprintf("AND t.%s = imp.%s ", field.Name, field.Name))
+	}
+}
+
+// importedTable returns the table the imported statistics are selected from.
+// Days without imported hourly statistics fall back to the imported visitor statistics at hour 0 (see Filter.ImportedUntil).
+// It must be called before the imported time condition is added, as the days with hourly statistics are selected for the same client and period.
+func (query *queryBuilder) importedTable(from string) string {
+	if from == "imported_hour" {
+		return fmt.Sprintf(`(SELECT client_id, date, hour, visitors, views, sessions, bounces FROM "imported_hour" `+
+			`UNION ALL SELECT client_id, date, 0 hour, visitors, views, sessions, bounces FROM "imported_visitors" `+
+			`WHERE (client_id, date) NOT IN (SELECT client_id, date FROM "imported_hour" %s))`, query.whereTimeImported())
+	}
+
+	return fmt.Sprintf(`"%s"`, from)
+}
+
+// importedHours returns the imported hourly statistics with the start of the hour (date + hour) for hourly time series.
+// Like importedTable, it must be called before the imported time condition is added.
+func (query *queryBuilder) importedHours() string {
+	return fmt.Sprintf(`(SELECT client_id, date, toDateTime(date, '%s') + toIntervalHour(hour) time, visitors, views, sessions, bounces FROM %s)`,
+		query.filter.Timezone.String(), query.importedTable("imported_hour"))
+}
+
+// joinFieldsImported returns the fields the imported statistics are joined on.
+// The first imported field is always used. Following fields are used as well, as long as they are no metrics (like the OS and OS version).
+func (query *queryBuilder) joinFieldsImported() []Field {
+	fields := []Field{query.fieldsImported[0]}
+
+	for _, field := range query.fieldsImported[1:] {
+		if field.sampleType != 0 {
+			break
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// importedTotals returns the imported table used to calculate relative values and the conversion rate.
+// Events and tags can be counted more than once per visitor, so the totals are taken from the imported visitor statistics instead.
+func (query *queryBuilder) importedTotals() string {
+	if query.fromImported == "imported_event" || query.fromImported == "imported_tag" {
+		return "imported_visitors"
+	}
+
+	return query.fromImported
 }
 
 func (query *queryBuilder) joinImportedSum(field Field) bool {
@@ -597,6 +725,7 @@ func (query *queryBuilder) whereFields() {
 		query.whereFieldPathPattern()
 		query.whereFieldPathIn()
 		query.whereFieldTag()
+		query.whereFieldPathGroup()
 	}
 
 	if query.from == events || query.includeEventFilter {
@@ -626,6 +755,9 @@ func (query *queryBuilder) whereFields() {
 	query.whereField(FieldUTMTerm.Name, query.filter.UTMTerm)
 	query.whereFieldPlatform()
 	query.whereFieldVisitorSessionID()
+	query.whereFieldRange()
+	query.whereFieldSegment()
+	query.whereExpression()
 	query.whereFieldSearch(query.search)
 
 	query.whereWrite()
@@ -717,9 +849,12 @@ func (query *queryBuilder) whereField(field string, value []string) {
 	}
 }
 
-func (query *queryBuilder) whereFieldImported(field string, value []string, joinField string) {
-	if joinField == field {
-		query.whereField(field, value)
+func (query *queryBuilder) whereFieldImported(field string, value []string, joinFields []Field) {
+	for _, joinField := range joinFields {
+		if joinField.Name == field {
+			query.whereField(field, value)
+			break
+		}
 	}
 }
 
@@ -939,6 +1074,290 @@ func (query *queryBuilder) whereFieldVisitorSessionID() {
 	}
 }
 
+// whereFieldRange filters for the numeric ranges.
+// Ranges on columns of other tables are matched by session using a subquery.
+func (query *queryBuilder) whereFieldRange() {
+	filter := query.filter
+
+	if query.from == sessions {
+		query.whereRange(query.column("duration_seconds"), filter.SessionDuration)
+		query.whereRange(query.column("page_views"), filter.SessionPageViews)
+	} else if filter.SessionDuration != nil || filter.SessionPageViews != nil {
+		sub := queryBuilder{
+			filter: filter.periodFilter(),
+			from:   sessions,
+		}
+		sub.filter.SessionDuration = filter.SessionDuration
+		sub.filter.SessionPageViews = filter.SessionPageViews
+		sub.q.WriteString("SELECT visitor_id, session_id ")
+		sub.fromTable()
+		sub.q.WriteString(sub.whereTime())
+		sub.whereFieldRange()
+		sub.whereWrite()
+		sub.q.WriteString("GROUP BY visitor_id, session_id HAVING sum(sign) > 0")
+		query.whereIn(query.sessionColumns(), false, &sub)
+	}
+
+	if query.from == events {
+		query.whereRange(query.column("duration_seconds"), filter.EventDuration)
+		query.whereFieldMetaRange()
+	} else if filter.EventDuration != nil || len(filter.EventMetaRange) > 0 {
+		sub := queryBuilder{
+			filter: filter.periodFilter(),
+			from:   events,
+		}
+		sub.filter.EventName = filter.EventName
+		sub.filter.EventDuration = filter.EventDuration
+		sub.filter.EventMetaRange = filter.EventMetaRange
+		sub.filter.CustomMetricType = filter.CustomMetricType
+		sub.q.WriteString("SELECT visitor_id, session_id ")
+		sub.fromTable()
+		sub.q.WriteString(sub.whereTime())
+		sub.whereField(FieldEventName.Name, sub.filter.EventName)
+		sub.whereFieldRange()
+		sub.whereWrite()
+		query.whereIn(query.sessionColumns(), false, &sub)
+	}
+
+	if filter.TimeOnPage != nil {
+		if query.timeOnPage {
+			query.whereRange("time_on_page", filter.TimeOnPage)
+		} else {
+			query.whereTimeOnPageRange()
+		}
+	}
+}
+
+// whereFieldPathGroup filters for the pages of the content group or directory to drill down into.
+func (query *queryBuilder) whereFieldPathGroup() {
+	if query.from == pageViews && query.filter.pathGroup != nil && query.filter.pathGroup.where != "" {
+		query.args = append(query.args, query.filter.pathGroup.whereArgs...)
+		query.where = append(query.where, where{eqContains: []string{query.filter.pathGroup.where}})
+	}
+}
+
+// whereFieldSegment filters for sessions matching the segment conditions using a subquery for each condition.
+func (query *queryBuilder) whereFieldSegment() {
+	if query.filter.Segment != "" {
+		// the segment has not been resolved by the Analyzer
+		query.where = append(query.where, where{eqContains: []string{"0 = 1 "}})
+	}
+
+	for _, condition := range query.filter.SegmentConditions {
+		sub := queryBuilder{
+			filter: query.filter.periodFilter(),
+			from:   pageViews,
+		}
+		columns, selectColumns := query.sessionColumns(), "visitor_id, session_id"
+
+		if condition.Visitor {
+			columns, selectColumns = query.column("visitor_id"), "visitor_id"
+		}
+
+		if condition.Event != "" {
+			sub.from = events
+		}
+
+		sub.q.WriteString(fmt.Sprintf("SELECT %s ", selectColumns))
+		sub.fromTable()
+		sub.q.WriteString(sub.whereTime())
+
+		if condition.Event != "" {
+			sub.whereField(FieldEventName.Name, []string{condition.Event})
+		} else {
+			sub.whereField(FieldPath.Name, []string{condition.Path})
+		}
+
+		sub.whereWrite()
+		sub.args = append(sub.args, max(condition.MinCount, 1))
+		sub.q.WriteString(fmt.Sprintf("GROUP BY %s HAVING count(*) >= ?", selectColumns))
+
+		if condition.MaxCount > 0 {
+			sub.args = append(sub.args, condition.MaxCount)
+			sub.q.WriteString(" AND count(*) <= ?")
+		}
+
+		query.whereIn(columns, condition.Not, &sub)
+	}
+}
+
+func (query *queryBuilder) whereFieldMetaRange() {
+	if len(query.filter.EventMetaRange) > 0 {
+		cast := query.filter.CustomMetricType
+
+		if cast == "" {
+			cast = pkg.CustomMetricTypeFloat
+		}
+
+		keys := slices.Sorted(maps.Keys(query.filter.EventMetaRange))
+		var group where
+
+		for _, key := range keys {
+			q, args := query.filter.EventMetaRange[key].where(fmt.Sprintf("%s(event_meta_values[indexOf(event_meta_keys, ?)])", cast))
+
+			if q != "" {
+				// use notEq because they will all be joined using AND
+				query.args = append(query.args, key, key)
+				query.args = append(query.args, args...)
+				group.notEq = append(group.notEq, "has(event_meta_keys, ?) AND "+q)
+			}
+		}
+
+		if len(group.notEq) > 0 {
+			query.where = append(query.where, group)
+		}
+	}
+}
+
+func (query *queryBuilder) whereTimeOnPageRange() {
+	sub := queryBuilder{
+		filter: query.filter.periodFilter(),
+	}
+	timeOnPage := "duration_seconds"
+
+	if query.filter.MaxTimeOnPageSeconds > 0 {
+		timeOnPage = fmt.Sprintf("least(duration_seconds, %d)", query.filter.MaxTimeOnPageSeconds)
+	}
+
+	columns, selectColumns := query.sessionColumns(), "visitor_id, session_id"
+
+	if query.from == pageViews {
+		columns = strings.TrimSuffix(columns, ")") + `, t."time")`
+		selectColumns += `, "time"`
+	}
+
+	sub.q.WriteString(fmt.Sprintf(`SELECT %s FROM (SELECT visitor_id, session_id, "time", `, selectColumns))
+	sub.q.WriteString(fmt.Sprintf(`nth_value(%s, 2) OVER (PARTITION BY visitor_id, session_id ORDER BY "time" ASC Rows BETWEEN CURRENT ROW AND 1 FOLLOWING) AS time_on_page FROM "page_view" `, timeOnPage))
+	sub.q.WriteString(sub.whereTime())
+	sub.q.WriteString(") WHERE time_on_page > 0 ")
+	sub.whereRange("time_on_page", query.filter.TimeOnPage)
+	sub.whereWrite()
+	query.whereIn(columns, false, &sub)
+}
+
+func (query *queryBuilder) whereRange(column string, r *Range) {
+	if r != nil {
+		if q, args := r.where(column); q != "" {
+			query.args = append(query.args, args...)
+			query.where = append(query.where, where{eqContains: []string{q}})
+		}
+	}
+}
+
+func (query *queryBuilder) whereIn(columns string, not bool, sub *queryBuilder) {
+	operator := "IN"
+
+	if not {
+		operator = "NOT IN"
+	}
+
+	query.args = append(query.args, sub.args...)
+	query.where = append(query.where, where{
+		eqContains: []string{fmt.Sprintf("%s %s (%s) ", columns, operator, strings.TrimSpace(sub.q.String()))},
+	})
+}
+
+// column returns the column name qualified by the table alias if the table has one.
+func (query *queryBuilder) column(name string) string {
+	if query.timeOnPage {
+		return name
+	}
+
+	return "t." + name
+}
+
+func (query *queryBuilder) sessionColumns() string {
+	return fmt.Sprintf("(%s, %s)", query.column("visitor_id"), query.column("session_id"))
+}
+
+func (query *queryBuilder) whereExpression() {
+	if query.expressionApplies() {
+		if q := query.whereExpressionNode(query.filter.Expression); q != "" {
+			query.where = append(query.where, where{eqContains: []string{q}})
+		}
+	}
+}
+
+func (query *queryBuilder) whereExpressionNode(expression *Expression) string {
+	if len(expression.And) > 0 || len(expression.Or) > 0 {
+		children, operator := expression.And, "AND "
+
+		if len(expression.Or) > 0 {
+			children, operator = expression.Or, "OR "
+		}
+
+		parts := make([]string, 0, len(children))
+
+		for i := range children {
+			if q := query.whereExpressionNode(&children[i]); q != "" {
+				parts = append(parts, q)
+			}
+		}
+
+		if len(parts) == 0 {
+			return ""
+		}
+
+		return fmt.Sprintf("(%s) ", strings.Join(parts, operator))
+	}
+
+	if expression.Not != nil {
+		if q := query.whereExpressionNode(expression.Not); q != "" {
+			return fmt.Sprintf("NOT (%s) ", q)
+		}
+
+		return ""
+	}
+
+	if !isExpressionField(expression.Field) || len(expression.Value) == 0 {
+		return ""
+	}
+
+	// build the predicate like a regular filter field and take it out of the where groups again
+	n := len(query.where)
+	query.whereField(expression.Field.Name, expression.Value)
+	group := query.where[n]
+	query.where = query.where[:n]
+	parts := make([]string, 0, len(group.notEq)+1)
+
+	if len(group.eqContains) > 1 {
+		parts = append(parts, fmt.Sprintf("(%s) ", strings.Join(group.eqContains, "OR ")))
+	} else if len(group.eqContains) == 1 {
+		parts = append(parts, group.eqContains[0])
+	}
+
+	parts = append(parts, group.notEq...)
+
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	return fmt.Sprintf("(%s) ", strings.Join(parts, "AND "))
+}
+
+// expressionApplies returns whether the table has all columns required to evaluate the Filter.Expression.
+func (query *queryBuilder) expressionApplies() bool {
+	if query.filter == nil || query.filter.Expression == nil {
+		return false
+	}
+
+	expression := query.filter.Expression
+
+	if expression.contains(FieldPath) && query.from == sessions {
+		return false
+	}
+
+	if (expression.contains(FieldEntryPath) || expression.contains(FieldExitPath)) && query.from != sessions {
+		return false
+	}
+
+	if expression.contains(FieldEventName) && query.from != events && !query.includeEventFilter {
+		return false
+	}
+
+	return true
+}
+
 func (query *queryBuilder) whereFieldPathPattern() {
 	if len(query.filter.PathPattern) > 0 {
 		var group where
@@ -988,10 +1407,14 @@ func (query *queryBuilder) groupByFields(imported bool) {
 				continue
 			} else if query.filter.Period != pkg.PeriodDay && query.groupBy[i] == FieldDay {
 				switch query.filter.Period {
+				case pkg.PeriodHour:
+					q.WriteString("hour,")
 				case pkg.PeriodWeek:
 					q.WriteString("week,")
 				case pkg.PeriodMonth:
 					q.WriteString("month,")
+				case pkg.PeriodQuarter:
+					q.WriteString("quarter,")
 				case pkg.PeriodYear:
 					q.WriteString("year,")
 				default:
@@ -1042,10 +1465,14 @@ func (query *queryBuilder) orderByFields() {
 
 				if query.filter.Period != pkg.PeriodDay && query.orderBy[i] == FieldDay {
 					switch query.filter.Period {
+					case pkg.PeriodHour:
+						name = "hour"
 					case pkg.PeriodWeek:
 						name = "week"
 					case pkg.PeriodMonth:
 						name = "month"
+					case pkg.PeriodQuarter:
+						name = "quarter"
 					case pkg.PeriodYear:
 						name = "year"
 					default:
@@ -1078,12 +1505,24 @@ func (query *queryBuilder) withFill() string {
 		q := ""
 
 		switch query.filter.Period {
+		case pkg.PeriodHour:
+			// the step is added to the timestamp, so DST changes don't cause duplicate or missing hours
+			tz := query.filter.Timezone.String()
+
+			if query.filter.IncludeTime {
+				query.args = append(query.args, from, to)
+				return fmt.Sprintf("WITH FILL FROM toStartOfHour(toDateTime(?, '%s')) TO toDateTime(?, '%s')+1 STEP INTERVAL 1 HOUR ", tz, tz)
+			}
+
+			q = fmt.Sprintf("WITH FILL FROM toDateTime(toDate(?), '%s') TO toDateTime(toDate(?)+1, '%s') STEP INTERVAL 1 HOUR ", tz, tz)
 		case pkg.PeriodDay:
 			q = "WITH FILL FROM toDate(?) TO toDate(?)+1 STEP INTERVAL 1 DAY "
 		case pkg.PeriodWeek:
 			q = fmt.Sprintf("WITH FILL FROM toStartOfWeek(toDate(?), %d) TO toDate(?)+1 STEP INTERVAL 1 WEEK ", query.filter.WeekdayMode)
 		case pkg.PeriodMonth:
 			q = "WITH FILL FROM toStartOfMonth(toDate(?)) TO toDate(?)+1 STEP INTERVAL 1 MONTH "
+		case pkg.PeriodQuarter:
+			q = "WITH FILL FROM toStartOfQuarter(toDate(?)) TO toDate(?)+1 STEP INTERVAL 1 QUARTER "
 		case pkg.PeriodYear:
 			q = "WITH FILL FROM toStartOfYear(toDate(?)) TO toDate(?)+1 STEP INTERVAL 1 YEAR "
 		}
@@ -2,6 +2,7 @@ package analyzer
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pirsch-analytics/pirsch/v6/pkg"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/util"
@@ -496,3 +497,312 @@ func TestQueryImported(t *testing.T) {
 	assert.Len(t, args, 17)
 	assert.Equal(t, `SELECT coalesce(nullif(t.country_code, ''), imp.country_code) country_code,sum(t.visitors + imp.visitors) visitors,toFloat64OrDefault(visitors / greatest((SELECT uniq(visitor_id) FROM "session" WHERE client_id = ? AND toDate(time, 'UTC') >= toDate(?) AND toDate(time, 'UTC') <= toDate(?) ) + (SELECT sum(visitors) FROM "imported_country" WHERE client_id = ? AND toDate(date, 'UTC') >= toDate(?) AND toDate(date, 'UTC') <= toDate(?) ), 1)) relative_visitors FROM (SELECT country_code country_code,uniq(t.visitor_id) visitors,toFloat64OrDefault(visitors / greatest((SELECT uniq(visitor_id) FROM "session" WHERE client_id = ? AND toDate(time, 'UTC') >= toDate(?) AND toDate(time, 'UTC') <= toDate(?) ), 1)) relative_visitors FROM "session" t WHERE client_id = ? AND toDate(time, 'UTC') >= toDate(?) AND toDate(time, 'UTC') <= toDate(?) AND country_code = ? GROUP BY country_code HAVING sum(sign) > 0 ORDER BY visitors DESC ) t FULL JOIN (SELECT country_code,sum(visitors) visitors FROM "imported_country" WHERE client_id = ? AND toDate(date, 'UTC') >= toDate(?) AND toDate(date, 'UTC') <= toDate(?)  AND country_code = ? GROUP BY country_code ) imp ON t.country_code = imp.country_code GROUP BY country_code ORDER BY visitors DESC LIMIT 10 `, queryStr)
 }
+
+func TestQueryImportedHour(t *testing.T) {
+	filter := &Filter{
+		ClientID:      42,
+		From:          util.PastDay(14),
+		To:            util.Today(),
+		ImportedUntil: util.PastDay(7),
+	}
+	filter.validate()
+	queryStr, args := filter.buildQuery([]Field{FieldHour, FieldVisitors}, []Field{FieldHour}, []Field{FieldHour}, []Field{FieldHour, FieldVisitors}, "imported_hour")
+	assert.Contains(t, queryStr, `FULL JOIN (SELECT hour hour,sum(visitors) visitors FROM (SELECT client_id, date, hour, visitors, views, sessions, bounces FROM "imported_hour" UNION ALL SELECT client_id, date, 0 hour, visitors, views, sessions, bounces FROM "imported_visitors" WHERE (client_id, date) NOT IN (SELECT client_id, date FROM "imported_hour" WHERE client_id = ? AND toDate(date, 'UTC') >= toDate(?) AND toDate(date, 'UTC') <= toDate(?) )) WHERE client_id = ? AND toDate(date, 'UTC') >= toDate(?) AND toDate(date, 'UTC') <= toDate(?) `)
+	from, until := util.PastDay(14).Format(time.DateOnly), util.PastDay(8).Format(time.DateOnly)
+	assert.Equal(t, []any{int64(42), from, until, int64(42), from, until}, args[len(args)-6:])
+}
+
+func TestQueryImportedPeriodHour(t *testing.T) {
+	tz, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	filter := &Filter{
+		ClientID:      42,
+		Timezone:      tz,
+		From:          util.PastDay(14),
+		To:            util.Today(),
+		ImportedUntil: util.PastDay(7),
+		Period:        pkg.PeriodHour,
+	}
+	filter.validate()
+	queryStr, args := filter.buildQuery([]Field{FieldDay, FieldVisitors}, []Field{FieldDay}, []Field{FieldDay}, []Field{FieldDay, FieldVisitors}, "imported_visitors")
+	assert.Contains(t, queryStr, `FULL JOIN (SELECT time hour,sum(visitors) visitors FROM (SELECT client_id, date, toDateTime(date, 'Europe/Berlin') + toIntervalHour(hour) time, visitors, views, sessions, bounces FROM (SELECT client_id, date, hour, visitors, views, sessions, bounces FROM "imported_hour" UNION ALL SELECT client_id, date, 0 hour, visitors, views, sessions, bounces FROM "imported_visitors" WHERE (client_id, date) NOT IN (SELECT client_id, date FROM "imported_hour" WHERE client_id = ? AND toDate(date, 'Europe/Berlin') >= toDate(?) AND toDate(date, 'Europe/Berlin') <= toDate(?) ))) WHERE client_id = ? AND toDate(date, 'Europe/Berlin') >= toDate(?) AND toDate(date, 'Europe/Berlin') <= toDate(?)  GROUP BY hour ) imp ON t.hour = imp.hour `)
+	from, until := util.PastDay(14).Format(time.DateOnly), util.PastDay(8).Format(time.DateOnly)
+	assert.Equal(t, []any{int64(42), from, until, int64(42), from, until}, args[len(args)-8:len(args)-2])
+}
+
+func TestQueryImportedEngagement(t *testing.T) {
+	filter := &Filter{
+		ClientID:      42,
+		From:          util.PastDay(14),
+		To:            util.Today(),
+		ImportedUntil: util.PastDay(7),
+	}
+	filter.validate()
+	queryStr, _ := filter.buildQuery([]Field{FieldSessions, FieldEngagedSessions, FieldEngagementRate}, nil, nil, []Field{FieldSessions}, "imported_visitors")
+	assert.Contains(t, queryStr, "SELECT sum(t.sessions + imp.sessions) sessions,sum(t.engaged_sessions) engaged_sessions,engaged_sessions / IF(sum(t.sessions) = 0, 1, sum(t.sessions)) engagement_rate FROM")
+}
+
+func TestQueryImportedMissingMetrics(t *testing.T) {
+	filter := &Filter{
+		ClientID:      42,
+		From:          util.PastDay(14),
+		To:            util.Today(),
+		ImportedUntil: util.PastDay(7),
+	}
+	filter.validate()
+	fields := append([]Field{FieldCountry}, metaFields...)
+	queryStr, _ := filter.buildQuery(fields, []Field{FieldCountry}, []Field{FieldVisitors, FieldCountry}, []Field{FieldCountry, FieldVisitors}, "imported_country")
+	assert.Contains(t, queryStr, "sum(t.sessions + imp.sessions) sessions,sum(t.views + imp.views) views,sum(t.engaged_sessions) engaged_sessions,")
+	assert.Contains(t, queryStr, `FULL JOIN (SELECT country_code,sum(visitors) visitors,0 sessions,0 views FROM "imported_country" WHERE `)
+}
+
+func TestQueryImportedVersion(t *testing.T) {
+	filter := &Filter{
+		ClientID:      42,
+		From:          util.PastDay(14),
+		To:            util.Today(),
+		ImportedUntil: util.PastDay(7),
+		OS:            []string{"Windows"},
+		OSVersion:     []string{"10"},
+	}
+	filter.validate()
+	q := queryBuilder{
+		filter: filter,
+		fields: []Field{
+			FieldOS,
+			FieldOSVersion,
+			FieldVisitors,
+		},
+		fieldsImported: []Field{
+			FieldOS,
+			FieldOSVersion,
+			FieldVisitors,
+		},
+		from:         sessions,
+		fromImported: "imported_os_version",
+		orderBy: []Field{
+			FieldVisitors,
+		},
+		groupBy: []Field{
+			FieldOS,
+			FieldOSVersion,
+		},
+	}
+	queryStr, args := q.query()
+	assert.Len(t, args, 10)
+	assert.Equal(t, `SELECT coalesce(nullif(t.os, ''), imp.os) os,coalesce(nullif(t.os_version, ''), imp.os_version) os_version,sum(t.visitors + imp.visitors) visitors FROM (SELECT os os,os_version os_version,uniq(t.visitor_id) visitors FROM "session" t WHERE client_id = ? AND toDate(time, 'UTC') >= toDate(?) AND toDate(time, 'UTC') <= toDate(?) AND os = ? AND os_version = ? GROUP BY os,os_version HAVING sum(sign) > 0 ORDER BY visitors DESC ) t FULL JOIN (SELECT os,os_version,sum(visitors) visitors FROM "imported_os_version" WHERE client_id = ? AND toDate(date, 'UTC') >= toDate(?) AND toDate(date, 'UTC') <= toDate(?)  AND os = ? AND os_version = ? GROUP BY os,os_version ) imp ON t.os = imp.os AND t.os_version = imp.os_version GROUP BY os,os_version ORDER BY visitors DESC `, queryStr)
+}
+
+func TestQueryImportedTag(t *testing.T) {
+	filter := &Filter{
+		ClientID:      42,
+		From:          util.PastDay(14),
+		To:            util.Today(),
+		ImportedUntil: util.PastDay(7),
+		Tag:           []string{"author"},
+	}
+	filter.validate()
+	queryStr, args := filter.buildQuery([]Field{
+		FieldTagValue,
+		FieldVisitors,
+		FieldRelativeVisitors,
+	}, []Field{
+		FieldTagValue,
+	}, []Field{
+		FieldVisitors,
+	}, []Field{
+		FieldTagValue,
+		FieldVisitors,
+	}, "imported_tag")
+	assert.Len(t, args, 18)
+	assert.Equal(t, "author", args[6])
+	assert.Equal(t, "author", args[13])
+	assert.Equal(t, "author", args[17])
+	assert.Contains(t, queryStr, "SELECT coalesce(nullif(t.value, ''), imp.value) value,")
+	assert.Contains(t, queryStr, `(SELECT sum(visitors) FROM "imported_visitors" WHERE`)
+	assert.Contains(t, queryStr, `FULL JOIN (SELECT tag_value value,sum(visitors) visitors FROM "imported_tag" WHERE client_id = ? AND toDate(date, 'UTC') >= toDate(?) AND toDate(date, 'UTC') <= toDate(?)  AND tag_key = ? GROUP BY value ) imp ON t.value = imp.value `)
+}
+
+func TestQueryExpression(t *testing.T) {
+	filter := &Filter{
+		ClientID: 42,
+		Country:  []string{"!gb"},
+		Expression: &Expression{Or: []Expression{
+			{And: []Expression{
+				{Field: FieldCountry, Value: []string{"de"}},
+				{Field: FieldChannel, Value: []string{"Paid Search"}},
+			}},
+			{Not: &Expression{Field: FieldUTMCampaign, Value: []string{"~spring", "!null"}}},
+		}},
+	}
+	filter.validate()
+	queryStr, args := filter.buildQuery([]Field{FieldVisitors}, nil, nil, nil, "")
+	assert.Equal(t, []any{int64(42), "gb", "de", "Paid Search", "%spring%", ""}, args)
+	assert.Equal(t, `SELECT uniq(t.visitor_id) visitors FROM "session" t WHERE client_id = ? AND country_code != ? AND ((country_code = ? AND channel = ? ) OR NOT ((ilike(utm_campaign, ?) = 1 AND utm_campaign != ? ) ) ) HAVING sum(sign) > 0 `, queryStr)
+
+	// evaluated by the joined page views
+	filter = &Filter{
+		ClientID: 42,
+		Expression: &Expression{Or: []Expression{
+			{Field: FieldPath, Value: []string{"/"}},
+			{Field: FieldCountry, Value: []string{"de"}},
+		}},
+	}
+	filter.validate()
+	queryStr, args = filter.buildQuery([]Field{FieldEntryPath, FieldVisitors}, nil, nil, nil, "")
+	assert.Equal(t, []any{int64(42), int64(42), "/", "de"}, args)
+	assert.Equal(t, `SELECT entry_path entry_path,uniq(t.visitor_id) visitors FROM "page_view" t JOIN (SELECT t.visitor_id visitor_id,t.session_id session_id,entry_path entry_path FROM "session" t WHERE client_id = ? GROUP BY t.visitor_id,t.session_id,entry_path HAVING sum(sign) > 0 ) j ON j.visitor_id = t.visitor_id AND j.session_id = t.session_id WHERE client_id = ? AND (path = ? OR country_code = ? ) `, queryStr)
+
+	// evaluated by the joined sessions
+	filter = &Filter{
+		ClientID: 42,
+		Expression: &Expression{Or: []Expression{
+			{Field: FieldEntryPath, Value: []string{"/"}},
+			{Field: FieldCountry, Value: []string{"de"}},
+		}},
+	}
+	filter.validate()
+	queryStr, args = filter.buildQuery([]Field{FieldPath, FieldVisitors}, nil, nil, nil, "")
+	assert.Equal(t, []any{int64(42), "/", "de", int64(42)}, args)
+	assert.Equal(t, `SELECT path path,uniq(t.visitor_id) visitors FROM "page_view" t JOIN (SELECT t.visitor_id visitor_id,t.session_id session_id FROM "session" t WHERE client_id = ? AND (entry_path = ? OR country_code = ? ) GROUP BY t.visitor_id,t.session_id HAVING sum(sign) > 0 ) j ON j.visitor_id = t.visitor_id AND j.session_id = t.session_id WHERE client_id = ? `, queryStr)
+
+	// evaluated on the left joined events
+	filter = &Filter{
+		ClientID: 42,
+		Expression: &Expression{Or: []Expression{
+			{Field: FieldEventName, Value: []string{"signup"}},
+			{Field: FieldCountry, Value: []string{"de"}},
+		}},
+	}
+	filter.validate()
+	queryStr, args = filter.buildQuery([]Field{FieldVisitors}, nil, nil, nil, "")
+	assert.Equal(t, []any{int64(42), int64(42), "signup", "de"}, args)
+	assert.Equal(t, `SELECT uniq(t.visitor_id) visitors FROM "session" t LEFT JOIN (SELECT t.visitor_id visitor_id,t.session_id session_id,event_name event_name FROM "event" t WHERE client_id = ? GROUP BY t.visitor_id,t.session_id,event_name ) l ON l.visitor_id = t.visitor_id AND l.session_id = t.session_id WHERE client_id = ? AND (event_name = ? OR country_code = ? ) HAVING sum(sign) > 0 `, queryStr)
+}
+
+func TestQueryRange(t *testing.T) {
+	filter := &Filter{
+		ClientID:         42,
+		SessionDuration:  &Range{Operator: RangeGreater, Value: 60},
+		SessionPageViews: &Range{Operator: RangeBetween, Value: 5, To: 3},
+	}
+	filter.validate()
+	queryStr, args := filter.buildQuery([]Field{FieldVisitors}, nil, nil, nil, "")
+	assert.Equal(t, []any{int64(42), float64(60), float64(3), float64(5)}, args)
+	assert.Equal(t, `SELECT uniq(t.visitor_id) visitors FROM "session" t WHERE client_id = ? AND t.duration_seconds > ? AND t.page_views BETWEEN ? AND ? HAVING sum(sign) > 0 `, queryStr)
+
+	// matched by session for other tables
+	filter = &Filter{
+		ClientID:        42,
+		SessionDuration: &Range{Operator: RangeGreater, Value: 60},
+	}
+	filter.validate()
+	queryStr, args = filter.buildQuery([]Field{FieldPath, FieldVisitors}, nil, nil, nil, "")
+	assert.Equal(t, []any{int64(42), int64(42), float64(60)}, args)
+	assert.Equal(t, `SELECT path path,uniq(t.visitor_id) visitors FROM "page_view" t WHERE client_id = ? AND (t.visitor_id, t.session_id) IN (SELECT visitor_id, session_id FROM "session" t WHERE client_id = ? AND t.duration_seconds > ? GROUP BY visitor_id, session_id HAVING sum(sign) > 0) `, queryStr)
+
+	// event metadata using the custom metric type
+	filter = &Filter{
+		ClientID:         42,
+		EventName:        []string{"Sale"},
+		EventDuration:    &Range{Operator: RangeLess, Value: 5},
+		EventMetaRange:   map[string]Range{"amount": {Operator: RangeGreater, Value: 100}},
+		CustomMetricType: pkg.CustomMetricTypeInteger,
+	}
+	filter.validate()
+	queryStr, args = filter.buildQuery([]Field{FieldEventName, FieldVisitors}, nil, nil, nil, "")
+	assert.Equal(t, []any{int64(42), "Sale", float64(5), "amount", "amount", float64(100)}, args)
+	assert.Equal(t, `SELECT event_name event_name,uniq(t.visitor_id) visitors FROM "event" t WHERE client_id = ? AND event_name = ? AND t.duration_seconds < ? AND has(event_meta_keys, ?) AND toInt64OrZero(event_meta_values[indexOf(event_meta_keys, ?)]) > ? `, queryStr)
+	filter.CustomMetricType = ""
+	queryStr, args = filter.buildQuery([]Field{FieldVisitors}, nil, nil, nil, "")
+	assert.Equal(t, []any{int64(42), int64(42), "Sale", int64(42), "Sale", float64(5), "amount", "amount", float64(100)}, args)
+	assert.Contains(t, queryStr, `AND (t.visitor_id, t.session_id) IN (SELECT visitor_id, session_id FROM "event" t WHERE client_id = ? AND event_name = ? AND t.duration_seconds < ? AND has(event_meta_keys, ?) AND toFloat64OrZero(event_meta_values[indexOf(event_meta_keys, ?)]) > ?) `)
+
+	// time on page
+	filter = &Filter{
+		ClientID:             42,
+		TimeOnPage:           &Range{Operator: RangeLess, Value: 5},
+		MaxTimeOnPageSeconds: 100,
+	}
+	filter.validate()
+	queryStr, args = filter.buildQuery([]Field{FieldPath, FieldVisitors}, nil, nil, nil, "")
+	assert.Equal(t, []any{int64(42), int64(42), float64(5)}, args)
+	assert.Equal(t, `SELECT path path,uniq(t.visitor_id) visitors FROM "page_view" t WHERE client_id = ? AND (t.visitor_id, t.session_id, t."time") IN (SELECT visitor_id, session_id, "time" FROM (SELECT visitor_id, session_id, "time", nth_value(least(duration_seconds, 100), 2) OVER (PARTITION BY visitor_id, session_id ORDER BY "time" ASC Rows BETWEEN CURRENT ROW AND 1 FOLLOWING) AS time_on_page FROM "page_view" WHERE client_id = ? ) WHERE time_on_page > 0 AND time_on_page < ?) `, queryStr)
+	queryStr, _ = filter.buildQuery([]Field{FieldVisitors}, nil, nil, nil, "")
+	assert.Contains(t, queryStr, `AND (t.visitor_id, t.session_id) IN (SELECT visitor_id, session_id FROM (SELECT visitor_id, session_id, "time", nth_value(`)
+}
+
+func TestQuerySegment(t *testing.T) {
+	filter := &Filter{
+		ClientID: 42,
+		SegmentConditions: []SegmentCondition{
+			{Path: "/pricing"},
+			{Event: "signup", Not: true, MinCount: 2, MaxCount: 5},
+		},
+	}
+	filter.validate()
+	queryStr, args := filter.buildQuery([]Field{FieldVisitors}, nil, nil, nil, "")
+	assert.Equal(t, []any{int64(42), int64(42), "/pricing", 1, int64(42), "signup", 2, 5}, args)
+	assert.Equal(t, `SELECT uniq(t.visitor_id) visitors FROM "session" t WHERE client_id = ? AND (t.visitor_id, t.session_id) IN (SELECT visitor_id, session_id FROM "page_view" t WHERE client_id = ? AND path = ? GROUP BY visitor_id, session_id HAVING count(*) >= ?) AND (t.visitor_id, t.session_id) NOT IN (SELECT visitor_id, session_id FROM "event" t WHERE client_id = ? AND event_name = ? GROUP BY visitor_id, session_id HAVING count(*) >= ? AND count(*) <= ?) HAVING sum(sign) > 0 `, queryStr)
+
+	// matched across all sessions of the visitor
+	filter = &Filter{
+		ClientID:          42,
+		SegmentConditions: []SegmentCondition{{Event: "~sign", Visitor: true}},
+	}
+	filter.validate()
+	queryStr, args = filter.buildQuery([]Field{FieldPath, FieldVisitors}, nil, nil, nil, "")
+	assert.Equal(t, []any{int64(42), int64(42), "%sign%", 1}, args)
+	assert.Equal(t, `SELECT path path,uniq(t.visitor_id) visitors FROM "page_view" t WHERE client_id = ? AND t.visitor_id IN (SELECT visitor_id FROM "event" t WHERE client_id = ? AND ilike(event_name, ?) = 1 GROUP BY visitor_id HAVING count(*) >= ?) `, queryStr)
+
+	// unresolved segments match nothing
+	filter = &Filter{ClientID: 42, Segment: "unknown"}
+	filter.validate()
+	queryStr, args = filter.buildQuery([]Field{FieldVisitors}, nil, nil, nil, "")
+	assert.Equal(t, []any{int64(42)}, args)
+	assert.Equal(t, `SELECT uniq(t.visitor_id) visitors FROM "session" t WHERE client_id = ? AND 0 = 1 HAVING sum(sign) > 0 `, queryStr)
+}
+
+func TestQueryPathGroup(t *testing.T) {
+	filter := &Filter{ClientID: 42}
+	filter.validate()
+	query, args := directoryQuery(2)
+	where, whereArgs := directoryQuery(1)
+	filter.pathGroup = &pathGroup{
+		query:     query,
+		args:      args,
+		where:     where + " = ? ",
+		whereArgs: append(whereArgs, "/blog"),
+	}
+	queryStr, args := filter.buildQuery([]Field{FieldPathGroup, FieldVisitors}, []Field{FieldPathGroup}, []Field{FieldVisitors, FieldPathGroup}, nil, "")
+	assert.Equal(t, []any{3, int64(42), 2, "/blog"}, args)
+	assert.Equal(t, `SELECT arrayStringConcat(arraySlice(splitByChar('/', replaceRegexpOne(path, '(.)/+$', '\\1')), 1, ?), '/') path_group,uniq(t.visitor_id) visitors FROM "page_view" t WHERE client_id = ? AND arrayStringConcat(arraySlice(splitByChar('/', replaceRegexpOne(path, '(.)/+$', '\\1')), 1, ?), '/') = ? GROUP BY path_group ORDER BY visitors DESC,path_group ASC `, queryStr)
+}
+
+func TestQueryPeriod(t *testing.T) {
+	tz, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	filter := &Filter{
+		ClientID: 42,
+		Timezone: tz,
+		From:     time.Date(2023, 10, 28, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2023, 10, 30, 0, 0, 0, 0, time.UTC),
+		Period:   pkg.PeriodHour,
+	}
+	filter.validate()
+	queryStr, args := filter.buildQuery([]Field{FieldDay, FieldVisitors}, []Field{FieldDay}, []Field{FieldDay}, nil, "")
+	assert.Equal(t, []any{int64(42), "2023-10-28", "2023-10-30", "2023-10-28", "2023-10-30"}, args)
+	assert.Equal(t, `SELECT toStartOfHour(time, 'Europe/Berlin') hour,uniq(t.visitor_id) visitors FROM "session" t WHERE client_id = ? AND toDate(time, 'Europe/Berlin') >= toDate(?) AND toDate(time, 'Europe/Berlin') <= toDate(?) GROUP BY hour HAVING sum(sign) > 0 ORDER BY hour ASC WITH FILL FROM toDateTime(toDate(?), 'Europe/Berlin') TO toDateTime(toDate(?)+1, 'Europe/Berlin') STEP INTERVAL 1 HOUR  `, queryStr)
+	filter.IncludeTime = true
+	filter.From = time.Date(2023, 10, 28, 10, 30, 0, 0, tz)
+	filter.To = time.Date(2023, 10, 29, 10, 30, 0, 0, tz)
+	filter.validate()
+	queryStr, args = filter.buildQuery([]Field{FieldDay, FieldVisitors}, []Field{FieldDay}, []Field{FieldDay}, nil, "")
+	assert.Equal(t, []any{int64(42), filter.From, filter.To, filter.From, filter.To}, args)
+	assert.Contains(t, queryStr, `ORDER BY hour ASC WITH FILL FROM toStartOfHour(toDateTime(?, 'Europe/Berlin')) TO toDateTime(?, 'Europe/Berlin')+1 STEP INTERVAL 1 HOUR `)
+	filter.IncludeTime = false
+	filter.Period = pkg.PeriodQuarter
+	filter.validate()
+	queryStr, _ = filter.buildQuery([]Field{FieldDay, FieldVisitors}, []Field{FieldDay}, []Field{FieldDay}, nil, "")
+	assert.Equal(t, `SELECT toStartOfQuarter(toDate(time, 'Europe/Berlin')) quarter,uniq(t.visitor_id) visitors FROM "session" t WHERE client_id = ? AND toDate(time, 'Europe/Berlin') >= toDate(?) AND toDate(time, 'Europe/Berlin') <= toDate(?) GROUP BY quarter HAVING sum(sign) > 0 ORDER BY quarter ASC WITH FILL FROM toStartOfQuarter(toDate(?)) TO toDate(?)+1 STEP INTERVAL 1 QUARTER  `, queryStr)
+}
@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+)
+
+var (
+	// ErrInvalidSegment is returned in case a segment has no name or a condition doesn't set exactly one of event or path.
+	ErrInvalidSegment = errors.New("segment requires a name and exactly one of event or path for each condition")
+
+	// ErrSegmentNotFound is returned in case a segment does not exist for the client.
+	ErrSegmentNotFound = errors.New("segment not found")
+)
+
+// Segment is a named list of conditions that sessions must match.
+// The conditions are combined using AND.
+type Segment struct {
+	Name       string
+	Conditions []SegmentCondition
+}
+
+// SegmentCondition matches sessions by what happened in them.
+// Either the Event or the Path must be set. They use the same syntax as the Filter fields (like "~" for contains).
+//
+// Example for sessions that viewed /pricing, but never triggered the signup event:
+//
+//	[]SegmentCondition{
+//		{Path: "/pricing"},
+//		{Event: "signup", Not: true},
+//	}
+type SegmentCondition struct {
+	// Event is the name of the event that must have been triggered.
+	Event string
+
+	// Path is the path of the page that must have been viewed.
+	Path string
+
+	// Not inverts the condition to match sessions that did not trigger the event or view the page (at least MinCount times).
+	Not bool
+
+	// Visitor matches the condition across all sessions of the visitor in the selected period instead of the session.
+	Visitor bool
+
+	// MinCount is the minimum number of times the event must have been triggered or the page must have been viewed.
+	// Defaults to 1.
+	MinCount int
+
+	// MaxCount is the optional maximum number of times the event must have been triggered or the page must have been viewed.
+	// Set to 0 to disable (default).
+	MaxCount int
+}
+
+// Segments manages saved segments that can be referenced using Filter.Segment.
+type Segments struct {
+	store db.Store
+}
+
+// Save creates or updates a segment for given client.
+// Segments are identified by their name (case-insensitive).
+func (segments *Segments) Save(ctx context.Context, clientID uint64, segment Segment) error {
+	if err := segment.validate(); err != nil {
+		return err
+	}
+
+	conditions := make([]model.SegmentCondition, 0, len(segment.Conditions))
+
+	for _, condition := range segment.Conditions {
+		conditions = append(conditions, model.SegmentCondition(condition))
+	}
+
+	return segments.store.SaveSegments(ctx, []model.Segment{
+		{
+			ClientID:   clientID,
+			Name:       segment.Name,
+			Conditions: conditions,
+			Version:    time.Now().UTC(),
+		},
+	})
+}
+
+// Delete removes the segment for given client and name.
+func (segments *Segments) Delete(ctx context.Context, clientID uint64, name string) error {
+	return segments.store.DeleteSegment(ctx, clientID, name)
+}
+
+// Get returns the segment for given client and name or ErrSegmentNotFound.
+func (segments *Segments) Get(ctx context.Context, clientID uint64, name string) (*Segment, error) {
+	list, err := segments.selectSegments(ctx, clientID, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, ErrSegmentNotFound
+	}
+
+	return &list[0], nil
+}
+
+// List returns all segments for given client ordered by name.
+func (segments *Segments) List(ctx context.Context, clientID uint64) ([]Segment, error) {
+	return segments.selectSegments(ctx, clientID, "")
+}
+
+func (segments *Segments) selectSegments(ctx context.Context, clientID uint64, name string) ([]Segment, error) {
+	args := []any{clientID}
+	var where string
+
+	if name != "" {
+		where = "AND key = ? "
+		args = append(args, strings.ToLower(name))
+	}
+
+	query := fmt.Sprintf(`SELECT client_id, name, condition_events, condition_paths, condition_not, condition_visitor,
+		condition_min_counts, condition_max_counts, version
+		FROM "segment" FINAL
+		WHERE client_id = ? %s
+		AND deleted = 0
+		ORDER BY key`, where)
+	list, err := segments.store.SelectSegments(ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Segment, 0, len(list))
+
+	for _, segment := range list {
+		conditions := make([]SegmentCondition, 0, len(segment.Conditions))
+
+		for _, condition := range segment.Conditions {
+			conditions = append(conditions, SegmentCondition(condition))
+		}
+
+		results = append(results, Segment{
+			Name:       segment.Name,
+			Conditions: conditions,
+		})
+	}
+
+	return results, nil
+}
+
+func (segment *Segment) validate() error {
+	if strings.TrimSpace(segment.Name) == "" || len(segment.Conditions) == 0 {
+		return ErrInvalidSegment
+	}
+
+	for i := range segment.Conditions {
+		if !segment.Conditions[i].valid() {
+			return ErrInvalidSegment
+		}
+	}
+
+	return nil
+}
+
+func (condition *SegmentCondition) valid() bool {
+	return (condition.Event == "") != (condition.Path == "") &&
+		condition.MinCount >= 0 &&
+		condition.MaxCount >= 0 &&
+		(condition.MaxCount == 0 || condition.MaxCount >= max(condition.MinCount, 1))
+}
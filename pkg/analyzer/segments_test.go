@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegments(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	ctx := context.Background()
+	segments := NewAnalyzer(dbClient).Segments
+	assert.ErrorIs(t, segments.Save(ctx, 1, Segment{Name: " ", Conditions: []SegmentCondition{{Path: "/"}}}), ErrInvalidSegment)
+	assert.ErrorIs(t, segments.Save(ctx, 1, Segment{Name: "Empty"}), ErrInvalidSegment)
+	assert.ErrorIs(t, segments.Save(ctx, 1, Segment{Name: "Both", Conditions: []SegmentCondition{{Event: "signup", Path: "/"}}}), ErrInvalidSegment)
+	assert.ErrorIs(t, segments.Save(ctx, 1, Segment{Name: "Count", Conditions: []SegmentCondition{{Path: "/", MinCount: 3, MaxCount: 2}}}), ErrInvalidSegment)
+	assert.ErrorIs(t, segments.Save(ctx, 1, Segment{Name: "Negative", Conditions: []SegmentCondition{{Path: "/", MinCount: -1}}}), ErrInvalidSegment)
+	assert.NoError(t, segments.Save(ctx, 1, Segment{Name: "Pricing", Conditions: []SegmentCondition{{Path: "/pricing"}, {Event: "signup", Not: true, Visitor: true}}}))
+	assert.NoError(t, segments.Save(ctx, 1, Segment{Name: "Buyers", Conditions: []SegmentCondition{{Event: "Sale", MaxCount: 1}}}))
+	assert.NoError(t, segments.Save(ctx, 2, Segment{Name: "Buyers", Conditions: []SegmentCondition{{Event: "Purchase", MinCount: 2}}}))
+	time.Sleep(time.Millisecond * 100)
+	segment, err := segments.Get(ctx, 1, "pricing")
+	assert.NoError(t, err)
+	assert.Equal(t, "Pricing", segment.Name)
+	assert.Equal(t, []SegmentCondition{{Path: "/pricing"}, {Event: "signup", Not: true, Visitor: true}}, segment.Conditions)
+	segment, err = segments.Get(ctx, 2, "Buyers")
+	assert.NoError(t, err)
+	assert.Equal(t, []SegmentCondition{{Event: "Purchase", MinCount: 2}}, segment.Conditions)
+	_, err = segments.Get(ctx, 3, "Buyers")
+	assert.ErrorIs(t, err, ErrSegmentNotFound)
+	list, err := segments.List(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, "Buyers", list[0].Name)
+	assert.Equal(t, "Pricing", list[1].Name)
+	assert.NoError(t, segments.Save(ctx, 1, Segment{Name: "BUYERS", Conditions: []SegmentCondition{{Event: "Sale"}}}))
+	time.Sleep(time.Millisecond * 100)
+	list, err = segments.List(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, "BUYERS", list[0].Name)
+	assert.NoError(t, segments.Delete(ctx, 1, "buyers"))
+	time.Sleep(time.Millisecond * 100)
+	_, err = segments.Get(ctx, 1, "Buyers")
+	assert.ErrorIs(t, err, ErrSegmentNotFound)
+	list, err = segments.List(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	list, err = segments.List(ctx, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+}
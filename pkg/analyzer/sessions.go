@@ -18,9 +18,24 @@ type Sessions struct {
 
 // List returns a list of sessions for a given filter.
 func (sessions *Sessions) List(filter *Filter) ([]model.Session, error) {
-	filter = sessions.analyzer.getFilter(filter)
+	stats, _, err := sessions.list(filter)
+	return stats, err
+}
+
+// ListPage returns a page of Sessions.List together with the total number of rows.
+func (sessions *Sessions) ListPage(filter *Filter) (*model.PaginatedStats[model.Session], error) {
+	return paginate(sessions.list(filter))
+}
+
+func (sessions *Sessions) list(filter *Filter) ([]model.Session, *rowCount, error) {
+	filter, err := sessions.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
 	filter.Sample = 0
-	q, args := filter.buildQuery([]Field{
+	q, args, count := filter.buildListQuery(sessions.store, []Field{
 		FieldSessionsAll,
 	}, []Field{
 		FieldVisitorID,
@@ -42,6 +57,7 @@ func (sessions *Sessions) List(filter *Filter) ([]model.Session, error) {
 				exit_paths[length(exit_paths)] exit_path,
 				max(t.session_page_views),
 				min(t.session_is_bounce),
+				max(t.session_is_engaged),
 				any(t.session_entry_title),
 		        groupArray(t.session_exit_title) exit_titles,
 				exit_titles[length(exit_titles)] exit_title,
@@ -72,15 +88,19 @@ func (sessions *Sessions) List(filter *Filter) ([]model.Session, error) {
 	stats, err := sessions.store.SelectSessions(filter.Ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return stats, nil
+	return stats, count, nil
 }
 
 // Breakdown returns the page views and events for a single session in chronological order.
 func (sessions *Sessions) Breakdown(filter *Filter) ([]model.SessionStep, error) {
-	filter = sessions.analyzer.getFilter(filter)
+	filter, err := sessions.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
 
 	if filter.VisitorID == 0 || filter.SessionID == 0 {
 		return nil, nil
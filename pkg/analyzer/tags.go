@@ -13,8 +13,23 @@ type Tags struct {
 
 // Keys return the visitor count grouped by tag keys.
 func (tags *Tags) Keys(filter *Filter) ([]model.TagStats, error) {
-	filter = tags.analyzer.getFilter(filter)
-	q, args := filter.buildQuery([]Field{
+	stats, _, err := tags.keys(filter)
+	return stats, err
+}
+
+// KeysPage returns a page of Tags.Keys together with the total number of rows.
+func (tags *Tags) KeysPage(filter *Filter) (*model.PaginatedStats[model.TagStats], error) {
+	return paginate(tags.keys(filter))
+}
+
+func (tags *Tags) keys(filter *Filter) ([]model.TagStats, *rowCount, error) {
+	filter, err := tags.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q, args, count := filter.buildListQuery(tags.store, []Field{
 		FieldTagKey,
 		FieldVisitors,
 		FieldViews,
@@ -25,26 +40,44 @@ func (tags *Tags) Keys(filter *Filter) ([]model.TagStats, error) {
 	}, []Field{
 		FieldVisitors,
 		FieldTagKey,
-	}, nil, "")
+	}, []Field{
+		FieldTagKey,
+		FieldVisitors,
+		FieldViews,
+	}, "imported_tag")
 	stats, err := tags.store.SelectTagStats(filter.Ctx, false, q, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return stats, nil
+	return stats, count, nil
 }
 
 // Breakdown returns the visitor count for tags grouping them by a given key.
 // The Filter.Tag must be set, or otherwise the result set will be empty.
 func (tags *Tags) Breakdown(filter *Filter) ([]model.TagStats, error) {
-	filter = tags.analyzer.getFilter(filter)
+	stats, _, err := tags.breakdown(filter)
+	return stats, err
+}
+
+// BreakdownPage returns a page of Tags.Breakdown together with the total number of rows.
+func (tags *Tags) BreakdownPage(filter *Filter) (*model.PaginatedStats[model.TagStats], error) {
+	return paginate(tags.breakdown(filter))
+}
+
+func (tags *Tags) breakdown(filter *Filter) ([]model.TagStats, *rowCount, error) {
+	filter, err := tags.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
 
 	if len(filter.Tag) == 0 {
-		return []model.TagStats{}, nil
+		return []model.TagStats{}, &rowCount{filter: filter}, nil
 	}
 
-	q, args := filter.buildQuery([]Field{
+	q, args, count := filter.buildListQuery(tags.store, []Field{
 		FieldTagValue,
 		FieldVisitors,
 		FieldViews,
@@ -55,12 +88,16 @@ func (tags *Tags) Breakdown(filter *Filter) ([]model.TagStats, error) {
 	}, []Field{
 		FieldVisitors,
 		FieldTagValue,
-	}, nil, "")
+	}, []Field{
+		FieldTagValue,
+		FieldVisitors,
+		FieldViews,
+	}, "imported_tag")
 	stats, err := tags.store.SelectTagStats(filter.Ctx, true, q, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return stats, nil
+	return stats, count, nil
 }
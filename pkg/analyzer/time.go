@@ -15,9 +15,14 @@ type Time struct {
 	store    db.Store
 }
 
-// AvgSessionDuration returns the average session duration grouped by day, week, month, or year.
+// AvgSessionDuration returns the average session duration grouped by hour, day, week, month, quarter, or year.
 func (t *Time) AvgSessionDuration(filter *Filter) ([]model.TimeSpentStats, error) {
-	filter = t.analyzer.getFilter(filter)
+	filter, err := t.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	table := filter.table([]Field{})
 
 	if table == events {
@@ -33,8 +38,8 @@ func (t *Time) AvgSessionDuration(filter *Filter) ([]model.TimeSpentStats, error
 	t.selectAvgTimeSpentPeriod(filter.Period, &query, filter.WeekdayMode)
 	query.WriteString(fmt.Sprintf(`SELECT "day", round(avg(duration)) average_time_spent_seconds
 		FROM (
-			SELECT toDate(time, '%s') "day", sum(duration_seconds*sign)/sum(sign) duration
-			FROM "session" s `, filter.Timezone.String()))
+			SELECT %s "day", sum(duration_seconds*sign)/sum(sign) duration
+			FROM "session" s `, t.selectDay(filter)))
 
 	if len(filter.Path) > 0 || len(filter.PathPattern) > 0 || len(filter.Tag) > 0 || len(filter.Tags) > 0 {
 		tagField := ""
@@ -81,9 +86,14 @@ func (t *Time) AvgSessionDuration(filter *Filter) ([]model.TimeSpentStats, error
 	return stats, nil
 }
 
-// AvgTimeOnPage returns the average time on the page grouped by day, week, month, or year.
+// AvgTimeOnPage returns the average time on the page grouped by hour, day, week, month, quarter, or year.
 func (t *Time) AvgTimeOnPage(filter *Filter) ([]model.TimeSpentStats, error) {
-	filter = t.analyzer.getFilter(filter)
+	filter, err := t.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	table := filter.table([]Field{})
 
 	if table == events {
@@ -91,9 +101,10 @@ func (t *Time) AvgTimeOnPage(filter *Filter) ([]model.TimeSpentStats, error) {
 	}
 
 	q := queryBuilder{
-		filter: filter,
-		from:   pageViews,
-		search: filter.Search,
+		filter:     filter,
+		from:       pageViews,
+		search:     filter.Search,
+		timeOnPage: true,
 	}
 	var query strings.Builder
 	t.selectAvgTimeSpentPeriod(filter.Period, &query, filter.WeekdayMode)
@@ -107,10 +118,10 @@ func (t *Time) AvgTimeOnPage(filter *Filter) ([]model.TimeSpentStats, error) {
 	fields = append(fields, FieldEventDurationSeconds.Name)
 	query.WriteString(fmt.Sprintf(`SELECT "day", round(avg(time_on_page)) average_time_spent_seconds
 		FROM (
-			SELECT toDate(time, '%s') "day",
+			SELECT %s "day",
 				nth_value(%s, 2) OVER (PARTITION BY v.visitor_id, v.session_id ORDER BY v."time" ASC Rows BETWEEN CURRENT ROW AND 1 FOLLOWING) AS time_on_page
 				%s
-			FROM page_view v `, filter.Timezone.String(), t.analyzer.timeOnPageQuery(filter), filterFields))
+			FROM page_view v `, t.selectDay(filter), t.analyzer.timeOnPageQuery(filter), filterFields))
 
 	if len(filter.EntryPath) > 0 || len(filter.ExitPath) > 0 {
 		query.WriteString(fmt.Sprintf(`INNER JOIN (
@@ -147,12 +158,16 @@ func (t *Time) AvgTimeOnPage(filter *Filter) ([]model.TimeSpentStats, error) {
 func (t *Time) selectAvgTimeSpentPeriod(period pkg.Period, query *strings.Builder, weekdayMode WeekdayMode) {
 	if period != pkg.PeriodDay {
 		switch period {
+		case pkg.PeriodHour:
+			query.WriteString(`SELECT "day" hour, round(avg(average_time_spent_seconds)) average_time_spent_seconds FROM (`)
 		case pkg.PeriodWeek:
-			query.WriteString(fmt.Sprintf(`SELECT round(avg(average_time_spent_seconds)) average_time_spent_seconds, toStartOfWeek("day", %d) week FROM (`, weekdayMode))
+			query.WriteString(fmt.Sprintf(`SELECT toStartOfWeek("day", %d) week, round(avg(average_time_spent_seconds)) average_time_spent_seconds FROM (`, weekdayMode))
 		case pkg.PeriodMonth:
-			query.WriteString(`SELECT round(avg(average_time_spent_seconds)) average_time_spent_seconds, toStartOfMonth("day") month FROM (`)
+			query.WriteString(`SELECT toStartOfMonth("day") month, round(avg(average_time_spent_seconds)) average_time_spent_seconds FROM (`)
+		case pkg.PeriodQuarter:
+			query.WriteString(`SELECT toStartOfQuarter("day") quarter, round(avg(average_time_spent_seconds)) average_time_spent_seconds FROM (`)
 		case pkg.PeriodYear:
-			query.WriteString(`SELECT round(avg(average_time_spent_seconds)) average_time_spent_seconds, toStartOfYear("day") year FROM (`)
+			query.WriteString(`SELECT toStartOfYear("day") year, round(avg(average_time_spent_seconds)) average_time_spent_seconds FROM (`)
 		default:
 			panic("unknown case for filter period")
 		}
@@ -162,10 +177,14 @@ func (t *Time) selectAvgTimeSpentPeriod(period pkg.Period, query *strings.Builde
 func (t *Time) groupByPeriod(period pkg.Period, query *strings.Builder) {
 	if period != pkg.PeriodDay {
 		switch period {
+		case pkg.PeriodHour:
+			query.WriteString(`) GROUP BY hour ORDER BY hour ASC`)
 		case pkg.PeriodWeek:
 			query.WriteString(`) GROUP BY week ORDER BY week ASC`)
 		case pkg.PeriodMonth:
 			query.WriteString(`) GROUP BY month ORDER BY month ASC`)
+		case pkg.PeriodQuarter:
+			query.WriteString(`) GROUP BY quarter ORDER BY quarter ASC`)
 		case pkg.PeriodYear:
 			query.WriteString(`) GROUP BY year ORDER BY year ASC`)
 		default:
@@ -173,3 +192,13 @@ func (t *Time) groupByPeriod(period pkg.Period, query *strings.Builder) {
 		}
 	}
 }
+
+// selectDay returns the column used to group the inner queries.
+// The time is truncated to the hour instead of the day for pkg.PeriodHour.
+func (t *Time) selectDay(filter *Filter) string {
+	if filter.Period == pkg.PeriodHour {
+		return fmt.Sprintf("toStartOfHour(time, '%s')", filter.Timezone.String())
+	}
+
+	return fmt.Sprintf("toDate(time, '%s')", filter.Timezone.String())
+}
@@ -121,6 +121,43 @@ func TestAnalyzer_AvgSessionDurationPeriod(t *testing.T) {
 	assert.Equal(t, time.Date(2023, 9, 24, 0, 0, 0, 0, time.UTC), stats[0].Week.Time)
 }
 
+func TestAnalyzer_AvgSessionDurationPeriodHourAndQuarter(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, VisitorID: 1, Time: time.Date(2023, 7, 1, 10, 5, 0, 0, time.UTC), Start: time.Now(), SessionID: 1, DurationSeconds: 28},
+			{Sign: 1, VisitorID: 2, Time: time.Date(2023, 7, 1, 10, 40, 0, 0, time.UTC), Start: time.Now(), SessionID: 2, DurationSeconds: 5},
+			{Sign: 1, VisitorID: 3, Time: time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC), Start: time.Now(), SessionID: 3, DurationSeconds: 35},
+			{Sign: 1, VisitorID: 4, Time: time.Date(2023, 10, 1, 8, 0, 0, 0, time.UTC), Start: time.Now(), SessionID: 4, DurationSeconds: 10},
+		},
+	})
+	analyzer := NewAnalyzer(dbClient)
+	stats, err := analyzer.Time.AvgSessionDuration(&Filter{
+		From:        time.Date(2023, 7, 1, 9, 0, 0, 0, time.UTC),
+		To:          time.Date(2023, 7, 1, 12, 30, 0, 0, time.UTC),
+		IncludeTime: true,
+		Period:      pkg.PeriodHour,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, stats, 4)
+	assert.Equal(t, time.Date(2023, 7, 1, 9, 0, 0, 0, time.UTC), stats[0].Hour.Time.UTC())
+	assert.Equal(t, 0, stats[0].AverageTimeSpentSeconds)
+	assert.Equal(t, (28+5)/2, stats[1].AverageTimeSpentSeconds)
+	assert.Equal(t, 0, stats[2].AverageTimeSpentSeconds)
+	assert.Equal(t, 35, stats[3].AverageTimeSpentSeconds)
+	stats, err = analyzer.Time.AvgSessionDuration(&Filter{
+		From:   time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2023, 10, 31, 0, 0, 0, 0, time.UTC),
+		Period: pkg.PeriodQuarter,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+	assert.Equal(t, time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), stats[0].Quarter.Time)
+	assert.Equal(t, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), stats[1].Quarter.Time)
+	assert.Equal(t, (28+5+35)/3, stats[0].AverageTimeSpentSeconds)
+	assert.Equal(t, 10, stats[1].AverageTimeSpentSeconds)
+}
+
 func TestAnalyzer_AvgSessionDurationTz(t *testing.T) {
 	db.CleanupDB(t, dbClient)
 	saveSessions(t, [][]model.Session{
@@ -414,6 +451,18 @@ func TestAnalyzer_AvgTimeOnPagePeriod(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, byDay, 1)
 	assert.Equal(t, 6, byDay[0].AverageTimeSpentSeconds)
+	byHour, err := analyzer.Time.AvgTimeOnPage(&Filter{
+		From:        time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2023, 10, 2, 1, 30, 0, 0, time.UTC),
+		IncludeTime: true,
+		Path:        []string{"/"},
+		Period:      pkg.PeriodHour,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, byHour, 2)
+	assert.Equal(t, time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC), byHour[0].Hour.Time.UTC())
+	assert.Equal(t, 8, byHour[0].AverageTimeSpentSeconds)
+	assert.Equal(t, 0, byHour[1].AverageTimeSpentSeconds)
 }
 
 func TestAnalyzer_AvgTimeOnPageTz(t *testing.T) {
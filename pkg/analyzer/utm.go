@@ -11,32 +11,137 @@ type UTM struct {
 	store    db.Store
 }
 
-// Source returns the visitor count grouped by utm source.
+// Source returns the visitor count and engagement grouped by utm source.
 func (utm *UTM) Source(filter *Filter) ([]model.UTMSourceStats, error) {
-	ctx, q, args := utm.analyzer.selectByAttribute(filter, "imported_utm_source", FieldUTMSource)
-	return utm.store.SelectUTMSourceStats(ctx, q, args...)
+	stats, _, err := utm.source(filter)
+	return stats, err
 }
 
-// Medium returns the visitor count grouped by utm medium.
+// SourcePage returns a page of UTM.Source together with the total number of rows.
+func (utm *UTM) SourcePage(filter *Filter) (*model.PaginatedStats[model.UTMSourceStats], error) {
+	return paginate(utm.source(filter))
+}
+
+func (utm *UTM) source(filter *Filter) ([]model.UTMSourceStats, *rowCount, error) {
+	ctx, q, args, count, err := utm.analyzer.selectByAttribute(filter, "imported_utm_source", FieldUTMSource)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := utm.store.SelectUTMSourceStats(ctx, q, args...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, count, nil
+}
+
+// Medium returns the visitor count and engagement grouped by utm medium.
 func (utm *UTM) Medium(filter *Filter) ([]model.UTMMediumStats, error) {
-	ctx, q, args := utm.analyzer.selectByAttribute(filter, "imported_utm_medium", FieldUTMMedium)
-	return utm.store.SelectUTMMediumStats(ctx, q, args...)
+	stats, _, err := utm.medium(filter)
+	return stats, err
+}
+
+// MediumPage returns a page of UTM.Medium together with the total number of rows.
+func (utm *UTM) MediumPage(filter *Filter) (*model.PaginatedStats[model.UTMMediumStats], error) {
+	return paginate(utm.medium(filter))
 }
 
-// Campaign returns the visitor count grouped by utm source.
+func (utm *UTM) medium(filter *Filter) ([]model.UTMMediumStats, *rowCount, error) {
+	ctx, q, args, count, err := utm.analyzer.selectByAttribute(filter, "imported_utm_medium", FieldUTMMedium)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := utm.store.SelectUTMMediumStats(ctx, q, args...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, count, nil
+}
+
+// Campaign returns the visitor count and engagement grouped by utm source.
 func (utm *UTM) Campaign(filter *Filter) ([]model.UTMCampaignStats, error) {
-	ctx, q, args := utm.analyzer.selectByAttribute(filter, "imported_utm_campaign", FieldUTMCampaign)
-	return utm.store.SelectUTMCampaignStats(ctx, q, args...)
+	stats, _, err := utm.campaign(filter)
+	return stats, err
+}
+
+// CampaignPage returns a page of UTM.Campaign together with the total number of rows.
+func (utm *UTM) CampaignPage(filter *Filter) (*model.PaginatedStats[model.UTMCampaignStats], error) {
+	return paginate(utm.campaign(filter))
+}
+
+func (utm *UTM) campaign(filter *Filter) ([]model.UTMCampaignStats, *rowCount, error) {
+	ctx, q, args, count, err := utm.analyzer.selectByAttribute(filter, "imported_utm_campaign", FieldUTMCampaign)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := utm.store.SelectUTMCampaignStats(ctx, q, args...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, count, nil
 }
 
-// Content returns the visitor count grouped by utm source.
+// Content returns the visitor count and engagement grouped by utm source.
 func (utm *UTM) Content(filter *Filter) ([]model.UTMContentStats, error) {
-	ctx, q, args := utm.analyzer.selectByAttribute(filter, "", FieldUTMContent)
-	return utm.store.SelectUTMContentStats(ctx, q, args...)
+	stats, _, err := utm.content(filter)
+	return stats, err
+}
+
+// ContentPage returns a page of UTM.Content together with the total number of rows.
+func (utm *UTM) ContentPage(filter *Filter) (*model.PaginatedStats[model.UTMContentStats], error) {
+	return paginate(utm.content(filter))
+}
+
+func (utm *UTM) content(filter *Filter) ([]model.UTMContentStats, *rowCount, error) {
+	ctx, q, args, count, err := utm.analyzer.selectByAttribute(filter, "imported_utm_content", FieldUTMContent)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := utm.store.SelectUTMContentStats(ctx, q, args...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, count, nil
 }
 
-// Term returns the visitor count grouped by utm source.
+// Term returns the visitor count and engagement grouped by utm source.
 func (utm *UTM) Term(filter *Filter) ([]model.UTMTermStats, error) {
-	ctx, q, args := utm.analyzer.selectByAttribute(filter, "", FieldUTMTerm)
-	return utm.store.SelectUTMTermStats(ctx, q, args...)
+	stats, _, err := utm.term(filter)
+	return stats, err
+}
+
+// TermPage returns a page of UTM.Term together with the total number of rows.
+func (utm *UTM) TermPage(filter *Filter) (*model.PaginatedStats[model.UTMTermStats], error) {
+	return paginate(utm.term(filter))
+}
+
+func (utm *UTM) term(filter *Filter) ([]model.UTMTermStats, *rowCount, error) {
+	ctx, q, args, count, err := utm.analyzer.selectByAttribute(filter, "imported_utm_term", FieldUTMTerm)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := utm.store.SelectUTMTermStats(ctx, q, args...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, count, nil
 }
@@ -173,6 +173,12 @@ func TestAnalyzer_UTM(t *testing.T) {
 	_, err = dbClient.Exec(fmt.Sprintf(`INSERT INTO "imported_utm_campaign" (date, utm_campaign, visitors) VALUES
 		('%s', 'campaign1', 2), ('%s', 'campaign2', 1)`, yesterday, yesterday))
 	assert.NoError(t, err)
+	_, err = dbClient.Exec(fmt.Sprintf(`INSERT INTO "imported_utm_content" (date, utm_content, visitors) VALUES
+		('%s', 'content1', 2), ('%s', 'content2', 1)`, yesterday, yesterday))
+	assert.NoError(t, err)
+	_, err = dbClient.Exec(fmt.Sprintf(`INSERT INTO "imported_utm_term" (date, utm_term, visitors) VALUES
+		('%s', 'term1', 2), ('%s', 'term2', 1)`, yesterday, yesterday))
+	assert.NoError(t, err)
 	time.Sleep(time.Millisecond * 100)
 	source, err = analyzer.UTM.Source(&Filter{
 		From:          util.PastDay(1),
@@ -255,4 +261,58 @@ func TestAnalyzer_UTM(t *testing.T) {
 	assert.Equal(t, "campaign1", campaign[0].UTMCampaign)
 	assert.Equal(t, 5, campaign[0].Visitors)
 	assert.InDelta(t, 0.5555, campaign[0].RelativeVisitors, 0.01)
+	content, err = analyzer.UTM.Content(&Filter{
+		From:          util.PastDay(1),
+		To:            util.Today(),
+		ImportedUntil: util.Today(),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, content, 3)
+	assert.Equal(t, "content1", content[0].UTMContent)
+	assert.Equal(t, "content2", content[1].UTMContent)
+	assert.Equal(t, "content3", content[2].UTMContent)
+	assert.Equal(t, 5, content[0].Visitors)
+	assert.Equal(t, 3, content[1].Visitors)
+	assert.Equal(t, 1, content[2].Visitors)
+	assert.InDelta(t, 0.5555, content[0].RelativeVisitors, 0.01)
+	assert.InDelta(t, 0.3333, content[1].RelativeVisitors, 0.01)
+	assert.InDelta(t, 0.1111, content[2].RelativeVisitors, 0.01)
+	content, err = analyzer.UTM.Content(&Filter{
+		From:          util.PastDay(1),
+		To:            util.Today(),
+		ImportedUntil: util.Today(),
+		UTMContent:    []string{"content1"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, content, 1)
+	assert.Equal(t, "content1", content[0].UTMContent)
+	assert.Equal(t, 5, content[0].Visitors)
+	assert.InDelta(t, 0.5555, content[0].RelativeVisitors, 0.01)
+	term, err = analyzer.UTM.Term(&Filter{
+		From:          util.PastDay(1),
+		To:            util.Today(),
+		ImportedUntil: util.Today(),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, term, 3)
+	assert.Equal(t, "term1", term[0].UTMTerm)
+	assert.Equal(t, "term2", term[1].UTMTerm)
+	assert.Equal(t, "term3", term[2].UTMTerm)
+	assert.Equal(t, 5, term[0].Visitors)
+	assert.Equal(t, 3, term[1].Visitors)
+	assert.Equal(t, 1, term[2].Visitors)
+	assert.InDelta(t, 0.5555, term[0].RelativeVisitors, 0.01)
+	assert.InDelta(t, 0.3333, term[1].RelativeVisitors, 0.01)
+	assert.InDelta(t, 0.1111, term[2].RelativeVisitors, 0.01)
+	term, err = analyzer.UTM.Term(&Filter{
+		From:          util.PastDay(1),
+		To:            util.Today(),
+		ImportedUntil: util.Today(),
+		UTMTerm:       []string{"term1"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, term, 1)
+	assert.Equal(t, "term1", term[0].UTMTerm)
+	assert.Equal(t, 5, term[0].Visitors)
+	assert.InDelta(t, 0.5555, term[0].RelativeVisitors, 0.01)
 }
@@ -8,7 +8,6 @@ import (
 
 	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
-	"github.com/pirsch-analytics/pirsch/v6/pkg/util"
 )
 
 var (
@@ -25,7 +24,12 @@ type Visitors struct {
 // Active returns the active visitors per hostname, path, and (optional) page title and the total number of active visitors for a given duration.
 // Use time.Minute * 5, for example, to get the active visitors for the past 5 minutes.
 func (visitors *Visitors) Active(filter *Filter, duration time.Duration) ([]model.ActiveVisitorStats, int, error) {
-	filter = visitors.analyzer.getFilter(filter)
+	filter, err := visitors.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
 	filter.From = time.Now().UTC().Add(-duration)
 	filter.IncludeTime = true
 	fields := []Field{FieldPath}
@@ -56,15 +60,23 @@ func (visitors *Visitors) Active(filter *Filter, duration time.Duration) ([]mode
 	return stats, count, nil
 }
 
-// Total returns the total visitor count, session count, bounce rate, views, CR, and average and total custom metric.
+// Total returns the total visitor count, session count, bounce rate, engagement, views, CR, and average and total custom metric.
 func (visitors *Visitors) Total(filter *Filter) (*model.TotalVisitorStats, error) {
-	filter = visitors.analyzer.getFilter(filter)
+	filter, err := visitors.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	fields := []Field{
 		FieldVisitors,
 		FieldSessions,
 		FieldViews,
 		FieldBounces,
 		FieldBounceRate,
+		FieldEngagedSessions,
+		FieldEngagementRate,
+		FieldPagesPerSession,
 	}
 
 	if filter.IncludeCR {
@@ -95,7 +107,12 @@ func (visitors *Visitors) Total(filter *Filter) (*model.TotalVisitorStats, error
 
 // TotalVisitors returns the total unique visitor count.
 func (visitors *Visitors) TotalVisitors(filter *Filter) (int, error) {
-	filter = visitors.analyzer.getFilter(filter)
+	filter, err := visitors.analyzer.getFilter(filter)
+
+	if err != nil {
+		return 0, err
+	}
+
 	f := &Filter{
 		ClientID:      filter.ClientID,
 		Timezone:      filter.Timezone,
@@ -117,7 +134,12 @@ func (visitors *Visitors) TotalVisitors(filter *Filter) (int, error) {
 
 // TotalPageViews returns the total number of page views.
 func (visitors *Visitors) TotalPageViews(filter *Filter) (int, error) {
-	filter = visitors.analyzer.getFilter(filter)
+	filter, err := visitors.analyzer.getFilter(filter)
+
+	if err != nil {
+		return 0, err
+	}
+
 	f := &Filter{
 		ClientID:      filter.ClientID,
 		Timezone:      filter.Timezone,
@@ -138,7 +160,12 @@ func (visitors *Visitors) TotalPageViews(filter *Filter) (int, error) {
 
 // TotalSessions returns the total number of sessions.
 func (visitors *Visitors) TotalSessions(filter *Filter) (int, error) {
-	filter = visitors.analyzer.getFilter(filter)
+	filter, err := visitors.analyzer.getFilter(filter)
+
+	if err != nil {
+		return 0, err
+	}
+
 	f := &Filter{
 		ClientID:      filter.ClientID,
 		Timezone:      filter.Timezone,
@@ -159,10 +186,14 @@ func (visitors *Visitors) TotalSessions(filter *Filter) (int, error) {
 
 // TotalVisitorsPageViews returns the total visitor count and number of page views, including the growth.
 func (visitors *Visitors) TotalVisitorsPageViews(filter *Filter) (*model.TotalVisitorsPageViewsStats, error) {
-	filter = visitors.analyzer.getFilter(filter)
+	filter, err := visitors.analyzer.getFilter(filter)
 
-	if filter.From.IsZero() || filter.To.IsZero() {
-		return nil, ErrNoPeriodOrDay
+	if err != nil {
+		return nil, err
+	}
+
+	if err := filter.validateComparison(); err != nil {
+		return nil, err
 	}
 
 	q, args := filter.buildQuery([]Field{
@@ -178,7 +209,7 @@ func (visitors *Visitors) TotalVisitorsPageViews(filter *Filter) (*model.TotalVi
 		return nil, err
 	}
 
-	visitors.getPreviousPeriod(filter)
+	filter.comparisonPeriod()
 	q, args = filter.buildQuery([]Field{
 		FieldVisitors,
 		FieldViews,
@@ -200,10 +231,15 @@ func (visitors *Visitors) TotalVisitorsPageViews(filter *Filter) (*model.TotalVi
 	}, nil
 }
 
-// ByPeriod returns the visitor count, session count, bounce rate, views, CR, and average and total custom metric
-// grouped by day, week, month, or year.
+// ByPeriod returns the visitor count, session count, bounce rate, engagement, views, CR, and average and total custom metric
+// grouped by hour, day, week, month, quarter, or year.
 func (visitors *Visitors) ByPeriod(filter *Filter) ([]model.VisitorStats, error) {
-	filter = visitors.analyzer.getFilter(filter)
+	filter, err := visitors.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	fields := []Field{
 		FieldDay,
 		FieldVisitors,
@@ -211,6 +247,9 @@ func (visitors *Visitors) ByPeriod(filter *Filter) ([]model.VisitorStats, error)
 		FieldViews,
 		FieldBounces,
 		FieldBounceRate,
+		FieldEngagedSessions,
+		FieldEngagementRate,
+		FieldPagesPerSession,
 	}
 
 	if filter.IncludeCR {
@@ -247,7 +286,12 @@ func (visitors *Visitors) ByPeriod(filter *Filter) ([]model.VisitorStats, error)
 
 // ByHour returns the visitor count grouped by time of day.
 func (visitors *Visitors) ByHour(filter *Filter) ([]model.VisitorHourStats, error) {
-	filter = visitors.analyzer.getFilter(filter)
+	filter, err := visitors.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	fields := []Field{
 		FieldHour,
 		FieldVisitors,
@@ -279,7 +323,7 @@ func (visitors *Visitors) ByHour(filter *Filter) ([]model.VisitorHourStats, erro
 		FieldSessions,
 		FieldViews,
 		FieldBounces,
-	}, "imported_visitors")
+	}, "imported_hour")
 	stats, err := visitors.store.SelectVisitorHourStats(filter.Ctx, q, filter.IncludeCR, includeCustomMetric, args...)
 
 	if err != nil {
@@ -291,7 +335,12 @@ func (visitors *Visitors) ByHour(filter *Filter) ([]model.VisitorHourStats, erro
 
 // ByMinute returns the visitor count grouped by the minute of the hour.
 func (visitors *Visitors) ByMinute(filter *Filter) ([]model.VisitorMinuteStats, error) {
-	filter = visitors.analyzer.getFilter(filter)
+	filter, err := visitors.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	fields := []Field{
 		FieldMinute,
 		FieldVisitors,
@@ -329,7 +378,12 @@ func (visitors *Visitors) ByMinute(filter *Filter) ([]model.VisitorMinuteStats,
 
 // ByWeekdayAndHour returns the visitor count grouped by time of day and weekday.
 func (visitors *Visitors) ByWeekdayAndHour(filter *Filter) ([]model.VisitorWeekdayHourStats, error) {
-	filter = visitors.analyzer.getFilter(filter)
+	filter, err := visitors.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	q, args := filter.buildQuery([]Field{
 		FieldWeekday,
 		FieldHour,
@@ -357,10 +411,14 @@ func (visitors *Visitors) ByWeekdayAndHour(filter *Filter) ([]model.VisitorWeekd
 // The growth rate is relative to the previous time range or day.
 // The period or day for the filter must be set, else an error is returned.
 func (visitors *Visitors) Growth(filter *Filter) (*model.Growth, error) {
-	filter = visitors.analyzer.getFilter(filter)
+	filter, err := visitors.analyzer.getFilter(filter)
 
-	if filter.From.IsZero() || filter.To.IsZero() {
-		return nil, ErrNoPeriodOrDay
+	if err != nil {
+		return nil, err
+	}
+
+	if err := filter.validateComparison(); err != nil {
+		return nil, err
 	}
 
 	// get current statistics
@@ -410,7 +468,7 @@ func (visitors *Visitors) Growth(filter *Filter) (*model.Growth, error) {
 		return nil, err
 	}
 
-	visitors.getPreviousPeriod(filter)
+	filter.comparisonPeriod()
 	q, args = filter.buildQuery(fields, nil, nil, fieldsImported, "imported_visitors")
 	previous, err := visitors.store.GetGrowthStats(filter.Ctx, q, filter.IncludeCR, includeCustomMetric, args...)
 
@@ -444,9 +502,24 @@ func (visitors *Visitors) Growth(filter *Filter) (*model.Growth, error) {
 	}, nil
 }
 
-// Referrer returns the visitor count and bounce rate grouped by referrer.
+// Referrer returns the visitor count, bounce rate, engagement, and views grouped by referrer.
 func (visitors *Visitors) Referrer(filter *Filter) ([]model.ReferrerStats, error) {
-	filter = visitors.analyzer.getFilter(filter)
+	stats, _, err := visitors.referrer(filter)
+	return stats, err
+}
+
+// ReferrerPage returns a page of Visitors.Referrer together with the total number of rows.
+func (visitors *Visitors) ReferrerPage(filter *Filter) (*model.PaginatedStats[model.ReferrerStats], error) {
+	return paginate(visitors.referrer(filter))
+}
+
+func (visitors *Visitors) referrer(filter *Filter) ([]model.ReferrerStats, *rowCount, error) {
+	filter, err := visitors.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var fields, groupBy, orderBy, importedFields []Field
 
 	if filter.ImportedUntil.IsZero() {
@@ -455,9 +528,13 @@ func (visitors *Visitors) Referrer(filter *Filter) ([]model.ReferrerStats, error
 			FieldReferrerIcon,
 			FieldVisitors,
 			FieldSessions,
+			FieldViews,
 			FieldRelativeVisitors,
 			FieldBounces,
 			FieldBounceRate,
+			FieldEngagedSessions,
+			FieldEngagementRate,
+			FieldPagesPerSession,
 		}
 		groupBy = []Field{
 			FieldReferrerName,
@@ -489,9 +566,13 @@ func (visitors *Visitors) Referrer(filter *Filter) ([]model.ReferrerStats, error
 				FieldAnyReferrerIcon,
 				FieldVisitors,
 				FieldSessions,
+				FieldViews,
 				FieldRelativeVisitors,
 				FieldBounces,
 				FieldBounceRate,
+				FieldEngagedSessions,
+				FieldEngagementRate,
+				FieldPagesPerSession,
 				FieldAnyReferrerImported,
 			}
 			groupBy = append(groupBy, FieldAnyReferrerImported)
@@ -503,9 +584,13 @@ func (visitors *Visitors) Referrer(filter *Filter) ([]model.ReferrerStats, error
 				FieldAnyReferrerIcon,
 				FieldVisitors,
 				FieldSessions,
+				FieldViews,
 				FieldRelativeVisitors,
 				FieldBounces,
 				FieldBounceRate,
+				FieldEngagedSessions,
+				FieldEngagementRate,
+				FieldPagesPerSession,
 				FieldAnyReferrer,
 			}
 			importedFields = []Field{FieldReferrer}
@@ -518,20 +603,35 @@ func (visitors *Visitors) Referrer(filter *Filter) ([]model.ReferrerStats, error
 		}...)
 	}
 
-	q, args := filter.buildQuery(fields, groupBy, orderBy, importedFields, "imported_referrer")
+	q, args, count := filter.buildListQuery(visitors.store, fields, groupBy, orderBy, importedFields, "imported_referrer")
 	stats, err := visitors.store.SelectReferrerStats(filter.Ctx, q, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return stats, nil
+	return stats, count, nil
 }
 
-// Channel returns the visitor count, session count, bounce rate, and views grouped by channel.
+// Channel returns the visitor count, session count, bounce rate, engagement, and views grouped by channel.
 func (visitors *Visitors) Channel(filter *Filter) ([]model.ChannelStats, error) {
-	filter = visitors.analyzer.getFilter(filter)
-	q, args := filter.buildQuery([]Field{
+	stats, _, err := visitors.channel(filter)
+	return stats, err
+}
+
+// ChannelPage returns a page of Visitors.Channel together with the total number of rows.
+func (visitors *Visitors) ChannelPage(filter *Filter) (*model.PaginatedStats[model.ChannelStats], error) {
+	return paginate(visitors.channel(filter))
+}
+
+func (visitors *Visitors) channel(filter *Filter) ([]model.ChannelStats, *rowCount, error) {
+	filter, err := visitors.analyzer.getFilter(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q, args, count := filter.buildListQuery(visitors.store, []Field{
 		FieldChannel,
 		FieldVisitors,
 		FieldViews,
@@ -540,56 +640,28 @@ func (visitors *Visitors) Channel(filter *Filter) ([]model.ChannelStats, error)
 		FieldRelativeVisitors,
 		FieldRelativeViews,
 		FieldBounceRate,
+		FieldEngagedSessions,
+		FieldEngagementRate,
+		FieldPagesPerSession,
 	}, []Field{
 		FieldChannel,
 	}, []Field{
 		FieldVisitors,
 		FieldChannel,
-	}, nil, "")
+	}, []Field{
+		FieldChannel,
+		FieldVisitors,
+		FieldViews,
+		FieldSessions,
+		FieldBounces,
+	}, "imported_channel")
 	stats, err := visitors.store.SelectChannelStats(filter.Ctx, q, args...)
 
 	if err != nil {
-		return nil, err
-	}
-
-	return stats, nil
-}
-
-func (visitors *Visitors) getPreviousPeriod(filter *Filter) {
-	from := filter.From
-	to := filter.To
-
-	if !filter.importedFrom.IsZero() && filter.importedFrom.Before(from) {
-		from = filter.importedFrom
+		return nil, nil, err
 	}
 
-	if from.Equal(to) {
-		if to.Equal(util.Today()) {
-			from = from.Add(-time.Hour * 24 * 7)
-			to = time.Now().UTC().Add(-time.Hour * 24 * 7)
-			filter.IncludeTime = true
-		} else {
-			from = from.Add(-time.Hour * 24 * 7)
-			to = to.Add(-time.Hour * 24 * 7)
-		}
-	} else {
-		days := to.Sub(from)
-
-		if days >= time.Hour*24 {
-			to = from.Add(-time.Hour * 24)
-			from = to.Add(-days)
-		} else {
-			from = from.Add(-time.Hour * 24)
-			to = to.Add(-time.Hour * 24)
-		}
-	}
-
-	filter.From = from
-	filter.To = to
-
-	if !filter.ImportedUntil.IsZero() {
-		filter.validate()
-	}
+	return stats, count, nil
 }
 
 func (visitors *Visitors) totalSessionDuration(filter *Filter) (int, error) {
@@ -675,9 +747,10 @@ func (visitors *Visitors) totalTimeOnPage(filter *Filter) (int, error) {
 	filterCopy := *filter
 	filterCopy.Sort = nil
 	q := queryBuilder{
-		filter: &filterCopy,
-		from:   pageViews,
-		search: filter.Search,
+		filter:     &filterCopy,
+		from:       pageViews,
+		search:     filter.Search,
+		timeOnPage: true,
 	}
 	fields := q.getFields()
 	fieldsQuery := strings.Join(fields, ",")
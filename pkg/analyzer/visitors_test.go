@@ -1634,6 +1634,100 @@ func TestAnalyzer_ByPeriodCustomMetric(t *testing.T) {
 	assert.InDelta(t, 0, visitors[5].CR, 0.01)
 }
 
+func TestAnalyzer_ByPeriodHourAndQuarter(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, VisitorID: 1, SessionID: 1, Time: time.Date(2023, 10, 29, 0, 30, 0, 0, time.UTC), Start: time.Now(), ExitPath: "/", PageViews: 1},
+			{Sign: 1, VisitorID: 2, SessionID: 2, Time: time.Date(2023, 10, 29, 1, 30, 0, 0, time.UTC), Start: time.Now(), ExitPath: "/", PageViews: 1},
+			{Sign: 1, VisitorID: 3, SessionID: 3, Time: time.Date(2023, 10, 29, 1, 45, 0, 0, time.UTC), Start: time.Now(), ExitPath: "/", PageViews: 1},
+			{Sign: 1, VisitorID: 4, SessionID: 4, Time: time.Date(2023, 2, 10, 12, 0, 0, 0, time.UTC), Start: time.Now(), ExitPath: "/", PageViews: 1},
+		},
+	})
+	tz, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	analyzer := NewAnalyzer(dbClient)
+	visitors, err := analyzer.Visitors.ByPeriod(&Filter{
+		Timezone: tz,
+		From:     time.Date(2023, 10, 29, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2023, 10, 29, 0, 0, 0, 0, time.UTC),
+		Period:   pkg.PeriodHour,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, visitors, 25) // the clocks are turned back one hour
+	assert.True(t, visitors[0].Hour.Time.Equal(time.Date(2023, 10, 28, 22, 0, 0, 0, time.UTC)))
+	assert.True(t, visitors[2].Hour.Time.Equal(time.Date(2023, 10, 29, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, visitors[3].Hour.Time.Equal(time.Date(2023, 10, 29, 1, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0, visitors[1].Visitors)
+	assert.Equal(t, 1, visitors[2].Visitors)
+	assert.Equal(t, 2, visitors[3].Visitors)
+	assert.Equal(t, 0, visitors[4].Visitors)
+	visitors, err = analyzer.Visitors.ByPeriod(&Filter{
+		Timezone:    tz,
+		From:        time.Date(2023, 10, 29, 1, 10, 0, 0, tz),
+		To:          time.Date(2023, 10, 29, 3, 10, 0, 0, tz),
+		IncludeTime: true,
+		Period:      pkg.PeriodHour,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, visitors, 4)
+	assert.Equal(t, 0, visitors[0].Visitors)
+	assert.Equal(t, 1, visitors[1].Visitors)
+	assert.Equal(t, 2, visitors[2].Visitors)
+	assert.Equal(t, 0, visitors[3].Visitors)
+	visitors, err = analyzer.Visitors.ByPeriod(&Filter{
+		From:   time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+		Period: pkg.PeriodQuarter,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, visitors, 4)
+	assert.Equal(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), visitors[0].Quarter.Time)
+	assert.Equal(t, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), visitors[3].Quarter.Time)
+	assert.Equal(t, 1, visitors[0].Visitors)
+	assert.Equal(t, 0, visitors[1].Visitors)
+	assert.Equal(t, 0, visitors[2].Visitors)
+	assert.Equal(t, 3, visitors[3].Visitors)
+}
+
+func TestAnalyzer_ByPeriodHourImported(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, VisitorID: 1, SessionID: 1, Time: time.Date(2024, 1, 8, 10, 30, 0, 0, time.UTC), Start: time.Now(), ExitPath: "/", PageViews: 1},
+			{Sign: 1, VisitorID: 2, SessionID: 2, Time: time.Date(2024, 1, 8, 10, 45, 0, 0, time.UTC), Start: time.Now(), ExitPath: "/", PageViews: 1},
+		},
+	})
+	_, err := dbClient.Exec(`INSERT INTO "imported_visitors" (date, visitors, views, sessions, bounces, session_duration) VALUES
+		('2024-01-06', 5, 6, 5, 1, 100), ('2024-01-07', 9, 9, 9, 9, 900)`)
+	assert.NoError(t, err)
+	_, err = dbClient.Exec(`INSERT INTO "imported_hour" (date, hour, visitors, views, sessions, bounces) VALUES
+		('2024-01-07', 3, 2, 4, 3, 1), ('2024-01-07', 15, 1, 1, 1, 0)`)
+	assert.NoError(t, err)
+	time.Sleep(time.Millisecond * 100)
+	analyzer := NewAnalyzer(dbClient)
+	visitors, err := analyzer.Visitors.ByPeriod(&Filter{
+		From:          time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC),
+		To:            time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+		ImportedUntil: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+		Period:        pkg.PeriodHour,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, visitors, 72)
+	expected := map[int][2]int{
+		0:  {5, 6}, // days without imported hourly statistics fall back to the first hour of the day
+		27: {2, 4},
+		39: {1, 1},
+		58: {2, 2},
+	}
+
+	for i, v := range visitors {
+		assert.True(t, v.Hour.Time.Equal(time.Date(2024, 1, 6, i, 0, 0, 0, time.UTC)))
+		assert.Equal(t, expected[i][0], v.Visitors)
+		assert.Equal(t, expected[i][1], v.Views)
+	}
+}
+
 func TestAnalyzer_ByHour(t *testing.T) {
 	db.CleanupDB(t, dbClient)
 	saveSessions(t, [][]model.Session{
@@ -1835,6 +1929,32 @@ func TestAnalyzer_ByHour(t *testing.T) {
 		assert.InDelta(t, 0, visitors[i].BounceRate, 0.01)
 		assert.InDelta(t, 0, visitors[i].CR, 0.01)
 	}
+
+	// imported hourly statistics take precedence over the daily statistics
+	past4Days := util.PastDay(4).Format(time.DateOnly)
+	_, err = dbClient.Exec(fmt.Sprintf(`INSERT INTO "imported_visitors" (date, visitors, views, sessions, bounces, session_duration) VALUES
+		('%s', 9, 9, 9, 9, 900)`, past4Days))
+	assert.NoError(t, err)
+	_, err = dbClient.Exec(fmt.Sprintf(`INSERT INTO "imported_hour" (date, hour, visitors, views, sessions, bounces) VALUES
+		('%s', 0, 2, 4, 3, 1), ('%s', 7, 1, 1, 1, 0)`, past4Days, past4Days))
+	assert.NoError(t, err)
+	time.Sleep(time.Millisecond * 100)
+	visitors, err = analyzer.Visitors.ByHour(&Filter{
+		From:          util.PastDay(4),
+		To:            util.PastDay(3),
+		ImportedUntil: util.PastDay(2),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, visitors, 24)
+	assert.Equal(t, 4, visitors[0].Visitors)
+	assert.Equal(t, 8, visitors[0].Views)
+	assert.Equal(t, 6, visitors[0].Sessions)
+	assert.Equal(t, 2, visitors[0].Bounces)
+	assert.Equal(t, 7, visitors[7].Hour)
+	assert.Equal(t, 1, visitors[7].Visitors)
+	assert.Equal(t, 1, visitors[7].Views)
+	assert.Equal(t, 1, visitors[7].Sessions)
+	assert.Zero(t, visitors[7].Bounces)
 }
 
 func TestAnalyzer_ByHourEvent(t *testing.T) {
@@ -3274,6 +3394,147 @@ func TestAnalyzer_Channel(t *testing.T) {
 	assert.InDelta(t, 0, visitors[1].BounceRate, 0.01)
 }
 
+func TestAnalyzer_Engagement(t *testing.T) {
+	db.CleanupDB(t, dbClient)
+	saveSessions(t, [][]model.Session{
+		{
+			{Sign: 1, VisitorID: 1, SessionID: 1, Time: util.Today(), Start: time.Now(), EntryPath: "/", ExitPath: "/", Channel: "A", UTMSource: "newsletter", Browser: pkg.BrowserChrome, Desktop: true, PageViews: 1, IsBounce: true},
+		},
+		{
+			{Sign: -1, VisitorID: 1, SessionID: 1, Time: util.Today(), Start: time.Now(), EntryPath: "/", ExitPath: "/", Channel: "A", UTMSource: "newsletter", Browser: pkg.BrowserChrome, Desktop: true, PageViews: 1, IsBounce: true},
+			{Sign: 1, VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Second * 20), Start: time.Now(), EntryPath: "/", ExitPath: "/b", Channel: "A", UTMSource: "newsletter", Browser: pkg.BrowserChrome, Desktop: true, PageViews: 3, IsEngaged: true},
+			{Sign: 1, VisitorID: 2, SessionID: 2, Time: util.Today(), Start: time.Now(), EntryPath: "/", ExitPath: "/", Channel: "A", ReferrerName: "Blog", UTMSource: "newsletter", Browser: pkg.BrowserFirefox, Mobile: true, PageViews: 1, IsBounce: true, IsEngaged: true},
+			{Sign: 1, VisitorID: 3, SessionID: 3, Time: util.Today(), Start: time.Now(), EntryPath: "/a", ExitPath: "/a", Channel: "B", UTMSource: "ads", Browser: pkg.BrowserChrome, Desktop: true, PageViews: 1, IsBounce: true},
+			{Sign: 1, VisitorID: 4, SessionID: 4, Time: util.Today(), Start: time.Now(), EntryPath: "/", ExitPath: "/b", Channel: "B", UTMSource: "newsletter", Browser: pkg.BrowserChrome, Desktop: true, PageViews: 2},
+		},
+	})
+	assert.NoError(t, dbClient.SavePageViews([]model.PageView{
+		{VisitorID: 1, SessionID: 1, Time: util.Today(), Path: "/"},
+		{VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Second * 10), Path: "/a"},
+		{VisitorID: 1, SessionID: 1, Time: util.Today().Add(time.Second * 20), Path: "/b"},
+		{VisitorID: 2, SessionID: 2, Time: util.Today(), Path: "/"},
+		{VisitorID: 3, SessionID: 3, Time: util.Today(), Path: "/a"},
+		{VisitorID: 4, SessionID: 4, Time: util.Today(), Path: "/"},
+		{VisitorID: 4, SessionID: 4, Time: util.Today().Add(time.Second), Path: "/b"},
+	}))
+	time.Sleep(time.Millisecond * 100)
+	analyzer := NewAnalyzer(dbClient)
+	total, err := analyzer.Visitors.Total(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, total.Sessions)
+	assert.Equal(t, 2, total.Bounces)
+	assert.Equal(t, 2, total.EngagedSessions)
+	assert.InDelta(t, 0.5, total.EngagementRate, 0.001)
+	assert.InDelta(t, 1.75, total.PagesPerSession, 0.001)
+	total, err = analyzer.Visitors.Total(&Filter{Path: []string{"/"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total.Sessions)
+	assert.Equal(t, 2, total.EngagedSessions)
+	assert.InDelta(t, 0.6666, total.EngagementRate, 0.001)
+	byPeriod, err := analyzer.Visitors.ByPeriod(&Filter{From: util.PastDay(1), To: util.Today()})
+	assert.NoError(t, err)
+	assert.Len(t, byPeriod, 2)
+	assert.Zero(t, byPeriod[0].EngagedSessions)
+	assert.Equal(t, 2, byPeriod[1].EngagedSessions)
+	assert.InDelta(t, 0.5, byPeriod[1].EngagementRate, 0.001)
+	assert.InDelta(t, 1.75, byPeriod[1].PagesPerSession, 0.001)
+	channel, err := analyzer.Visitors.Channel(nil)
+	assert.NoError(t, err)
+	assert.Len(t, channel, 2)
+	assert.Equal(t, "A", channel[0].Channel)
+	assert.Equal(t, 2, channel[0].EngagedSessions)
+	assert.InDelta(t, 1, channel[0].EngagementRate, 0.001)
+	assert.InDelta(t, 2, channel[0].PagesPerSession, 0.001)
+	assert.Equal(t, "B", channel[1].Channel)
+	assert.Zero(t, channel[1].EngagedSessions)
+	assert.InDelta(t, 1.5, channel[1].PagesPerSession, 0.001)
+	referrer, err := analyzer.Visitors.Referrer(&Filter{ReferrerName: []string{"Blog"}})
+	assert.NoError(t, err)
+	assert.Len(t, referrer, 1)
+	assert.Equal(t, 1, referrer[0].EngagedSessions)
+	assert.InDelta(t, 1, referrer[0].EngagementRate, 0.001)
+	assert.InDelta(t, 1, referrer[0].PagesPerSession, 0.001)
+	hostname, err := analyzer.Pages.Hostname(nil)
+	assert.NoError(t, err)
+	assert.Len(t, hostname, 1)
+	assert.Equal(t, 2, hostname[0].EngagedSessions)
+	assert.InDelta(t, 1.75, hostname[0].PagesPerSession, 0.001)
+	pages, err := analyzer.Pages.ByPath(nil)
+	assert.NoError(t, err)
+	assert.Len(t, pages, 3)
+	assert.Equal(t, "/", pages[0].Path)
+	assert.Equal(t, 3, pages[0].Sessions)
+	assert.Equal(t, 2, pages[0].EngagedSessions)
+	assert.InDelta(t, 0.6666, pages[0].EngagementRate, 0.001)
+	assert.InDelta(t, 1, pages[0].PagesPerSession, 0.001)
+	assert.Equal(t, "/a", pages[1].Path)
+	assert.Equal(t, 1, pages[1].EngagedSessions)
+	assert.InDelta(t, 0.5, pages[1].EngagementRate, 0.001)
+	entries, err := analyzer.Pages.Entry(nil)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "/", entries[0].Path)
+	assert.Equal(t, 3, entries[0].Entries)
+	assert.Equal(t, 2, entries[0].EngagedSessions)
+	assert.InDelta(t, 0.6666, entries[0].EngagementRate, 0.001)
+	assert.InDelta(t, 2, entries[0].PagesPerSession, 0.001)
+	assert.Equal(t, "/a", entries[1].Path)
+	assert.Zero(t, entries[1].EngagedSessions)
+	assert.InDelta(t, 1, entries[1].PagesPerSession, 0.001)
+	exits, err := analyzer.Pages.Exit(nil)
+	assert.NoError(t, err)
+	assert.Len(t, exits, 3)
+	assert.Equal(t, "/b", exits[0].Path)
+	assert.Equal(t, 2, exits[0].Exits)
+	assert.Equal(t, 1, exits[0].EngagedSessions)
+	assert.InDelta(t, 0.5, exits[0].EngagementRate, 0.001)
+	assert.InDelta(t, 2.5, exits[0].PagesPerSession, 0.001)
+	utmSource, err := analyzer.UTM.Source(nil)
+	assert.NoError(t, err)
+	assert.Len(t, utmSource, 2)
+	assert.Equal(t, "newsletter", utmSource[0].UTMSource)
+	assert.Equal(t, 3, utmSource[0].Sessions)
+	assert.Equal(t, 6, utmSource[0].Views)
+	assert.Equal(t, 2, utmSource[0].EngagedSessions)
+	assert.InDelta(t, 0.6666, utmSource[0].EngagementRate, 0.001)
+	assert.InDelta(t, 2, utmSource[0].PagesPerSession, 0.001)
+	assert.Equal(t, "ads", utmSource[1].UTMSource)
+	assert.Zero(t, utmSource[1].EngagedSessions)
+	assert.InDelta(t, 1, utmSource[1].PagesPerSession, 0.001)
+	browser, err := analyzer.Device.Browser(nil)
+	assert.NoError(t, err)
+	assert.Len(t, browser, 2)
+	assert.Equal(t, pkg.BrowserChrome, browser[0].Browser)
+	assert.Equal(t, 1, browser[0].EngagedSessions)
+	assert.InDelta(t, 0.3333, browser[0].EngagementRate, 0.001)
+	assert.InDelta(t, 2, browser[0].PagesPerSession, 0.001)
+	assert.Equal(t, pkg.BrowserFirefox, browser[1].Browser)
+	assert.Equal(t, 1, browser[1].EngagedSessions)
+	assert.InDelta(t, 1, browser[1].EngagementRate, 0.001)
+	assert.InDelta(t, 1, browser[1].PagesPerSession, 0.001)
+	platform, err := analyzer.Device.Platform(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, platform.PlatformDesktop)
+	assert.Equal(t, 1, platform.PlatformMobile)
+	assert.Equal(t, 1, platform.EngagedSessionsDesktop)
+	assert.Equal(t, 1, platform.EngagedSessionsMobile)
+	assert.Zero(t, platform.EngagedSessionsUnknown)
+	assert.InDelta(t, 0.3333, platform.EngagementRateDesktop, 0.001)
+	assert.InDelta(t, 1, platform.EngagementRateMobile, 0.001)
+	assert.InDelta(t, 2, platform.PagesPerSessionDesktop, 0.001)
+	assert.InDelta(t, 1, platform.PagesPerSessionMobile, 0.001)
+	_, err = analyzer.UTM.Source(getMaxFilter(""))
+	assert.NoError(t, err)
+	_, err = analyzer.Device.Platform(getMaxFilter(""))
+	assert.NoError(t, err)
+	_, err = analyzer.Visitors.Total(getMaxFilter(""))
+	assert.NoError(t, err)
+	_, err = analyzer.Visitors.Total(getMaxFilter("event"))
+	assert.NoError(t, err)
+	_, err = analyzer.Visitors.ByPeriod(getMaxFilter(""))
+	assert.NoError(t, err)
+}
+
 func TestAnalyzer_Timezone(t *testing.T) {
 	db.CleanupDB(t, dbClient)
 	assert.NoError(t, dbClient.SaveSessions([]model.Session{
@@ -3337,13 +3598,12 @@ func TestAnalyzer_CalculateGrowth(t *testing.T) {
 	assert.InDelta(t, -0.5, growth, 0.001)
 }
 
-func TestAnalyzer_GetPreviousPeriod(t *testing.T) {
-	analyzer := NewAnalyzer(dbClient)
+func TestFilter_ComparisonPeriod(t *testing.T) {
 	f := &Filter{
 		From: util.PastDay(5),
 		To:   util.Today(),
 	}
-	analyzer.Visitors.getPreviousPeriod(f)
+	f.comparisonPeriod()
 	assert.Equal(t, util.PastDay(11), f.From)
 	assert.Equal(t, util.PastDay(6), f.To)
 	f = &Filter{
@@ -3352,7 +3612,7 @@ func TestAnalyzer_GetPreviousPeriod(t *testing.T) {
 		ImportedUntil: util.PastDay(6),
 	}
 	f.validate()
-	analyzer.Visitors.getPreviousPeriod(f)
+	f.comparisonPeriod()
 	assert.Equal(t, util.PastDay(6), f.From)
 	assert.Equal(t, util.PastDay(6), f.To)
 	assert.Equal(t, util.PastDay(11), f.importedFrom)
@@ -3363,7 +3623,7 @@ func TestAnalyzer_GetPreviousPeriod(t *testing.T) {
 		ImportedUntil: util.PastDay(5),
 	}
 	f.validate()
-	analyzer.Visitors.getPreviousPeriod(f)
+	f.comparisonPeriod()
 	assert.Equal(t, util.PastDay(21), f.From)
 	assert.Equal(t, util.PastDay(11), f.To)
 	assert.Equal(t, util.PastDay(21), f.importedFrom)
@@ -3374,9 +3634,29 @@ func TestAnalyzer_GetPreviousPeriod(t *testing.T) {
 		ImportedUntil: util.PastDay(9),
 	}
 	f.validate()
-	analyzer.Visitors.getPreviousPeriod(f)
+	f.comparisonPeriod()
 	assert.Equal(t, util.PastDay(21), f.From)
 	assert.Equal(t, util.PastDay(11), f.To)
 	assert.Equal(t, util.PastDay(21), f.importedFrom)
 	assert.Equal(t, util.PastDay(11), f.importedTo)
+	f = &Filter{
+		From:    time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		Compare: CompareYear,
+	}
+	f.validate()
+	f.comparisonPeriod()
+	assert.Equal(t, time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), f.From)
+	assert.Equal(t, time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC), f.To)
+	f = &Filter{
+		From:        util.PastDay(5),
+		To:          util.Today(),
+		Compare:     CompareCustom,
+		CompareFrom: util.PastDay(30),
+		CompareTo:   util.PastDay(20),
+	}
+	f.validate()
+	f.comparisonPeriod()
+	assert.Equal(t, util.PastDay(30), f.From)
+	assert.Equal(t, util.PastDay(20), f.To)
 }
@@ -84,6 +84,13 @@ const (
 
 	// PeriodYear groups the result by year.
 	PeriodYear
+
+	// PeriodHour groups the results by hour.
+	// Unlike Visitors.ByHour, the hours are not merged for multiple days.
+	PeriodHour
+
+	// PeriodQuarter groups the results by quarter.
+	PeriodQuarter
 )
 
 // Period is used to group results.
@@ -0,0 +1,238 @@
+package db
+
+import (
+	"context"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+)
+
+func (client *Client) savePageViewsBatch(pageViews []model.PageView) error {
+	ctx := client.insertContext()
+	batch, err := client.conn.PrepareBatch(ctx, `INSERT INTO "page_view" (client_id, visitor_id, session_id, time, duration_seconds,
+		hostname, path, title, language, country_code, region, city, referrer, referrer_name, referrer_icon, os, os_version,
+		browser, browser_version, desktop, mobile, screen_class,
+		utm_source, utm_medium, utm_campaign, utm_content, utm_term, channel,
+		tag_keys, tag_values)`)
+
+	if err != nil {
+		return err
+	}
+
+	for _, pageView := range pageViews {
+		if err := batch.Append(pageView.ClientID,
+			pageView.VisitorID,
+			pageView.SessionID,
+			pageView.Time,
+			pageView.DurationSeconds,
+			pageView.Hostname,
+			pageView.Path,
+			pageView.Title,
+			pageView.Language,
+			pageView.CountryCode,
+			pageView.Region,
+			pageView.City,
+			pageView.Referrer,
+			pageView.ReferrerName,
+			pageView.ReferrerIcon,
+			pageView.OS,
+			pageView.OSVersion,
+			pageView.Browser,
+			pageView.BrowserVersion,
+			client.boolean(pageView.Desktop),
+			client.boolean(pageView.Mobile),
+			pageView.ScreenClass,
+			pageView.UTMSource,
+			pageView.UTMMedium,
+			pageView.UTMCampaign,
+			pageView.UTMContent,
+			pageView.UTMTerm,
+			pageView.Channel,
+			pageView.TagKeys,
+			pageView.TagValues); err != nil {
+			_ = batch.Abort()
+			return err
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return err
+	}
+
+	if client.debug {
+		client.logger.Debug("saved page views", "count", len(pageViews))
+	}
+
+	return nil
+}
+
+func (client *Client) saveSessionsBatch(sessions []model.Session) error {
+	ctx := client.insertContext()
+	batch, err := client.conn.PrepareBatch(ctx, `INSERT INTO "session" (sign, version, client_id, visitor_id, session_id, time, start, duration_seconds,
+		hostname, entry_path, exit_path, page_views, is_bounce, is_engaged, entry_title, exit_title, language, country_code, region, city, referrer, referrer_name, referrer_icon, os, os_version,
+		browser, browser_version, desktop, mobile, screen_class,
+		utm_source, utm_medium, utm_campaign, utm_content, utm_term, channel, extended)`)
+
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if err := batch.Append(session.Sign,
+			session.Version,
+			session.ClientID,
+			session.VisitorID,
+			session.SessionID,
+			session.Time,
+			session.Start,
+			session.DurationSeconds,
+			session.Hostname,
+			session.EntryPath,
+			session.ExitPath,
+			session.PageViews,
+			client.boolean(session.IsBounce),
+			client.boolean(session.IsEngaged),
+			session.EntryTitle,
+			session.ExitTitle,
+			session.Language,
+			session.CountryCode,
+			session.Region,
+			session.City,
+			session.Referrer,
+			session.ReferrerName,
+			session.ReferrerIcon,
+			session.OS,
+			session.OSVersion,
+			session.Browser,
+			session.BrowserVersion,
+			client.boolean(session.Desktop),
+			client.boolean(session.Mobile),
+			session.ScreenClass,
+			session.UTMSource,
+			session.UTMMedium,
+			session.UTMCampaign,
+			session.UTMContent,
+			session.UTMTerm,
+			session.Channel,
+			session.Extended); err != nil {
+			_ = batch.Abort()
+			return err
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return err
+	}
+
+	if client.debug {
+		client.logger.Debug("saved sessions", "count", len(sessions))
+	}
+
+	return nil
+}
+
+func (client *Client) saveEventsBatch(events []model.Event) error {
+	ctx := client.insertContext()
+	batch, err := client.conn.PrepareBatch(ctx, `INSERT INTO "event" (client_id, visitor_id, time, session_id, event_name, event_meta_keys, event_meta_values, duration_seconds,
+		hostname, path, title, language, country_code, region, city, referrer, referrer_name, referrer_icon, os, os_version,
+		browser, browser_version, desktop, mobile, screen_class,
+		utm_source, utm_medium, utm_campaign, utm_content, utm_term, channel)`)
+
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if err := batch.Append(event.ClientID,
+			event.VisitorID,
+			event.Time,
+			event.SessionID,
+			event.Name,
+			event.MetaKeys,
+			event.MetaValues,
+			event.DurationSeconds,
+			event.Hostname,
+			event.Path,
+			event.Title,
+			event.Language,
+			event.CountryCode,
+			event.Region,
+			event.City,
+			event.Referrer,
+			event.ReferrerName,
+			event.ReferrerIcon,
+			event.OS,
+			event.OSVersion,
+			event.Browser,
+			event.BrowserVersion,
+			client.boolean(event.Desktop),
+			client.boolean(event.Mobile),
+			event.ScreenClass,
+			event.UTMSource,
+			event.UTMMedium,
+			event.UTMCampaign,
+			event.UTMContent,
+			event.UTMTerm,
+			event.Channel); err != nil {
+			_ = batch.Abort()
+			return err
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return err
+	}
+
+	if client.debug {
+		client.logger.Debug("saved events", "count", len(events))
+	}
+
+	return nil
+}
+
+func (client *Client) saveRequestsBatch(requests []model.Request) error {
+	ctx := client.insertContext()
+	batch, err := client.conn.PrepareBatch(ctx, `INSERT INTO "request" (client_id, visitor_id, time, ip, user_agent, hostname, path, event_name, referrer, utm_source, utm_medium, utm_campaign, bot, bot_reason)`)
+
+	if err != nil {
+		return err
+	}
+
+	for _, req := range requests {
+		if err := batch.Append(req.ClientID,
+			req.VisitorID,
+			req.Time,
+			req.IP,
+			req.UserAgent,
+			req.Hostname,
+			req.Path,
+			req.Event,
+			req.Referrer,
+			req.UTMSource,
+			req.UTMMedium,
+			req.UTMCampaign,
+			req.Bot,
+			req.BotReason); err != nil {
+			_ = batch.Abort()
+			return err
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return err
+	}
+
+	if client.debug {
+		client.logger.Debug("saved requests", "count", len(requests))
+	}
+
+	return nil
+}
+
+func (client *Client) insertContext() context.Context {
+	if len(client.insertSettings) == 0 {
+		return context.Background()
+	}
+
+	return clickhouse.Context(context.Background(), clickhouse.WithSettings(client.insertSettings))
+}
@@ -0,0 +1,216 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// workerBufferSize is the default buffer size of the tracker workers.
+const workerBufferSize = 500
+
+func TestClient_NativeInsert(t *testing.T) {
+	for _, async := range []bool{false, true} {
+		CleanupDB(t, dbClient)
+		client := connectNative(t, async)
+		now := time.Now()
+		assert.NoError(t, client.SavePageViews(testPageViews(10, now)))
+		assert.NoError(t, client.SaveSessions(testSessions(10, now)))
+		assert.NoError(t, client.SaveEvents(testEvents(10, now)))
+		assert.NoError(t, client.SaveRequests(testRequests(10, now)))
+		assert.NoError(t, client.SavePageViews([]model.PageView{{ClientID: 1, Time: now}}))
+
+		for table, expected := range map[string]int{
+			"page_view": 11,
+			"session":   10,
+			"event":     10,
+			"request":   10,
+		} {
+			var count int
+			assert.NoError(t, dbClient.QueryRow(fmt.Sprintf(`SELECT count(*) FROM "%s"`, table)).Scan(&count))
+			assert.Equal(t, expected, count)
+		}
+
+		var tags []string
+		assert.NoError(t, dbClient.QueryRow(`SELECT tag_keys FROM "page_view" WHERE visitor_id = 1`).Scan(&tags))
+		assert.Equal(t, []string{"author"}, tags)
+		assert.NoError(t, client.Close())
+	}
+}
+
+func BenchmarkClient_SavePageViews(b *testing.B) {
+	benchmarkInsert(b, func(client *Client) error {
+		return client.SavePageViews(testPageViews(workerBufferSize, time.Now()))
+	})
+}
+
+func BenchmarkClient_SaveSessions(b *testing.B) {
+	benchmarkInsert(b, func(client *Client) error {
+		// the tracker buffers twice as many sessions as page views and events
+		return client.SaveSessions(testSessions(workerBufferSize*2, time.Now()))
+	})
+}
+
+func BenchmarkClient_SaveEvents(b *testing.B) {
+	benchmarkInsert(b, func(client *Client) error {
+		return client.SaveEvents(testEvents(workerBufferSize, time.Now()))
+	})
+}
+
+func BenchmarkClient_SaveRequests(b *testing.B) {
+	benchmarkInsert(b, func(client *Client) error {
+		return client.SaveRequests(testRequests(workerBufferSize, time.Now()))
+	})
+}
+
+func benchmarkInsert(b *testing.B, save func(*Client) error) {
+	CleanupDB(b, dbClient)
+	native := connectNative(b, false)
+	async := connectNative(b, true)
+	defer func() {
+		assert.NoError(b, native.Close())
+		assert.NoError(b, async.Close())
+	}()
+
+	for name, client := range map[string]*Client{
+		"sql":          dbClient,
+		"native":       native,
+		"native_async": async,
+	} {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				assert.NoError(b, save(client))
+			}
+		})
+	}
+}
+
+func connectNative(tb testing.TB, async bool) *Client {
+	client, err := NewClient(&ClientConfig{
+		Hostnames:          []string{"127.0.0.1"},
+		Port:               9000,
+		Database:           "pirschtest",
+		Password:           "default",
+		SSLSkipVerify:      true,
+		MaxOpenConnections: 1,
+		NativeInsert:       true,
+		AsyncInsert:        async,
+		WaitForAsyncInsert: true,
+		dev:                true,
+	})
+	assert.NoError(tb, err)
+	return client
+}
+
+func testPageViews(n int, now time.Time) []model.PageView {
+	pageViews := make([]model.PageView, 0, n)
+
+	for i := range n {
+		pageViews = append(pageViews, model.PageView{
+			ClientID:        1,
+			VisitorID:       uint64(i + 1),
+			SessionID:       util.RandUint32(),
+			Time:            now,
+			DurationSeconds: 42,
+			Hostname:        "example.com",
+			Path:            "/path",
+			Title:           "title",
+			Language:        "en",
+			CountryCode:     "en",
+			City:            "London",
+			Referrer:        "https://google.com",
+			ReferrerName:    "Google",
+			OS:              pkg.OSWindows,
+			OSVersion:       "10",
+			Browser:         pkg.BrowserChrome,
+			BrowserVersion:  "134.0",
+			Desktop:         true,
+			ScreenClass:     "XL",
+			UTMSource:       "source",
+			Channel:         "Organic Search",
+			TagKeys:         []string{"author"},
+			TagValues:       []string{"John"},
+		})
+	}
+
+	return pageViews
+}
+
+func testSessions(n int, now time.Time) []model.Session {
+	sessions := make([]model.Session, 0, n)
+
+	for i := range n {
+		sessions = append(sessions, model.Session{
+			Sign:            1,
+			Version:         1,
+			ClientID:        1,
+			VisitorID:       uint64(i + 1),
+			SessionID:       util.RandUint32(),
+			Time:            now,
+			Start:           now,
+			DurationSeconds: 42,
+			Hostname:        "example.com",
+			EntryPath:       "/",
+			ExitPath:        "/path",
+			PageViews:       2,
+			Language:        "en",
+			CountryCode:     "en",
+			OS:              pkg.OSWindows,
+			Browser:         pkg.BrowserChrome,
+			Desktop:         true,
+			ScreenClass:     "XL",
+			Channel:         "Direct",
+		})
+	}
+
+	return sessions
+}
+
+func testEvents(n int, now time.Time) []model.Event {
+	events := make([]model.Event, 0, n)
+
+	for i := range n {
+		events = append(events, model.Event{
+			ClientID:   1,
+			VisitorID:  uint64(i + 1),
+			Time:       now,
+			SessionID:  util.RandUint32(),
+			Name:       "event",
+			MetaKeys:   []string{"key"},
+			MetaValues: []string{"value"},
+			Hostname:   "example.com",
+			Path:       "/path",
+			Language:   "en",
+			OS:         pkg.OSWindows,
+			Browser:    pkg.BrowserChrome,
+			Desktop:    true,
+		})
+	}
+
+	return events
+}
+
+func testRequests(n int, now time.Time) []model.Request {
+	requests := make([]model.Request, 0, n)
+
+	for i := range n {
+		requests = append(requests, model.Request{
+			ClientID:  1,
+			VisitorID: uint64(i + 1),
+			Time:      now,
+			IP:        "127.0.0.1",
+			UserAgent: "Mozilla/5.0",
+			Hostname:  "example.com",
+			Path:      "/path",
+			Bot:       true,
+			BotReason: "ua",
+		})
+	}
+
+	return requests
+}
@@ -0,0 +1,22 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	keyPrefix = "pirsch_query_"
+)
+
+// Cache is a backend to store query results.
+type Cache interface {
+	// Get returns the value for given key and true if found.
+	Get(context.Context, string) ([]byte, bool)
+
+	// Set stores a value for given key and time to live.
+	Set(context.Context, string, []byte, time.Duration)
+
+	// Clear removes all query results from the cache.
+	Clear(context.Context)
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxBytes = 64 * 1024 * 1024
+)
+
+type memEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// MemCache is an in-memory least recently used cache.
+// The memory used by keys and values is limited to the configured maximum size.
+type MemCache struct {
+	maxBytes int
+	bytes    int
+	entries  map[string]*list.Element
+	lru      *list.List
+	m        sync.Mutex
+}
+
+// NewMemCache creates a new in-memory cache for a given maximum size in bytes.
+func NewMemCache(maxBytes int) *MemCache {
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBytes
+	}
+
+	return &MemCache{
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Get implements the Cache interface.
+func (cache *MemCache) Get(_ context.Context, key string) ([]byte, bool) {
+	cache.m.Lock()
+	defer cache.m.Unlock()
+	element, found := cache.entries[key]
+
+	if !found {
+		return nil, false
+	}
+
+	entry := element.Value.(*memEntry)
+
+	if time.Now().After(entry.expires) {
+		cache.remove(element)
+		return nil, false
+	}
+
+	cache.lru.MoveToFront(element)
+	return entry.value, true
+}
+
+// Set implements the Cache interface.
+// Values larger than the maximum size are not stored.
+func (cache *MemCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) {
+	size := len(key) + len(value)
+
+	if size > cache.maxBytes {
+		return
+	}
+
+	cache.m.Lock()
+	defer cache.m.Unlock()
+
+	if element, found := cache.entries[key]; found {
+		cache.remove(element)
+	}
+
+	for cache.bytes+size > cache.maxBytes {
+		cache.remove(cache.lru.Back())
+	}
+
+	cache.entries[key] = cache.lru.PushFront(&memEntry{
+		key:     key,
+		value:   value,
+		expires: time.Now().Add(ttl),
+	})
+	cache.bytes += size
+}
+
+// Clear implements the Cache interface.
+func (cache *MemCache) Clear(context.Context) {
+	cache.m.Lock()
+	defer cache.m.Unlock()
+	cache.entries = make(map[string]*list.Element)
+	cache.lru.Init()
+	cache.bytes = 0
+}
+
+// Size returns the number of bytes used by keys and values.
+func (cache *MemCache) Size() int {
+	cache.m.Lock()
+	defer cache.m.Unlock()
+	return cache.bytes
+}
+
+func (cache *MemCache) remove(element *list.Element) {
+	entry := cache.lru.Remove(element).(*memEntry)
+	delete(cache.entries, entry.key)
+	cache.bytes -= len(entry.key) + len(entry.value)
+}
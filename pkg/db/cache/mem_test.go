@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemCache(20)
+	_, found := cache.Get(ctx, "a")
+	assert.False(t, found)
+	cache.Set(ctx, "a", []byte("12345"), time.Minute)
+	cache.Set(ctx, "b", []byte("12345"), time.Minute)
+	cache.Set(ctx, "c", []byte("12345"), time.Minute)
+	assert.Equal(t, 18, cache.Size())
+	value, found := cache.Get(ctx, "a")
+	assert.True(t, found)
+	assert.Equal(t, "12345", string(value))
+	cache.Set(ctx, "d", []byte("12345"), time.Minute)
+	assert.Equal(t, 18, cache.Size())
+	_, found = cache.Get(ctx, "b")
+	assert.False(t, found)
+	_, found = cache.Get(ctx, "a")
+	assert.True(t, found)
+	cache.Set(ctx, "e", []byte("this value is too large"), time.Minute)
+	_, found = cache.Get(ctx, "e")
+	assert.False(t, found)
+	cache.Set(ctx, "a", []byte("1"), time.Minute)
+	assert.Equal(t, 14, cache.Size())
+	cache.Clear(ctx)
+	assert.Equal(t, 0, cache.Size())
+	_, found = cache.Get(ctx, "a")
+	assert.False(t, found)
+}
+
+func TestMemCache_TTL(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemCache(0)
+	cache.Set(ctx, "a", []byte("value"), time.Millisecond*10)
+	_, found := cache.Get(ctx, "a")
+	assert.True(t, found)
+	time.Sleep(time.Millisecond * 20)
+	_, found = cache.Get(ctx, "a")
+	assert.False(t, found)
+	assert.Equal(t, 0, cache.Size())
+}
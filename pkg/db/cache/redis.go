@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisCache caches query results in Redis.
+// The memory limit and eviction policy must be configured in Redis (maxmemory and maxmemory-policy).
+type RedisCache struct {
+	rds    *redis.Client
+	logger *slog.Logger
+}
+
+// NewRedisCache creates a new cache for a given redis connection.
+func NewRedisCache(log *slog.Logger, redisOptions *redis.Options) *RedisCache {
+	if log == nil {
+		log = slog.New(slog.NewTextHandler(os.Stdout, nil))
+	}
+
+	return &RedisCache{
+		rds:    redis.NewClient(redisOptions),
+		logger: log,
+	}
+}
+
+// Get implements the Cache interface.
+func (cache *RedisCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	value, err := cache.rds.Get(ctx, key).Bytes()
+
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			cache.logger.Error("error reading query result from cache", "err", err)
+		}
+
+		return nil, false
+	}
+
+	return value, true
+}
+
+// Set implements the Cache interface.
+func (cache *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	if err := cache.rds.SetEX(ctx, key, value, ttl).Err(); err != nil {
+		cache.logger.Error("error storing query result in cache", "err", err)
+	}
+}
+
+// Clear implements the Cache interface.
+// Only keys stored by the cache are removed, so that the Redis database can be shared.
+func (cache *RedisCache) Clear(ctx context.Context) {
+	iter := cache.rds.Scan(ctx, 0, keyPrefix+"*", 1000).Iterator()
+
+	for iter.Next(ctx) {
+		if err := cache.rds.Del(ctx, iter.Val()).Err(); err != nil {
+			cache.logger.Error("error removing query result from cache", "err", err)
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		cache.logger.Error("error clearing cache", "err", err)
+	}
+}
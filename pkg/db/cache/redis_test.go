@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewRedisCache(nil, &redis.Options{
+		Addr: "localhost:6379",
+	})
+	cache.Clear(ctx)
+	_, found := cache.Get(ctx, keyPrefix+"a")
+	assert.False(t, found)
+	cache.Set(ctx, keyPrefix+"a", []byte("value"), time.Second)
+	value, found := cache.Get(ctx, keyPrefix+"a")
+	assert.True(t, found)
+	assert.Equal(t, "value", string(value))
+	cache.Clear(ctx)
+	_, found = cache.Get(ctx, keyPrefix+"a")
+	assert.False(t, found)
+	cache.Set(ctx, keyPrefix+"a", []byte("value"), time.Second)
+	time.Sleep(time.Second * 2)
+	_, found = cache.Get(ctx, keyPrefix+"a")
+	assert.False(t, found)
+}
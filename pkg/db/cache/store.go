@@ -0,0 +1,540 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+)
+
+const (
+	defaultTTL = time.Minute * 10
+	dateFormat = "2006-01-02"
+)
+
+// StoreConfig is the optional configuration for the Store.
+type StoreConfig struct {
+	// TTL is the time to live for query results.
+	// 10 minutes by default.
+	TTL time.Duration
+
+	// TodayTTL is the time to live for results of queries including today.
+	// These results change with every new page view or event, so they are not cached unless this is set.
+	TodayTTL time.Duration
+}
+
+// Stats are the cache hits and misses since the Store has been created.
+type Stats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// Store caches query results for a db.Store.
+// Results are keyed by the query, its arguments, and the method called.
+// Active visitors, sessions, exports, goal, segment, and content group definitions, and all writes are passed to the underlying db.Store.
+type Store struct {
+	db.Store
+
+	cache    Cache
+	ttl      time.Duration
+	todayTTL time.Duration
+	hits     atomic.Uint64
+	misses   atomic.Uint64
+}
+
+// NewStore creates a new caching Store for given db.Store and Cache.
+func NewStore(store db.Store, cache Cache, config *StoreConfig) *Store {
+	if config == nil {
+		config = new(StoreConfig)
+	}
+
+	if config.TTL <= 0 {
+		config.TTL = defaultTTL
+	}
+
+	return &Store{
+		Store:    store,
+		cache:    cache,
+		ttl:      config.TTL,
+		todayTTL: config.TodayTTL,
+	}
+}
+
+// Stats returns the cache hits and misses.
+func (store *Store) Stats() Stats {
+	return Stats{
+		Hits:   store.hits.Load(),
+		Misses: store.misses.Load(),
+	}
+}
+
+// Clear removes all query results from the cache.
+func (store *Store) Clear(ctx context.Context) {
+	store.cache.Clear(ctx)
+}
+
+// Count implements the Store interface.
+func (store *Store) Count(ctx context.Context, query string, args ...any) (int, error) {
+	return cached(ctx, store, "Count", query, nil, args, func() (int, error) {
+		return store.Store.Count(ctx, query, args...)
+	})
+}
+
+// GetTotalVisitorStats implements the Store interface.
+func (store *Store) GetTotalVisitorStats(ctx context.Context, query string, includeCR, includeCustomMetric bool, args ...any) (*model.TotalVisitorStats, error) {
+	return cached(ctx, store, "GetTotalVisitorStats", query, []any{includeCR, includeCustomMetric}, args, func() (*model.TotalVisitorStats, error) {
+		return store.Store.GetTotalVisitorStats(ctx, query, includeCR, includeCustomMetric, args...)
+	})
+}
+
+// GetTotalUniqueVisitorStats implements the Store interface.
+func (store *Store) GetTotalUniqueVisitorStats(ctx context.Context, query string, args ...any) (int, error) {
+	return cached(ctx, store, "GetTotalUniqueVisitorStats", query, nil, args, func() (int, error) {
+		return store.Store.GetTotalUniqueVisitorStats(ctx, query, args...)
+	})
+}
+
+// GetTotalPageViewStats implements the Store interface.
+func (store *Store) GetTotalPageViewStats(ctx context.Context, query string, args ...any) (int, error) {
+	return cached(ctx, store, "GetTotalPageViewStats", query, nil, args, func() (int, error) {
+		return store.Store.GetTotalPageViewStats(ctx, query, args...)
+	})
+}
+
+// GetTotalSessionStats implements the Store interface.
+func (store *Store) GetTotalSessionStats(ctx context.Context, query string, args ...any) (int, error) {
+	return cached(ctx, store, "GetTotalSessionStats", query, nil, args, func() (int, error) {
+		return store.Store.GetTotalSessionStats(ctx, query, args...)
+	})
+}
+
+// GetTotalVisitorsPageViewsStats implements the Store interface.
+func (store *Store) GetTotalVisitorsPageViewsStats(ctx context.Context, query string, args ...any) (*model.TotalVisitorsPageViewsStats, error) {
+	return cached(ctx, store, "GetTotalVisitorsPageViewsStats", query, nil, args, func() (*model.TotalVisitorsPageViewsStats, error) {
+		return store.Store.GetTotalVisitorsPageViewsStats(ctx, query, args...)
+	})
+}
+
+// SelectVisitorStats implements the Store interface.
+func (store *Store) SelectVisitorStats(ctx context.Context, period pkg.Period, query string, includeCR, includeCustomMetric bool, args ...any) ([]model.VisitorStats, error) {
+	return cached(ctx, store, "SelectVisitorStats", query, []any{period, includeCR, includeCustomMetric}, args, func() ([]model.VisitorStats, error) {
+		return store.Store.SelectVisitorStats(ctx, period, query, includeCR, includeCustomMetric, args...)
+	})
+}
+
+// SelectTimeSpentStats implements the Store interface.
+func (store *Store) SelectTimeSpentStats(ctx context.Context, period pkg.Period, query string, args ...any) ([]model.TimeSpentStats, error) {
+	return cached(ctx, store, "SelectTimeSpentStats", query, []any{period}, args, func() ([]model.TimeSpentStats, error) {
+		return store.Store.SelectTimeSpentStats(ctx, period, query, args...)
+	})
+}
+
+// GetGrowthStats implements the Store interface.
+func (store *Store) GetGrowthStats(ctx context.Context, query string, includeCR, includeCustomMetrics bool, args ...any) (*model.GrowthStats, error) {
+	return cached(ctx, store, "GetGrowthStats", query, []any{includeCR, includeCustomMetrics}, args, func() (*model.GrowthStats, error) {
+		return store.Store.GetGrowthStats(ctx, query, includeCR, includeCustomMetrics, args...)
+	})
+}
+
+// SelectVisitorHourStats implements the Store interface.
+func (store *Store) SelectVisitorHourStats(ctx context.Context, query string, includeCR, includeCustomMetrics bool, args ...any) ([]model.VisitorHourStats, error) {
+	return cached(ctx, store, "SelectVisitorHourStats", query, []any{includeCR, includeCustomMetrics}, args, func() ([]model.VisitorHourStats, error) {
+		return store.Store.SelectVisitorHourStats(ctx, query, includeCR, includeCustomMetrics, args...)
+	})
+}
+
+// SelectVisitorWeekdayHourStats implements the Store interface.
+func (store *Store) SelectVisitorWeekdayHourStats(ctx context.Context, query string, args ...any) ([]model.VisitorWeekdayHourStats, error) {
+	return cached(ctx, store, "SelectVisitorWeekdayHourStats", query, nil, args, func() ([]model.VisitorWeekdayHourStats, error) {
+		return store.Store.SelectVisitorWeekdayHourStats(ctx, query, args...)
+	})
+}
+
+// SelectVisitorMinuteStats implements the Store interface.
+func (store *Store) SelectVisitorMinuteStats(ctx context.Context, query string, includeCR, includeCustomMetrics bool, args ...any) ([]model.VisitorMinuteStats, error) {
+	return cached(ctx, store, "SelectVisitorMinuteStats", query, []any{includeCR, includeCustomMetrics}, args, func() ([]model.VisitorMinuteStats, error) {
+		return store.Store.SelectVisitorMinuteStats(ctx, query, includeCR, includeCustomMetrics, args...)
+	})
+}
+
+// SelectHostnameStats implements the Store interface.
+func (store *Store) SelectHostnameStats(ctx context.Context, query string, args ...any) ([]model.HostnameStats, error) {
+	return cached(ctx, store, "SelectHostnameStats", query, nil, args, func() ([]model.HostnameStats, error) {
+		return store.Store.SelectHostnameStats(ctx, query, args...)
+	})
+}
+
+// SelectPageStats implements the Store interface.
+func (store *Store) SelectPageStats(ctx context.Context, includeTitle, includeTimeSpent bool, query string, args ...any) ([]model.PageStats, error) {
+	return cached(ctx, store, "SelectPageStats", query, []any{includeTitle, includeTimeSpent}, args, func() ([]model.PageStats, error) {
+		return store.Store.SelectPageStats(ctx, includeTitle, includeTimeSpent, query, args...)
+	})
+}
+
+// SelectPathGroupStats implements the Store interface.
+func (store *Store) SelectPathGroupStats(ctx context.Context, query string, args ...any) ([]model.PathGroupStats, error) {
+	return cached(ctx, store, "SelectPathGroupStats", query, nil, args, func() ([]model.PathGroupStats, error) {
+		return store.Store.SelectPathGroupStats(ctx, query, args...)
+	})
+}
+
+// SelectAvgTimeSpentStats implements the Store interface.
+func (store *Store) SelectAvgTimeSpentStats(ctx context.Context, query string, args ...any) ([]model.AvgTimeSpentStats, error) {
+	return cached(ctx, store, "SelectAvgTimeSpentStats", query, nil, args, func() ([]model.AvgTimeSpentStats, error) {
+		return store.Store.SelectAvgTimeSpentStats(ctx, query, args...)
+	})
+}
+
+// SelectEntryStats implements the Store interface.
+func (store *Store) SelectEntryStats(ctx context.Context, includeTitle bool, query string, args ...any) ([]model.EntryStats, error) {
+	return cached(ctx, store, "SelectEntryStats", query, []any{includeTitle}, args, func() ([]model.EntryStats, error) {
+		return store.Store.SelectEntryStats(ctx, includeTitle, query, args...)
+	})
+}
+
+// SelectExitStats implements the Store interface.
+func (store *Store) SelectExitStats(ctx context.Context, includeTitle bool, query string, args ...any) ([]model.ExitStats, error) {
+	return cached(ctx, store, "SelectExitStats", query, []any{includeTitle}, args, func() ([]model.ExitStats, error) {
+		return store.Store.SelectExitStats(ctx, includeTitle, query, args...)
+	})
+}
+
+// SelectTotalSessions implements the Store interface.
+func (store *Store) SelectTotalSessions(ctx context.Context, query string, args ...any) (int, error) {
+	return cached(ctx, store, "SelectTotalSessions", query, nil, args, func() (int, error) {
+		return store.Store.SelectTotalSessions(ctx, query, args...)
+	})
+}
+
+// SelectTotalVisitorSessionStats implements the Store interface.
+func (store *Store) SelectTotalVisitorSessionStats(ctx context.Context, query string, args ...any) ([]model.TotalVisitorSessionStats, error) {
+	return cached(ctx, store, "SelectTotalVisitorSessionStats", query, nil, args, func() ([]model.TotalVisitorSessionStats, error) {
+		return store.Store.SelectTotalVisitorSessionStats(ctx, query, args...)
+	})
+}
+
+// GetConversionsStats implements the Store interface.
+func (store *Store) GetConversionsStats(ctx context.Context, query string, includeCustomMetric bool, args ...any) (*model.ConversionsStats, error) {
+	return cached(ctx, store, "GetConversionsStats", query, []any{includeCustomMetric}, args, func() (*model.ConversionsStats, error) {
+		return store.Store.GetConversionsStats(ctx, query, includeCustomMetric, args...)
+	})
+}
+
+// SelectEventStats implements the Store interface.
+func (store *Store) SelectEventStats(ctx context.Context, breakdown bool, query string, args ...any) ([]model.EventStats, error) {
+	return cached(ctx, store, "SelectEventStats", query, []any{breakdown}, args, func() ([]model.EventStats, error) {
+		return store.Store.SelectEventStats(ctx, breakdown, query, args...)
+	})
+}
+
+// SelectEventListStats implements the Store interface.
+func (store *Store) SelectEventListStats(ctx context.Context, query string, args ...any) ([]model.EventListStats, error) {
+	return cached(ctx, store, "SelectEventListStats", query, nil, args, func() ([]model.EventListStats, error) {
+		return store.Store.SelectEventListStats(ctx, query, args...)
+	})
+}
+
+// SelectReferrerStats implements the Store interface.
+func (store *Store) SelectReferrerStats(ctx context.Context, query string, args ...any) ([]model.ReferrerStats, error) {
+	return cached(ctx, store, "SelectReferrerStats", query, nil, args, func() ([]model.ReferrerStats, error) {
+		return store.Store.SelectReferrerStats(ctx, query, args...)
+	})
+}
+
+// GetPlatformStats implements the Store interface.
+func (store *Store) GetPlatformStats(ctx context.Context, query string, args ...any) (*model.PlatformStats, error) {
+	return cached(ctx, store, "GetPlatformStats", query, nil, args, func() (*model.PlatformStats, error) {
+		return store.Store.GetPlatformStats(ctx, query, args...)
+	})
+}
+
+// SelectLanguageStats implements the Store interface.
+func (store *Store) SelectLanguageStats(ctx context.Context, query string, args ...any) ([]model.LanguageStats, error) {
+	return cached(ctx, store, "SelectLanguageStats", query, nil, args, func() ([]model.LanguageStats, error) {
+		return store.Store.SelectLanguageStats(ctx, query, args...)
+	})
+}
+
+// SelectCountryStats implements the Store interface.
+func (store *Store) SelectCountryStats(ctx context.Context, query string, args ...any) ([]model.CountryStats, error) {
+	return cached(ctx, store, "SelectCountryStats", query, nil, args, func() ([]model.CountryStats, error) {
+		return store.Store.SelectCountryStats(ctx, query, args...)
+	})
+}
+
+// SelectRegionStats implements the Store interface.
+func (store *Store) SelectRegionStats(ctx context.Context, query string, args ...any) ([]model.RegionStats, error) {
+	return cached(ctx, store, "SelectRegionStats", query, nil, args, func() ([]model.RegionStats, error) {
+		return store.Store.SelectRegionStats(ctx, query, args...)
+	})
+}
+
+// SelectCityStats implements the Store interface.
+func (store *Store) SelectCityStats(ctx context.Context, query string, args ...any) ([]model.CityStats, error) {
+	return cached(ctx, store, "SelectCityStats", query, nil, args, func() ([]model.CityStats, error) {
+		return store.Store.SelectCityStats(ctx, query, args...)
+	})
+}
+
+// SelectBrowserStats implements the Store interface.
+func (store *Store) SelectBrowserStats(ctx context.Context, query string, args ...any) ([]model.BrowserStats, error) {
+	return cached(ctx, store, "SelectBrowserStats", query, nil, args, func() ([]model.BrowserStats, error) {
+		return store.Store.SelectBrowserStats(ctx, query, args...)
+	})
+}
+
+// SelectOSStats implements the Store interface.
+func (store *Store) SelectOSStats(ctx context.Context, query string, args ...any) ([]model.OSStats, error) {
+	return cached(ctx, store, "SelectOSStats", query, nil, args, func() ([]model.OSStats, error) {
+		return store.Store.SelectOSStats(ctx, query, args...)
+	})
+}
+
+// SelectScreenClassStats implements the Store interface.
+func (store *Store) SelectScreenClassStats(ctx context.Context, query string, args ...any) ([]model.ScreenClassStats, error) {
+	return cached(ctx, store, "SelectScreenClassStats", query, nil, args, func() ([]model.ScreenClassStats, error) {
+		return store.Store.SelectScreenClassStats(ctx, query, args...)
+	})
+}
+
+// SelectUTMSourceStats implements the Store interface.
+func (store *Store) SelectUTMSourceStats(ctx context.Context, query string, args ...any) ([]model.UTMSourceStats, error) {
+	return cached(ctx, store, "SelectUTMSourceStats", query, nil, args, func() ([]model.UTMSourceStats, error) {
+		return store.Store.SelectUTMSourceStats(ctx, query, args...)
+	})
+}
+
+// SelectUTMMediumStats implements the Store interface.
+func (store *Store) SelectUTMMediumStats(ctx context.Context, query string, args ...any) ([]model.UTMMediumStats, error) {
+	return cached(ctx, store, "SelectUTMMediumStats", query, nil, args, func() ([]model.UTMMediumStats, error) {
+		return store.Store.SelectUTMMediumStats(ctx, query, args...)
+	})
+}
+
+// SelectUTMCampaignStats implements the Store interface.
+func (store *Store) SelectUTMCampaignStats(ctx context.Context, query string, args ...any) ([]model.UTMCampaignStats, error) {
+	return cached(ctx, store, "SelectUTMCampaignStats", query, nil, args, func() ([]model.UTMCampaignStats, error) {
+		return store.Store.SelectUTMCampaignStats(ctx, query, args...)
+	})
+}
+
+// SelectUTMContentStats implements the Store interface.
+func (store *Store) SelectUTMContentStats(ctx context.Context, query string, args ...any) ([]model.UTMContentStats, error) {
+	return cached(ctx, store, "SelectUTMContentStats", query, nil, args, func() ([]model.UTMContentStats, error) {
+		return store.Store.SelectUTMContentStats(ctx, query, args...)
+	})
+}
+
+// SelectUTMTermStats implements the Store interface.
+func (store *Store) SelectUTMTermStats(ctx context.Context, query string, args ...any) ([]model.UTMTermStats, error) {
+	return cached(ctx, store, "SelectUTMTermStats", query, nil, args, func() ([]model.UTMTermStats, error) {
+		return store.Store.SelectUTMTermStats(ctx, query, args...)
+	})
+}
+
+// SelectChannelStats implements the Store interface.
+func (store *Store) SelectChannelStats(ctx context.Context, query string, args ...any) ([]model.ChannelStats, error) {
+	return cached(ctx, store, "SelectChannelStats", query, nil, args, func() ([]model.ChannelStats, error) {
+		return store.Store.SelectChannelStats(ctx, query, args...)
+	})
+}
+
+// SelectOSVersionStats implements the Store interface.
+func (store *Store) SelectOSVersionStats(ctx context.Context, query string, args ...any) ([]model.OSVersionStats, error) {
+	return cached(ctx, store, "SelectOSVersionStats", query, nil, args, func() ([]model.OSVersionStats, error) {
+		return store.Store.SelectOSVersionStats(ctx, query, args...)
+	})
+}
+
+// SelectBrowserVersionStats implements the Store interface.
+func (store *Store) SelectBrowserVersionStats(ctx context.Context, query string, args ...any) ([]model.BrowserVersionStats, error) {
+	return cached(ctx, store, "SelectBrowserVersionStats", query, nil, args, func() ([]model.BrowserVersionStats, error) {
+		return store.Store.SelectBrowserVersionStats(ctx, query, args...)
+	})
+}
+
+// SelectOptions implements the Store interface.
+func (store *Store) SelectOptions(ctx context.Context, query string, args ...any) ([]string, error) {
+	return cached(ctx, store, "SelectOptions", query, nil, args, func() ([]string, error) {
+		return store.Store.SelectOptions(ctx, query, args...)
+	})
+}
+
+// SelectTagStats implements the Store interface.
+func (store *Store) SelectTagStats(ctx context.Context, breakdown bool, query string, args ...any) ([]model.TagStats, error) {
+	return cached(ctx, store, "SelectTagStats", query, []any{breakdown}, args, func() ([]model.TagStats, error) {
+		return store.Store.SelectTagStats(ctx, breakdown, query, args...)
+	})
+}
+
+// SelectSessions implements the Store interface.
+func (store *Store) SelectSessions(ctx context.Context, query string, args ...any) ([]model.Session, error) {
+	return cached(ctx, store, "SelectSessions", query, nil, args, func() ([]model.Session, error) {
+		return store.Store.SelectSessions(ctx, query, args...)
+	})
+}
+
+// SelectPageViews implements the Store interface.
+func (store *Store) SelectPageViews(ctx context.Context, query string, args ...any) ([]model.PageView, error) {
+	return cached(ctx, store, "SelectPageViews", query, nil, args, func() ([]model.PageView, error) {
+		return store.Store.SelectPageViews(ctx, query, args...)
+	})
+}
+
+// SelectEvents implements the Store interface.
+func (store *Store) SelectEvents(ctx context.Context, query string, args ...any) ([]model.Event, error) {
+	return cached(ctx, store, "SelectEvents", query, nil, args, func() ([]model.Event, error) {
+		return store.Store.SelectEvents(ctx, query, args...)
+	})
+}
+
+// SelectFunnelSteps implements the Store interface.
+func (store *Store) SelectFunnelSteps(ctx context.Context, query string, args ...any) ([]model.FunnelStep, error) {
+	return cached(ctx, store, "SelectFunnelSteps", query, nil, args, func() ([]model.FunnelStep, error) {
+		return store.Store.SelectFunnelSteps(ctx, query, args...)
+	})
+}
+
+// SelectFunnelBreakdownSteps implements the Store interface.
+func (store *Store) SelectFunnelBreakdownSteps(ctx context.Context, query string, args ...any) ([]model.FunnelBreakdownStep, error) {
+	return cached(ctx, store, "SelectFunnelBreakdownSteps", query, nil, args, func() ([]model.FunnelBreakdownStep, error) {
+		return store.Store.SelectFunnelBreakdownSteps(ctx, query, args...)
+	})
+}
+
+// SelectPageTransitionStats implements the Store interface.
+func (store *Store) SelectPageTransitionStats(ctx context.Context, query string, args ...any) ([]model.PageTransitionStats, error) {
+	return cached(ctx, store, "SelectPageTransitionStats", query, nil, args, func() ([]model.PageTransitionStats, error) {
+		return store.Store.SelectPageTransitionStats(ctx, query, args...)
+	})
+}
+
+// SelectPageFlowSteps implements the Store interface.
+func (store *Store) SelectPageFlowSteps(ctx context.Context, query string, args ...any) ([]model.PageFlowStep, error) {
+	return cached(ctx, store, "SelectPageFlowSteps", query, nil, args, func() ([]model.PageFlowStep, error) {
+		return store.Store.SelectPageFlowSteps(ctx, query, args...)
+	})
+}
+
+// SelectGoalStats implements the Store interface.
+func (store *Store) SelectGoalStats(ctx context.Context, query string, args ...any) ([]model.GoalStats, error) {
+	return cached(ctx, store, "SelectGoalStats", query, nil, args, func() ([]model.GoalStats, error) {
+		return store.Store.SelectGoalStats(ctx, query, args...)
+	})
+}
+
+// SelectGoalBreakdownStats implements the Store interface.
+func (store *Store) SelectGoalBreakdownStats(ctx context.Context, query string, args ...any) ([]model.GoalBreakdownStats, error) {
+	return cached(ctx, store, "SelectGoalBreakdownStats", query, nil, args, func() ([]model.GoalBreakdownStats, error) {
+		return store.Store.SelectGoalBreakdownStats(ctx, query, args...)
+	})
+}
+
+// SelectGoalPeriodStats implements the Store interface.
+func (store *Store) SelectGoalPeriodStats(ctx context.Context, period pkg.Period, query string, args ...any) ([]model.GoalPeriodStats, error) {
+	return cached(ctx, store, "SelectGoalPeriodStats", query, []any{period}, args, func() ([]model.GoalPeriodStats, error) {
+		return store.Store.SelectGoalPeriodStats(ctx, period, query, args...)
+	})
+}
+
+// GetPercentileStats implements the Store interface.
+func (store *Store) GetPercentileStats(ctx context.Context, query string, args ...any) (*model.PercentileStats, error) {
+	return cached(ctx, store, "GetPercentileStats", query, nil, args, func() (*model.PercentileStats, error) {
+		return store.Store.GetPercentileStats(ctx, query, args...)
+	})
+}
+
+// SelectHistogramBuckets implements the Store interface.
+func (store *Store) SelectHistogramBuckets(ctx context.Context, query string, args ...any) ([]model.HistogramBucket, error) {
+	return cached(ctx, store, "SelectHistogramBuckets", query, nil, args, func() ([]model.HistogramBucket, error) {
+		return store.Store.SelectHistogramBuckets(ctx, query, args...)
+	})
+}
+
+// SelectBreakdownRows implements the Store interface.
+func (store *Store) SelectBreakdownRows(ctx context.Context, dimensions int, query string, args ...any) ([]model.BreakdownRow, error) {
+	return cached(ctx, store, "SelectBreakdownRows", query, []any{dimensions}, args, func() ([]model.BreakdownRow, error) {
+		return store.Store.SelectBreakdownRows(ctx, dimensions, query, args...)
+	})
+}
+
+func cached[T any](ctx context.Context, store *Store, method, query string, params, args []any, f func() (T, error)) (T, error) {
+	ttl := store.ttl
+
+	if includesToday(args) {
+		ttl = store.todayTTL
+	}
+
+	if ttl <= 0 {
+		return f()
+	}
+
+	key, err := cacheKey(method, query, params, args)
+
+	if err != nil {
+		return f()
+	}
+
+	if value, found := store.cache.Get(ctx, key); found {
+		var result T
+
+		if err := json.Unmarshal(value, &result); err == nil {
+			store.hits.Add(1)
+			return result, nil
+		}
+	}
+
+	store.misses.Add(1)
+	result, err := f()
+
+	if err != nil {
+		return result, err
+	}
+
+	if value, err := json.Marshal(result); err == nil {
+		store.cache.Set(ctx, key, value, ttl)
+	}
+
+	return result, nil
+}
+
+func cacheKey(method, query string, params, args []any) (string, error) {
+	data, err := json.Marshal([]any{method, query, params, args})
+
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+	return keyPrefix + hex.EncodeToString(hash[:]), nil
+}
+
+// includesToday returns true if any date or time argument is within the last day.
+// A day is subtracted to cover all time zones.
+func includesToday(args []any) bool {
+	yesterday := time.Now().UTC().Add(-time.Hour * 24)
+	yesterdayDate := yesterday.Truncate(time.Hour * 24)
+
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			if len(v) == len(dateFormat) {
+				if date, err := time.Parse(dateFormat, v); err == nil && !date.Before(yesterdayDate) {
+					return true
+				}
+			}
+		case time.Time:
+			if v.After(yesterday) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type storeMock struct {
+	*db.ClientMock
+
+	calls int
+	err   error
+}
+
+func (store *storeMock) SelectPageStats(context.Context, bool, bool, string, ...any) ([]model.PageStats, error) {
+	store.calls++
+
+	if store.err != nil {
+		return nil, store.err
+	}
+
+	return []model.PageStats{{Path: "/", Visitors: 42}}, nil
+}
+
+func (store *storeMock) SelectPathGroupStats(context.Context, string, ...any) ([]model.PathGroupStats, error) {
+	store.calls++
+	return []model.PathGroupStats{{Name: "/blog", Visitors: 42}}, nil
+}
+
+func (store *storeMock) SelectBreakdownRows(_ context.Context, dimensions int, _ string, _ ...any) ([]model.BreakdownRow, error) {
+	store.calls++
+	return []model.BreakdownRow{{Dimensions: make([]string, dimensions), Metrics: map[string]float64{"visitors": 42}}}, nil
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	mock := &storeMock{ClientMock: db.NewClientMock()}
+	var client db.Store = NewStore(mock, NewMemCache(0), nil)
+	store := client.(*Store)
+	from := time.Now().UTC().Add(-time.Hour * 24 * 30).Format(dateFormat)
+	to := time.Now().UTC().Add(-time.Hour * 24 * 7).Format(dateFormat)
+
+	for range 3 {
+		stats, err := client.SelectPageStats(ctx, false, false, "SELECT path", 1, from, to)
+		assert.NoError(t, err)
+		assert.Len(t, stats, 1)
+		assert.Equal(t, "/", stats[0].Path)
+		assert.Equal(t, 42, stats[0].Visitors)
+	}
+
+	assert.Equal(t, 1, mock.calls)
+	assert.Equal(t, Stats{Hits: 2, Misses: 1}, store.Stats())
+	_, err := client.SelectPageStats(ctx, true, false, "SELECT path", 1, from, to)
+	assert.NoError(t, err)
+	_, err = client.SelectPageStats(ctx, false, false, "SELECT path", 2, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, mock.calls)
+	assert.Equal(t, Stats{Hits: 2, Misses: 3}, store.Stats())
+	store.Clear(ctx)
+	_, err = client.SelectPageStats(ctx, false, false, "SELECT path", 1, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, mock.calls)
+	mock.err = errors.New("error")
+	_, err = client.SelectPageStats(ctx, false, false, "SELECT path", 3, from, to)
+	assert.Error(t, err)
+	_, err = client.SelectPageStats(ctx, false, false, "SELECT path", 3, from, to)
+	assert.Error(t, err)
+	assert.Equal(t, 6, mock.calls)
+}
+
+func TestStore_SelectPathGroupStats(t *testing.T) {
+	ctx := context.Background()
+	mock := &storeMock{ClientMock: db.NewClientMock()}
+	store := NewStore(mock, NewMemCache(0), nil)
+	from := time.Now().UTC().Add(-time.Hour * 24 * 30).Format(dateFormat)
+	to := time.Now().UTC().Add(-time.Hour * 24 * 7).Format(dateFormat)
+
+	for range 2 {
+		stats, err := store.SelectPathGroupStats(ctx, "SELECT path_group", 1, from, to)
+		assert.NoError(t, err)
+		assert.Len(t, stats, 1)
+		assert.Equal(t, "/blog", stats[0].Name)
+		assert.Equal(t, 42, stats[0].Visitors)
+	}
+
+	_, err := store.SelectPathGroupStats(ctx, "SELECT path_group", 1, "/docs", from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, mock.calls)
+	assert.Equal(t, Stats{Hits: 1, Misses: 2}, store.Stats())
+}
+
+func TestStore_SelectBreakdownRows(t *testing.T) {
+	ctx := context.Background()
+	mock := &storeMock{ClientMock: db.NewClientMock()}
+	store := NewStore(mock, NewMemCache(0), nil)
+	from := time.Now().UTC().Add(-time.Hour * 24 * 30).Format(dateFormat)
+	to := time.Now().UTC().Add(-time.Hour * 24 * 7).Format(dateFormat)
+
+	for range 2 {
+		rows, err := store.SelectBreakdownRows(ctx, 2, "SELECT country_code, browser", 1, from, to)
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		assert.Len(t, rows[0].Dimensions, 2)
+		assert.Equal(t, 42.0, rows[0].Metrics["visitors"])
+	}
+
+	assert.Equal(t, 1, mock.calls)
+
+	// the totals are selected without dimensions for the same query
+	for range 2 {
+		rows, err := store.SelectBreakdownRows(ctx, 0, "SELECT country_code, browser", 1, from, to)
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		assert.Empty(t, rows[0].Dimensions)
+	}
+
+	assert.Equal(t, 2, mock.calls)
+	assert.Equal(t, Stats{Hits: 2, Misses: 2}, store.Stats())
+}
+
+func TestStore_Today(t *testing.T) {
+	ctx := context.Background()
+	mock := &storeMock{ClientMock: db.NewClientMock()}
+	store := NewStore(mock, NewMemCache(0), nil)
+	from := time.Now().UTC().Add(-time.Hour * 24 * 7).Format(dateFormat)
+	to := time.Now().UTC().Format(dateFormat)
+
+	for range 2 {
+		_, err := store.SelectPageStats(ctx, false, false, "SELECT path", 1, from, to)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, mock.calls)
+	assert.Equal(t, Stats{}, store.Stats())
+	store = NewStore(mock, NewMemCache(0), &StoreConfig{TodayTTL: time.Minute})
+
+	for range 2 {
+		_, err := store.SelectPageStats(ctx, false, false, "SELECT path", 1, from, to)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, mock.calls)
+	assert.Equal(t, Stats{Hits: 1, Misses: 1}, store.Stats())
+}
+
+func TestIncludesToday(t *testing.T) {
+	now := time.Now().UTC()
+	assert.False(t, includesToday(nil))
+	assert.False(t, includesToday([]any{1, "/path", now.Add(-time.Hour * 24 * 3).Format(dateFormat)}))
+	assert.True(t, includesToday([]any{1, now.Format(dateFormat)}))
+	assert.True(t, includesToday([]any{now.Add(-time.Minute)}))
+	assert.False(t, includesToday([]any{now.Add(-time.Hour * 48)}))
+}
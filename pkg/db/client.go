@@ -8,11 +8,14 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 	_ "github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/emvi/null"
 	"github.com/pirsch-analytics/pirsch/v6/pkg"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
 )
@@ -70,6 +73,22 @@ type ClientConfig struct {
 	// The default log will be used printing to os.Stdout with "pirsch" in its prefix in case it is not set.
 	Logger *slog.Logger
 
+	// NativeInsert inserts page views, sessions, events, and requests using the native ClickHouse batch API.
+	// This opens a second connection pool using the native protocol.
+	NativeInsert bool
+
+	// AsyncInsert enables asynchronous inserts on the server (async_insert).
+	// Data is buffered by ClickHouse and written in larger parts.
+	AsyncInsert bool
+
+	// WaitForAsyncInsert waits for asynchronous inserts to be written before returning (wait_for_async_insert).
+	// This is only used in combination with AsyncInsert.
+	WaitForAsyncInsert bool
+
+	// QuerySettings are the default resource limits for all queries.
+	// They can be overridden per query using WithQuerySettings.
+	QuerySettings QuerySettings
+
 	// Debug will enable verbose logging.
 	Debug bool
 
@@ -101,9 +120,13 @@ func (config *ClientConfig) validate() {
 // Client is a ClickHouse database client.
 type Client struct {
 	*sql.DB
-	logger *slog.Logger
-	debug  bool
-	dev    bool
+	conn           driver.Conn
+	logger         *slog.Logger
+	cluster        string
+	settings       QuerySettings
+	insertSettings clickhouse.Settings
+	debug          bool
+	dev            bool
 }
 
 // NewClient returns a new client for a given database connection string.
@@ -128,7 +151,7 @@ func NewClient(config *ClientConfig) (*Client, error) {
 		addr[i] = fmt.Sprintf("%s:%d", hostname, config.Port)
 	}
 
-	db := clickhouse.OpenDB(&clickhouse.Options{
+	options := &clickhouse.Options{
 		Addr: addr,
 		Auth: clickhouse.Auth{
 			Database: config.Database,
@@ -138,26 +161,86 @@ func NewClient(config *ClientConfig) (*Client, error) {
 		TLS:         tlsConn,
 		DialTimeout: time.Second * 30,
 		Debug:       config.Debug,
-	})
+	}
+	db := clickhouse.OpenDB(options)
 	db.SetMaxOpenConns(config.MaxOpenConnections)
 	db.SetMaxIdleConns(config.MaxIdleConnections)
 	db.SetConnMaxLifetime(time.Duration(config.MaxConnectionLifetimeSeconds) * time.Second)
 	db.SetConnMaxIdleTime(time.Duration(config.MaxConnectionIdleTimeSeconds) * time.Second)
 
 	if err := db.Ping(); err != nil {
-		return nil, err
+		return nil, errors.Join(err, db.Close())
+	}
+
+	var conn driver.Conn
+
+	if config.NativeInsert {
+		options.MaxOpenConns = config.MaxOpenConnections
+		options.MaxIdleConns = config.MaxIdleConnections
+		options.ConnMaxLifetime = time.Duration(config.MaxConnectionLifetimeSeconds) * time.Second
+		var err error
+		conn, err = clickhouse.Open(options)
+
+		if err != nil {
+			return nil, errors.Join(err, db.Close())
+		}
+
+		if err := conn.Ping(context.Background()); err != nil {
+			return nil, errors.Join(err, conn.Close(), db.Close())
+		}
+	}
+
+	insertSettings := make(clickhouse.Settings)
+
+	if config.AsyncInsert {
+		insertSettings["async_insert"] = 1
+
+		if config.WaitForAsyncInsert {
+			insertSettings["wait_for_async_insert"] = 1
+		} else {
+			insertSettings["wait_for_async_insert"] = 0
+		}
 	}
 
 	return &Client{
 		db,
+		conn,
 		config.Logger,
+		config.Cluster,
+		config.QuerySettings,
+		insertSettings,
 		config.Debug,
 		config.dev,
 	}, nil
 }
 
+// Close closes the database connections.
+func (client *Client) Close() error {
+	if client.conn != nil {
+		if err := client.conn.Close(); err != nil {
+			return err
+		}
+	}
+
+	return client.DB.Close()
+}
+
+// QueryContext executes a query that returns rows using the QuerySettings of the Client and context.
+func (client *Client) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return client.DB.QueryContext(client.queryContext(ctx), query, args...)
+}
+
+// QueryRowContext executes a query that is expected to return at most one row using the QuerySettings of the Client and context.
+func (client *Client) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return client.DB.QueryRowContext(client.queryContext(ctx), query, args...)
+}
+
 // SavePageViews implements the Store interface.
 func (client *Client) SavePageViews(pageViews []model.PageView) error {
+	if client.conn != nil {
+		return client.savePageViewsBatch(pageViews)
+	}
+
 	values := make([]string, 0, len(pageViews))
 	args := make([]any, 0, len(pageViews)*29)
 
@@ -196,7 +279,7 @@ func (client *Client) SavePageViews(pageViews []model.PageView) error {
 			pageView.TagValues)
 	}
 
-	if _, err := client.Exec(fmt.Sprintf(`INSERT INTO "page_view" (client_id, visitor_id, session_id, time, duration_seconds,
+	if _, err := client.ExecContext(client.insertContext(), fmt.Sprintf(`INSERT INTO "page_view" (client_id, visitor_id, session_id, time, duration_seconds,
 		hostname, path, title, language, country_code, region, city, referrer, referrer_name, referrer_icon, os, os_version,
 		browser, browser_version, desktop, mobile, screen_class,
 		utm_source, utm_medium, utm_campaign, utm_content, utm_term, channel,
@@ -213,11 +296,15 @@ func (client *Client) SavePageViews(pageViews []model.PageView) error {
 
 // SaveSessions implements the Store interface.
 func (client *Client) SaveSessions(sessions []model.Session) error {
+	if client.conn != nil {
+		return client.saveSessionsBatch(sessions)
+	}
+
 	values := make([]string, 0, len(sessions))
 	args := make([]any, 0, len(sessions)*35)
 
 	for _, session := range sessions {
-		values = append(values, "(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
+		values = append(values, "(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
 		args = append(args,
 			session.Sign,
 			session.Version,
@@ -232,6 +319,7 @@ func (client *Client) SaveSessions(sessions []model.Session) error {
 			session.ExitPath,
 			session.PageViews,
 			client.boolean(session.IsBounce),
+			client.boolean(session.IsEngaged),
 			session.EntryTitle,
 			session.ExitTitle,
 			session.Language,
@@ -257,8 +345,8 @@ func (client *Client) SaveSessions(sessions []model.Session) error {
 			session.Extended)
 	}
 
-	if _, err := client.Exec(fmt.Sprintf(`INSERT INTO "session" (sign, version, client_id, visitor_id, session_id, time, start, duration_seconds,
-		hostname, entry_path, exit_path, page_views, is_bounce, entry_title, exit_title, language, country_code, region, city, referrer, referrer_name, referrer_icon, os, os_version,
+	if _, err := client.ExecContext(client.insertContext(), fmt.Sprintf(`INSERT INTO "session" (sign, version, client_id, visitor_id, session_id, time, start, duration_seconds,
+		hostname, entry_path, exit_path, page_views, is_bounce, is_engaged, entry_title, exit_title, language, country_code, region, city, referrer, referrer_name, referrer_icon, os, os_version,
 		browser, browser_version, desktop, mobile, screen_class,
 		utm_source, utm_medium, utm_campaign, utm_content, utm_term, channel, extended) VALUES %s`, strings.Join(values, ",")), args...); err != nil {
 		return err
@@ -273,6 +361,10 @@ func (client *Client) SaveSessions(sessions []model.Session) error {
 
 // SaveEvents implements the Store interface.
 func (client *Client) SaveEvents(events []model.Event) error {
+	if client.conn != nil {
+		return client.saveEventsBatch(events)
+	}
+
 	values := make([]string, 0, len(events))
 	args := make([]any, 0, len(events)*30)
 
@@ -312,7 +404,7 @@ func (client *Client) SaveEvents(events []model.Event) error {
 			event.Channel)
 	}
 
-	if _, err := client.Exec(fmt.Sprintf(`INSERT INTO "event" (client_id, visitor_id, time, session_id, event_name, event_meta_keys, event_meta_values, duration_seconds,
+	if _, err := client.ExecContext(client.insertContext(), fmt.Sprintf(`INSERT INTO "event" (client_id, visitor_id, time, session_id, event_name, event_meta_keys, event_meta_values, duration_seconds,
 		hostname, path, title, language, country_code, region, city, referrer, referrer_name, referrer_icon, os, os_version,
 		browser, browser_version, desktop, mobile, screen_class,
 		utm_source, utm_medium, utm_campaign, utm_content, utm_term, channel) VALUES %s`, strings.Join(values, ",")), args...); err != nil {
@@ -328,6 +420,10 @@ func (client *Client) SaveEvents(events []model.Event) error {
 
 // SaveRequests implements the Store interface.
 func (client *Client) SaveRequests(requests []model.Request) error {
+	if client.conn != nil {
+		return client.saveRequestsBatch(requests)
+	}
+
 	values := make([]string, 0, len(requests))
 	args := make([]any, 0, len(requests)*14)
 
@@ -350,7 +446,7 @@ func (client *Client) SaveRequests(requests []model.Request) error {
 			req.BotReason)
 	}
 
-	if _, err := client.Exec(fmt.Sprintf(`INSERT INTO "request" (client_id, visitor_id, time, ip, user_agent, hostname, path, event_name, referrer, utm_source, utm_medium, utm_campaign, bot, bot_reason) VALUES %s`, strings.Join(values, ",")), args...); err != nil {
+	if _, err := client.ExecContext(client.insertContext(), fmt.Sprintf(`INSERT INTO "request" (client_id, visitor_id, time, ip, user_agent, hostname, path, event_name, referrer, utm_source, utm_medium, utm_campaign, bot, bot_reason) VALUES %s`, strings.Join(values, ",")), args...); err != nil {
 		return err
 	}
 
@@ -361,6 +457,478 @@ func (client *Client) SaveRequests(requests []model.Request) error {
 	return nil
 }
 
+// SaveImportedBrowser implements the Store interface.
+func (client *Client) SaveImportedBrowser(rows []model.ImportedBrowser) error {
+	args := make([]any, 0, len(rows)*4)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.Browser, row.Visitors)
+	}
+
+	return client.saveImported("imported_browser", []string{"client_id", "date", "browser", "visitors"}, len(rows), args)
+}
+
+// SaveImportedBrowserVersion implements the Store interface.
+func (client *Client) SaveImportedBrowserVersion(rows []model.ImportedBrowserVersion) error {
+	args := make([]any, 0, len(rows)*5)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.Browser, row.BrowserVersion, row.Visitors)
+	}
+
+	return client.saveImported("imported_browser_version", []string{"client_id", "date", "browser", "browser_version", "visitors"}, len(rows), args)
+}
+
+// SaveImportedChannel implements the Store interface.
+func (client *Client) SaveImportedChannel(rows []model.ImportedChannel) error {
+	args := make([]any, 0, len(rows)*7)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.Channel, row.Visitors, row.Views, row.Sessions, row.Bounces)
+	}
+
+	return client.saveImported("imported_channel", []string{"client_id", "date", "channel", "visitors", "views", "sessions", "bounces"}, len(rows), args)
+}
+
+// SaveImportedCity implements the Store interface.
+func (client *Client) SaveImportedCity(rows []model.ImportedCity) error {
+	args := make([]any, 0, len(rows)*4)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.City, row.Visitors)
+	}
+
+	return client.saveImported("imported_city", []string{"client_id", "date", "city", "visitors"}, len(rows), args)
+}
+
+// SaveImportedCountry implements the Store interface.
+func (client *Client) SaveImportedCountry(rows []model.ImportedCountry) error {
+	args := make([]any, 0, len(rows)*4)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.CountryCode, row.Visitors)
+	}
+
+	return client.saveImported("imported_country", []string{"client_id", "date", "country_code", "visitors"}, len(rows), args)
+}
+
+// SaveImportedDevice implements the Store interface.
+func (client *Client) SaveImportedDevice(rows []model.ImportedDevice) error {
+	args := make([]any, 0, len(rows)*4)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.Category, row.Visitors)
+	}
+
+	return client.saveImported("imported_device", []string{"client_id", "date", "category", "visitors"}, len(rows), args)
+}
+
+// SaveImportedEntryPage implements the Store interface.
+func (client *Client) SaveImportedEntryPage(rows []model.ImportedEntryPage) error {
+	args := make([]any, 0, len(rows)*5)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.EntryPath, row.Visitors, row.Sessions)
+	}
+
+	return client.saveImported("imported_entry_page", []string{"client_id", "date", "entry_path", "visitors", "sessions"}, len(rows), args)
+}
+
+// SaveImportedEvent implements the Store interface.
+func (client *Client) SaveImportedEvent(rows []model.ImportedEvent) error {
+	args := make([]any, 0, len(rows)*6)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.EventName, row.Visitors, row.Views, row.Events)
+	}
+
+	return client.saveImported("imported_event", []string{"client_id", "date", "event_name", "visitors", "views", "events"}, len(rows), args)
+}
+
+// SaveImportedExitPage implements the Store interface.
+func (client *Client) SaveImportedExitPage(rows []model.ImportedExitPage) error {
+	args := make([]any, 0, len(rows)*5)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.ExitPath, row.Visitors, row.Sessions)
+	}
+
+	return client.saveImported("imported_exit_page", []string{"client_id", "date", "exit_path", "visitors", "sessions"}, len(rows), args)
+}
+
+// SaveImportedHostname implements the Store interface.
+func (client *Client) SaveImportedHostname(rows []model.ImportedHostname) error {
+	args := make([]any, 0, len(rows)*7)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.Hostname, row.Visitors, row.Views, row.Sessions, row.Bounces)
+	}
+
+	return client.saveImported("imported_hostname", []string{"client_id", "date", "hostname", "visitors", "views", "sessions", "bounces"}, len(rows), args)
+}
+
+// SaveImportedHour implements the Store interface.
+func (client *Client) SaveImportedHour(rows []model.ImportedHour) error {
+	args := make([]any, 0, len(rows)*7)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.Hour, row.Visitors, row.Views, row.Sessions, row.Bounces)
+	}
+
+	return client.saveImported("imported_hour", []string{"client_id", "date", "hour", "visitors", "views", "sessions", "bounces"}, len(rows), args)
+}
+
+// SaveImportedLanguage implements the Store interface.
+func (client *Client) SaveImportedLanguage(rows []model.ImportedLanguage) error {
+	args := make([]any, 0, len(rows)*4)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.Language, row.Visitors)
+	}
+
+	return client.saveImported("imported_language", []string{"client_id", "date", "language", "visitors"}, len(rows), args)
+}
+
+// SaveImportedOS implements the Store interface.
+func (client *Client) SaveImportedOS(rows []model.ImportedOS) error {
+	args := make([]any, 0, len(rows)*4)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.OS, row.Visitors)
+	}
+
+	return client.saveImported("imported_os", []string{"client_id", "date", "os", "visitors"}, len(rows), args)
+}
+
+// SaveImportedOSVersion implements the Store interface.
+func (client *Client) SaveImportedOSVersion(rows []model.ImportedOSVersion) error {
+	args := make([]any, 0, len(rows)*5)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.OS, row.OSVersion, row.Visitors)
+	}
+
+	return client.saveImported("imported_os_version", []string{"client_id", "date", "os", "os_version", "visitors"}, len(rows), args)
+}
+
+// SaveImportedPage implements the Store interface.
+func (client *Client) SaveImportedPage(rows []model.ImportedPage) error {
+	args := make([]any, 0, len(rows)*7)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.Path, row.Visitors, row.Views, row.Sessions, row.Bounces)
+	}
+
+	return client.saveImported("imported_page", []string{"client_id", "date", "path", "visitors", "views", "sessions", "bounces"}, len(rows), args)
+}
+
+// SaveImportedReferrer implements the Store interface.
+func (client *Client) SaveImportedReferrer(rows []model.ImportedReferrer) error {
+	args := make([]any, 0, len(rows)*6)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.Referrer, row.Visitors, row.Sessions, row.Bounces)
+	}
+
+	return client.saveImported("imported_referrer", []string{"client_id", "date", "referrer", "visitors", "sessions", "bounces"}, len(rows), args)
+}
+
+// SaveImportedRegion implements the Store interface.
+func (client *Client) SaveImportedRegion(rows []model.ImportedRegion) error {
+	args := make([]any, 0, len(rows)*4)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.Region, row.Visitors)
+	}
+
+	return client.saveImported("imported_region", []string{"client_id", "date", "region", "visitors"}, len(rows), args)
+}
+
+// SaveImportedTag implements the Store interface.
+func (client *Client) SaveImportedTag(rows []model.ImportedTag) error {
+	args := make([]any, 0, len(rows)*6)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.TagKey, row.TagValue, row.Visitors, row.Views)
+	}
+
+	return client.saveImported("imported_tag", []string{"client_id", "date", "tag_key", "tag_value", "visitors", "views"}, len(rows), args)
+}
+
+// SaveImportedUTMCampaign implements the Store interface.
+func (client *Client) SaveImportedUTMCampaign(rows []model.ImportedUTMCampaign) error {
+	args := make([]any, 0, len(rows)*4)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.UTMCampaign, row.Visitors)
+	}
+
+	return client.saveImported("imported_utm_campaign", []string{"client_id", "date", "utm_campaign", "visitors"}, len(rows), args)
+}
+
+// SaveImportedUTMContent implements the Store interface.
+func (client *Client) SaveImportedUTMContent(rows []model.ImportedUTMContent) error {
+	args := make([]any, 0, len(rows)*4)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.UTMContent, row.Visitors)
+	}
+
+	return client.saveImported("imported_utm_content", []string{"client_id", "date", "utm_content", "visitors"}, len(rows), args)
+}
+
+// SaveImportedUTMMedium implements the Store interface.
+func (client *Client) SaveImportedUTMMedium(rows []model.ImportedUTMMedium) error {
+	args := make([]any, 0, len(rows)*4)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.UTMMedium, row.Visitors)
+	}
+
+	return client.saveImported("imported_utm_medium", []string{"client_id", "date", "utm_medium", "visitors"}, len(rows), args)
+}
+
+// SaveImportedUTMSource implements the Store interface.
+func (client *Client) SaveImportedUTMSource(rows []model.ImportedUTMSource) error {
+	args := make([]any, 0, len(rows)*4)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.UTMSource, row.Visitors)
+	}
+
+	return client.saveImported("imported_utm_source", []string{"client_id", "date", "utm_source", "visitors"}, len(rows), args)
+}
+
+// SaveImportedUTMTerm implements the Store interface.
+func (client *Client) SaveImportedUTMTerm(rows []model.ImportedUTMTerm) error {
+	args := make([]any, 0, len(rows)*4)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.UTMTerm, row.Visitors)
+	}
+
+	return client.saveImported("imported_utm_term", []string{"client_id", "date", "utm_term", "visitors"}, len(rows), args)
+}
+
+// SaveImportedVisitors implements the Store interface.
+func (client *Client) SaveImportedVisitors(rows []model.ImportedVisitors) error {
+	args := make([]any, 0, len(rows)*7)
+
+	for _, row := range rows {
+		args = append(args, row.ClientID, row.Date.Format(time.DateOnly), row.Visitors, row.Views, row.Sessions, row.Bounces, row.SessionDuration)
+	}
+
+	return client.saveImported("imported_visitors", []string{"client_id", "date", "visitors", "views", "sessions", "bounces", "session_duration"}, len(rows), args)
+}
+
+// LatestImport implements the Store interface.
+func (client *Client) LatestImport(ctx context.Context, clientID uint64, from, to time.Time) (time.Time, error) {
+	queries := make([]string, 0, len(importedTables))
+	args := make([]any, 0, len(importedTables)*3)
+
+	for _, table := range importedTables {
+		queries = append(queries, fmt.Sprintf(`SELECT imported_at FROM "%s" WHERE client_id = ? AND date >= toDate(?) AND date <= toDate(?)`, table))
+		args = append(args, clientID, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	}
+
+	var latest null.Time
+
+	if err := client.QueryRowContext(ctx, fmt.Sprintf(`SELECT maxOrNull(imported_at) FROM (%s)`, strings.Join(queries, " UNION ALL ")), args...).Scan(&latest); err != nil {
+		return time.Time{}, queryError(err)
+	}
+
+	return latest.Time, nil
+}
+
+// DeleteImported implements the Store interface.
+func (client *Client) DeleteImported(ctx context.Context, clientID uint64, from, to, after, before time.Time) error {
+	onCluster := ""
+
+	if client.cluster != "" {
+		onCluster = fmt.Sprintf("ON CLUSTER '%s'", client.cluster)
+	}
+
+	var where strings.Builder
+	args := []any{clientID, from.Format(time.DateOnly), to.Format(time.DateOnly)}
+	where.WriteString("client_id = ? AND date >= toDate(?) AND date <= toDate(?) ")
+
+	if !after.IsZero() {
+		where.WriteString("AND imported_at > fromUnixTimestamp64Milli(?, 'UTC') ")
+		args = append(args, after.UnixMilli())
+	}
+
+	if !before.IsZero() {
+		where.WriteString("AND imported_at <= fromUnixTimestamp64Milli(?, 'UTC') ")
+		args = append(args, before.UnixMilli())
+	}
+
+	for _, table := range importedTables {
+		if _, err := client.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE "%s" %s DELETE WHERE %s SETTINGS mutations_sync = 2`, table, onCluster, where.String()), args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveGoals implements the Store interface.
+func (client *Client) SaveGoals(ctx context.Context, goals []model.Goal) error {
+	if len(goals) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(goals))
+	args := make([]any, 0, len(goals)*12)
+
+	for _, goal := range goals {
+		keys := make([]string, 0, len(goal.EventMeta))
+
+		for key := range goal.EventMeta {
+			keys = append(keys, key)
+		}
+
+		slices.Sort(keys)
+		metaValues := make([]string, 0, len(keys))
+
+		for _, key := range keys {
+			metaValues = append(metaValues, goal.EventMeta[key])
+		}
+
+		values = append(values, "(?,?,?,?,?,?,?,?,?,?,?,?)")
+		args = append(args,
+			goal.ClientID,
+			goal.ID,
+			goal.Name,
+			goal.PathPattern,
+			goal.EventName,
+			keys,
+			metaValues,
+			goal.MinDurationSeconds,
+			goal.MinPageViews,
+			goal.Value,
+			goal.ValueMetaKey,
+			goal.Version.UnixMilli())
+	}
+
+	if _, err := client.ExecContext(ctx, fmt.Sprintf(`INSERT INTO "goal" (client_id, id, name, path_pattern, event_name, event_meta_keys, event_meta_values,
+		min_duration_seconds, min_page_views, value, value_meta_key, version) VALUES %s`, strings.Join(values, ",")), args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteGoal implements the Store interface.
+func (client *Client) DeleteGoal(ctx context.Context, clientID, id uint64) error {
+	if _, err := client.ExecContext(ctx, `INSERT INTO "goal" (client_id, id, deleted, version) VALUES (?, ?, 1, ?)`,
+		clientID, id, time.Now().UnixMilli()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveSegments implements the Store interface.
+func (client *Client) SaveSegments(ctx context.Context, segments []model.Segment) error {
+	if len(segments) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(segments))
+	args := make([]any, 0, len(segments)*10)
+
+	for _, segment := range segments {
+		events := make([]string, 0, len(segment.Conditions))
+		paths := make([]string, 0, len(segment.Conditions))
+		not := make([]uint8, 0, len(segment.Conditions))
+		visitor := make([]uint8, 0, len(segment.Conditions))
+		minCounts := make([]uint32, 0, len(segment.Conditions))
+		maxCounts := make([]uint32, 0, len(segment.Conditions))
+
+		for _, condition := range segment.Conditions {
+			events = append(events, condition.Event)
+			paths = append(paths, condition.Path)
+			not = append(not, uint8(client.boolean(condition.Not)))
+			visitor = append(visitor, uint8(client.boolean(condition.Visitor)))
+			minCounts = append(minCounts, uint32(condition.MinCount))
+			maxCounts = append(maxCounts, uint32(condition.MaxCount))
+		}
+
+		values = append(values, "(?,?,?,?,?,?,?,?,?,?)")
+		args = append(args,
+			segment.ClientID,
+			strings.ToLower(segment.Name),
+			segment.Name,
+			events,
+			paths,
+			not,
+			visitor,
+			minCounts,
+			maxCounts,
+			segment.Version.UnixMilli())
+	}
+
+	if _, err := client.ExecContext(ctx, fmt.Sprintf(`INSERT INTO "segment" (client_id, key, name, condition_events, condition_paths,
+		condition_not, condition_visitor, condition_min_counts, condition_max_counts, version) VALUES %s`, strings.Join(values, ",")), args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSegment implements the Store interface.
+func (client *Client) DeleteSegment(ctx context.Context, clientID uint64, name string) error {
+	if _, err := client.ExecContext(ctx, `INSERT INTO "segment" (client_id, key, deleted, version) VALUES (?, ?, 1, ?)`,
+		clientID, strings.ToLower(name), time.Now().UnixMilli()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveContentGroups implements the Store interface.
+func (client *Client) SaveContentGroups(ctx context.Context, groups []model.ContentGroups) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(groups))
+	args := make([]any, 0, len(groups)*7)
+
+	for _, group := range groups {
+		names := make([]string, 0, len(group.Groups))
+		pathPrefixes := make([]string, 0, len(group.Groups))
+		pathPatterns := make([]string, 0, len(group.Groups))
+		tagKeys := make([]string, 0, len(group.Groups))
+		tagValues := make([]string, 0, len(group.Groups))
+
+		for _, g := range group.Groups {
+			names = append(names, g.Name)
+			pathPrefixes = append(pathPrefixes, g.PathPrefix)
+			pathPatterns = append(pathPatterns, g.PathPattern)
+			tagKeys = append(tagKeys, g.TagKey)
+			tagValues = append(tagValues, g.TagValue)
+		}
+
+		values = append(values, "(?,?,?,?,?,?,?)")
+		args = append(args,
+			group.ClientID,
+			names,
+			pathPrefixes,
+			pathPatterns,
+			tagKeys,
+			tagValues,
+			group.Version.UnixMilli())
+	}
+
+	if _, err := client.ExecContext(ctx, fmt.Sprintf(`INSERT INTO "content_group" (client_id, names, path_prefixes, path_patterns,
+		tag_keys, tag_values, version) VALUES %s`, strings.Join(values, ",")), args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // Session implements the Store interface.
 func (client *Client) Session(ctx context.Context, clientID, fingerprint uint64, maxAge time.Time) (*model.Session, error) {
 	query := `SELECT sign,
@@ -374,6 +942,7 @@ func (client *Client) Session(ctx context.Context, clientID, fingerprint uint64,
 		exit_path,
 		page_views,
 		is_bounce,
+		is_engaged,
 		entry_title,
 		exit_title,
 		language,
@@ -415,6 +984,7 @@ func (client *Client) Session(ctx context.Context, clientID, fingerprint uint64,
 		&session.ExitPath,
 		&session.PageViews,
 		&session.IsBounce,
+		&session.IsEngaged,
 		&session.EntryTitle,
 		&session.ExitTitle,
 		&session.Language,
@@ -447,7 +1017,7 @@ func (client *Client) Session(ctx context.Context, clientID, fingerprint uint64,
 				client.logger.Error("error reading session", "err", err)
 			}
 
-			return nil, err
+			return nil, queryError(err)
 		}
 	}
 
@@ -466,7 +1036,7 @@ func (client *Client) Count(ctx context.Context, query string, args ...any) (int
 				client.logger.Error("error counting results", "err", err)
 			}
 
-			return 0, err
+			return 0, queryError(err)
 		}
 	}
 
@@ -478,7 +1048,7 @@ func (client *Client) SelectActiveVisitorStats(ctx context.Context, includeTitle
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -491,7 +1061,7 @@ func (client *Client) SelectActiveVisitorStats(ctx context.Context, includeTitle
 			if err := rows.Scan(&result.Path,
 				&result.Title,
 				&result.Visitors); err != nil {
-				return nil, err
+				return nil, queryError(err)
 			}
 
 			results = append(results, result)
@@ -502,13 +1072,17 @@ func (client *Client) SelectActiveVisitorStats(ctx context.Context, includeTitle
 
 			if err := rows.Scan(&result.Path,
 				&result.Visitors); err != nil {
-				return nil, err
+				return nil, queryError(err)
 			}
 
 			results = append(results, result)
 		}
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -523,10 +1097,13 @@ func (client *Client) GetTotalVisitorStats(ctx context.Context, query string, in
 				&result.Views,
 				&result.Bounces,
 				&result.BounceRate,
+				&result.EngagedSessions,
+				&result.EngagementRate,
+				&result.PagesPerSession,
 				&result.CR,
 				&result.CustomMetricAvg,
 				&result.CustomMetricTotal); err != nil && !errors.Is(err, sql.ErrNoRows) {
-				return nil, err
+				return nil, queryError(err)
 			}
 		} else {
 			if err := client.QueryRowContext(ctx, query, args...).Scan(&result.Visitors,
@@ -534,8 +1111,11 @@ func (client *Client) GetTotalVisitorStats(ctx context.Context, query string, in
 				&result.Views,
 				&result.Bounces,
 				&result.BounceRate,
+				&result.EngagedSessions,
+				&result.EngagementRate,
+				&result.PagesPerSession,
 				&result.CR); err != nil && !errors.Is(err, sql.ErrNoRows) {
-				return nil, err
+				return nil, queryError(err)
 			}
 		}
 	} else {
@@ -545,17 +1125,23 @@ func (client *Client) GetTotalVisitorStats(ctx context.Context, query string, in
 				&result.Views,
 				&result.Bounces,
 				&result.BounceRate,
+				&result.EngagedSessions,
+				&result.EngagementRate,
+				&result.PagesPerSession,
 				&result.CustomMetricAvg,
 				&result.CustomMetricTotal); err != nil && !errors.Is(err, sql.ErrNoRows) {
-				return nil, err
+				return nil, queryError(err)
 			}
 		} else {
 			if err := client.QueryRowContext(ctx, query, args...).Scan(&result.Visitors,
 				&result.Sessions,
 				&result.Views,
 				&result.Bounces,
-				&result.BounceRate); err != nil && !errors.Is(err, sql.ErrNoRows) {
-				return nil, err
+				&result.BounceRate,
+				&result.EngagedSessions,
+				&result.EngagementRate,
+				&result.PagesPerSession); err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return nil, queryError(err)
 			}
 		}
 	}
@@ -568,7 +1154,7 @@ func (client *Client) GetTotalUniqueVisitorStats(ctx context.Context, query stri
 	var result int
 
 	if err := client.QueryRowContext(ctx, query, args...).Scan(&result); err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return 0, err
+		return 0, queryError(err)
 	}
 
 	return result, nil
@@ -579,7 +1165,7 @@ func (client *Client) GetTotalPageViewStats(ctx context.Context, query string, a
 	var result int
 
 	if err := client.QueryRowContext(ctx, query, args...).Scan(&result); err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return 0, err
+		return 0, queryError(err)
 	}
 
 	return result, nil
@@ -590,7 +1176,7 @@ func (client *Client) GetTotalSessionStats(ctx context.Context, query string, ar
 	var result int
 
 	if err := client.QueryRowContext(ctx, query, args...).Scan(&result); err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return 0, err
+		return 0, queryError(err)
 	}
 
 	return result, nil
@@ -601,7 +1187,7 @@ func (client *Client) GetTotalVisitorsPageViewsStats(ctx context.Context, query
 	result := new(model.TotalVisitorsPageViewsStats)
 
 	if err := client.QueryRowContext(ctx, query, args...).Scan(&result.Visitors, &result.Views); err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	return result, nil
@@ -612,229 +1198,96 @@ func (client *Client) SelectVisitorStats(ctx context.Context, period pkg.Period,
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
 	var results []model.VisitorStats
 
-	switch period {
-	case pkg.PeriodWeek:
-		for rows.Next() {
-			var result model.VisitorStats
-
-			if includeCustomMetric {
-				if includeCR {
-					if err := rows.Scan(&result.Week,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate,
-						&result.CR,
-						&result.CustomMetricAvg,
-						&result.CustomMetricTotal); err != nil {
-						return nil, err
-					}
-				} else {
-					if err := rows.Scan(&result.Week,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate,
-						&result.CustomMetricAvg,
-						&result.CustomMetricTotal); err != nil {
-						return nil, err
-					}
+	for rows.Next() {
+		var result model.VisitorStats
+		var date null.Time
+
+		if includeCustomMetric {
+			if includeCR {
+				if err := rows.Scan(&date,
+					&result.Visitors,
+					&result.Sessions,
+					&result.Views,
+					&result.Bounces,
+					&result.BounceRate,
+					&result.EngagedSessions,
+					&result.EngagementRate,
+					&result.PagesPerSession,
+					&result.CR,
+					&result.CustomMetricAvg,
+					&result.CustomMetricTotal); err != nil {
+					return nil, queryError(err)
 				}
 			} else {
-				if includeCR {
-					if err := rows.Scan(&result.Week,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate,
-						&result.CR); err != nil {
-						return nil, err
-					}
-				} else {
-					if err := rows.Scan(&result.Week,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate); err != nil {
-						return nil, err
-					}
+				if err := rows.Scan(&date,
+					&result.Visitors,
+					&result.Sessions,
+					&result.Views,
+					&result.Bounces,
+					&result.BounceRate,
+					&result.EngagedSessions,
+					&result.EngagementRate,
+					&result.PagesPerSession,
+					&result.CustomMetricAvg,
+					&result.CustomMetricTotal); err != nil {
+					return nil, queryError(err)
 				}
 			}
-
-			results = append(results, result)
-		}
-	case pkg.PeriodMonth:
-		for rows.Next() {
-			var result model.VisitorStats
-
-			if includeCustomMetric {
-				if includeCR {
-					if err := rows.Scan(&result.Month,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate,
-						&result.CR,
-						&result.CustomMetricAvg,
-						&result.CustomMetricTotal); err != nil {
-						return nil, err
-					}
-				} else {
-					if err := rows.Scan(&result.Month,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate,
-						&result.CustomMetricAvg,
-						&result.CustomMetricTotal); err != nil {
-						return nil, err
-					}
+		} else {
+			if includeCR {
+				if err := rows.Scan(&date,
+					&result.Visitors,
+					&result.Sessions,
+					&result.Views,
+					&result.Bounces,
+					&result.BounceRate,
+					&result.EngagedSessions,
+					&result.EngagementRate,
+					&result.PagesPerSession,
+					&result.CR); err != nil {
+					return nil, queryError(err)
 				}
 			} else {
-				if includeCR {
-					if err := rows.Scan(&result.Month,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate,
-						&result.CR); err != nil {
-						return nil, err
-					}
-				} else {
-					if err := rows.Scan(&result.Month,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate); err != nil {
-						return nil, err
-					}
+				if err := rows.Scan(&date,
+					&result.Visitors,
+					&result.Sessions,
+					&result.Views,
+					&result.Bounces,
+					&result.BounceRate,
+					&result.EngagedSessions,
+					&result.EngagementRate,
+					&result.PagesPerSession); err != nil {
+					return nil, queryError(err)
 				}
 			}
-
-			results = append(results, result)
 		}
-	case pkg.PeriodYear:
-		for rows.Next() {
-			var result model.VisitorStats
-
-			if includeCustomMetric {
-				if includeCR {
-					if err := rows.Scan(&result.Year,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate,
-						&result.CR,
-						&result.CustomMetricAvg,
-						&result.CustomMetricTotal); err != nil {
-						return nil, err
-					}
-				} else {
-					if err := rows.Scan(&result.Year,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate,
-						&result.CustomMetricAvg,
-						&result.CustomMetricTotal); err != nil {
-						return nil, err
-					}
-				}
-			} else {
-				if includeCR {
-					if err := rows.Scan(&result.Year,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate,
-						&result.CR); err != nil {
-						return nil, err
-					}
-				} else {
-					if err := rows.Scan(&result.Year,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate); err != nil {
-						return nil, err
-					}
-				}
-			}
 
-			results = append(results, result)
+		switch period {
+		case pkg.PeriodHour:
+			result.Hour = date
+		case pkg.PeriodWeek:
+			result.Week = date
+		case pkg.PeriodMonth:
+			result.Month = date
+		case pkg.PeriodQuarter:
+			result.Quarter = date
+		case pkg.PeriodYear:
+			result.Year = date
+		default:
+			result.Day = date
 		}
-	default:
-		for rows.Next() {
-			var result model.VisitorStats
-
-			if includeCustomMetric {
-				if includeCR {
-					if err := rows.Scan(&result.Day,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate,
-						&result.CR,
-						&result.CustomMetricAvg,
-						&result.CustomMetricTotal); err != nil {
-						return nil, err
-					}
-				} else {
-					if err := rows.Scan(&result.Day,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate,
-						&result.CustomMetricAvg,
-						&result.CustomMetricTotal); err != nil {
-						return nil, err
-					}
-				}
-			} else {
-				if includeCR {
-					if err := rows.Scan(&result.Day,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate,
-						&result.CR); err != nil {
-						return nil, err
-					}
-				} else {
-					if err := rows.Scan(&result.Day,
-						&result.Visitors,
-						&result.Sessions,
-						&result.Views,
-						&result.Bounces,
-						&result.BounceRate); err != nil {
-						return nil, err
-					}
-				}
-			}
 
-			results = append(results, result)
-		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
 	}
 
 	return results, nil
@@ -845,53 +1298,40 @@ func (client *Client) SelectTimeSpentStats(ctx context.Context, period pkg.Perio
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
 	var results []model.TimeSpentStats
 
-	switch period {
-	case pkg.PeriodWeek:
-		for rows.Next() {
-			var result model.TimeSpentStats
-
-			if err := rows.Scan(&result.AverageTimeSpentSeconds, &result.Week); err != nil {
-				return nil, err
-			}
-
-			results = append(results, result)
-		}
-	case pkg.PeriodMonth:
-		for rows.Next() {
-			var result model.TimeSpentStats
-
-			if err := rows.Scan(&result.AverageTimeSpentSeconds, &result.Month); err != nil {
-				return nil, err
-			}
+	for rows.Next() {
+		var result model.TimeSpentStats
+		var date null.Time
 
-			results = append(results, result)
+		if err := rows.Scan(&date, &result.AverageTimeSpentSeconds); err != nil {
+			return nil, queryError(err)
 		}
-	case pkg.PeriodYear:
-		for rows.Next() {
-			var result model.TimeSpentStats
 
-			if err := rows.Scan(&result.AverageTimeSpentSeconds, &result.Year); err != nil {
-				return nil, err
-			}
-
-			results = append(results, result)
+		switch period {
+		case pkg.PeriodHour:
+			result.Hour = date
+		case pkg.PeriodWeek:
+			result.Week = date
+		case pkg.PeriodMonth:
+			result.Month = date
+		case pkg.PeriodQuarter:
+			result.Quarter = date
+		case pkg.PeriodYear:
+			result.Year = date
+		default:
+			result.Day = date
 		}
-	default:
-		for rows.Next() {
-			var result model.TimeSpentStats
 
-			if err := rows.Scan(&result.Day, &result.AverageTimeSpentSeconds); err != nil {
-				return nil, err
-			}
+		results = append(results, result)
+	}
 
-			results = append(results, result)
-		}
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
 	}
 
 	return results, nil
@@ -911,7 +1351,7 @@ func (client *Client) GetGrowthStats(ctx context.Context, query string, includeC
 				&result.CR,
 				&result.CustomMetricAvg,
 				&result.CustomMetricTotal); err != nil && !errors.Is(err, sql.ErrNoRows) {
-				return nil, err
+				return nil, queryError(err)
 			}
 		} else {
 			if err := client.QueryRowContext(ctx, query, args...).Scan(&result.Visitors,
@@ -921,7 +1361,7 @@ func (client *Client) GetGrowthStats(ctx context.Context, query string, includeC
 				&result.BounceRate,
 				&result.CustomMetricAvg,
 				&result.CustomMetricTotal); err != nil && !errors.Is(err, sql.ErrNoRows) {
-				return nil, err
+				return nil, queryError(err)
 			}
 		}
 	} else {
@@ -932,7 +1372,7 @@ func (client *Client) GetGrowthStats(ctx context.Context, query string, includeC
 				&result.Bounces,
 				&result.BounceRate,
 				&result.CR); err != nil && !errors.Is(err, sql.ErrNoRows) {
-				return nil, err
+				return nil, queryError(err)
 			}
 		} else {
 			if err := client.QueryRowContext(ctx, query, args...).Scan(&result.Visitors,
@@ -940,7 +1380,7 @@ func (client *Client) GetGrowthStats(ctx context.Context, query string, includeC
 				&result.Views,
 				&result.Bounces,
 				&result.BounceRate); err != nil && !errors.Is(err, sql.ErrNoRows) {
-				return nil, err
+				return nil, queryError(err)
 			}
 		}
 	}
@@ -953,7 +1393,7 @@ func (client *Client) SelectVisitorHourStats(ctx context.Context, query string,
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -973,7 +1413,7 @@ func (client *Client) SelectVisitorHourStats(ctx context.Context, query string,
 					&result.CR,
 					&result.CustomMetricAvg,
 					&result.CustomMetricTotal); err != nil {
-					return nil, err
+					return nil, queryError(err)
 				}
 			} else {
 				if err := rows.Scan(&result.Hour,
@@ -984,7 +1424,7 @@ func (client *Client) SelectVisitorHourStats(ctx context.Context, query string,
 					&result.BounceRate,
 					&result.CustomMetricAvg,
 					&result.CustomMetricTotal); err != nil {
-					return nil, err
+					return nil, queryError(err)
 				}
 			}
 		} else {
@@ -996,7 +1436,7 @@ func (client *Client) SelectVisitorHourStats(ctx context.Context, query string,
 					&result.Bounces,
 					&result.BounceRate,
 					&result.CR); err != nil {
-					return nil, err
+					return nil, queryError(err)
 				}
 			} else {
 				if err := rows.Scan(&result.Hour,
@@ -1005,7 +1445,7 @@ func (client *Client) SelectVisitorHourStats(ctx context.Context, query string,
 					&result.Views,
 					&result.Bounces,
 					&result.BounceRate); err != nil {
-					return nil, err
+					return nil, queryError(err)
 				}
 			}
 		}
@@ -1013,6 +1453,10 @@ func (client *Client) SelectVisitorHourStats(ctx context.Context, query string,
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1021,7 +1465,7 @@ func (client *Client) SelectVisitorWeekdayHourStats(ctx context.Context, query s
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1036,12 +1480,16 @@ func (client *Client) SelectVisitorWeekdayHourStats(ctx context.Context, query s
 			&result.Sessions,
 			&result.Views,
 			&result.Bounces); err != nil {
-			return nil, err
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1050,7 +1498,7 @@ func (client *Client) SelectVisitorMinuteStats(ctx context.Context, query string
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1070,7 +1518,7 @@ func (client *Client) SelectVisitorMinuteStats(ctx context.Context, query string
 					&result.CR,
 					&result.CustomMetricAvg,
 					&result.CustomMetricTotal); err != nil {
-					return nil, err
+					return nil, queryError(err)
 				}
 			} else {
 				if err := rows.Scan(&result.Minute,
@@ -1081,7 +1529,7 @@ func (client *Client) SelectVisitorMinuteStats(ctx context.Context, query string
 					&result.BounceRate,
 					&result.CustomMetricAvg,
 					&result.CustomMetricTotal); err != nil {
-					return nil, err
+					return nil, queryError(err)
 				}
 			}
 		} else {
@@ -1093,7 +1541,7 @@ func (client *Client) SelectVisitorMinuteStats(ctx context.Context, query string
 					&result.Bounces,
 					&result.BounceRate,
 					&result.CR); err != nil {
-					return nil, err
+					return nil, queryError(err)
 				}
 			} else {
 				if err := rows.Scan(&result.Minute,
@@ -1102,7 +1550,7 @@ func (client *Client) SelectVisitorMinuteStats(ctx context.Context, query string
 					&result.Views,
 					&result.Bounces,
 					&result.BounceRate); err != nil {
-					return nil, err
+					return nil, queryError(err)
 				}
 			}
 		}
@@ -1110,6 +1558,10 @@ func (client *Client) SelectVisitorMinuteStats(ctx context.Context, query string
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1118,7 +1570,7 @@ func (client *Client) SelectHostnameStats(ctx context.Context, query string, arg
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1134,13 +1586,20 @@ func (client *Client) SelectHostnameStats(ctx context.Context, query string, arg
 			&result.Bounces,
 			&result.RelativeVisitors,
 			&result.RelativeViews,
-			&result.BounceRate); err != nil {
-			return nil, err
+			&result.BounceRate,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1149,7 +1608,7 @@ func (client *Client) SelectPageStats(ctx context.Context, includeTitle, include
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1168,9 +1627,12 @@ func (client *Client) SelectPageStats(ctx context.Context, includeTitle, include
 					&result.RelativeViews,
 					&result.Bounces,
 					&result.BounceRate,
+					&result.EngagedSessions,
+					&result.EngagementRate,
+					&result.PagesPerSession,
 					&result.Title,
 					&result.AverageTimeSpentSeconds); err != nil {
-					return nil, err
+					return nil, queryError(err)
 				}
 
 				results = append(results, result)
@@ -1187,8 +1649,11 @@ func (client *Client) SelectPageStats(ctx context.Context, includeTitle, include
 					&result.RelativeViews,
 					&result.Bounces,
 					&result.BounceRate,
+					&result.EngagedSessions,
+					&result.EngagementRate,
+					&result.PagesPerSession,
 					&result.Title); err != nil {
-					return nil, err
+					return nil, queryError(err)
 				}
 
 				results = append(results, result)
@@ -1207,8 +1672,11 @@ func (client *Client) SelectPageStats(ctx context.Context, includeTitle, include
 					&result.RelativeViews,
 					&result.Bounces,
 					&result.BounceRate,
+					&result.EngagedSessions,
+					&result.EngagementRate,
+					&result.PagesPerSession,
 					&result.AverageTimeSpentSeconds); err != nil {
-					return nil, err
+					return nil, queryError(err)
 				}
 
 				results = append(results, result)
@@ -1224,8 +1692,11 @@ func (client *Client) SelectPageStats(ctx context.Context, includeTitle, include
 					&result.Views,
 					&result.RelativeViews,
 					&result.Bounces,
-					&result.BounceRate); err != nil {
-					return nil, err
+					&result.BounceRate,
+					&result.EngagedSessions,
+					&result.EngagementRate,
+					&result.PagesPerSession); err != nil {
+					return nil, queryError(err)
 				}
 
 				results = append(results, result)
@@ -1233,6 +1704,44 @@ func (client *Client) SelectPageStats(ctx context.Context, includeTitle, include
 		}
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
+}
+
+// SelectPathGroupStats implements the Store interface.
+func (client *Client) SelectPathGroupStats(ctx context.Context, query string, args ...any) ([]model.PathGroupStats, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	defer client.closeRows(rows)
+	var results []model.PathGroupStats
+
+	for rows.Next() {
+		var result model.PathGroupStats
+
+		if err := rows.Scan(&result.Name,
+			&result.Visitors,
+			&result.Views,
+			&result.Sessions,
+			&result.Bounces,
+			&result.RelativeVisitors,
+			&result.BounceRate); err != nil {
+			return nil, queryError(err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1241,7 +1750,7 @@ func (client *Client) SelectAvgTimeSpentStats(ctx context.Context, query string,
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1251,12 +1760,16 @@ func (client *Client) SelectAvgTimeSpentStats(ctx context.Context, query string,
 		var result model.AvgTimeSpentStats
 
 		if err := rows.Scan(&result.Path, &result.AverageTimeSpentSeconds); err != nil {
-			return nil, err
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1265,7 +1778,7 @@ func (client *Client) SelectEntryStats(ctx context.Context, includeTitle bool, q
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1274,12 +1787,18 @@ func (client *Client) SelectEntryStats(ctx context.Context, includeTitle bool, q
 	if includeTitle {
 		for rows.Next() {
 			var result model.EntryStats
+			var sessions, views int // only used to calculate the engagement
 
 			if err := rows.Scan(&result.Path,
 				&result.Entries,
 				&result.EntryRate,
+				&sessions,
+				&views,
+				&result.EngagedSessions,
+				&result.EngagementRate,
+				&result.PagesPerSession,
 				&result.Title); err != nil {
-				return nil, err
+				return nil, queryError(err)
 			}
 
 			results = append(results, result)
@@ -1287,17 +1806,27 @@ func (client *Client) SelectEntryStats(ctx context.Context, includeTitle bool, q
 	} else {
 		for rows.Next() {
 			var result model.EntryStats
+			var sessions, views int // only used to calculate the engagement
 
 			if err := rows.Scan(&result.Path,
 				&result.Entries,
-				&result.EntryRate); err != nil {
-				return nil, err
+				&result.EntryRate,
+				&sessions,
+				&views,
+				&result.EngagedSessions,
+				&result.EngagementRate,
+				&result.PagesPerSession); err != nil {
+				return nil, queryError(err)
 			}
 
 			results = append(results, result)
 		}
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1306,7 +1835,7 @@ func (client *Client) SelectExitStats(ctx context.Context, includeTitle bool, qu
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1315,12 +1844,18 @@ func (client *Client) SelectExitStats(ctx context.Context, includeTitle bool, qu
 	if includeTitle {
 		for rows.Next() {
 			var result model.ExitStats
+			var sessions, views int // only used to calculate the engagement
 
 			if err := rows.Scan(&result.Path,
 				&result.Exits,
 				&result.ExitRate,
+				&sessions,
+				&views,
+				&result.EngagedSessions,
+				&result.EngagementRate,
+				&result.PagesPerSession,
 				&result.Title); err != nil {
-				return nil, err
+				return nil, queryError(err)
 			}
 
 			results = append(results, result)
@@ -1328,17 +1863,27 @@ func (client *Client) SelectExitStats(ctx context.Context, includeTitle bool, qu
 	} else {
 		for rows.Next() {
 			var result model.ExitStats
+			var sessions, views int // only used to calculate the engagement
 
 			if err := rows.Scan(&result.Path,
 				&result.Exits,
-				&result.ExitRate); err != nil {
-				return nil, err
+				&result.ExitRate,
+				&sessions,
+				&views,
+				&result.EngagedSessions,
+				&result.EngagementRate,
+				&result.PagesPerSession); err != nil {
+				return nil, queryError(err)
 			}
 
 			results = append(results, result)
 		}
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1347,7 +1892,7 @@ func (client *Client) SelectTotalSessions(ctx context.Context, query string, arg
 	var result int
 
 	if err := client.QueryRowContext(ctx, query, args...).Scan(&result); err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return 0, err
+		return 0, queryError(err)
 	}
 
 	return result, nil
@@ -1358,7 +1903,7 @@ func (client *Client) SelectTotalVisitorSessionStats(ctx context.Context, query
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1368,12 +1913,16 @@ func (client *Client) SelectTotalVisitorSessionStats(ctx context.Context, query
 		var result model.TotalVisitorSessionStats
 
 		if err := rows.Scan(&result.Path, &result.Visitors, &result.Sessions, &result.Views); err != nil {
-			return nil, err
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1387,13 +1936,13 @@ func (client *Client) GetConversionsStats(ctx context.Context, query string, inc
 			&result.CR,
 			&result.CustomMetricAvg,
 			&result.CustomMetricTotal); err != nil && !errors.Is(err, sql.ErrNoRows) {
-			return nil, err
+			return nil, queryError(err)
 		}
 	} else {
 		if err := client.QueryRowContext(ctx, query, args...).Scan(&result.Visitors,
 			&result.Views,
 			&result.CR); err != nil && !errors.Is(err, sql.ErrNoRows) {
-			return nil, err
+			return nil, queryError(err)
 		}
 	}
 
@@ -1405,7 +1954,7 @@ func (client *Client) SelectEventStats(ctx context.Context, breakdown bool, quer
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1422,7 +1971,7 @@ func (client *Client) SelectEventStats(ctx context.Context, breakdown bool, quer
 				&result.CR,
 				&result.AverageDurationSeconds,
 				&result.MetaValue); err != nil {
-				return nil, err
+				return nil, queryError(err)
 			}
 
 			results = append(results, result)
@@ -1438,13 +1987,17 @@ func (client *Client) SelectEventStats(ctx context.Context, breakdown bool, quer
 				&result.CR,
 				&result.AverageDurationSeconds,
 				&result.MetaKeys); err != nil {
-				return nil, err
+				return nil, queryError(err)
 			}
 
 			results = append(results, result)
 		}
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1453,7 +2006,7 @@ func (client *Client) SelectEventListStats(ctx context.Context, query string, ar
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1463,12 +2016,16 @@ func (client *Client) SelectEventListStats(ctx context.Context, query string, ar
 		var result model.EventListStats
 
 		if err := rows.Scan(&result.Name, &result.Meta, &result.Visitors, &result.Count); err != nil {
-			return nil, err
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1477,7 +2034,7 @@ func (client *Client) SelectReferrerStats(ctx context.Context, query string, arg
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1490,16 +2047,24 @@ func (client *Client) SelectReferrerStats(ctx context.Context, query string, arg
 			&result.ReferrerIcon,
 			&result.Visitors,
 			&result.Sessions,
+			&result.Views,
 			&result.RelativeVisitors,
 			&result.Bounces,
 			&result.BounceRate,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession,
 			&result.Referrer); err != nil {
-			return nil, err
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1513,7 +2078,7 @@ func (client *Client) GetPlatformStats(ctx context.Context, query string, args .
 		&result.RelativePlatformDesktop,
 		&result.RelativePlatformMobile,
 		&result.RelativePlatformUnknown); err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	return result, nil
@@ -1524,7 +2089,7 @@ func (client *Client) SelectLanguageStats(ctx context.Context, query string, arg
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1533,13 +2098,24 @@ func (client *Client) SelectLanguageStats(ctx context.Context, query string, arg
 	for rows.Next() {
 		var result model.LanguageStats
 
-		if err := rows.Scan(&result.Language, &result.Visitors, &result.RelativeVisitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.Language,
+			&result.Visitors,
+			&result.RelativeVisitors,
+			&result.Sessions,
+			&result.Views,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1548,7 +2124,7 @@ func (client *Client) SelectCountryStats(ctx context.Context, query string, args
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1557,13 +2133,24 @@ func (client *Client) SelectCountryStats(ctx context.Context, query string, args
 	for rows.Next() {
 		var result model.CountryStats
 
-		if err := rows.Scan(&result.CountryCode, &result.Visitors, &result.RelativeVisitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.CountryCode,
+			&result.Visitors,
+			&result.RelativeVisitors,
+			&result.Sessions,
+			&result.Views,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1572,7 +2159,7 @@ func (client *Client) SelectRegionStats(ctx context.Context, query string, args
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1581,13 +2168,25 @@ func (client *Client) SelectRegionStats(ctx context.Context, query string, args
 	for rows.Next() {
 		var result model.RegionStats
 
-		if err := rows.Scan(&result.Region, &result.CountryCode, &result.Visitors, &result.RelativeVisitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.Region,
+			&result.CountryCode,
+			&result.Visitors,
+			&result.RelativeVisitors,
+			&result.Sessions,
+			&result.Views,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1596,7 +2195,7 @@ func (client *Client) SelectCityStats(ctx context.Context, query string, args ..
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1605,13 +2204,26 @@ func (client *Client) SelectCityStats(ctx context.Context, query string, args ..
 	for rows.Next() {
 		var result model.CityStats
 
-		if err := rows.Scan(&result.City, &result.Region, &result.CountryCode, &result.Visitors, &result.RelativeVisitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.City,
+			&result.Region,
+			&result.CountryCode,
+			&result.Visitors,
+			&result.RelativeVisitors,
+			&result.Sessions,
+			&result.Views,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1620,7 +2232,7 @@ func (client *Client) SelectBrowserStats(ctx context.Context, query string, args
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1629,13 +2241,24 @@ func (client *Client) SelectBrowserStats(ctx context.Context, query string, args
 	for rows.Next() {
 		var result model.BrowserStats
 
-		if err := rows.Scan(&result.Browser, &result.Visitors, &result.RelativeVisitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.Browser,
+			&result.Visitors,
+			&result.RelativeVisitors,
+			&result.Sessions,
+			&result.Views,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1644,7 +2267,7 @@ func (client *Client) SelectOSStats(ctx context.Context, query string, args ...a
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1653,13 +2276,24 @@ func (client *Client) SelectOSStats(ctx context.Context, query string, args ...a
 	for rows.Next() {
 		var result model.OSStats
 
-		if err := rows.Scan(&result.OS, &result.Visitors, &result.RelativeVisitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.OS,
+			&result.Visitors,
+			&result.RelativeVisitors,
+			&result.Sessions,
+			&result.Views,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1668,7 +2302,7 @@ func (client *Client) SelectScreenClassStats(ctx context.Context, query string,
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1677,13 +2311,24 @@ func (client *Client) SelectScreenClassStats(ctx context.Context, query string,
 	for rows.Next() {
 		var result model.ScreenClassStats
 
-		if err := rows.Scan(&result.ScreenClass, &result.Visitors, &result.RelativeVisitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.ScreenClass,
+			&result.Visitors,
+			&result.RelativeVisitors,
+			&result.Sessions,
+			&result.Views,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1692,7 +2337,7 @@ func (client *Client) SelectUTMSourceStats(ctx context.Context, query string, ar
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1701,13 +2346,24 @@ func (client *Client) SelectUTMSourceStats(ctx context.Context, query string, ar
 	for rows.Next() {
 		var result model.UTMSourceStats
 
-		if err := rows.Scan(&result.UTMSource, &result.Visitors, &result.RelativeVisitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.UTMSource,
+			&result.Visitors,
+			&result.RelativeVisitors,
+			&result.Sessions,
+			&result.Views,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1716,7 +2372,7 @@ func (client *Client) SelectUTMMediumStats(ctx context.Context, query string, ar
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1725,13 +2381,24 @@ func (client *Client) SelectUTMMediumStats(ctx context.Context, query string, ar
 	for rows.Next() {
 		var result model.UTMMediumStats
 
-		if err := rows.Scan(&result.UTMMedium, &result.Visitors, &result.RelativeVisitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.UTMMedium,
+			&result.Visitors,
+			&result.RelativeVisitors,
+			&result.Sessions,
+			&result.Views,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1740,7 +2407,7 @@ func (client *Client) SelectUTMCampaignStats(ctx context.Context, query string,
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1749,13 +2416,24 @@ func (client *Client) SelectUTMCampaignStats(ctx context.Context, query string,
 	for rows.Next() {
 		var result model.UTMCampaignStats
 
-		if err := rows.Scan(&result.UTMCampaign, &result.Visitors, &result.RelativeVisitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.UTMCampaign,
+			&result.Visitors,
+			&result.RelativeVisitors,
+			&result.Sessions,
+			&result.Views,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1764,7 +2442,7 @@ func (client *Client) SelectUTMContentStats(ctx context.Context, query string, a
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1773,13 +2451,24 @@ func (client *Client) SelectUTMContentStats(ctx context.Context, query string, a
 	for rows.Next() {
 		var result model.UTMContentStats
 
-		if err := rows.Scan(&result.UTMContent, &result.Visitors, &result.RelativeVisitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.UTMContent,
+			&result.Visitors,
+			&result.RelativeVisitors,
+			&result.Sessions,
+			&result.Views,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1788,7 +2477,7 @@ func (client *Client) SelectUTMTermStats(ctx context.Context, query string, args
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1797,13 +2486,24 @@ func (client *Client) SelectUTMTermStats(ctx context.Context, query string, args
 	for rows.Next() {
 		var result model.UTMTermStats
 
-		if err := rows.Scan(&result.UTMTerm, &result.Visitors, &result.RelativeVisitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.UTMTerm,
+			&result.Visitors,
+			&result.RelativeVisitors,
+			&result.Sessions,
+			&result.Views,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1812,7 +2512,7 @@ func (client *Client) SelectChannelStats(ctx context.Context, query string, args
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1828,13 +2528,20 @@ func (client *Client) SelectChannelStats(ctx context.Context, query string, args
 			&result.Bounces,
 			&result.RelativeVisitors,
 			&result.RelativeViews,
-			&result.BounceRate); err != nil {
-			return nil, err
+			&result.BounceRate,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1843,7 +2550,7 @@ func (client *Client) SelectOSVersionStats(ctx context.Context, query string, ar
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1852,13 +2559,25 @@ func (client *Client) SelectOSVersionStats(ctx context.Context, query string, ar
 	for rows.Next() {
 		var result model.OSVersionStats
 
-		if err := rows.Scan(&result.OS, &result.OSVersion, &result.Visitors, &result.RelativeVisitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.OS,
+			&result.OSVersion,
+			&result.Visitors,
+			&result.RelativeVisitors,
+			&result.Sessions,
+			&result.Views,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1867,7 +2586,7 @@ func (client *Client) SelectBrowserVersionStats(ctx context.Context, query strin
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1876,13 +2595,25 @@ func (client *Client) SelectBrowserVersionStats(ctx context.Context, query strin
 	for rows.Next() {
 		var result model.BrowserVersionStats
 
-		if err := rows.Scan(&result.Browser, &result.BrowserVersion, &result.Visitors, &result.RelativeVisitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.Browser,
+			&result.BrowserVersion,
+			&result.Visitors,
+			&result.RelativeVisitors,
+			&result.Sessions,
+			&result.Views,
+			&result.EngagedSessions,
+			&result.EngagementRate,
+			&result.PagesPerSession); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1891,7 +2622,7 @@ func (client *Client) SelectOptions(ctx context.Context, query string, args ...a
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1901,12 +2632,16 @@ func (client *Client) SelectOptions(ctx context.Context, query string, args ...a
 		var result string
 
 		if err := rows.Scan(&result); err != nil {
-			return nil, err
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1915,7 +2650,7 @@ func (client *Client) SelectTagStats(ctx context.Context, breakdown bool, query
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1930,7 +2665,7 @@ func (client *Client) SelectTagStats(ctx context.Context, breakdown bool, query
 				&result.Views,
 				&result.RelativeVisitors,
 				&result.RelativeViews); err != nil {
-				return nil, err
+				return nil, queryError(err)
 			}
 		} else {
 			if err := rows.Scan(&result.Key,
@@ -1938,13 +2673,17 @@ func (client *Client) SelectTagStats(ctx context.Context, breakdown bool, query
 				&result.Views,
 				&result.RelativeVisitors,
 				&result.RelativeViews); err != nil {
-				return nil, err
+				return nil, queryError(err)
 			}
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -1953,7 +2692,7 @@ func (client *Client) SelectSessions(ctx context.Context, query string, args ...
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -1971,6 +2710,7 @@ func (client *Client) SelectSessions(ctx context.Context, query string, args ...
 			&result.ExitPath,
 			&result.PageViews,
 			&result.IsBounce,
+			&result.IsEngaged,
 			&result.EntryTitle,
 			&result.ExitTitle,
 			&result.Language,
@@ -1993,12 +2733,16 @@ func (client *Client) SelectSessions(ctx context.Context, query string, args ...
 			&result.UTMContent,
 			&result.UTMTerm,
 			&result.Extended); err != nil {
-			return nil, err
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -2007,7 +2751,7 @@ func (client *Client) SelectPageViews(ctx context.Context, query string, args ..
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -2043,12 +2787,16 @@ func (client *Client) SelectPageViews(ctx context.Context, query string, args ..
 			&result.UTMTerm,
 			&result.TagKeys,
 			&result.TagValues); err != nil {
-			return nil, err
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
@@ -2057,7 +2805,7 @@ func (client *Client) SelectEvents(ctx context.Context, query string, args ...an
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
@@ -2094,46 +2842,735 @@ func (client *Client) SelectEvents(ctx context.Context, query string, args ...an
 			&result.UTMCampaign,
 			&result.UTMContent,
 			&result.UTMTerm); err != nil {
-			return nil, err
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
-// SelectFunnelSteps implements the Store interface.
-func (client *Client) SelectFunnelSteps(ctx context.Context, query string, args ...any) ([]model.FunnelStep, error) {
+// ExportPageViews implements the Store interface.
+func (client *Client) ExportPageViews(ctx context.Context, query string, args ...any) ([]model.PageView, error) {
 	rows, err := client.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, queryError(err)
 	}
 
 	defer client.closeRows(rows)
-	var results []model.FunnelStep
+	var results []model.PageView
 
 	for rows.Next() {
-		var result model.FunnelStep
+		var result model.PageView
 
-		if err := rows.Scan(&result.Step,
-			&result.Visitors); err != nil {
-			return nil, err
+		if err := rows.Scan(&result.ClientID,
+			&result.VisitorID,
+			&result.SessionID,
+			&result.Time,
+			&result.DurationSeconds,
+			&result.Hostname,
+			&result.Path,
+			&result.Title,
+			&result.Language,
+			&result.CountryCode,
+			&result.Region,
+			&result.City,
+			&result.Referrer,
+			&result.ReferrerName,
+			&result.ReferrerIcon,
+			&result.OS,
+			&result.OSVersion,
+			&result.Browser,
+			&result.BrowserVersion,
+			&result.Desktop,
+			&result.Mobile,
+			&result.ScreenClass,
+			&result.UTMSource,
+			&result.UTMMedium,
+			&result.UTMCampaign,
+			&result.UTMContent,
+			&result.UTMTerm,
+			&result.Channel,
+			&result.TagKeys,
+			&result.TagValues); err != nil {
+			return nil, queryError(err)
 		}
 
 		results = append(results, result)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
 	return results, nil
 }
 
-func (client *Client) boolean(b bool) int8 {
-	if b {
-		return 1
+// ExportSessions implements the Store interface.
+func (client *Client) ExportSessions(ctx context.Context, query string, args ...any) ([]model.Session, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
 	}
 
-	return 0
+	defer client.closeRows(rows)
+	var results []model.Session
+
+	for rows.Next() {
+		var result model.Session
+
+		if err := rows.Scan(&result.Sign,
+			&result.Version,
+			&result.ClientID,
+			&result.VisitorID,
+			&result.SessionID,
+			&result.Time,
+			&result.Start,
+			&result.DurationSeconds,
+			&result.Hostname,
+			&result.EntryPath,
+			&result.ExitPath,
+			&result.PageViews,
+			&result.IsBounce,
+			&result.IsEngaged,
+			&result.EntryTitle,
+			&result.ExitTitle,
+			&result.Language,
+			&result.CountryCode,
+			&result.Region,
+			&result.City,
+			&result.Referrer,
+			&result.ReferrerName,
+			&result.ReferrerIcon,
+			&result.OS,
+			&result.OSVersion,
+			&result.Browser,
+			&result.BrowserVersion,
+			&result.Desktop,
+			&result.Mobile,
+			&result.ScreenClass,
+			&result.UTMSource,
+			&result.UTMMedium,
+			&result.UTMCampaign,
+			&result.UTMContent,
+			&result.UTMTerm,
+			&result.Channel,
+			&result.Extended); err != nil {
+			return nil, queryError(err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
+}
+
+// ExportEvents implements the Store interface.
+func (client *Client) ExportEvents(ctx context.Context, query string, args ...any) ([]model.Event, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	defer client.closeRows(rows)
+	var results []model.Event
+
+	for rows.Next() {
+		var result model.Event
+
+		if err := rows.Scan(&result.ClientID,
+			&result.VisitorID,
+			&result.Time,
+			&result.SessionID,
+			&result.Name,
+			&result.MetaKeys,
+			&result.MetaValues,
+			&result.DurationSeconds,
+			&result.Hostname,
+			&result.Path,
+			&result.Title,
+			&result.Language,
+			&result.CountryCode,
+			&result.Region,
+			&result.City,
+			&result.Referrer,
+			&result.ReferrerName,
+			&result.ReferrerIcon,
+			&result.OS,
+			&result.OSVersion,
+			&result.Browser,
+			&result.BrowserVersion,
+			&result.Desktop,
+			&result.Mobile,
+			&result.ScreenClass,
+			&result.UTMSource,
+			&result.UTMMedium,
+			&result.UTMCampaign,
+			&result.UTMContent,
+			&result.UTMTerm,
+			&result.Channel); err != nil {
+			return nil, queryError(err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
+}
+
+// SelectFunnelSteps implements the Store interface.
+func (client *Client) SelectFunnelSteps(ctx context.Context, query string, args ...any) ([]model.FunnelStep, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	defer client.closeRows(rows)
+	var results []model.FunnelStep
+
+	for rows.Next() {
+		var result model.FunnelStep
+
+		if err := rows.Scan(&result.Step,
+			&result.Visitors,
+			&result.MedianTimeToConvertSeconds); err != nil {
+			return nil, queryError(err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
+}
+
+// SelectFunnelBreakdownSteps implements the Store interface.
+func (client *Client) SelectFunnelBreakdownSteps(ctx context.Context, query string, args ...any) ([]model.FunnelBreakdownStep, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	defer client.closeRows(rows)
+	var results []model.FunnelBreakdownStep
+
+	for rows.Next() {
+		var result model.FunnelBreakdownStep
+
+		if err := rows.Scan(&result.Step,
+			&result.Value,
+			&result.Other,
+			&result.Visitors); err != nil {
+			return nil, queryError(err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
+}
+
+// SelectPageTransitionStats implements the Store interface.
+func (client *Client) SelectPageTransitionStats(ctx context.Context, query string, args ...any) ([]model.PageTransitionStats, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	defer client.closeRows(rows)
+	var results []model.PageTransitionStats
+
+	for rows.Next() {
+		var result model.PageTransitionStats
+
+		if err := rows.Scan(&result.Path,
+			&result.Visitors,
+			&result.Transitions,
+			&result.RelativeTransitions); err != nil {
+			return nil, queryError(err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
+}
+
+// SelectPageFlowSteps implements the Store interface.
+func (client *Client) SelectPageFlowSteps(ctx context.Context, query string, args ...any) ([]model.PageFlowStep, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	defer client.closeRows(rows)
+	var results []model.PageFlowStep
+
+	for rows.Next() {
+		var result model.PageFlowStep
+
+		if err := rows.Scan(&result.Step,
+			&result.Path,
+			&result.NextPath,
+			&result.Visitors,
+			&result.PathVisitors); err != nil {
+			return nil, queryError(err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
+}
+
+// SelectGoals implements the Store interface.
+func (client *Client) SelectGoals(ctx context.Context, query string, args ...any) ([]model.Goal, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	defer client.closeRows(rows)
+	var results []model.Goal
+
+	for rows.Next() {
+		var result model.Goal
+		var metaKeys, metaValues []string
+
+		if err := rows.Scan(&result.ClientID,
+			&result.ID,
+			&result.Name,
+			&result.PathPattern,
+			&result.EventName,
+			&metaKeys,
+			&metaValues,
+			&result.MinDurationSeconds,
+			&result.MinPageViews,
+			&result.Value,
+			&result.ValueMetaKey,
+			&result.Version); err != nil {
+			return nil, queryError(err)
+		}
+
+		if len(metaKeys) > 0 {
+			result.EventMeta = make(map[string]string, len(metaKeys))
+
+			for i, key := range metaKeys {
+				if i < len(metaValues) {
+					result.EventMeta[key] = metaValues[i]
+				}
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
+}
+
+// SelectGoalStats implements the Store interface.
+func (client *Client) SelectGoalStats(ctx context.Context, query string, args ...any) ([]model.GoalStats, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	defer client.closeRows(rows)
+	var results []model.GoalStats
+
+	for rows.Next() {
+		var result model.GoalStats
+
+		if err := rows.Scan(&result.Goal.ID,
+			&result.Visitors,
+			&result.Conversions,
+			&result.CR,
+			&result.Value); err != nil {
+			return nil, queryError(err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
+}
+
+// SelectGoalBreakdownStats implements the Store interface.
+func (client *Client) SelectGoalBreakdownStats(ctx context.Context, query string, args ...any) ([]model.GoalBreakdownStats, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	defer client.closeRows(rows)
+	var results []model.GoalBreakdownStats
+
+	for rows.Next() {
+		var result model.GoalBreakdownStats
+
+		if err := rows.Scan(&result.Dimension,
+			&result.Visitors,
+			&result.Conversions,
+			&result.CR,
+			&result.Value); err != nil {
+			return nil, queryError(err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
+}
+
+// SelectGoalPeriodStats implements the Store interface.
+func (client *Client) SelectGoalPeriodStats(ctx context.Context, period pkg.Period, query string, args ...any) ([]model.GoalPeriodStats, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	defer client.closeRows(rows)
+	var results []model.GoalPeriodStats
+
+	for rows.Next() {
+		var result model.GoalPeriodStats
+		var date null.Time
+
+		if err := rows.Scan(&date,
+			&result.Visitors,
+			&result.Conversions,
+			&result.CR,
+			&result.Value); err != nil {
+			return nil, queryError(err)
+		}
+
+		switch period {
+		case pkg.PeriodHour:
+			result.Hour = date
+		case pkg.PeriodWeek:
+			result.Week = date
+		case pkg.PeriodMonth:
+			result.Month = date
+		case pkg.PeriodQuarter:
+			result.Quarter = date
+		case pkg.PeriodYear:
+			result.Year = date
+		default:
+			result.Day = date
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
+}
+
+// SelectSegments implements the Store interface.
+func (client *Client) SelectSegments(ctx context.Context, query string, args ...any) ([]model.Segment, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	defer client.closeRows(rows)
+	var results []model.Segment
+
+	for rows.Next() {
+		var result model.Segment
+		var events, paths []string
+		var not, visitor []uint8
+		var minCounts, maxCounts []uint32
+
+		if err := rows.Scan(&result.ClientID,
+			&result.Name,
+			&events,
+			&paths,
+			&not,
+			&visitor,
+			&minCounts,
+			&maxCounts,
+			&result.Version); err != nil {
+			return nil, queryError(err)
+		}
+
+		result.Conditions = make([]model.SegmentCondition, 0, len(events))
+
+		for i := range events {
+			if i < len(paths) && i < len(not) && i < len(visitor) && i < len(minCounts) && i < len(maxCounts) {
+				result.Conditions = append(result.Conditions, model.SegmentCondition{
+					Event:    events[i],
+					Path:     paths[i],
+					Not:      not[i] == 1,
+					Visitor:  visitor[i] == 1,
+					MinCount: int(minCounts[i]),
+					MaxCount: int(maxCounts[i]),
+				})
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
+}
+
+// SelectContentGroups implements the Store interface.
+func (client *Client) SelectContentGroups(ctx context.Context, query string, args ...any) ([]model.ContentGroups, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	defer client.closeRows(rows)
+	var results []model.ContentGroups
+
+	for rows.Next() {
+		var result model.ContentGroups
+		var names, pathPrefixes, pathPatterns, tagKeys, tagValues []string
+
+		if err := rows.Scan(&result.ClientID,
+			&names,
+			&pathPrefixes,
+			&pathPatterns,
+			&tagKeys,
+			&tagValues,
+			&result.Version); err != nil {
+			return nil, queryError(err)
+		}
+
+		result.Groups = make([]model.ContentGroup, 0, len(names))
+
+		for i := range names {
+			if i < len(pathPrefixes) && i < len(pathPatterns) && i < len(tagKeys) && i < len(tagValues) {
+				result.Groups = append(result.Groups, model.ContentGroup{
+					Name:        names[i],
+					PathPrefix:  pathPrefixes[i],
+					PathPattern: pathPatterns[i],
+					TagKey:      tagKeys[i],
+					TagValue:    tagValues[i],
+				})
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
+}
+
+func (client *Client) saveImported(table string, columns []string, n int, args []any) error {
+	if n == 0 {
+		return nil
+	}
+
+	// the import time is set by the database, so that it can be compared to previous imports without clock skew
+	columns = append(columns, "imported_at")
+	placeholder := "(" + strings.Repeat("?,", len(columns)-1) + "now64(3))"
+	values := make([]string, 0, n)
+
+	for range n {
+		values = append(values, placeholder)
+	}
+
+	if _, err := client.Exec(fmt.Sprintf(`INSERT INTO "%s" (%s) VALUES %s`, table, strings.Join(columns, ", "), strings.Join(values, ",")), args...); err != nil {
+		return err
+	}
+
+	if client.debug {
+		client.logger.Debug("saved imported statistics", "table", table, "count", n)
+	}
+
+	return nil
+}
+
+func (client *Client) queryContext(ctx context.Context) context.Context {
+	settings := client.settings.merge(ctx).clickhouse()
+
+	if len(settings) == 0 {
+		return ctx
+	}
+
+	return clickhouse.Context(ctx, clickhouse.WithSettings(settings))
+}
+
+func (client *Client) boolean(b bool) int8 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// GetPercentileStats implements the Store interface.
+func (client *Client) GetPercentileStats(ctx context.Context, query string, args ...any) (*model.PercentileStats, error) {
+	result := new(model.PercentileStats)
+
+	if err := client.QueryRowContext(ctx, query, args...).Scan(&result.Count,
+		&result.Min,
+		&result.Max,
+		&result.Average,
+		&result.Median,
+		&result.P75,
+		&result.P90,
+		&result.P95); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, queryError(err)
+	}
+
+	return result, nil
+}
+
+// SelectHistogramBuckets implements the Store interface.
+func (client *Client) SelectHistogramBuckets(ctx context.Context, query string, args ...any) ([]model.HistogramBucket, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	defer client.closeRows(rows)
+	var results []model.HistogramBucket
+
+	for rows.Next() {
+		var result model.HistogramBucket
+
+		if err := rows.Scan(&result.From,
+			&result.Count,
+			&result.Visitors,
+			&result.RelativeCount); err != nil {
+			return nil, queryError(err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
+}
+
+// SelectBreakdownRows implements the Store interface.
+// The dimensions are scanned as strings and all remaining columns as metrics keyed by the column name.
+func (client *Client) SelectBreakdownRows(ctx context.Context, dimensions int, query string, args ...any) ([]model.BreakdownRow, error) {
+	rows, err := client.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	defer client.closeRows(rows)
+	columns, err := rows.Columns()
+
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	if dimensions > len(columns) {
+		return nil, errors.New("more dimensions than columns selected")
+	}
+
+	var results []model.BreakdownRow
+
+	for rows.Next() {
+		dimensionValues := make([]string, dimensions)
+		metricValues := make([]float64, len(columns)-dimensions)
+		dest := make([]any, 0, len(columns))
+
+		for i := range dimensionValues {
+			dest = append(dest, &dimensionValues[i])
+		}
+
+		for i := range metricValues {
+			dest = append(dest, &metricValues[i])
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, queryError(err)
+		}
+
+		result := model.BreakdownRow{
+			Dimensions: dimensionValues,
+			Metrics:    make(map[string]float64, len(metricValues)),
+		}
+
+		for i, value := range metricValues {
+			result.Metrics[columns[dimensions+i]] = value
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+
+	return results, nil
 }
 
 func (client *Client) closeRows(rows *sql.Rows) {
@@ -126,6 +126,161 @@ func (client *ClientMock) SaveRequests(bots []model.Request) error {
 	return nil
 }
 
+// SaveImportedBrowser implements the Store interface.
+func (client *ClientMock) SaveImportedBrowser([]model.ImportedBrowser) error {
+	return nil
+}
+
+// SaveImportedBrowserVersion implements the Store interface.
+func (client *ClientMock) SaveImportedBrowserVersion([]model.ImportedBrowserVersion) error {
+	return nil
+}
+
+// SaveImportedChannel implements the Store interface.
+func (client *ClientMock) SaveImportedChannel([]model.ImportedChannel) error {
+	return nil
+}
+
+// SaveImportedCity implements the Store interface.
+func (client *ClientMock) SaveImportedCity([]model.ImportedCity) error {
+	return nil
+}
+
+// SaveImportedCountry implements the Store interface.
+func (client *ClientMock) SaveImportedCountry([]model.ImportedCountry) error {
+	return nil
+}
+
+// SaveImportedDevice implements the Store interface.
+func (client *ClientMock) SaveImportedDevice([]model.ImportedDevice) error {
+	return nil
+}
+
+// SaveImportedEntryPage implements the Store interface.
+func (client *ClientMock) SaveImportedEntryPage([]model.ImportedEntryPage) error {
+	return nil
+}
+
+// SaveImportedEvent implements the Store interface.
+func (client *ClientMock) SaveImportedEvent([]model.ImportedEvent) error {
+	return nil
+}
+
+// SaveImportedExitPage implements the Store interface.
+func (client *ClientMock) SaveImportedExitPage([]model.ImportedExitPage) error {
+	return nil
+}
+
+// SaveImportedHostname implements the Store interface.
+func (client *ClientMock) SaveImportedHostname([]model.ImportedHostname) error {
+	return nil
+}
+
+// SaveImportedHour implements the Store interface.
+func (client *ClientMock) SaveImportedHour([]model.ImportedHour) error {
+	return nil
+}
+
+// SaveImportedLanguage implements the Store interface.
+func (client *ClientMock) SaveImportedLanguage([]model.ImportedLanguage) error {
+	return nil
+}
+
+// SaveImportedOS implements the Store interface.
+func (client *ClientMock) SaveImportedOS([]model.ImportedOS) error {
+	return nil
+}
+
+// SaveImportedOSVersion implements the Store interface.
+func (client *ClientMock) SaveImportedOSVersion([]model.ImportedOSVersion) error {
+	return nil
+}
+
+// SaveImportedPage implements the Store interface.
+func (client *ClientMock) SaveImportedPage([]model.ImportedPage) error {
+	return nil
+}
+
+// SaveImportedReferrer implements the Store interface.
+func (client *ClientMock) SaveImportedReferrer([]model.ImportedReferrer) error {
+	return nil
+}
+
+// SaveImportedRegion implements the Store interface.
+func (client *ClientMock) SaveImportedRegion([]model.ImportedRegion) error {
+	return nil
+}
+
+// SaveImportedTag implements the Store interface.
+func (client *ClientMock) SaveImportedTag([]model.ImportedTag) error {
+	return nil
+}
+
+// SaveImportedUTMCampaign implements the Store interface.
+func (client *ClientMock) SaveImportedUTMCampaign([]model.ImportedUTMCampaign) error {
+	return nil
+}
+
+// SaveImportedUTMContent implements the Store interface.
+func (client *ClientMock) SaveImportedUTMContent([]model.ImportedUTMContent) error {
+	return nil
+}
+
+// SaveImportedUTMMedium implements the Store interface.
+func (client *ClientMock) SaveImportedUTMMedium([]model.ImportedUTMMedium) error {
+	return nil
+}
+
+// SaveImportedUTMSource implements the Store interface.
+func (client *ClientMock) SaveImportedUTMSource([]model.ImportedUTMSource) error {
+	return nil
+}
+
+// SaveImportedUTMTerm implements the Store interface.
+func (client *ClientMock) SaveImportedUTMTerm([]model.ImportedUTMTerm) error {
+	return nil
+}
+
+// SaveImportedVisitors implements the Store interface.
+func (client *ClientMock) SaveImportedVisitors([]model.ImportedVisitors) error {
+	return nil
+}
+
+// LatestImport implements the Store interface.
+func (client *ClientMock) LatestImport(context.Context, uint64, time.Time, time.Time) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+// DeleteImported implements the Store interface.
+func (client *ClientMock) DeleteImported(context.Context, uint64, time.Time, time.Time, time.Time, time.Time) error {
+	return nil
+}
+
+// SaveGoals implements the Store interface.
+func (client *ClientMock) SaveGoals(context.Context, []model.Goal) error {
+	return nil
+}
+
+// DeleteGoal implements the Store interface.
+func (client *ClientMock) DeleteGoal(context.Context, uint64, uint64) error {
+	return nil
+}
+
+// SaveSegments implements the Store interface.
+func (client *ClientMock) SaveSegments(context.Context, []model.Segment) error {
+	return nil
+}
+
+// DeleteSegment implements the Store interface.
+func (client *ClientMock) DeleteSegment(context.Context, uint64, string) error {
+	return nil
+}
+
+// SaveContentGroups implements the Store interface.
+func (client *ClientMock) SaveContentGroups(context.Context, []model.ContentGroups) error {
+	return nil
+}
+
 // Session implements the Store interface.
 func (client *ClientMock) Session(context.Context, uint64, uint64, time.Time) (*model.Session, error) {
 	if client.ReturnSession != nil {
@@ -210,6 +365,11 @@ func (client *ClientMock) SelectPageStats(context.Context, bool, bool, string, .
 	return nil, nil
 }
 
+// SelectPathGroupStats implements the Store interface.
+func (client *ClientMock) SelectPathGroupStats(context.Context, string, ...any) ([]model.PathGroupStats, error) {
+	return nil, nil
+}
+
 // SelectAvgTimeSpentStats implements the Store interface.
 func (client *ClientMock) SelectAvgTimeSpentStats(context.Context, string, ...any) ([]model.AvgTimeSpentStats, error) {
 	return nil, nil
@@ -360,7 +520,82 @@ func (client *ClientMock) SelectEvents(context.Context, string, ...any) ([]model
 	return nil, nil
 }
 
+// ExportPageViews implements the Store interface.
+func (client *ClientMock) ExportPageViews(context.Context, string, ...any) ([]model.PageView, error) {
+	return nil, nil
+}
+
+// ExportSessions implements the Store interface.
+func (client *ClientMock) ExportSessions(context.Context, string, ...any) ([]model.Session, error) {
+	return nil, nil
+}
+
+// ExportEvents implements the Store interface.
+func (client *ClientMock) ExportEvents(context.Context, string, ...any) ([]model.Event, error) {
+	return nil, nil
+}
+
 // SelectFunnelSteps implements the Store interface.
 func (client *ClientMock) SelectFunnelSteps(context.Context, string, ...any) ([]model.FunnelStep, error) {
 	return nil, nil
 }
+
+// SelectFunnelBreakdownSteps implements the Store interface.
+func (client *ClientMock) SelectFunnelBreakdownSteps(context.Context, string, ...any) ([]model.FunnelBreakdownStep, error) {
+	return nil, nil
+}
+
+// SelectPageTransitionStats implements the Store interface.
+func (client *ClientMock) SelectPageTransitionStats(context.Context, string, ...any) ([]model.PageTransitionStats, error) {
+	return nil, nil
+}
+
+// SelectPageFlowSteps implements the Store interface.
+func (client *ClientMock) SelectPageFlowSteps(context.Context, string, ...any) ([]model.PageFlowStep, error) {
+	return nil, nil
+}
+
+// SelectGoals implements the Store interface.
+func (client *ClientMock) SelectGoals(context.Context, string, ...any) ([]model.Goal, error) {
+	return nil, nil
+}
+
+// SelectGoalStats implements the Store interface.
+func (client *ClientMock) SelectGoalStats(context.Context, string, ...any) ([]model.GoalStats, error) {
+	return nil, nil
+}
+
+// SelectGoalBreakdownStats implements the Store interface.
+func (client *ClientMock) SelectGoalBreakdownStats(context.Context, string, ...any) ([]model.GoalBreakdownStats, error) {
+	return nil, nil
+}
+
+// SelectGoalPeriodStats implements the Store interface.
+func (client *ClientMock) SelectGoalPeriodStats(context.Context, pkg.Period, string, ...any) ([]model.GoalPeriodStats, error) {
+	return nil, nil
+}
+
+// SelectSegments implements the Store interface.
+func (client *ClientMock) SelectSegments(context.Context, string, ...any) ([]model.Segment, error) {
+	return nil, nil
+}
+
+// SelectContentGroups implements the Store interface.
+func (client *ClientMock) SelectContentGroups(context.Context, string, ...any) ([]model.ContentGroups, error) {
+	return nil, nil
+}
+
+// GetPercentileStats implements the Store interface.
+func (client *ClientMock) GetPercentileStats(context.Context, string, ...any) (*model.PercentileStats, error) {
+	return nil, nil
+}
+
+// SelectHistogramBuckets implements the Store interface.
+func (client *ClientMock) SelectHistogramBuckets(context.Context, string, ...any) ([]model.HistogramBucket, error) {
+	return nil, nil
+}
+
+// SelectBreakdownRows implements the Store interface.
+func (client *ClientMock) SelectBreakdownRows(context.Context, int, string, ...any) ([]model.BreakdownRow, error) {
+	return nil, nil
+}
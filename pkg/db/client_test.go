@@ -259,6 +259,7 @@ func TestClient_Session(t *testing.T) {
 			ExitPath:  "/path2",
 			EntryPath: "/entry2",
 			PageViews: 3,
+			IsEngaged: true,
 		},
 		{
 			Sign:      -1,
@@ -280,6 +281,61 @@ func TestClient_Session(t *testing.T) {
 	assert.Equal(t, "/path2", session.ExitPath)
 	assert.Equal(t, "/entry2", session.EntryPath)
 	assert.Equal(t, uint16(3), session.PageViews)
+	assert.True(t, session.IsEngaged)
+}
+
+func TestClient_SaveImported(t *testing.T) {
+	CleanupDB(t, dbClient)
+	day := util.PastDay(3)
+	assert.NoError(t, dbClient.SaveImportedVisitors([]model.ImportedVisitors{
+		{ClientID: 1, Date: day, Visitors: 10, Views: 20, Sessions: 12, Bounces: 4, SessionDuration: 600},
+		{ClientID: 1, Date: util.PastDay(2), Visitors: 5, Views: 8, Sessions: 6, Bounces: 1, SessionDuration: 120},
+	}))
+	assert.NoError(t, dbClient.SaveImportedPage([]model.ImportedPage{
+		{ClientID: 1, Date: day, Path: "/", Visitors: 10, Views: 20, Sessions: 12, Bounces: 4},
+	}))
+	assert.NoError(t, dbClient.SaveImportedReferrer([]model.ImportedReferrer{
+		{ClientID: 1, Date: day, Referrer: "Google", Visitors: 3, Sessions: 3, Bounces: 1},
+	}))
+	assert.NoError(t, dbClient.SaveImportedCountry([]model.ImportedCountry{
+		{ClientID: 1, Date: day, CountryCode: "de", Visitors: 7},
+	}))
+	assert.NoError(t, dbClient.SaveImportedOSVersion([]model.ImportedOSVersion{
+		{ClientID: 1, Date: day, OS: "Windows", OSVersion: "10", Visitors: 4},
+	}))
+	assert.NoError(t, dbClient.SaveImportedHour([]model.ImportedHour{
+		{ClientID: 1, Date: day, Hour: 13, Visitors: 2, Views: 3, Sessions: 2, Bounces: 1},
+	}))
+	assert.NoError(t, dbClient.SaveImportedBrowser(nil))
+	count, err := dbClient.Count(context.Background(), `SELECT sum(visitors) FROM "imported_visitors" WHERE client_id = 1`)
+	assert.NoError(t, err)
+	assert.Equal(t, 15, count)
+	count, err = dbClient.Count(context.Background(), `SELECT sum(views) FROM "imported_page" WHERE client_id = 1 AND path = '/'`)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, count)
+	count, err = dbClient.Count(context.Background(), `SELECT sum(visitors) FROM "imported_hour" WHERE client_id = 1 AND hour = 13`)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	latest, err := dbClient.LatestImport(context.Background(), 1, day, day)
+	assert.NoError(t, err)
+	assert.False(t, latest.IsZero())
+	assert.NoError(t, dbClient.DeleteImported(context.Background(), 1, day, day, latest, time.Time{}))
+	count, err = dbClient.Count(context.Background(), `SELECT sum(visitors) FROM "imported_visitors" WHERE client_id = 1`)
+	assert.NoError(t, err)
+	assert.Equal(t, 15, count)
+	assert.NoError(t, dbClient.DeleteImported(context.Background(), 1, day, day, time.Time{}, latest))
+	count, err = dbClient.Count(context.Background(), `SELECT sum(visitors) FROM "imported_visitors" WHERE client_id = 1`)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, count)
+	latest, err = dbClient.LatestImport(context.Background(), 1, day, day)
+	assert.NoError(t, err)
+	assert.True(t, latest.IsZero())
+	count, err = dbClient.Count(context.Background(), `SELECT count(*) FROM "imported_country"`)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	count, err = dbClient.Count(context.Background(), `SELECT count(*) FROM "imported_os_version"`)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
 }
 
 func TestClient_GetNoError(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io/fs"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"text/template"
@@ -18,9 +19,34 @@ import (
 //go:embed schema
 var migrationFiles embed.FS
 
-type migration struct {
-	version    int
-	statements []string
+const (
+	upSuffix   = ".up.sql"
+	downSuffix = ".down.sql"
+)
+
+// Migration is a database schema migration.
+type Migration struct {
+	// Version is the schema version after running the migration.
+	Version int
+
+	// Name is the migration filename.
+	Name string
+
+	// Statements are the SQL statements of the migration.
+	Statements []string
+}
+
+// MigrationStatus is the state of the database schema.
+type MigrationStatus struct {
+	// Version is the current schema version.
+	Version int
+
+	// Dirty is true if a migration failed and the database needs to be repaired.
+	// Use ForceMigrationVersion to clear the flag after fixing the schema manually.
+	Dirty bool
+
+	// Pending is the list of migrations that haven't been run yet.
+	Pending []Migration
 }
 
 // Migrate runs the database migration for a given connection string.
@@ -49,6 +75,127 @@ func Migrate(config *ClientConfig) error {
 	return client.Close()
 }
 
+// GetMigrationStatus returns the current schema version and pending migrations.
+func GetMigrationStatus(config *ClientConfig) (*MigrationStatus, error) {
+	client, err := NewClient(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer client.Close()
+
+	if err := createMigrationsTable(client, config.Cluster); err != nil {
+		return nil, err
+	}
+
+	version, dirty, err := readMigrationVersion(client)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := PendingMigrations(version, config.Cluster)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &MigrationStatus{
+		Version: version,
+		Dirty:   dirty,
+		Pending: pending,
+	}, nil
+}
+
+// PendingMigrations returns the migrations that would be run for given schema version and cluster.
+// This doesn't connect to the database and can be used for a dry run.
+func PendingMigrations(version int, cluster string) ([]Migration, error) {
+	files, err := migrationFiles.ReadDir("schema")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return loadMigrations(files, version, cluster)
+}
+
+// ForceMigrationVersion sets the schema version and clears the dirty flag.
+// This doesn't run any migrations and should only be used after repairing the schema manually.
+func ForceMigrationVersion(config *ClientConfig, version int) error {
+	client, err := NewClient(config)
+
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	if err := createMigrationsTable(client, config.Cluster); err != nil {
+		return err
+	}
+
+	return setMigrationVersion(client, version, false)
+}
+
+// MigrateDown rolls back the database schema to given version.
+// All migrations above the version must provide a down migration script, or no migration will be run.
+func MigrateDown(config *ClientConfig, version int) error {
+	client, err := NewClient(config)
+
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	if err := createMigrationsTable(client, config.Cluster); err != nil {
+		return err
+	}
+
+	current, err := getMigrationVersion(client)
+
+	if err != nil {
+		return err
+	}
+
+	files, err := migrationFiles.ReadDir("schema")
+
+	if err != nil {
+		return err
+	}
+
+	migrations, err := loadDownMigrations(files, current, version, config.Cluster)
+
+	if err != nil {
+		return err
+	}
+
+	for i, m := range migrations {
+		if err := setMigrationVersion(client, m.Version, true); err != nil {
+			return err
+		}
+
+		for _, s := range m.Statements {
+			if _, err := client.DB.Exec(s); err != nil {
+				return err
+			}
+		}
+
+		previous := version
+
+		if i < len(migrations)-1 {
+			previous = migrations[i+1].Version
+		}
+
+		if err := setMigrationVersion(client, previous, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func createMigrationsTable(client *Client, cluster string) error {
 	table := ""
 	err := client.QueryRow("SHOW TABLES LIKE 'schema_migrations'").Scan(&table)
@@ -75,6 +222,20 @@ func createMigrationsTable(client *Client, cluster string) error {
 }
 
 func getMigrationVersion(client *Client) (int, error) {
+	version, dirty, err := readMigrationVersion(client)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if dirty {
+		return 0, errors.New("database dirty")
+	}
+
+	return version, nil
+}
+
+func readMigrationVersion(client *Client) (int, bool, error) {
 	migration := struct {
 		Version int
 		Dirty   bool
@@ -82,14 +243,10 @@ func getMigrationVersion(client *Client) (int, error) {
 	err := client.QueryRow("SELECT version, dirty FROM schema_migrations ORDER BY sequence DESC LIMIT 1").Scan(&migration.Version, &migration.Dirty)
 
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return 0, err
-	}
-
-	if migration.Dirty {
-		return 0, errors.New("database dirty")
+		return 0, false, err
 	}
 
-	return migration.Version, nil
+	return migration.Version, migration.Dirty, nil
 }
 
 func setMigrationVersion(client *Client, version int, dirty bool) error {
@@ -98,30 +255,24 @@ func setMigrationVersion(client *Client, version int, dirty bool) error {
 }
 
 func runMigrations(client *Client, version int, cluster string) error {
-	files, err := migrationFiles.ReadDir("schema")
-
-	if err != nil {
-		return err
-	}
-
-	migrations, err := loadMigrations(files, version, cluster)
+	migrations, err := PendingMigrations(version, cluster)
 
 	if err != nil {
 		return err
 	}
 
 	for _, m := range migrations {
-		if err := setMigrationVersion(client, m.version, true); err != nil {
+		if err := setMigrationVersion(client, m.Version, true); err != nil {
 			return err
 		}
 
-		for _, s := range m.statements {
+		for _, s := range m.Statements {
 			if _, err := client.DB.Exec(s); err != nil {
 				return err
 			}
 		}
 
-		if err := setMigrationVersion(client, m.version, false); err != nil {
+		if err := setMigrationVersion(client, m.Version, false); err != nil {
 			return err
 		}
 	}
@@ -129,27 +280,28 @@ func runMigrations(client *Client, version int, cluster string) error {
 	return nil
 }
 
-func loadMigrations(files []fs.DirEntry, version int, cluster string) ([]migration, error) {
-	migrations := make([]migration, 0)
+func loadMigrations(files []fs.DirEntry, version int, cluster string) ([]Migration, error) {
+	migrations := make([]Migration, 0)
 
 	for _, f := range files {
-		if filepath.Ext(f.Name()) == ".sql" {
+		if strings.HasSuffix(f.Name(), upSuffix) {
 			v, err := parseVersion(f.Name())
 
 			if err != nil {
 				return nil, err
 			}
 
-			statements, err := parseStatements(f.Name(), cluster)
+			if v > version {
+				statements, err := parseStatements(f.Name(), cluster)
 
-			if err != nil {
-				return nil, err
-			}
+				if err != nil {
+					return nil, err
+				}
 
-			if v > version {
-				migrations = append(migrations, migration{
-					version:    v,
-					statements: statements,
+				migrations = append(migrations, Migration{
+					Version:    v,
+					Name:       f.Name(),
+					Statements: statements,
 				})
 			}
 		}
@@ -158,6 +310,54 @@ func loadMigrations(files []fs.DirEntry, version int, cluster string) ([]migrati
 	return migrations, nil
 }
 
+// loadDownMigrations returns the down migrations to roll back from version to target in descending order.
+// The version of each migration is the version to be rolled back.
+func loadDownMigrations(files []fs.DirEntry, version, target int, cluster string) ([]Migration, error) {
+	if target < 0 || target > version {
+		return nil, fmt.Errorf("cannot migrate down from version %d to %d", version, target)
+	}
+
+	down := make(map[int]string)
+	migrations := make([]Migration, 0)
+
+	for _, f := range files {
+		v, err := parseVersion(f.Name())
+
+		if err != nil {
+			return nil, err
+		}
+
+		if v > target && v <= version {
+			if strings.HasSuffix(f.Name(), downSuffix) {
+				down[v] = f.Name()
+			} else if strings.HasSuffix(f.Name(), upSuffix) {
+				migrations = append(migrations, Migration{Version: v})
+			}
+		}
+	}
+
+	slices.Reverse(migrations)
+
+	for i, m := range migrations {
+		name, found := down[m.Version]
+
+		if !found {
+			return nil, fmt.Errorf("down migration for version %d not found", m.Version)
+		}
+
+		statements, err := parseStatements(name, cluster)
+
+		if err != nil {
+			return nil, err
+		}
+
+		migrations[i].Name = name
+		migrations[i].Statements = statements
+	}
+
+	return migrations, nil
+}
+
 func parseVersion(name string) (int, error) {
 	left, _, found := strings.Cut(name, "_")
 
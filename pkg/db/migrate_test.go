@@ -105,3 +105,80 @@ PARTITION BY toYYYYMM(date)
 ORDER BY (client_id, date)
 SETTINGS index_granularity = 8192`, statements[12])
 }
+
+func TestMigrationStatus(t *testing.T) {
+	config := &ClientConfig{
+		Hostnames:     []string{"127.0.0.1"},
+		Port:          9000,
+		Database:      "pirschtest",
+		Password:      "default",
+		SSLSkipVerify: true,
+	}
+	latest := latestMigrationVersion(t)
+	status, err := GetMigrationStatus(config)
+	assert.NoError(t, err)
+	assert.Equal(t, latest, status.Version)
+	assert.False(t, status.Dirty)
+	assert.Empty(t, status.Pending)
+	assert.NoError(t, setMigrationVersion(dbClient, latest, true))
+	status, err = GetMigrationStatus(config)
+	assert.NoError(t, err)
+	assert.True(t, status.Dirty)
+	assert.Error(t, Migrate(config))
+	assert.NoError(t, ForceMigrationVersion(config, latest))
+	status, err = GetMigrationStatus(config)
+	assert.NoError(t, err)
+	assert.Equal(t, latest, status.Version)
+	assert.False(t, status.Dirty)
+	assert.Error(t, MigrateDown(config, 30))
+	status, err = GetMigrationStatus(config)
+	assert.NoError(t, err)
+	assert.Equal(t, latest, status.Version)
+}
+
+func TestPendingMigrations(t *testing.T) {
+	latest := latestMigrationVersion(t)
+	migrations, err := PendingMigrations(29, "")
+	assert.NoError(t, err)
+	assert.Len(t, migrations, latest-29)
+	assert.Equal(t, 30, migrations[0].Version)
+	assert.Equal(t, "0030_channel.up.sql", migrations[0].Name)
+	assert.NotEmpty(t, migrations[0].Statements)
+	assert.Equal(t, 31, migrations[1].Version)
+	assert.Equal(t, 32, migrations[2].Version)
+	assert.Equal(t, latest, migrations[len(migrations)-1].Version)
+	migrations, err = PendingMigrations(0, "")
+	assert.NoError(t, err)
+	assert.Len(t, migrations, latest)
+	assert.Equal(t, 1, migrations[0].Version)
+}
+
+func TestLoadDownMigrations(t *testing.T) {
+	files, err := migrationFiles.ReadDir("schema")
+	assert.NoError(t, err)
+	migrations, err := loadDownMigrations(files, 32, 32, "")
+	assert.NoError(t, err)
+	assert.Empty(t, migrations)
+	_, err = loadDownMigrations(files, 32, 33, "")
+	assert.Error(t, err)
+	migrations, err = loadDownMigrations(files, 32, 31, "")
+	assert.NoError(t, err)
+	assert.Len(t, migrations, 1)
+	assert.Equal(t, 32, migrations[0].Version)
+	assert.Equal(t, "0032_goal.down.sql", migrations[0].Name)
+	assert.NotEmpty(t, migrations[0].Statements)
+	migrations, err = loadDownMigrations(files, 33, 32, "")
+	assert.NoError(t, err)
+	assert.Len(t, migrations, 1)
+	assert.Equal(t, "0033_engagement.down.sql", migrations[0].Name)
+	_, err = loadDownMigrations(files, 32, 29, "")
+	assert.Equal(t, "down migration for version 31 not found", err.Error())
+}
+
+// latestMigrationVersion returns the version of the last embedded schema migration.
+func latestMigrationVersion(t *testing.T) int {
+	migrations, err := PendingMigrations(0, "")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+	return migrations[len(migrations)-1].Version
+}
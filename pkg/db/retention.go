@@ -0,0 +1,242 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/util"
+)
+
+var (
+	// rawTables are the tables storing tracking data with a "time" column.
+	rawTables = []string{
+		"page_view",
+		"session",
+		"event",
+		"request",
+	}
+
+	// importedTables are the tables storing imported statistics with a "date" column.
+	importedTables = []string{
+		"imported_browser",
+		"imported_browser_version",
+		"imported_channel",
+		"imported_city",
+		"imported_country",
+		"imported_device",
+		"imported_entry_page",
+		"imported_event",
+		"imported_exit_page",
+		"imported_hostname",
+		"imported_hour",
+		"imported_language",
+		"imported_os",
+		"imported_os_version",
+		"imported_page",
+		"imported_referrer",
+		"imported_region",
+		"imported_tag",
+		"imported_utm_campaign",
+		"imported_utm_content",
+		"imported_utm_medium",
+		"imported_utm_source",
+		"imported_utm_term",
+		"imported_visitors",
+	}
+)
+
+// RetentionPolicy is the data retention period for a single client.
+type RetentionPolicy struct {
+	// ClientID is the client the policy applies to.
+	ClientID uint64
+
+	// Months is the number of full months data is kept for.
+	// Everything before the first day of the month Months ago (based on today) will be removed.
+	// Policies with Months <= 0 are ignored.
+	Months int
+}
+
+// RetentionConfig is the configuration for the RetentionManager.
+type RetentionConfig struct {
+	// Policies are the per-client retention periods.
+	// Clients without a policy will keep their data forever.
+	Policies []RetentionPolicy
+
+	// Cluster is the optional database cluster to use for mutations.
+	Cluster string
+
+	// DryRun counts the rows that would be removed instead of deleting them.
+	DryRun bool
+
+	// Logger is the log.Logger used for logging.
+	// The default log will be used printing to os.Stdout with "pirsch" in its prefix in case it is not set.
+	Logger *slog.Logger
+}
+
+func (config *RetentionConfig) validate() {
+	if config.Logger == nil {
+		config.Logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+	}
+}
+
+// RetentionResult is the number of expired rows found in a table for a client.
+type RetentionResult struct {
+	ClientID uint64
+	Table    string
+	Before   time.Time
+	Rows     int
+}
+
+// RetentionManager removes expired data for clients with a retention policy.
+type RetentionManager struct {
+	client   *Client
+	policies []RetentionPolicy
+	cluster  string
+	dryRun   bool
+	logger   *slog.Logger
+	cancel   context.CancelFunc
+	m        sync.Mutex
+}
+
+// NewRetentionManager creates a new RetentionManager for given Client.
+// Pass nil for the config to use the defaults.
+func NewRetentionManager(client *Client, config *RetentionConfig) *RetentionManager {
+	if config == nil {
+		config = new(RetentionConfig)
+	}
+
+	config.validate()
+	return &RetentionManager{
+		client:   client,
+		policies: config.Policies,
+		cluster:  config.Cluster,
+		dryRun:   config.DryRun,
+		logger:   config.Logger,
+	}
+}
+
+// SetPolicies replaces the retention policies used for the next run.
+func (manager *RetentionManager) SetPolicies(policies []RetentionPolicy) {
+	manager.m.Lock()
+	defer manager.m.Unlock()
+	manager.policies = policies
+}
+
+// Start runs the retention manager every night at midnight (UTC).
+func (manager *RetentionManager) Start() {
+	manager.m.Lock()
+	defer manager.m.Unlock()
+
+	if manager.cancel != nil {
+		return
+	}
+
+	manager.cancel = util.RunAtMidnight(func() {
+		if _, err := manager.Run(context.Background()); err != nil {
+			manager.logger.Error("error removing expired data", "err", err)
+		}
+	})
+}
+
+// Stop stops the nightly runs started by Start.
+func (manager *RetentionManager) Stop() {
+	manager.m.Lock()
+	defer manager.m.Unlock()
+
+	if manager.cancel != nil {
+		manager.cancel()
+		manager.cancel = nil
+	}
+}
+
+// Run removes all expired rows from the raw and imported tables for each client with a retention policy.
+// The result contains the number of rows removed per client and table.
+// In dry-run mode, rows are counted but not deleted.
+func (manager *RetentionManager) Run(ctx context.Context) ([]RetentionResult, error) {
+	manager.m.Lock()
+	policies := manager.policies
+	manager.m.Unlock()
+	results := make([]RetentionResult, 0, len(policies)*(len(rawTables)+len(importedTables)))
+	var errs []error
+
+	for _, policy := range policies {
+		if policy.Months <= 0 {
+			continue
+		}
+
+		before := policy.before(util.Today())
+
+		for _, table := range rawTables {
+			result, err := manager.removeExpired(ctx, policy.ClientID, table, "toDate(time)", before)
+
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			results = append(results, *result)
+		}
+
+		for _, table := range importedTables {
+			result, err := manager.removeExpired(ctx, policy.ClientID, table, "date", before)
+
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			results = append(results, *result)
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+func (manager *RetentionManager) removeExpired(ctx context.Context, clientID uint64, table, column string, before time.Time) (*RetentionResult, error) {
+	beforeDate := before.Format(time.DateOnly)
+	count, err := manager.client.Count(ctx, fmt.Sprintf(`SELECT count(*) FROM "%s" WHERE client_id = ? AND %s < toDate(?)`, table, column), clientID, beforeDate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RetentionResult{
+		ClientID: clientID,
+		Table:    table,
+		Before:   before,
+		Rows:     count,
+	}
+
+	if manager.dryRun {
+		manager.logger.Info("found expired rows (dry run)", "client_id", clientID, "table", table, "before", beforeDate, "rows", count)
+		return result, nil
+	}
+
+	if count == 0 {
+		return result, nil
+	}
+
+	if _, err := manager.client.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE "%s" %s DELETE WHERE client_id = ? AND %s < toDate(?)`, table, manager.onCluster(), column), clientID, beforeDate); err != nil {
+		return nil, err
+	}
+
+	manager.logger.Info("removed expired rows", "client_id", clientID, "table", table, "before", beforeDate, "rows", count)
+	return result, nil
+}
+
+func (manager *RetentionManager) onCluster() string {
+	if manager.cluster != "" {
+		return fmt.Sprintf("ON CLUSTER '%s'", manager.cluster)
+	}
+
+	return ""
+}
+
+func (policy *RetentionPolicy) before(today time.Time) time.Time {
+	return time.Date(today.Year(), today.Month()-time.Month(policy.Months), 1, 0, 0, 0, 0, time.UTC)
+}
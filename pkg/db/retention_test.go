@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionManager_Run(t *testing.T) {
+	CleanupDB(t, dbClient)
+	old := util.Today().AddDate(0, -14, 0)
+	assert.NoError(t, dbClient.SavePageViews([]model.PageView{
+		{ClientID: 1, VisitorID: 1, Time: old, Path: "/"},
+		{ClientID: 1, VisitorID: 2, Time: util.Today(), Path: "/"},
+		{ClientID: 2, VisitorID: 3, Time: old, Path: "/"},
+	}))
+	_, err := dbClient.Exec(`INSERT INTO "imported_visitors" (client_id, date, visitors, views, sessions, bounces, session_duration) VALUES (1, ?, 1, 1, 1, 0, 0)`, old.Format(time.DateOnly))
+	assert.NoError(t, err)
+	manager := NewRetentionManager(dbClient, &RetentionConfig{
+		Policies: []RetentionPolicy{{ClientID: 1, Months: 13}},
+		DryRun:   true,
+	})
+	results, err := manager.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, results, len(rawTables)+len(importedTables))
+	assert.Equal(t, 1, retentionRows(results, "page_view"))
+	assert.Equal(t, 1, retentionRows(results, "imported_visitors"))
+	count, err := dbClient.Count(context.Background(), `SELECT count(*) FROM "page_view"`)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	manager = NewRetentionManager(dbClient, &RetentionConfig{
+		Policies: []RetentionPolicy{{ClientID: 1, Months: 13}},
+	})
+	results, err = manager.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, retentionRows(results, "page_view"))
+
+	assert.Eventually(t, func() bool {
+		count, err := dbClient.Count(context.Background(), `SELECT count(*) FROM "page_view"`)
+		return err == nil && count == 2
+	}, time.Second*10, time.Millisecond*100)
+}
+
+func TestRetentionPolicy_Before(t *testing.T) {
+	policy := RetentionPolicy{ClientID: 1, Months: 13}
+	assert.Equal(t, time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC), policy.before(time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)))
+	policy.Months = 25
+	assert.Equal(t, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), policy.before(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)))
+}
+
+func retentionRows(results []RetentionResult, table string) int {
+	for _, result := range results {
+		if result.Table == table {
+			return result.Rows
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,113 @@
+CREATE TABLE IF NOT EXISTS imported_browser_version {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} (
+    `client_id` UInt64,
+    `date` Date,
+    `browser` String,
+    `browser_version` String,
+    `visitors` UInt32
+)
+ENGINE = {{if .Cluster}}ReplicatedMergeTree('/clickhouse/tables/imported_browser_version/{shard}', '{replica}'){{else}}MergeTree{{end}}
+PARTITION BY toYYYYMM(date)
+ORDER BY (client_id, date)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS imported_channel {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} (
+    `client_id` UInt64,
+    `date` Date,
+    `channel` String,
+    `visitors` UInt32,
+    `views` UInt32,
+    `sessions` UInt32,
+    `bounces` UInt32
+)
+ENGINE = {{if .Cluster}}ReplicatedMergeTree('/clickhouse/tables/imported_channel/{shard}', '{replica}'){{else}}MergeTree{{end}}
+PARTITION BY toYYYYMM(date)
+ORDER BY (client_id, date)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS imported_event {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} (
+    `client_id` UInt64,
+    `date` Date,
+    `event_name` String,
+    `visitors` UInt32,
+    `views` UInt32,
+    `events` UInt32
+)
+ENGINE = {{if .Cluster}}ReplicatedMergeTree('/clickhouse/tables/imported_event/{shard}', '{replica}'){{else}}MergeTree{{end}}
+PARTITION BY toYYYYMM(date)
+ORDER BY (client_id, date)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS imported_hostname {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} (
+    `client_id` UInt64,
+    `date` Date,
+    `hostname` String,
+    `visitors` UInt32,
+    `views` UInt32,
+    `sessions` UInt32,
+    `bounces` UInt32
+)
+ENGINE = {{if .Cluster}}ReplicatedMergeTree('/clickhouse/tables/imported_hostname/{shard}', '{replica}'){{else}}MergeTree{{end}}
+PARTITION BY toYYYYMM(date)
+ORDER BY (client_id, date)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS imported_hour {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} (
+    `client_id` UInt64,
+    `date` Date,
+    `hour` UInt8,
+    `visitors` UInt32,
+    `views` UInt32,
+    `sessions` UInt32,
+    `bounces` UInt32
+)
+ENGINE = {{if .Cluster}}ReplicatedMergeTree('/clickhouse/tables/imported_hour/{shard}', '{replica}'){{else}}MergeTree{{end}}
+PARTITION BY toYYYYMM(date)
+ORDER BY (client_id, date)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS imported_os_version {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} (
+    `client_id` UInt64,
+    `date` Date,
+    `os` String,
+    `os_version` String,
+    `visitors` UInt32
+)
+ENGINE = {{if .Cluster}}ReplicatedMergeTree('/clickhouse/tables/imported_os_version/{shard}', '{replica}'){{else}}MergeTree{{end}}
+PARTITION BY toYYYYMM(date)
+ORDER BY (client_id, date)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS imported_tag {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} (
+    `client_id` UInt64,
+    `date` Date,
+    `tag_key` String,
+    `tag_value` String,
+    `visitors` UInt32,
+    `views` UInt32
+)
+ENGINE = {{if .Cluster}}ReplicatedMergeTree('/clickhouse/tables/imported_tag/{shard}', '{replica}'){{else}}MergeTree{{end}}
+PARTITION BY toYYYYMM(date)
+ORDER BY (client_id, date)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS imported_utm_content {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} (
+    `client_id` UInt64,
+    `date` Date,
+    `utm_content` String,
+    `visitors` UInt32
+)
+ENGINE = {{if .Cluster}}ReplicatedMergeTree('/clickhouse/tables/imported_utm_content/{shard}', '{replica}'){{else}}MergeTree{{end}}
+PARTITION BY toYYYYMM(date)
+ORDER BY (client_id, date)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS imported_utm_term {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} (
+    `client_id` UInt64,
+    `date` Date,
+    `utm_term` String,
+    `visitors` UInt32
+)
+ENGINE = {{if .Cluster}}ReplicatedMergeTree('/clickhouse/tables/imported_utm_term/{shard}', '{replica}'){{else}}MergeTree{{end}}
+PARTITION BY toYYYYMM(date)
+ORDER BY (client_id, date)
+SETTINGS index_granularity = 8192;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS goal {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}};
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS goal {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} (
+    `client_id` UInt64,
+    `id` UInt64,
+    `name` String,
+    `path_pattern` String,
+    `event_name` String,
+    `event_meta_keys` Array(String),
+    `event_meta_values` Array(String),
+    `min_duration_seconds` UInt32,
+    `min_page_views` UInt16,
+    `value` Float64,
+    `value_meta_key` String,
+    `deleted` UInt8,
+    `version` DateTime64(3, 'UTC')
+)
+ENGINE = {{if .Cluster}}ReplicatedReplacingMergeTree('/clickhouse/tables/goal/{shard}', '{replica}', version){{else}}ReplacingMergeTree(version){{end}}
+ORDER BY (client_id, id)
+SETTINGS index_granularity = 8192;
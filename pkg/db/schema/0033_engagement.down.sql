@@ -0,0 +1 @@
+ALTER TABLE "session" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "is_engaged";
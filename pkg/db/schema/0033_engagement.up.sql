@@ -0,0 +1 @@
+ALTER TABLE "session" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} ADD COLUMN "is_engaged" Int8;
@@ -0,0 +1,24 @@
+ALTER TABLE "imported_browser" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_browser_version" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_channel" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_city" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_country" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_device" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_entry_page" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_event" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_exit_page" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_hostname" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_hour" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_language" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_os" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_os_version" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_page" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_referrer" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_region" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_tag" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_utm_campaign" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_utm_content" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_utm_medium" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_utm_source" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_utm_term" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
+ALTER TABLE "imported_visitors" {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} DROP COLUMN "imported_at";
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS segment {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}};
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS segment {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} (
+    `client_id` UInt64,
+    `key` String,
+    `name` String,
+    `condition_events` Array(String),
+    `condition_paths` Array(String),
+    `condition_not` Array(UInt8),
+    `condition_visitor` Array(UInt8),
+    `condition_min_counts` Array(UInt32),
+    `condition_max_counts` Array(UInt32),
+    `deleted` UInt8,
+    `version` DateTime64(3, 'UTC')
+)
+ENGINE = {{if .Cluster}}ReplicatedReplacingMergeTree('/clickhouse/tables/segment/{shard}', '{replica}', version){{else}}ReplacingMergeTree(version){{end}}
+ORDER BY (client_id, key)
+SETTINGS index_granularity = 8192;
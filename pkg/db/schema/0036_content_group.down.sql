@@ -0,0 +1 @@
+DROP TABLE IF EXISTS content_group {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}};
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS content_group {{if .Cluster}}ON CLUSTER '{{.Cluster}}'{{end}} (
+    `client_id` UInt64,
+    `names` Array(String),
+    `path_prefixes` Array(String),
+    `path_patterns` Array(String),
+    `tag_keys` Array(String),
+    `tag_values` Array(String),
+    `version` DateTime64(3, 'UTC')
+)
+ENGINE = {{if .Cluster}}ReplicatedReplacingMergeTree('/clickhouse/tables/content_group/{shard}', '{replica}', version){{else}}ReplacingMergeTree(version){{end}}
+ORDER BY (client_id)
+SETTINGS index_granularity = 8192;
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+const (
+	codeTooManyRows        = 158
+	codeTimeoutExceeded    = 159
+	codeTooSlow            = 160
+	codeMemoryLimit        = 241
+	codeTooManyRowsOrBytes = 396
+)
+
+var (
+	// ErrQueryTooExpensive is returned when a query exceeds one of the configured QuerySettings.
+	// All other query errors wrap this error, so that errors.Is can be used to check for any limit.
+	ErrQueryTooExpensive = errors.New("query too expensive")
+
+	// ErrQueryTimeout is returned when a query exceeds the maximum execution time or the context deadline.
+	ErrQueryTimeout = fmt.Errorf("%w: maximum execution time exceeded", ErrQueryTooExpensive)
+
+	// ErrQueryMemoryLimit is returned when a query exceeds the maximum memory usage.
+	ErrQueryMemoryLimit = fmt.Errorf("%w: maximum memory usage exceeded", ErrQueryTooExpensive)
+
+	// ErrQueryRowLimit is returned when a query exceeds the maximum number of rows to read.
+	ErrQueryRowLimit = fmt.Errorf("%w: maximum rows to read exceeded", ErrQueryTooExpensive)
+)
+
+type querySettingsKey struct{}
+
+// QuerySettings are ClickHouse settings to limit the resources used by a query.
+// Zero values are ignored.
+type QuerySettings struct {
+	// MaxExecutionTime is the maximum time a query can run (max_execution_time).
+	// The time will be rounded up to full seconds.
+	// Note that the ClickHouse driver sets max_execution_time itself if the context has a deadline.
+	MaxExecutionTime time.Duration
+
+	// MaxMemoryUsage is the maximum amount of memory in bytes a query can use on a single server (max_memory_usage).
+	MaxMemoryUsage uint64
+
+	// MaxRowsToRead is the maximum number of rows that can be read from a table (max_rows_to_read).
+	MaxRowsToRead uint64
+
+	// Priority is the priority of the query (priority).
+	// Lower values mean higher priority. Queries with a lower priority are paused while higher priority queries run.
+	Priority uint64
+}
+
+// WithQuerySettings returns a copy of the context overriding the default QuerySettings of the Client.
+// Only fields that are set override the defaults.
+func WithQuerySettings(ctx context.Context, settings QuerySettings) context.Context {
+	if settings == (QuerySettings{}) {
+		return ctx
+	}
+
+	return context.WithValue(ctx, querySettingsKey{}, settings)
+}
+
+func (settings QuerySettings) merge(ctx context.Context) QuerySettings {
+	override, ok := ctx.Value(querySettingsKey{}).(QuerySettings)
+
+	if !ok {
+		return settings
+	}
+
+	if override.MaxExecutionTime > 0 {
+		settings.MaxExecutionTime = override.MaxExecutionTime
+	}
+
+	if override.MaxMemoryUsage > 0 {
+		settings.MaxMemoryUsage = override.MaxMemoryUsage
+	}
+
+	if override.MaxRowsToRead > 0 {
+		settings.MaxRowsToRead = override.MaxRowsToRead
+	}
+
+	if override.Priority > 0 {
+		settings.Priority = override.Priority
+	}
+
+	return settings
+}
+
+func (settings QuerySettings) clickhouse() clickhouse.Settings {
+	result := make(clickhouse.Settings)
+
+	if settings.MaxExecutionTime > 0 {
+		result["max_execution_time"] = int(math.Ceil(settings.MaxExecutionTime.Seconds()))
+	}
+
+	if settings.MaxMemoryUsage > 0 {
+		result["max_memory_usage"] = settings.MaxMemoryUsage
+	}
+
+	if settings.MaxRowsToRead > 0 {
+		result["max_rows_to_read"] = settings.MaxRowsToRead
+	}
+
+	if settings.Priority > 0 {
+		result["priority"] = settings.Priority
+	}
+
+	return result
+}
+
+func queryError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrQueryTimeout, err)
+	}
+
+	var exception *clickhouse.Exception
+
+	if errors.As(err, &exception) {
+		switch exception.Code {
+		case codeTimeoutExceeded, codeTooSlow:
+			return fmt.Errorf("%w: %w", ErrQueryTimeout, err)
+		case codeMemoryLimit:
+			return fmt.Errorf("%w: %w", ErrQueryMemoryLimit, err)
+		case codeTooManyRows, codeTooManyRowsOrBytes:
+			return fmt.Errorf("%w: %w", ErrQueryRowLimit, err)
+		}
+	}
+
+	return err
+}
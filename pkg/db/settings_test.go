@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuerySettings(t *testing.T) {
+	defaults := QuerySettings{
+		MaxExecutionTime: time.Millisecond * 1500,
+		MaxMemoryUsage:   1024,
+		Priority:         1,
+	}
+	ctx := context.Background()
+	assert.Equal(t, ctx, WithQuerySettings(ctx, QuerySettings{}))
+	assert.Equal(t, defaults, defaults.merge(ctx))
+	ctx = WithQuerySettings(ctx, QuerySettings{MaxRowsToRead: 100, Priority: 5})
+	settings := defaults.merge(ctx)
+	assert.Equal(t, QuerySettings{
+		MaxExecutionTime: time.Millisecond * 1500,
+		MaxMemoryUsage:   1024,
+		MaxRowsToRead:    100,
+		Priority:         5,
+	}, settings)
+	assert.Equal(t, clickhouse.Settings{
+		"max_execution_time": 2,
+		"max_memory_usage":   uint64(1024),
+		"max_rows_to_read":   uint64(100),
+		"priority":           uint64(5),
+	}, settings.clickhouse())
+	assert.Empty(t, QuerySettings{}.clickhouse())
+}
+
+func TestQueryError(t *testing.T) {
+	assert.NoError(t, queryError(nil))
+	err := errors.New("error")
+	assert.Equal(t, err, queryError(err))
+	assert.ErrorIs(t, queryError(context.DeadlineExceeded), ErrQueryTimeout)
+	assert.ErrorIs(t, queryError(&clickhouse.Exception{Code: codeTimeoutExceeded}), ErrQueryTimeout)
+	assert.ErrorIs(t, queryError(&clickhouse.Exception{Code: codeMemoryLimit}), ErrQueryMemoryLimit)
+	assert.ErrorIs(t, queryError(&clickhouse.Exception{Code: codeTooManyRows}), ErrQueryRowLimit)
+	assert.ErrorIs(t, queryError(&clickhouse.Exception{Code: codeTooManyRows}), ErrQueryTooExpensive)
+	assert.NotErrorIs(t, queryError(&clickhouse.Exception{Code: 62}), ErrQueryTooExpensive)
+}
+
+func TestClient_QuerySettings(t *testing.T) {
+	CleanupDB(t, dbClient)
+	pageViews := make([]model.PageView, 0, 10)
+
+	for i := range 10 {
+		pageViews = append(pageViews, model.PageView{
+			ClientID:  1,
+			VisitorID: uint64(i),
+			Time:      time.Now(),
+			Path:      "/",
+		})
+	}
+
+	assert.NoError(t, dbClient.SavePageViews(pageViews))
+	count, err := dbClient.Count(context.Background(), "SELECT count(*) FROM page_view WHERE client_id = 1")
+	assert.NoError(t, err)
+	assert.Equal(t, 10, count)
+	ctx := WithQuerySettings(context.Background(), QuerySettings{MaxRowsToRead: 5})
+	_, err = dbClient.SelectOptions(ctx, "SELECT toString(visitor_id) FROM page_view WHERE client_id = 1 ORDER BY visitor_id")
+	assert.ErrorIs(t, err, ErrQueryRowLimit)
+	assert.ErrorIs(t, err, ErrQueryTooExpensive)
+}
@@ -22,6 +22,101 @@ type Store interface {
 	// SaveRequests saves given requests.
 	SaveRequests([]model.Request) error
 
+	// SaveImportedBrowser saves given imported browser statistics.
+	SaveImportedBrowser([]model.ImportedBrowser) error
+
+	// SaveImportedBrowserVersion saves given imported browser version statistics.
+	SaveImportedBrowserVersion([]model.ImportedBrowserVersion) error
+
+	// SaveImportedChannel saves given imported channel statistics.
+	SaveImportedChannel([]model.ImportedChannel) error
+
+	// SaveImportedCity saves given imported city statistics.
+	SaveImportedCity([]model.ImportedCity) error
+
+	// SaveImportedCountry saves given imported country statistics.
+	SaveImportedCountry([]model.ImportedCountry) error
+
+	// SaveImportedDevice saves given imported device statistics.
+	SaveImportedDevice([]model.ImportedDevice) error
+
+	// SaveImportedEntryPage saves given imported entry page statistics.
+	SaveImportedEntryPage([]model.ImportedEntryPage) error
+
+	// SaveImportedEvent saves given imported event statistics.
+	SaveImportedEvent([]model.ImportedEvent) error
+
+	// SaveImportedExitPage saves given imported exit page statistics.
+	SaveImportedExitPage([]model.ImportedExitPage) error
+
+	// SaveImportedHostname saves given imported hostname statistics.
+	SaveImportedHostname([]model.ImportedHostname) error
+
+	// SaveImportedHour saves given imported hourly statistics.
+	SaveImportedHour([]model.ImportedHour) error
+
+	// SaveImportedLanguage saves given imported language statistics.
+	SaveImportedLanguage([]model.ImportedLanguage) error
+
+	// SaveImportedOS saves given imported operating system statistics.
+	SaveImportedOS([]model.ImportedOS) error
+
+	// SaveImportedOSVersion saves given imported operating system version statistics.
+	SaveImportedOSVersion([]model.ImportedOSVersion) error
+
+	// SaveImportedPage saves given imported page statistics.
+	SaveImportedPage([]model.ImportedPage) error
+
+	// SaveImportedReferrer saves given imported referrer statistics.
+	SaveImportedReferrer([]model.ImportedReferrer) error
+
+	// SaveImportedRegion saves given imported region statistics.
+	SaveImportedRegion([]model.ImportedRegion) error
+
+	// SaveImportedTag saves given imported tag statistics.
+	SaveImportedTag([]model.ImportedTag) error
+
+	// SaveImportedUTMCampaign saves given imported UTM campaign statistics.
+	SaveImportedUTMCampaign([]model.ImportedUTMCampaign) error
+
+	// SaveImportedUTMContent saves given imported UTM content statistics.
+	SaveImportedUTMContent([]model.ImportedUTMContent) error
+
+	// SaveImportedUTMMedium saves given imported UTM medium statistics.
+	SaveImportedUTMMedium([]model.ImportedUTMMedium) error
+
+	// SaveImportedUTMSource saves given imported UTM source statistics.
+	SaveImportedUTMSource([]model.ImportedUTMSource) error
+
+	// SaveImportedUTMTerm saves given imported UTM term statistics.
+	SaveImportedUTMTerm([]model.ImportedUTMTerm) error
+
+	// SaveImportedVisitors saves given imported visitor statistics.
+	SaveImportedVisitors([]model.ImportedVisitors) error
+
+	// LatestImport returns the time statistics have last been imported at for given client and date range.
+	// A zero time is returned if there are no imported statistics.
+	LatestImport(context.Context, uint64, time.Time, time.Time) (time.Time, error)
+
+	// DeleteImported removes the imported statistics for given client and date range.
+	// The statistics can be narrowed down to those imported after and up to (including) a point in time. Pass zero times to remove all.
+	DeleteImported(ctx context.Context, clientID uint64, from, to, after, before time.Time) error
+
+	// SaveGoals saves given goals, replacing goals with the same client and ID.
+	SaveGoals(context.Context, []model.Goal) error
+
+	// DeleteGoal removes the goal for given client and ID.
+	DeleteGoal(context.Context, uint64, uint64) error
+
+	// SaveSegments saves given segments, replacing segments with the same client and name (case-insensitive).
+	SaveSegments(context.Context, []model.Segment) error
+
+	// DeleteSegment removes the segment for given client and name (case-insensitive).
+	DeleteSegment(context.Context, uint64, string) error
+
+	// SaveContentGroups saves given content groups, replacing all content groups of the client.
+	SaveContentGroups(context.Context, []model.ContentGroups) error
+
 	// Session returns the last hit for a given client, fingerprint, and maximum age.
 	Session(context.Context, uint64, uint64, time.Time) (*model.Session, error)
 
@@ -70,6 +165,9 @@ type Store interface {
 	// SelectPageStats selects model.PageStats.
 	SelectPageStats(context.Context, bool, bool, string, ...any) ([]model.PageStats, error)
 
+	// SelectPathGroupStats selects model.PathGroupStats.
+	SelectPathGroupStats(context.Context, string, ...any) ([]model.PathGroupStats, error)
+
 	// SelectAvgTimeSpentStats selects model.AvgTimeSpentStats.
 	SelectAvgTimeSpentStats(context.Context, string, ...any) ([]model.AvgTimeSpentStats, error)
 
@@ -160,6 +258,51 @@ type Store interface {
 	// SelectEvents selects events.
 	SelectEvents(context.Context, string, ...any) ([]model.Event, error)
 
+	// ExportPageViews selects page views including all columns.
+	ExportPageViews(context.Context, string, ...any) ([]model.PageView, error)
+
+	// ExportSessions selects sessions including all columns.
+	ExportSessions(context.Context, string, ...any) ([]model.Session, error)
+
+	// ExportEvents selects events including all columns.
+	ExportEvents(context.Context, string, ...any) ([]model.Event, error)
+
 	// SelectFunnelSteps selects funnel steps.
 	SelectFunnelSteps(context.Context, string, ...any) ([]model.FunnelStep, error)
+
+	// SelectFunnelBreakdownSteps selects funnel steps grouped by a dimension.
+	SelectFunnelBreakdownSteps(context.Context, string, ...any) ([]model.FunnelBreakdownStep, error)
+
+	// SelectPageTransitionStats selects page transition statistics.
+	SelectPageTransitionStats(context.Context, string, ...any) ([]model.PageTransitionStats, error)
+
+	// SelectPageFlowSteps selects page flow steps.
+	SelectPageFlowSteps(context.Context, string, ...any) ([]model.PageFlowStep, error)
+
+	// SelectGoals selects goals.
+	SelectGoals(context.Context, string, ...any) ([]model.Goal, error)
+
+	// SelectGoalStats selects goal conversions.
+	SelectGoalStats(context.Context, string, ...any) ([]model.GoalStats, error)
+
+	// SelectGoalBreakdownStats selects goal conversions grouped by a dimension.
+	SelectGoalBreakdownStats(context.Context, string, ...any) ([]model.GoalBreakdownStats, error)
+
+	// SelectGoalPeriodStats selects goal conversions grouped by period.
+	SelectGoalPeriodStats(context.Context, pkg.Period, string, ...any) ([]model.GoalPeriodStats, error)
+
+	// SelectSegments selects segments.
+	SelectSegments(context.Context, string, ...any) ([]model.Segment, error)
+
+	// SelectContentGroups selects content groups.
+	SelectContentGroups(context.Context, string, ...any) ([]model.ContentGroups, error)
+
+	// GetPercentileStats returns the model.PercentileStats.
+	GetPercentileStats(context.Context, string, ...any) (*model.PercentileStats, error)
+
+	// SelectHistogramBuckets selects histogram buckets.
+	SelectHistogramBuckets(context.Context, string, ...any) ([]model.HistogramBucket, error)
+
+	// SelectBreakdownRows selects rows for the given number of dimensions followed by the metrics.
+	SelectBreakdownRows(context.Context, int, string, ...any) ([]model.BreakdownRow, error)
 }
@@ -42,7 +42,7 @@ func Disconnect(client *Client) {
 }
 
 // CleanupDB clears all database tables.
-func CleanupDB(t *testing.T, client *Client) {
+func CleanupDB(t testing.TB, client *Client) {
 	if !client.dev {
 		panic("client not in dev mode")
 	}
@@ -66,7 +66,19 @@ func CleanupDB(t *testing.T, client *Client) {
 		"imported_referrer",
 		"imported_region",
 		"imported_utm_source",
+		"imported_browser_version",
+		"imported_channel",
+		"imported_event",
+		"imported_hostname",
+		"imported_hour",
+		"imported_os_version",
+		"imported_tag",
+		"imported_utm_content",
+		"imported_utm_term",
 		"imported_visitors",
+		"goal",
+		"segment",
+		"content_group",
 	}
 	var wg sync.WaitGroup
 	wg.Add(len(tables))
@@ -119,6 +131,15 @@ func DropDB(t *testing.T, client *Client) {
 		"imported_referrer",
 		"imported_region",
 		"imported_utm_source",
+		"imported_browser_version",
+		"imported_channel",
+		"imported_event",
+		"imported_hostname",
+		"imported_hour",
+		"imported_os_version",
+		"imported_tag",
+		"imported_utm_content",
+		"imported_utm_term",
 		"imported_visitors",
 	}
 
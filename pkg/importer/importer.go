@@ -0,0 +1,785 @@
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+)
+
+const (
+	// GoogleAnalytics is a CSV export from Google Analytics (Universal Analytics or GA4).
+	GoogleAnalytics = Source("google_analytics")
+
+	// Plausible is a CSV export from Plausible Analytics.
+	Plausible = Source("plausible")
+)
+
+const (
+	columnDate               = "date"
+	columnPath               = "path"
+	columnEntryPath          = "entry_path"
+	columnExitPath           = "exit_path"
+	columnReferrer           = "referrer"
+	columnUTMSource          = "utm_source"
+	columnUTMMedium          = "utm_medium"
+	columnUTMCampaign        = "utm_campaign"
+	columnUTMContent         = "utm_content"
+	columnUTMTerm            = "utm_term"
+	columnChannel            = "channel"
+	columnHostname           = "hostname"
+	columnEventName          = "event_name"
+	columnHour               = "hour"
+	columnCountryCode        = "country_code"
+	columnRegion             = "region"
+	columnCity               = "city"
+	columnBrowser            = "browser"
+	columnOS                 = "os"
+	columnOSVersion          = "os_version"
+	columnBrowserVersion     = "browser_version"
+	columnCategory           = "category"
+	columnLanguage           = "language"
+	columnVisitors           = "visitors"
+	columnViews              = "views"
+	columnSessions           = "sessions"
+	columnBounces            = "bounces"
+	columnEvents             = "events"
+	columnSessionDuration    = "session_duration"
+	columnAvgSessionDuration = "avg_session_duration"
+)
+
+var (
+	// ErrUnknownSource is returned if the export source is not supported.
+	ErrUnknownSource = errors.New("unknown source")
+
+	// ErrNoDateColumn is returned if a CSV file has no date column.
+	ErrNoDateColumn = errors.New("date column missing")
+
+	// ErrInvalidDateRange is returned if the end date is before the start date.
+	ErrInvalidDateRange = errors.New("invalid date range")
+
+	// columns maps normalized CSV header names to the imported table columns.
+	// Headers are normalized by removing the "ga:" prefix, spaces, underscores, and dots, and converting them to lowercase.
+	columns = map[string]string{
+		"date":                       columnDate,
+		"day":                        columnDate,
+		"page":                       columnPath,
+		"pagepath":                   columnPath,
+		"pagepathandscreenclass":     columnPath,
+		"landingpage":                columnEntryPath,
+		"entrypage":                  columnEntryPath,
+		"exitpage":                   columnExitPath,
+		"source":                     columnReferrer,
+		"sessionsource":              columnReferrer,
+		"utmsource":                  columnUTMSource,
+		"medium":                     columnUTMMedium,
+		"sessionmedium":              columnUTMMedium,
+		"utmmedium":                  columnUTMMedium,
+		"campaign":                   columnUTMCampaign,
+		"sessioncampaignname":        columnUTMCampaign,
+		"utmcampaign":                columnUTMCampaign,
+		"adcontent":                  columnUTMContent,
+		"sessionmanualadcontent":     columnUTMContent,
+		"utmcontent":                 columnUTMContent,
+		"keyword":                    columnUTMTerm,
+		"sessionmanualterm":          columnUTMTerm,
+		"utmterm":                    columnUTMTerm,
+		"channelgrouping":            columnChannel,
+		"defaultchannelgroup":        columnChannel,
+		"sessiondefaultchannelgroup": columnChannel,
+		"hostname":                   columnHostname,
+		"eventname":                  columnEventName,
+		"hour":                       columnHour,
+		"country":                    columnCountryCode,
+		"countryisocode":             columnCountryCode,
+		"countryid":                  columnCountryCode,
+		"region":                     columnRegion,
+		"city":                       columnCity,
+		"browser":                    columnBrowser,
+		"browserversion":             columnBrowserVersion,
+		"operatingsystem":            columnOS,
+		"operatingsystemversion":     columnOSVersion,
+		"devicecategory":             columnCategory,
+		"device":                     columnCategory,
+		"language":                   columnLanguage,
+		"users":                      columnVisitors,
+		"totalusers":                 columnVisitors,
+		"activeusers":                columnVisitors,
+		"visitors":                   columnVisitors,
+		"pageviews":                  columnViews,
+		"views":                      columnViews,
+		"sessions":                   columnSessions,
+		"visits":                     columnSessions,
+		"entrances":                  columnSessions,
+		"exits":                      columnSessions,
+		"bounces":                    columnBounces,
+		"eventcount":                 columnEvents,
+		"totalevents":                columnEvents,
+		"events":                     columnEvents,
+		"sessionduration":            columnSessionDuration,
+		"visitduration":              columnSessionDuration,
+		"avgsessionduration":         columnAvgSessionDuration,
+		"averagesessionduration":     columnAvgSessionDuration,
+	}
+
+	// dimensions are the columns stored in a table of their own.
+	dimensions = []string{
+		columnPath,
+		columnEntryPath,
+		columnExitPath,
+		columnReferrer,
+		columnUTMSource,
+		columnUTMMedium,
+		columnUTMCampaign,
+		columnUTMContent,
+		columnUTMTerm,
+		columnChannel,
+		columnHostname,
+		columnEventName,
+		columnHour,
+		columnCountryCode,
+		columnRegion,
+		columnCity,
+		columnBrowser,
+		columnBrowserVersion,
+		columnOS,
+		columnOSVersion,
+		columnCategory,
+		columnLanguage,
+	}
+
+	// versions maps dimensions to the dimension they are a version of.
+	versions = map[string]string{
+		columnBrowserVersion: columnBrowser,
+		columnOSVersion:      columnOS,
+	}
+
+	// emptyValues are placeholders used by Google Analytics for unknown values.
+	emptyValues = []string{
+		"(direct)",
+		"(none)",
+		"(not set)",
+		"(not provided)",
+		"(other)",
+	}
+
+	// dateFormats are the supported date formats.
+	dateFormats = []string{
+		"20060102",
+		time.DateOnly,
+		"01/02/2006",
+		"1/2/06",
+	}
+)
+
+// Source is the analytics tool a CSV file was exported from.
+type Source string
+
+// Importer fills the imported statistics tables from CSV exports of other analytics tools.
+type Importer struct {
+	store db.Store
+}
+
+// NewImporter creates a new Importer for given Store.
+func NewImporter(store db.Store) *Importer {
+	return &Importer{
+		store: store,
+	}
+}
+
+// Import reads the CSV files and saves the statistics for given client and date range.
+// Rows outside the date range are ignored.
+// All previously imported statistics for the client in the date range are replaced, so that an import can safely be repeated.
+// They are removed after the new statistics have been saved. If saving fails, the new statistics are removed instead.
+//
+// The table a file is imported into is detected from its header.
+// Google Analytics exports require a date column and one or more dimensions (page, landing page, source, country ISO code, ...).
+// Plausible exports are expected to be the unzipped CSV files (imported_visitors.csv, imported_pages.csv, ...).
+// Regions and cities are skipped for Plausible, as they are exported as ISO and GeoNames codes.
+// Hostnames are only imported from files without any other dimension, as the visitor count cannot be summed up per page.
+func (importer *Importer) Import(ctx context.Context, clientID uint64, from, to time.Time, source Source, files ...io.Reader) error {
+	if source != GoogleAnalytics && source != Plausible {
+		return ErrUnknownSource
+	}
+
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+
+	if to.Before(from) {
+		return ErrInvalidDateRange
+	}
+
+	data := newImportedData()
+
+	for _, file := range files {
+		if err := data.read(file, source, from, to); err != nil {
+			return err
+		}
+	}
+
+	latest, err := importer.store.LatestImport(ctx, clientID, from, to)
+
+	if err != nil {
+		return err
+	}
+
+	if err := data.save(importer.store, clientID); err != nil {
+		return errors.Join(err, importer.store.DeleteImported(ctx, clientID, from, to, latest, time.Time{}))
+	}
+
+	if latest.IsZero() {
+		return nil
+	}
+
+	return importer.store.DeleteImported(ctx, clientID, from, to, time.Time{}, latest)
+}
+
+type metrics struct {
+	visitors        int
+	views           int
+	sessions        int
+	bounces         int
+	sessionDuration int
+	events          int
+}
+
+type importedKey struct {
+	date   time.Time
+	parent string
+	value  string
+}
+
+type importedData struct {
+	tables map[string]map[importedKey]*metrics
+}
+
+func newImportedData() *importedData {
+	return &importedData{
+		tables: make(map[string]map[importedKey]*metrics),
+	}
+}
+
+func (data *importedData) read(file io.Reader, source Source, from, to time.Time) error {
+	r := csv.NewReader(file)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	header, err := r.Read()
+
+	if err != nil {
+		return err
+	}
+
+	mapping := make(map[int]string)
+
+	for i, name := range header {
+		if column, ok := columns[normalizeHeader(name)]; ok {
+			// the first column wins if multiple columns are mapped to the same one (like visits and exits)
+			if mappingContains(mapping, column) ||
+				source == Plausible && (column == columnRegion || column == columnCity) {
+				continue
+			}
+
+			mapping[i] = column
+		}
+	}
+
+	if !mappingContains(mapping, columnDate) {
+		return ErrNoDateColumn
+	}
+
+	for {
+		record, err := r.Read()
+
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		values := make(map[string]string)
+
+		for i, column := range mapping {
+			if i < len(record) {
+				values[column] = strings.TrimSpace(record[i])
+			}
+		}
+
+		// skip empty lines and totals
+		if values[columnDate] == "" {
+			continue
+		}
+
+		date, err := parseDate(values[columnDate])
+
+		if err != nil {
+			return err
+		}
+
+		if date.Before(from) || date.After(to) {
+			continue
+		}
+
+		m, err := parseMetrics(values)
+
+		if err != nil {
+			return err
+		}
+
+		data.add(date, values, m)
+	}
+
+	return nil
+}
+
+func (data *importedData) add(date time.Time, values map[string]string, m *metrics) {
+	hasDimension := false
+
+	for _, dimension := range dimensions {
+		value, ok := values[dimension]
+
+		if !ok {
+			continue
+		}
+
+		hasDimension = true
+		value = normalizeValue(dimension, value)
+
+		if value == "" && dimension != columnReferrer ||
+			dimension == columnHostname && hasOtherDimension(values, columnHostname) {
+			continue
+		}
+
+		parent := ""
+
+		if column, ok := versions[dimension]; ok {
+			parent = normalizeValue(column, values[column])
+		}
+
+		data.sum(dimension, importedKey{date, parent, value}, m)
+	}
+
+	if !hasDimension {
+		data.sum(columnDate, importedKey{date: date}, m)
+	}
+}
+
+func (data *importedData) sum(table string, key importedKey, m *metrics) {
+	if data.tables[table] == nil {
+		data.tables[table] = make(map[importedKey]*metrics)
+	}
+
+	entry, ok := data.tables[table][key]
+
+	if !ok {
+		entry = new(metrics)
+		data.tables[table][key] = entry
+	}
+
+	entry.visitors += m.visitors
+	entry.views += m.views
+	entry.sessions += m.sessions
+	entry.bounces += m.bounces
+	entry.sessionDuration += m.sessionDuration
+	entry.events += m.events
+}
+
+func (data *importedData) keys(table string) []importedKey {
+	keys := make([]importedKey, 0, len(data.tables[table]))
+
+	for key := range data.tables[table] {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].date.Equal(keys[j].date) {
+			if keys[i].parent == keys[j].parent {
+				return keys[i].value < keys[j].value
+			}
+
+			return keys[i].parent < keys[j].parent
+		}
+
+		return keys[i].date.Before(keys[j].date)
+	})
+	return keys
+}
+
+func (data *importedData) save(store db.Store, clientID uint64) error {
+	if err := store.SaveImportedVisitors(convert(data, columnDate, func(key importedKey, m *metrics) model.ImportedVisitors {
+		return model.ImportedVisitors{
+			ClientID:        clientID,
+			Date:            key.date,
+			Visitors:        m.visitors,
+			Views:           m.views,
+			Sessions:        m.sessions,
+			Bounces:         m.bounces,
+			SessionDuration: m.sessionDuration,
+		}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedPage(convert(data, columnPath, func(key importedKey, m *metrics) model.ImportedPage {
+		return model.ImportedPage{
+			ClientID: clientID,
+			Date:     key.date,
+			Path:     key.value,
+			Visitors: m.visitors,
+			Views:    m.views,
+			Sessions: m.sessions,
+			Bounces:  m.bounces,
+		}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedEntryPage(convert(data, columnEntryPath, func(key importedKey, m *metrics) model.ImportedEntryPage {
+		return model.ImportedEntryPage{
+			ClientID:  clientID,
+			Date:      key.date,
+			EntryPath: key.value,
+			Visitors:  m.visitors,
+			Sessions:  m.sessions,
+		}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedExitPage(convert(data, columnExitPath, func(key importedKey, m *metrics) model.ImportedExitPage {
+		return model.ImportedExitPage{
+			ClientID: clientID,
+			Date:     key.date,
+			ExitPath: key.value,
+			Visitors: m.visitors,
+			Sessions: m.sessions,
+		}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedReferrer(convert(data, columnReferrer, func(key importedKey, m *metrics) model.ImportedReferrer {
+		return model.ImportedReferrer{
+			ClientID: clientID,
+			Date:     key.date,
+			Referrer: key.value,
+			Visitors: m.visitors,
+			Sessions: m.sessions,
+			Bounces:  m.bounces,
+		}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedUTMSource(convert(data, columnUTMSource, func(key importedKey, m *metrics) model.ImportedUTMSource {
+		return model.ImportedUTMSource{ClientID: clientID, Date: key.date, UTMSource: key.value, Visitors: m.visitors}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedUTMMedium(convert(data, columnUTMMedium, func(key importedKey, m *metrics) model.ImportedUTMMedium {
+		return model.ImportedUTMMedium{ClientID: clientID, Date: key.date, UTMMedium: key.value, Visitors: m.visitors}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedUTMCampaign(convert(data, columnUTMCampaign, func(key importedKey, m *metrics) model.ImportedUTMCampaign {
+		return model.ImportedUTMCampaign{ClientID: clientID, Date: key.date, UTMCampaign: key.value, Visitors: m.visitors}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedUTMContent(convert(data, columnUTMContent, func(key importedKey, m *metrics) model.ImportedUTMContent {
+		return model.ImportedUTMContent{ClientID: clientID, Date: key.date, UTMContent: key.value, Visitors: m.visitors}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedUTMTerm(convert(data, columnUTMTerm, func(key importedKey, m *metrics) model.ImportedUTMTerm {
+		return model.ImportedUTMTerm{ClientID: clientID, Date: key.date, UTMTerm: key.value, Visitors: m.visitors}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedChannel(convert(data, columnChannel, func(key importedKey, m *metrics) model.ImportedChannel {
+		return model.ImportedChannel{
+			ClientID: clientID,
+			Date:     key.date,
+			Channel:  key.value,
+			Visitors: m.visitors,
+			Views:    m.views,
+			Sessions: m.sessions,
+			Bounces:  m.bounces,
+		}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedHostname(convert(data, columnHostname, func(key importedKey, m *metrics) model.ImportedHostname {
+		return model.ImportedHostname{
+			ClientID: clientID,
+			Date:     key.date,
+			Hostname: key.value,
+			Visitors: m.visitors,
+			Views:    m.views,
+			Sessions: m.sessions,
+			Bounces:  m.bounces,
+		}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedEvent(convert(data, columnEventName, func(key importedKey, m *metrics) model.ImportedEvent {
+		return model.ImportedEvent{
+			ClientID:  clientID,
+			Date:      key.date,
+			EventName: key.value,
+			Visitors:  m.visitors,
+			Views:     m.views,
+			Events:    m.events,
+		}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedHour(convert(data, columnHour, func(key importedKey, m *metrics) model.ImportedHour {
+		// the value has been validated by normalizeValue
+		hour, _ := strconv.Atoi(key.value)
+		return model.ImportedHour{
+			ClientID: clientID,
+			Date:     key.date,
+			Hour:     hour,
+			Visitors: m.visitors,
+			Views:    m.views,
+			Sessions: m.sessions,
+			Bounces:  m.bounces,
+		}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedCountry(convert(data, columnCountryCode, func(key importedKey, m *metrics) model.ImportedCountry {
+		return model.ImportedCountry{ClientID: clientID, Date: key.date, CountryCode: key.value, Visitors: m.visitors}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedRegion(convert(data, columnRegion, func(key importedKey, m *metrics) model.ImportedRegion {
+		return model.ImportedRegion{ClientID: clientID, Date: key.date, Region: key.value, Visitors: m.visitors}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedCity(convert(data, columnCity, func(key importedKey, m *metrics) model.ImportedCity {
+		return model.ImportedCity{ClientID: clientID, Date: key.date, City: key.value, Visitors: m.visitors}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedBrowser(convert(data, columnBrowser, func(key importedKey, m *metrics) model.ImportedBrowser {
+		return model.ImportedBrowser{ClientID: clientID, Date: key.date, Browser: key.value, Visitors: m.visitors}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedBrowserVersion(convert(data, columnBrowserVersion, func(key importedKey, m *metrics) model.ImportedBrowserVersion {
+		return model.ImportedBrowserVersion{ClientID: clientID, Date: key.date, Browser: key.parent, BrowserVersion: key.value, Visitors: m.visitors}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedOS(convert(data, columnOS, func(key importedKey, m *metrics) model.ImportedOS {
+		return model.ImportedOS{ClientID: clientID, Date: key.date, OS: key.value, Visitors: m.visitors}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedOSVersion(convert(data, columnOSVersion, func(key importedKey, m *metrics) model.ImportedOSVersion {
+		return model.ImportedOSVersion{ClientID: clientID, Date: key.date, OS: key.parent, OSVersion: key.value, Visitors: m.visitors}
+	})); err != nil {
+		return err
+	}
+
+	if err := store.SaveImportedDevice(convert(data, columnCategory, func(key importedKey, m *metrics) model.ImportedDevice {
+		return model.ImportedDevice{ClientID: clientID, Date: key.date, Category: key.value, Visitors: m.visitors}
+	})); err != nil {
+		return err
+	}
+
+	return store.SaveImportedLanguage(convert(data, columnLanguage, func(key importedKey, m *metrics) model.ImportedLanguage {
+		return model.ImportedLanguage{ClientID: clientID, Date: key.date, Language: key.value, Visitors: m.visitors}
+	}))
+}
+
+func convert[T any](data *importedData, table string, row func(importedKey, *metrics) T) []T {
+	keys := data.keys(table)
+	rows := make([]T, 0, len(keys))
+
+	for _, key := range keys {
+		rows = append(rows, row(key, data.tables[table][key]))
+	}
+
+	return rows
+}
+
+func parseMetrics(values map[string]string) (*metrics, error) {
+	var m metrics
+	var err error
+
+	if m.visitors, err = parseInt(values[columnVisitors]); err != nil {
+		return nil, err
+	}
+
+	if m.views, err = parseInt(values[columnViews]); err != nil {
+		return nil, err
+	}
+
+	if m.sessions, err = parseInt(values[columnSessions]); err != nil {
+		return nil, err
+	}
+
+	if m.bounces, err = parseInt(values[columnBounces]); err != nil {
+		return nil, err
+	}
+
+	if m.events, err = parseInt(values[columnEvents]); err != nil {
+		return nil, err
+	}
+
+	if m.sessionDuration, err = parseDuration(values[columnSessionDuration]); err != nil {
+		return nil, err
+	}
+
+	if m.sessionDuration == 0 {
+		avg, err := parseDuration(values[columnAvgSessionDuration])
+
+		if err != nil {
+			return nil, err
+		}
+
+		m.sessionDuration = avg * m.sessions
+	}
+
+	return &m, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	for _, format := range dateFormats {
+		if date, err := time.Parse(format, value); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date: %s", value)
+}
+
+func parseInt(value string) (int, error) {
+	value = strings.ReplaceAll(value, ",", "")
+
+	if value == "" {
+		return 0, nil
+	}
+
+	if i, err := strconv.Atoi(value); err == nil {
+		return i, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid number: %s", value)
+	}
+
+	return int(f), nil
+}
+
+// parseDuration parses a duration in seconds or in the format hh:mm:ss.
+func parseDuration(value string) (int, error) {
+	if !strings.Contains(value, ":") {
+		return parseInt(value)
+	}
+
+	seconds := 0
+
+	for _, part := range strings.Split(value, ":") {
+		i, err := strconv.Atoi(part)
+
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+
+		seconds = seconds*60 + i
+	}
+
+	return seconds, nil
+}
+
+func normalizeHeader(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimPrefix(name, "ga:")
+	return strings.NewReplacer(" ", "", "_", "", ".", "", "-", "").Replace(name)
+}
+
+func normalizeValue(dimension, value string) string {
+	for _, empty := range emptyValues {
+		if strings.EqualFold(value, empty) {
+			return ""
+		}
+	}
+
+	switch dimension {
+	case columnCountryCode:
+		if len(value) != 2 {
+			return ""
+		}
+
+		return strings.ToLower(value)
+	case columnCategory:
+		return strings.ToLower(value)
+	case columnLanguage:
+		// en-us -> en
+		return strings.ToLower(strings.Split(value, "-")[0])
+	case columnReferrer:
+		if strings.EqualFold(value, "Direct / None") {
+			return ""
+		}
+	case columnHour:
+		hour, err := strconv.Atoi(value)
+
+		if err != nil || hour < 0 || hour > 23 {
+			return ""
+		}
+
+		return strconv.Itoa(hour)
+	}
+
+	return value
+}
+
+func hasOtherDimension(values map[string]string, dimension string) bool {
+	for _, d := range dimensions {
+		if _, ok := values[d]; ok && d != dimension {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mappingContains(mapping map[int]string, column string) bool {
+	for _, c := range mapping {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,208 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImporter_Import(t *testing.T) {
+	importer := NewImporter(db.NewClientMock())
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	assert.ErrorIs(t, importer.Import(context.Background(), 1, from, to, "matomo"), ErrUnknownSource)
+	assert.ErrorIs(t, importer.Import(context.Background(), 1, to, from, Plausible), ErrInvalidDateRange)
+	assert.ErrorIs(t, importer.Import(context.Background(), 1, from, to, Plausible, strings.NewReader("page,visitors\n/,1")), ErrNoDateColumn)
+	assert.NoError(t, importer.Import(context.Background(), 1, from, to, Plausible, strings.NewReader("date,visitors,pageviews,bounces,visits,visit_duration\n2024-01-01,1,2,0,1,42")))
+}
+
+func TestImporter_ImportReplace(t *testing.T) {
+	latest := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	store := &importStore{ClientMock: db.NewClientMock(), latest: latest}
+	importer := NewImporter(store)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	file := "date,visitors,pageviews,bounces,visits,visit_duration\n2024-01-01,1,2,0,1,42"
+	assert.NoError(t, importer.Import(context.Background(), 1, from, to, Plausible, strings.NewReader(file)))
+	assert.Equal(t, [][2]time.Time{{{}, latest}}, store.deleted)
+	store.deleted = nil
+	store.err = errors.New("insert failed")
+	assert.ErrorIs(t, importer.Import(context.Background(), 1, from, to, Plausible, strings.NewReader(file)), store.err)
+	assert.Equal(t, [][2]time.Time{{latest, {}}}, store.deleted)
+	store.deleted = nil
+	store.err = nil
+	store.latest = time.Time{}
+	assert.NoError(t, importer.Import(context.Background(), 1, from, to, Plausible, strings.NewReader(file)))
+	assert.Empty(t, store.deleted)
+}
+
+type importStore struct {
+	*db.ClientMock
+	latest  time.Time
+	err     error
+	deleted [][2]time.Time
+}
+
+func (store *importStore) LatestImport(context.Context, uint64, time.Time, time.Time) (time.Time, error) {
+	return store.latest, nil
+}
+
+func (store *importStore) SaveImportedVisitors([]model.ImportedVisitors) error {
+	return store.err
+}
+
+func (store *importStore) DeleteImported(_ context.Context, _ uint64, _, _, after, before time.Time) error {
+	store.deleted = append(store.deleted, [2]time.Time{after, before})
+	return nil
+}
+
+func TestImportedData_Plausible(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	data := newImportedData()
+	assert.NoError(t, data.read(strings.NewReader(`date,visitors,pageviews,bounces,visits,visit_duration
+2024-01-01,10,25,3,12,600
+2024-01-02,5,8,1,6,120
+2024-01-03,99,99,99,99,99`), Plausible, from, to))
+	assert.NoError(t, data.read(strings.NewReader(`date,hostname,page,visits,visitors,pageviews,exits
+2024-01-01,example.com,/,8,7,12,5
+2024-01-01,www.example.com,/,2,2,3,1`), Plausible, from, to))
+	assert.NoError(t, data.read(strings.NewReader(`date,source,referrer,utm_source,utm_medium,utm_campaign,utm_content,utm_term,pageviews,visitors,visits,visit_duration,bounces
+2024-01-01,,,,,,,,5,4,4,100,2
+2024-01-01,Google,https://google.com,newsletter,email,,,,3,2,2,50,1`), Plausible, from, to))
+	assert.NoError(t, data.read(strings.NewReader(`date,country,region,city,pageviews,visitors,visits,visit_duration,bounces
+2024-01-01,DE,DE-BE,2950159,4,3,3,10,1`), Plausible, from, to))
+	assert.NoError(t, data.read(strings.NewReader(`date,device,pageviews,visitors,visits,visit_duration,bounces
+2024-01-01,Desktop,4,3,3,10,1`), Plausible, from, to))
+	visitors := data.keys(columnDate)
+	assert.Len(t, visitors, 2)
+	assert.Equal(t, metrics{10, 25, 12, 3, 600, 0}, *data.tables[columnDate][visitors[0]])
+	assert.Equal(t, metrics{5, 8, 6, 1, 120, 0}, *data.tables[columnDate][visitors[1]])
+	pages := data.keys(columnPath)
+	assert.Len(t, pages, 1)
+	assert.Equal(t, metrics{9, 15, 10, 0, 0, 0}, *data.tables[columnPath][pages[0]])
+	referrer := data.keys(columnReferrer)
+	assert.Len(t, referrer, 2)
+	assert.Equal(t, "", referrer[0].value)
+	assert.Equal(t, "Google", referrer[1].value)
+	assert.Len(t, data.keys(columnUTMSource), 1)
+	assert.Len(t, data.keys(columnUTMMedium), 1)
+	assert.Empty(t, data.keys(columnUTMCampaign))
+	countries := data.keys(columnCountryCode)
+	assert.Len(t, countries, 1)
+	assert.Equal(t, "de", countries[0].value)
+	assert.Empty(t, data.keys(columnRegion))
+	assert.Empty(t, data.keys(columnCity))
+	devices := data.keys(columnCategory)
+	assert.Len(t, devices, 1)
+	assert.Equal(t, "desktop", devices[0].value)
+}
+
+func TestImportedData_GoogleAnalytics(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	data := newImportedData()
+	assert.NoError(t, data.read(strings.NewReader(`# ----------------------------------------
+# All Web Site Data
+# Landing Pages
+# 20240101-20240131
+# ----------------------------------------
+
+Date,Landing Page,Users,Sessions,Avg. Session Duration
+20240101,/,"1,200","1,500",00:01:10
+20240101,/blog,20,25,00:00:30
+,,"1,220","1,525",00:01:09`), GoogleAnalytics, from, to))
+	assert.NoError(t, data.read(strings.NewReader(`ga:date,ga:source,ga:medium,ga:users,ga:sessions,ga:bounces
+20240102,(direct),(none),10,12,4
+20240102,google,organic,5,6,2`), GoogleAnalytics, from, to))
+	assert.NoError(t, data.read(strings.NewReader(`Date,Country ISO Code,Language,Total users
+20240103,US,en-us,7
+20240103,(not set),de,1`), GoogleAnalytics, from, to))
+	entries := data.keys(columnEntryPath)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "/", entries[0].value)
+	assert.Equal(t, metrics{1200, 0, 1500, 0, 105000, 0}, *data.tables[columnEntryPath][entries[0]])
+	referrer := data.keys(columnReferrer)
+	assert.Len(t, referrer, 2)
+	assert.Equal(t, "", referrer[0].value)
+	assert.Equal(t, 12, data.tables[columnReferrer][referrer[0]].sessions)
+	mediums := data.keys(columnUTMMedium)
+	assert.Len(t, mediums, 1)
+	assert.Equal(t, "organic", mediums[0].value)
+	countries := data.keys(columnCountryCode)
+	assert.Len(t, countries, 1)
+	assert.Equal(t, "us", countries[0].value)
+	languages := data.keys(columnLanguage)
+	assert.Len(t, languages, 2)
+	assert.Equal(t, "de", languages[0].value)
+	assert.Equal(t, "en", languages[1].value)
+	assert.Empty(t, data.keys(columnDate))
+	assert.Error(t, data.read(strings.NewReader("Date,Users\nyesterday,1"), GoogleAnalytics, from, to))
+}
+
+func TestImportedData_Dimensions(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	data := newImportedData()
+	assert.NoError(t, data.read(strings.NewReader(`Date,Operating system,Operating system version,Total users
+20240101,Windows,10,5
+20240101,Windows,11,3
+20240101,Android,14,2`), GoogleAnalytics, from, to))
+	assert.NoError(t, data.read(strings.NewReader(`Date,Hour,Total users,Views,Sessions,Bounces
+20240101,00,2,4,3,1
+20240101,07,1,1,1,0
+20240101,24,9,9,9,9`), GoogleAnalytics, from, to))
+	assert.NoError(t, data.read(strings.NewReader(`Date,Event name,Total users,Event count
+20240101,sign_up,4,6`), GoogleAnalytics, from, to))
+	assert.NoError(t, data.read(strings.NewReader(`Date,Hostname,Total users
+20240101,example.com,8`), GoogleAnalytics, from, to))
+	assert.NoError(t, data.read(strings.NewReader(`Date,Hostname,Page path,Total users
+20240101,example.com,/,8`), GoogleAnalytics, from, to))
+	assert.NoError(t, data.read(strings.NewReader(`Date,Session default channel group,Session manual ad content,Session manual term,Total users
+20240101,Organic Search,banner,shoes,3`), GoogleAnalytics, from, to))
+	os := data.keys(columnOS)
+	assert.Len(t, os, 2)
+	assert.Equal(t, 8, data.tables[columnOS][os[1]].visitors)
+	versions := data.keys(columnOSVersion)
+	assert.Len(t, versions, 3)
+	assert.Equal(t, "Android", versions[0].parent)
+	assert.Equal(t, "14", versions[0].value)
+	assert.Equal(t, "Windows", versions[2].parent)
+	assert.Equal(t, "11", versions[2].value)
+	hours := data.keys(columnHour)
+	assert.Len(t, hours, 2)
+	assert.Equal(t, "0", hours[0].value)
+	assert.Equal(t, "7", hours[1].value)
+	events := data.keys(columnEventName)
+	assert.Len(t, events, 1)
+	assert.Equal(t, metrics{4, 0, 0, 0, 0, 6}, *data.tables[columnEventName][events[0]])
+	hostnames := data.keys(columnHostname)
+	assert.Len(t, hostnames, 1)
+	assert.Equal(t, 8, data.tables[columnHostname][hostnames[0]].visitors)
+	assert.Len(t, data.keys(columnChannel), 1)
+	assert.Len(t, data.keys(columnUTMContent), 1)
+	assert.Len(t, data.keys(columnUTMTerm), 1)
+}
+
+func TestParseDuration(t *testing.T) {
+	for input, expected := range map[string]int{
+		"":         0,
+		"42":       42,
+		"42.7":     42,
+		"00:01:10": 70,
+		"1:00:00":  3600,
+	} {
+		d, err := parseDuration(input)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, d)
+	}
+
+	_, err := parseDuration("a:b")
+	assert.Error(t, err)
+}
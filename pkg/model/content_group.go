@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// ContentGroups is the list of content groups for a client in the order they are matched.
+type ContentGroups struct {
+	ClientID uint64         `db:"client_id" json:"client_id"`
+	Groups   []ContentGroup `json:"groups"`
+	Version  time.Time      `json:"version"`
+}
+
+// ContentGroup maps pages to a named group.
+type ContentGroup struct {
+	Name        string `json:"name"`
+	PathPrefix  string `db:"path_prefix" json:"path_prefix,omitempty"`
+	PathPattern string `db:"path_pattern" json:"path_pattern,omitempty"`
+	TagKey      string `db:"tag_key" json:"tag_key,omitempty"`
+	TagValue    string `db:"tag_value" json:"tag_value,omitempty"`
+}
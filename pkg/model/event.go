@@ -8,37 +8,37 @@ import (
 // Event represents a single data point for custom events.
 // It's basically the same as Session, but with some additional fields (event name, time, and meta fields).
 type Event struct {
-	ClientID        uint64    `db:"client_id" json:"client_id"`
-	VisitorID       uint64    `db:"visitor_id" json:"visitor_id"`
-	Time            time.Time `json:"time"`
-	SessionID       uint32    `db:"session_id" json:"session_id"`
-	Name            string    `db:"event_name" json:"name"`
-	MetaKeys        []string  `db:"event_meta_keys" json:"meta_keys"`
-	MetaValues      []string  `db:"event_meta_values" json:"meta_values"`
-	DurationSeconds uint32    `db:"duration_seconds" json:"duration_seconds"`
-	Hostname        string    `json:"hostname"`
-	Path            string    `json:"path"`
-	Title           string    `json:"title"`
-	Language        string    `json:"language"`
-	CountryCode     string    `db:"country_code" json:"country_code"`
-	Region          string    `json:"region"`
-	City            string    `json:"city"`
-	Referrer        string    `json:"referrer"`
-	ReferrerName    string    `db:"referrer_name" json:"referrer_name"`
-	ReferrerIcon    string    `db:"referrer_icon" json:"referrer_icon"`
-	OS              string    `json:"os"`
-	OSVersion       string    `db:"os_version" json:"os_version"`
-	Browser         string    `json:"browser"`
-	BrowserVersion  string    `db:"browser_version" json:"browser_version"`
-	Desktop         bool      `json:"desktop"`
-	Mobile          bool      `json:"mobile"`
-	ScreenClass     string    `db:"screen_class" json:"screen_class"`
-	UTMSource       string    `db:"utm_source" json:"utm_source"`
-	UTMMedium       string    `db:"utm_medium" json:"utm_medium"`
-	UTMCampaign     string    `db:"utm_campaign" json:"utm_campaign"`
-	UTMContent      string    `db:"utm_content" json:"utm_content"`
-	UTMTerm         string    `db:"utm_term" json:"utm_term"`
-	Channel         string    `json:"channel"`
+	ClientID        uint64    `db:"client_id" json:"client_id" parquet:"client_id"`
+	VisitorID       uint64    `db:"visitor_id" json:"visitor_id" parquet:"visitor_id"`
+	Time            time.Time `json:"time" parquet:"time,timestamp(millisecond)"`
+	SessionID       uint32    `db:"session_id" json:"session_id" parquet:"session_id"`
+	Name            string    `db:"event_name" json:"name" parquet:"name"`
+	MetaKeys        []string  `db:"event_meta_keys" json:"meta_keys" parquet:"meta_keys"`
+	MetaValues      []string  `db:"event_meta_values" json:"meta_values" parquet:"meta_values"`
+	DurationSeconds uint32    `db:"duration_seconds" json:"duration_seconds" parquet:"duration_seconds"`
+	Hostname        string    `json:"hostname" parquet:"hostname"`
+	Path            string    `json:"path" parquet:"path"`
+	Title           string    `json:"title" parquet:"title"`
+	Language        string    `json:"language" parquet:"language"`
+	CountryCode     string    `db:"country_code" json:"country_code" parquet:"country_code"`
+	Region          string    `json:"region" parquet:"region"`
+	City            string    `json:"city" parquet:"city"`
+	Referrer        string    `json:"referrer" parquet:"referrer"`
+	ReferrerName    string    `db:"referrer_name" json:"referrer_name" parquet:"referrer_name"`
+	ReferrerIcon    string    `db:"referrer_icon" json:"referrer_icon" parquet:"referrer_icon"`
+	OS              string    `json:"os" parquet:"os"`
+	OSVersion       string    `db:"os_version" json:"os_version" parquet:"os_version"`
+	Browser         string    `json:"browser" parquet:"browser"`
+	BrowserVersion  string    `db:"browser_version" json:"browser_version" parquet:"browser_version"`
+	Desktop         bool      `json:"desktop" parquet:"desktop"`
+	Mobile          bool      `json:"mobile" parquet:"mobile"`
+	ScreenClass     string    `db:"screen_class" json:"screen_class" parquet:"screen_class"`
+	UTMSource       string    `db:"utm_source" json:"utm_source" parquet:"utm_source"`
+	UTMMedium       string    `db:"utm_medium" json:"utm_medium" parquet:"utm_medium"`
+	UTMCampaign     string    `db:"utm_campaign" json:"utm_campaign" parquet:"utm_campaign"`
+	UTMContent      string    `db:"utm_content" json:"utm_content" parquet:"utm_content"`
+	UTMTerm         string    `db:"utm_term" json:"utm_term" parquet:"utm_term"`
+	Channel         string    `json:"channel" parquet:"channel"`
 }
 
 // String implements the Stringer interface.
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/emvi/null"
+)
+
+// Goal is a conversion goal for a client.
+// A session converts when it matches the one condition set: PathPattern, EventName (and EventMeta),
+// MinDurationSeconds, or MinPageViews.
+type Goal struct {
+	ClientID           uint64            `db:"client_id" json:"client_id"`
+	ID                 uint64            `json:"id"`
+	Name               string            `json:"name"`
+	PathPattern        string            `db:"path_pattern" json:"path_pattern,omitempty"`
+	EventName          string            `db:"event_name" json:"event_name,omitempty"`
+	EventMeta          map[string]string `db:"event_meta" json:"event_meta,omitempty"`
+	MinDurationSeconds int               `db:"min_duration_seconds" json:"min_duration_seconds,omitempty"`
+	MinPageViews       int               `db:"min_page_views" json:"min_page_views,omitempty"`
+	Value              float64           `json:"value"`
+	ValueMetaKey       string            `db:"value_meta_key" json:"value_meta_key,omitempty"`
+	Version            time.Time         `json:"version"`
+}
+
+// GoalStats is the result type for goal conversions.
+// Visitors and Conversions are the number of converted visitors and sessions.
+type GoalStats struct {
+	Goal        Goal    `json:"goal"`
+	Visitors    int     `json:"visitors"`
+	Conversions int     `json:"conversions"`
+	CR          float64 `json:"cr"`
+	Value       float64 `json:"value"`
+}
+
+// GoalBreakdownStats is the result type for goal conversions grouped by a dimension.
+type GoalBreakdownStats struct {
+	Dimension   string  `json:"dimension"`
+	Visitors    int     `json:"visitors"`
+	Conversions int     `json:"conversions"`
+	CR          float64 `json:"cr"`
+	Value       float64 `json:"value"`
+}
+
+// GoalPeriodStats is the result type for goal conversions grouped by hour, day, week, month, quarter, or year.
+type GoalPeriodStats struct {
+	Hour        null.Time `json:"hour"`
+	Day         null.Time `json:"day"`
+	Week        null.Time `json:"week"`
+	Month       null.Time `json:"month"`
+	Quarter     null.Time `json:"quarter"`
+	Year        null.Time `json:"year"`
+	Visitors    int       `json:"visitors"`
+	Conversions int       `json:"conversions"`
+	CR          float64   `json:"cr"`
+	Value       float64   `json:"value"`
+}
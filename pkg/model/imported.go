@@ -0,0 +1,222 @@
+package model
+
+import "time"
+
+// ImportedBrowser is imported browser statistics for a day.
+type ImportedBrowser struct {
+	ClientID uint64    `db:"client_id" json:"client_id"`
+	Date     time.Time `json:"date"`
+	Browser  string    `json:"browser"`
+	Visitors int       `json:"visitors"`
+}
+
+// ImportedBrowserVersion is imported browser version statistics for a day.
+type ImportedBrowserVersion struct {
+	ClientID       uint64    `db:"client_id" json:"client_id"`
+	Date           time.Time `json:"date"`
+	Browser        string    `json:"browser"`
+	BrowserVersion string    `db:"browser_version" json:"browser_version"`
+	Visitors       int       `json:"visitors"`
+}
+
+// ImportedChannel is imported channel statistics for a day.
+type ImportedChannel struct {
+	ClientID uint64    `db:"client_id" json:"client_id"`
+	Date     time.Time `json:"date"`
+	Channel  string    `json:"channel"`
+	Visitors int       `json:"visitors"`
+	Views    int       `json:"views"`
+	Sessions int       `json:"sessions"`
+	Bounces  int       `json:"bounces"`
+}
+
+// ImportedCity is imported city statistics for a day.
+type ImportedCity struct {
+	ClientID uint64    `db:"client_id" json:"client_id"`
+	Date     time.Time `json:"date"`
+	City     string    `json:"city"`
+	Visitors int       `json:"visitors"`
+}
+
+// ImportedCountry is imported country statistics for a day.
+type ImportedCountry struct {
+	ClientID    uint64    `db:"client_id" json:"client_id"`
+	Date        time.Time `json:"date"`
+	CountryCode string    `db:"country_code" json:"country_code"`
+	Visitors    int       `json:"visitors"`
+}
+
+// ImportedDevice is imported device category statistics for a day.
+type ImportedDevice struct {
+	ClientID uint64    `db:"client_id" json:"client_id"`
+	Date     time.Time `json:"date"`
+	Category string    `json:"category"`
+	Visitors int       `json:"visitors"`
+}
+
+// ImportedEntryPage is imported entry page statistics for a day.
+type ImportedEntryPage struct {
+	ClientID  uint64    `db:"client_id" json:"client_id"`
+	Date      time.Time `json:"date"`
+	EntryPath string    `db:"entry_path" json:"entry_path"`
+	Visitors  int       `json:"visitors"`
+	Sessions  int       `json:"sessions"`
+}
+
+// ImportedEvent is imported event statistics for a day.
+// Events is the number of times the event has been triggered.
+type ImportedEvent struct {
+	ClientID  uint64    `db:"client_id" json:"client_id"`
+	Date      time.Time `json:"date"`
+	EventName string    `db:"event_name" json:"event_name"`
+	Visitors  int       `json:"visitors"`
+	Views     int       `json:"views"`
+	Events    int       `json:"events"`
+}
+
+// ImportedExitPage is imported exit page statistics for a day.
+type ImportedExitPage struct {
+	ClientID uint64    `db:"client_id" json:"client_id"`
+	Date     time.Time `json:"date"`
+	ExitPath string    `db:"exit_path" json:"exit_path"`
+	Visitors int       `json:"visitors"`
+	Sessions int       `json:"sessions"`
+}
+
+// ImportedHostname is imported hostname statistics for a day.
+type ImportedHostname struct {
+	ClientID uint64    `db:"client_id" json:"client_id"`
+	Date     time.Time `json:"date"`
+	Hostname string    `json:"hostname"`
+	Visitors int       `json:"visitors"`
+	Views    int       `json:"views"`
+	Sessions int       `json:"sessions"`
+	Bounces  int       `json:"bounces"`
+}
+
+// ImportedHour is imported statistics grouped by the hour of the day for a day.
+type ImportedHour struct {
+	ClientID uint64    `db:"client_id" json:"client_id"`
+	Date     time.Time `json:"date"`
+	Hour     int       `json:"hour"`
+	Visitors int       `json:"visitors"`
+	Views    int       `json:"views"`
+	Sessions int       `json:"sessions"`
+	Bounces  int       `json:"bounces"`
+}
+
+// ImportedLanguage is imported language statistics for a day.
+type ImportedLanguage struct {
+	ClientID uint64    `db:"client_id" json:"client_id"`
+	Date     time.Time `json:"date"`
+	Language string    `json:"language"`
+	Visitors int       `json:"visitors"`
+}
+
+// ImportedOS is imported operating system statistics for a day.
+type ImportedOS struct {
+	ClientID uint64    `db:"client_id" json:"client_id"`
+	Date     time.Time `json:"date"`
+	OS       string    `json:"os"`
+	Visitors int       `json:"visitors"`
+}
+
+// ImportedOSVersion is imported operating system version statistics for a day.
+type ImportedOSVersion struct {
+	ClientID  uint64    `db:"client_id" json:"client_id"`
+	Date      time.Time `json:"date"`
+	OS        string    `json:"os"`
+	OSVersion string    `db:"os_version" json:"os_version"`
+	Visitors  int       `json:"visitors"`
+}
+
+// ImportedPage is imported page statistics for a day.
+type ImportedPage struct {
+	ClientID uint64    `db:"client_id" json:"client_id"`
+	Date     time.Time `json:"date"`
+	Path     string    `json:"path"`
+	Visitors int       `json:"visitors"`
+	Views    int       `json:"views"`
+	Sessions int       `json:"sessions"`
+	Bounces  int       `json:"bounces"`
+}
+
+// ImportedReferrer is imported referrer statistics for a day.
+type ImportedReferrer struct {
+	ClientID uint64    `db:"client_id" json:"client_id"`
+	Date     time.Time `json:"date"`
+	Referrer string    `json:"referrer"`
+	Visitors int       `json:"visitors"`
+	Sessions int       `json:"sessions"`
+	Bounces  int       `json:"bounces"`
+}
+
+// ImportedRegion is imported region statistics for a day.
+type ImportedRegion struct {
+	ClientID uint64    `db:"client_id" json:"client_id"`
+	Date     time.Time `json:"date"`
+	Region   string    `json:"region"`
+	Visitors int       `json:"visitors"`
+}
+
+// ImportedTag is imported tag statistics for a day.
+type ImportedTag struct {
+	ClientID uint64    `db:"client_id" json:"client_id"`
+	Date     time.Time `json:"date"`
+	TagKey   string    `db:"tag_key" json:"tag_key"`
+	TagValue string    `db:"tag_value" json:"tag_value"`
+	Visitors int       `json:"visitors"`
+	Views    int       `json:"views"`
+}
+
+// ImportedUTMCampaign is imported UTM campaign statistics for a day.
+type ImportedUTMCampaign struct {
+	ClientID    uint64    `db:"client_id" json:"client_id"`
+	Date        time.Time `json:"date"`
+	UTMCampaign string    `db:"utm_campaign" json:"utm_campaign"`
+	Visitors    int       `json:"visitors"`
+}
+
+// ImportedUTMContent is imported UTM content statistics for a day.
+type ImportedUTMContent struct {
+	ClientID   uint64    `db:"client_id" json:"client_id"`
+	Date       time.Time `json:"date"`
+	UTMContent string    `db:"utm_content" json:"utm_content"`
+	Visitors   int       `json:"visitors"`
+}
+
+// ImportedUTMMedium is imported UTM medium statistics for a day.
+type ImportedUTMMedium struct {
+	ClientID  uint64    `db:"client_id" json:"client_id"`
+	Date      time.Time `json:"date"`
+	UTMMedium string    `db:"utm_medium" json:"utm_medium"`
+	Visitors  int       `json:"visitors"`
+}
+
+// ImportedUTMSource is imported UTM source statistics for a day.
+type ImportedUTMSource struct {
+	ClientID  uint64    `db:"client_id" json:"client_id"`
+	Date      time.Time `json:"date"`
+	UTMSource string    `db:"utm_source" json:"utm_source"`
+	Visitors  int       `json:"visitors"`
+}
+
+// ImportedUTMTerm is imported UTM term statistics for a day.
+type ImportedUTMTerm struct {
+	ClientID uint64    `db:"client_id" json:"client_id"`
+	Date     time.Time `json:"date"`
+	UTMTerm  string    `db:"utm_term" json:"utm_term"`
+	Visitors int       `json:"visitors"`
+}
+
+// ImportedVisitors is imported visitor statistics for a day.
+// SessionDuration is the sum of all session durations in seconds.
+type ImportedVisitors struct {
+	ClientID        uint64    `db:"client_id" json:"client_id"`
+	Date            time.Time `json:"date"`
+	Visitors        int       `json:"visitors"`
+	Views           int       `json:"views"`
+	Sessions        int       `json:"sessions"`
+	Bounces         int       `json:"bounces"`
+	SessionDuration int       `db:"session_duration" json:"session_duration"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// RealtimeStats is the result type for real-time statistics of the sessions active within the sliding window.
+type RealtimeStats struct {
+	Time      time.Time               `json:"time"`
+	Visitors  int                     `json:"visitors"`
+	Sessions  int                     `json:"sessions"`
+	Pages     []ActiveVisitorStats    `json:"pages"`
+	Countries []RealtimeCountryStats  `json:"countries"`
+	Referrers []RealtimeReferrerStats `json:"referrers"`
+}
+
+// RealtimeCountryStats is the result type for real-time country statistics.
+type RealtimeCountryStats struct {
+	CountryCode string `json:"country_code"`
+	Visitors    int    `json:"visitors"`
+}
+
+// RealtimeReferrerStats is the result type for real-time referrer statistics.
+type RealtimeReferrerStats struct {
+	ReferrerName string `json:"referrer_name"`
+	ReferrerIcon string `json:"referrer_icon"`
+	Visitors     int    `json:"visitors"`
+}
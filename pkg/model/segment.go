@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Segment is a named list of conditions that sessions must match for a client.
+// Segments are identified by their name (case-insensitive).
+type Segment struct {
+	ClientID   uint64             `db:"client_id" json:"client_id"`
+	Name       string             `json:"name"`
+	Conditions []SegmentCondition `json:"conditions"`
+	Version    time.Time          `json:"version"`
+}
+
+// SegmentCondition is a condition of a Segment.
+type SegmentCondition struct {
+	Event    string `json:"event,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Not      bool   `json:"not,omitempty"`
+	Visitor  bool   `json:"visitor,omitempty"`
+	MinCount int    `db:"min_count" json:"min_count,omitempty"`
+	MaxCount int    `db:"max_count" json:"max_count,omitempty"`
+}
@@ -7,42 +7,43 @@ import (
 
 // Session represents a single visitor.
 type Session struct {
-	Sign            int8      `json:"sign"`
-	Version         uint16    `json:"version"`
-	ClientID        uint64    `db:"client_id" json:"client_id"`
-	VisitorID       uint64    `db:"visitor_id" json:"visitor_id"`
-	SessionID       uint32    `db:"session_id" json:"session_id"`
-	Time            time.Time `json:"time"`
-	Start           time.Time `json:"start"`
-	DurationSeconds uint32    `db:"duration_seconds" json:"duration_seconds"`
-	Hostname        string    `json:"hostname"`
-	EntryPath       string    `db:"entry_path" json:"entry_path"`
-	ExitPath        string    `db:"exit_path" json:"exit_path"`
-	PageViews       uint16    `db:"page_views" json:"page_views"`
-	IsBounce        bool      `db:"is_bounce" json:"is_bounce"`
-	EntryTitle      string    `db:"entry_title" json:"entry_title"`
-	ExitTitle       string    `db:"exit_title" json:"exit_title"`
-	Language        string    `json:"language"`
-	CountryCode     string    `db:"country_code" json:"country_code"`
-	Region          string    `json:"region"`
-	City            string    `json:"city"`
-	Referrer        string    `json:"referrer"`
-	ReferrerName    string    `db:"referrer_name" json:"referrer_name"`
-	ReferrerIcon    string    `db:"referrer_icon" json:"referrer_icon"`
-	OS              string    `json:"os"`
-	OSVersion       string    `db:"os_version" json:"os_version"`
-	Browser         string    `json:"browser"`
-	BrowserVersion  string    `db:"browser_version" json:"browser_version"`
-	Desktop         bool      `json:"desktop"`
-	Mobile          bool      `json:"mobile"`
-	ScreenClass     string    `db:"screen_class" json:"screen_class"`
-	UTMSource       string    `db:"utm_source" json:"utm_source"`
-	UTMMedium       string    `db:"utm_medium" json:"utm_medium"`
-	UTMCampaign     string    `db:"utm_campaign" json:"utm_campaign"`
-	UTMContent      string    `db:"utm_content" json:"utm_content"`
-	UTMTerm         string    `db:"utm_term" json:"utm_term"`
-	Channel         string    `json:"channel"`
-	Extended        uint16    `json:"extended"`
+	Sign            int8      `json:"sign" parquet:"sign"`
+	Version         uint16    `json:"version" parquet:"version"`
+	ClientID        uint64    `db:"client_id" json:"client_id" parquet:"client_id"`
+	VisitorID       uint64    `db:"visitor_id" json:"visitor_id" parquet:"visitor_id"`
+	SessionID       uint32    `db:"session_id" json:"session_id" parquet:"session_id"`
+	Time            time.Time `json:"time" parquet:"time,timestamp(millisecond)"`
+	Start           time.Time `json:"start" parquet:"start,timestamp(millisecond)"`
+	DurationSeconds uint32    `db:"duration_seconds" json:"duration_seconds" parquet:"duration_seconds"`
+	Hostname        string    `json:"hostname" parquet:"hostname"`
+	EntryPath       string    `db:"entry_path" json:"entry_path" parquet:"entry_path"`
+	ExitPath        string    `db:"exit_path" json:"exit_path" parquet:"exit_path"`
+	PageViews       uint16    `db:"page_views" json:"page_views" parquet:"page_views"`
+	IsBounce        bool      `db:"is_bounce" json:"is_bounce" parquet:"is_bounce"`
+	IsEngaged       bool      `db:"is_engaged" json:"is_engaged" parquet:"is_engaged"`
+	EntryTitle      string    `db:"entry_title" json:"entry_title" parquet:"entry_title"`
+	ExitTitle       string    `db:"exit_title" json:"exit_title" parquet:"exit_title"`
+	Language        string    `json:"language" parquet:"language"`
+	CountryCode     string    `db:"country_code" json:"country_code" parquet:"country_code"`
+	Region          string    `json:"region" parquet:"region"`
+	City            string    `json:"city" parquet:"city"`
+	Referrer        string    `json:"referrer" parquet:"referrer"`
+	ReferrerName    string    `db:"referrer_name" json:"referrer_name" parquet:"referrer_name"`
+	ReferrerIcon    string    `db:"referrer_icon" json:"referrer_icon" parquet:"referrer_icon"`
+	OS              string    `json:"os" parquet:"os"`
+	OSVersion       string    `db:"os_version" json:"os_version" parquet:"os_version"`
+	Browser         string    `json:"browser" parquet:"browser"`
+	BrowserVersion  string    `db:"browser_version" json:"browser_version" parquet:"browser_version"`
+	Desktop         bool      `json:"desktop" parquet:"desktop"`
+	Mobile          bool      `json:"mobile" parquet:"mobile"`
+	ScreenClass     string    `db:"screen_class" json:"screen_class" parquet:"screen_class"`
+	UTMSource       string    `db:"utm_source" json:"utm_source" parquet:"utm_source"`
+	UTMMedium       string    `db:"utm_medium" json:"utm_medium" parquet:"utm_medium"`
+	UTMCampaign     string    `db:"utm_campaign" json:"utm_campaign" parquet:"utm_campaign"`
+	UTMContent      string    `db:"utm_content" json:"utm_content" parquet:"utm_content"`
+	UTMTerm         string    `db:"utm_term" json:"utm_term" parquet:"utm_term"`
+	Channel         string    `json:"channel" parquet:"channel"`
+	Extended        uint16    `json:"extended" parquet:"extended"`
 }
 
 // String implements the Stringer interface.
@@ -18,6 +18,9 @@ type TotalVisitorStats struct {
 	Sessions          int     `json:"sessions"`
 	Bounces           int     `json:"bounces"`
 	BounceRate        float64 `db:"bounce_rate" json:"bounce_rate"`
+	EngagedSessions   int     `db:"engaged_sessions" json:"engaged_sessions"`
+	EngagementRate    float64 `db:"engagement_rate" json:"engagement_rate"`
+	PagesPerSession   float64 `db:"pages_per_session" json:"pages_per_session"`
 	CR                float64 `json:"cr"`
 	CustomMetricAvg   float64 `db:"custom_metric_avg" json:"custom_metric_avg"`
 	CustomMetricTotal float64 `db:"custom_metric_total" json:"custom_metric_total"`
@@ -33,9 +36,11 @@ type TotalVisitorsPageViewsStats struct {
 
 // VisitorStats is the result type for visitor statistics.
 type VisitorStats struct {
+	Hour              null.Time `json:"hour"`
 	Day               null.Time `json:"day"`
 	Week              null.Time `json:"week"`
 	Month             null.Time `json:"month"`
+	Quarter           null.Time `json:"quarter"`
 	Year              null.Time `json:"year"`
 	Minute            null.Time `json:"minute"`
 	Visitors          int       `json:"visitors"`
@@ -43,6 +48,9 @@ type VisitorStats struct {
 	Sessions          int       `json:"sessions"`
 	Bounces           int       `json:"bounces"`
 	BounceRate        float64   `db:"bounce_rate" json:"bounce_rate"`
+	EngagedSessions   int       `db:"engaged_sessions" json:"engaged_sessions"`
+	EngagementRate    float64   `db:"engagement_rate" json:"engagement_rate"`
+	PagesPerSession   float64   `db:"pages_per_session" json:"pages_per_session"`
 	CR                float64   `json:"cr"`
 	CustomMetricAvg   float64   `db:"custom_metric_avg" json:"custom_metric_avg"`
 	CustomMetricTotal float64   `db:"custom_metric_total" json:"custom_metric_total"`
@@ -106,6 +114,9 @@ type HostnameStats struct {
 	RelativeVisitors float64 `db:"relative_visitors" json:"relative_visitors"`
 	RelativeViews    float64 `db:"relative_views" json:"relative_views"`
 	BounceRate       float64 `db:"bounce_rate" json:"bounce_rate"`
+	EngagedSessions  int     `db:"engaged_sessions" json:"engaged_sessions"`
+	EngagementRate   float64 `db:"engagement_rate" json:"engagement_rate"`
+	PagesPerSession  float64 `db:"pages_per_session" json:"pages_per_session"`
 }
 
 // PageStats is the result type for page statistics.
@@ -119,6 +130,9 @@ type PageStats struct {
 	RelativeVisitors        float64 `db:"relative_visitors" json:"relative_visitors"`
 	RelativeViews           float64 `db:"relative_views" json:"relative_views"`
 	BounceRate              float64 `db:"bounce_rate" json:"bounce_rate"`
+	EngagedSessions         int     `db:"engaged_sessions" json:"engaged_sessions"`
+	EngagementRate          float64 `db:"engagement_rate" json:"engagement_rate"`
+	PagesPerSession         float64 `db:"pages_per_session" json:"pages_per_session"`
 	AverageTimeSpentSeconds int     `db:"average_time_spent_seconds" json:"average_time_spent_seconds"`
 }
 
@@ -126,6 +140,19 @@ func (stats PageStats) GetPath() string {
 	return stats.Path
 }
 
+// PathGroupStats is the result type for pages grouped by content group or directory.
+// The Name is the content group, directory, or path.
+type PathGroupStats struct {
+	Name                    string  `json:"name"`
+	Visitors                int     `json:"visitors"`
+	Views                   int     `json:"views"`
+	Sessions                int     `json:"sessions"`
+	Bounces                 int     `json:"bounces"`
+	RelativeVisitors        float64 `db:"relative_visitors" json:"relative_visitors"`
+	BounceRate              float64 `db:"bounce_rate" json:"bounce_rate"`
+	AverageTimeSpentSeconds int     `db:"average_time_spent_seconds" json:"average_time_spent_seconds"`
+}
+
 // EntryStats is the result type for entry page statistics.
 type EntryStats struct {
 	Path                    string  `db:"entry_path" json:"path"`
@@ -134,6 +161,9 @@ type EntryStats struct {
 	Sessions                int     `json:"sessions"`
 	Entries                 int     `json:"entries"`
 	EntryRate               float64 `db:"entry_rate" json:"entry_rate"`
+	EngagedSessions         int     `db:"engaged_sessions" json:"engaged_sessions"`
+	EngagementRate          float64 `db:"engagement_rate" json:"engagement_rate"`
+	PagesPerSession         float64 `db:"pages_per_session" json:"pages_per_session"`
 	AverageTimeSpentSeconds int     `db:"average_time_spent_seconds" json:"average_time_spent_seconds"`
 }
 
@@ -143,18 +173,69 @@ func (stats EntryStats) GetPath() string {
 
 // ExitStats is the result type for exit page statistics.
 type ExitStats struct {
-	Path     string  `db:"exit_path" json:"path"`
-	Title    string  `json:"title"`
-	Visitors int     `json:"visitors"`
-	Sessions int     `json:"sessions"`
-	Exits    int     `json:"exits"`
-	ExitRate float64 `db:"exit_rate" json:"exit_rate"`
+	Path            string  `db:"exit_path" json:"path"`
+	Title           string  `json:"title"`
+	Visitors        int     `json:"visitors"`
+	Sessions        int     `json:"sessions"`
+	Exits           int     `json:"exits"`
+	ExitRate        float64 `db:"exit_rate" json:"exit_rate"`
+	EngagedSessions int     `db:"engaged_sessions" json:"engaged_sessions"`
+	EngagementRate  float64 `db:"engagement_rate" json:"engagement_rate"`
+	PagesPerSession float64 `db:"pages_per_session" json:"pages_per_session"`
 }
 
 func (stats ExitStats) GetPath() string {
 	return stats.Path
 }
 
+// PageTransitionStats is the result type for pages visited before or after a page.
+// An empty path is an entrance for previous pages and an exit for next pages.
+type PageTransitionStats struct {
+	Path                string  `json:"path"`
+	Visitors            int     `json:"visitors"`
+	Transitions         int     `json:"transitions"`
+	RelativeTransitions float64 `db:"relative_transitions" json:"relative_transitions"`
+}
+
+// PageTransitions is the result type for the navigation to and from a page.
+type PageTransitions struct {
+	Path     string                `json:"path"`
+	Previous []PageTransitionStats `json:"previous"`
+	Next     []PageTransitionStats `json:"next"`
+}
+
+// PageFlowStep is the visitor count for a path at a step of a page flow and the path visited next.
+// An empty next path is an exit. PathVisitors is the number of unique visitors for the path at the step, regardless of the next path.
+type PageFlowStep struct {
+	Step         int
+	Path         string
+	NextPath     string
+	Visitors     int
+	PathVisitors int
+}
+
+// PageFlowNode is a path at a step of a page flow.
+type PageFlowNode struct {
+	Step     int    `json:"step"`
+	Path     string `json:"path"`
+	Visitors int    `json:"visitors"`
+	Exits    int    `json:"exits"`
+}
+
+// PageFlowLink is the visitor count from a path at a step of a page flow to a path at the next step.
+type PageFlowLink struct {
+	Step     int    `json:"step"`
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Visitors int    `json:"visitors"`
+}
+
+// PageFlow is the result type for a page flow.
+type PageFlow struct {
+	Nodes []PageFlowNode `json:"nodes"`
+	Links []PageFlowLink `json:"links"`
+}
+
 // ConversionsStats is the result type for page conversions.
 type ConversionsStats struct {
 	Visitors          int     `json:"visitors"`
@@ -191,9 +272,13 @@ type ReferrerStats struct {
 	ReferrerIcon     string  `db:"referrer_icon" json:"referrer_icon"`
 	Visitors         int     `json:"visitors"`
 	Sessions         int     `json:"sessions"`
+	Views            int     `json:"views"`
 	RelativeVisitors float64 `db:"relative_visitors" json:"relative_visitors"`
 	Bounces          int     `json:"bounces"`
 	BounceRate       float64 `db:"bounce_rate" json:"bounce_rate"`
+	EngagedSessions  int     `db:"engaged_sessions" json:"engaged_sessions"`
+	EngagementRate   float64 `db:"engagement_rate" json:"engagement_rate"`
+	PagesPerSession  float64 `db:"pages_per_session" json:"pages_per_session"`
 }
 
 // PlatformStats is the result type for platform statistics.
@@ -204,13 +289,24 @@ type PlatformStats struct {
 	RelativePlatformDesktop float64 `db:"relative_platform_desktop" json:"relative_platform_desktop"`
 	RelativePlatformMobile  float64 `db:"relative_platform_mobile" json:"relative_platform_mobile"`
 	RelativePlatformUnknown float64 `db:"relative_platform_unknown" json:"relative_platform_unknown"`
+	EngagedSessionsDesktop  int     `db:"engaged_sessions_desktop" json:"engaged_sessions_desktop"`
+	EngagedSessionsMobile   int     `db:"engaged_sessions_mobile" json:"engaged_sessions_mobile"`
+	EngagedSessionsUnknown  int     `db:"engaged_sessions_unknown" json:"engaged_sessions_unknown"`
+	EngagementRateDesktop   float64 `db:"engagement_rate_desktop" json:"engagement_rate_desktop"`
+	EngagementRateMobile    float64 `db:"engagement_rate_mobile" json:"engagement_rate_mobile"`
+	EngagementRateUnknown   float64 `db:"engagement_rate_unknown" json:"engagement_rate_unknown"`
+	PagesPerSessionDesktop  float64 `db:"pages_per_session_desktop" json:"pages_per_session_desktop"`
+	PagesPerSessionMobile   float64 `db:"pages_per_session_mobile" json:"pages_per_session_mobile"`
+	PagesPerSessionUnknown  float64 `db:"pages_per_session_unknown" json:"pages_per_session_unknown"`
 }
 
 // TimeSpentStats is the result type for average time spent statistics (sessions, time on page).
 type TimeSpentStats struct {
+	Hour                    null.Time `json:"hour"`
 	Day                     null.Time `json:"day"`
 	Week                    null.Time `json:"week"`
 	Month                   null.Time `json:"month"`
+	Quarter                 null.Time `json:"quarter"`
 	Year                    null.Time `json:"year"`
 	Minute                  null.Time `json:"minute"`
 	Path                    string    `json:"path"`
@@ -222,6 +318,11 @@ type TimeSpentStats struct {
 type MetaStats struct {
 	Visitors         int     `json:"visitors"`
 	RelativeVisitors float64 `db:"relative_visitors" json:"relative_visitors"`
+	Sessions         int     `json:"sessions"`
+	Views            int     `json:"views"`
+	EngagedSessions  int     `db:"engaged_sessions" json:"engaged_sessions"`
+	EngagementRate   float64 `db:"engagement_rate" json:"engagement_rate"`
+	PagesPerSession  float64 `db:"pages_per_session" json:"pages_per_session"`
 }
 
 // LanguageStats is the result type for language statistics.
@@ -323,6 +424,9 @@ type ChannelStats struct {
 	RelativeVisitors float64 `db:"relative_visitors" json:"relative_visitors"`
 	RelativeViews    float64 `db:"relative_views" json:"relative_views"`
 	BounceRate       float64 `db:"bounce_rate" json:"bounce_rate"`
+	EngagedSessions  int     `db:"engaged_sessions" json:"engaged_sessions"`
+	EngagementRate   float64 `db:"engagement_rate" json:"engagement_rate"`
+	PagesPerSession  float64 `db:"pages_per_session" json:"pages_per_session"`
 }
 
 // GrowthStats is the sum to calculate the growth rate.
@@ -369,11 +473,85 @@ type SessionStep struct {
 
 // FunnelStep is the result type for a funnel step.
 type FunnelStep struct {
-	Step                     int     `json:"step"`
-	Visitors                 int     `json:"visitors"`
-	RelativeVisitors         float64 `db:"relative_visitors" json:"relative_visitors"`
-	PreviousVisitors         int     `db:"previous_visitors" json:"previous_visitors"`
-	RelativePreviousVisitors float64 `db:"relative_previous_visitors" json:"relative_previous_visitors"`
-	Dropped                  int     `json:"dropped"`
-	DropOff                  float64 `db:"drop_off" json:"drop_off"`
+	Step                       int     `json:"step"`
+	Visitors                   int     `json:"visitors"`
+	RelativeVisitors           float64 `db:"relative_visitors" json:"relative_visitors"`
+	PreviousVisitors           int     `db:"previous_visitors" json:"previous_visitors"`
+	RelativePreviousVisitors   float64 `db:"relative_previous_visitors" json:"relative_previous_visitors"`
+	Dropped                    int     `json:"dropped"`
+	DropOff                    float64 `db:"drop_off" json:"drop_off"`
+	MedianTimeToConvertSeconds int     `db:"median_time_to_convert_seconds" json:"median_time_to_convert_seconds"`
+}
+
+// FunnelBreakdownStep is the visitor count for a funnel step and dimension value.
+// Other is true for the visitors of all values that are not part of the top values.
+type FunnelBreakdownStep struct {
+	Step     int
+	Value    string
+	Other    bool
+	Visitors int
+}
+
+// FunnelBreakdownStats is the result type for a funnel broken down by a dimension.
+type FunnelBreakdownStats struct {
+	Value string       `json:"value"`
+	Other bool         `json:"other"`
+	Steps []FunnelStep `json:"steps"`
+}
+
+// ComparisonStats is the result type for statistics compared to another time range.
+// Growth contains the growth rate for each metric by its JSON name.
+type ComparisonStats[T any] struct {
+	Stats    T                  `json:"stats"`
+	Previous T                  `json:"previous"`
+	Growth   map[string]float64 `json:"growth"`
+}
+
+// PaginatedStats is the result type for a page of a list report.
+// Total is the number of rows for all pages.
+type PaginatedStats[T any] struct {
+	Stats   []T  `json:"stats"`
+	Total   int  `json:"total"`
+	Offset  int  `json:"offset"`
+	Limit   int  `json:"limit"`
+	HasMore bool `json:"has_more"`
+}
+
+// PercentileStats is the result type for the distribution of a metric.
+type PercentileStats struct {
+	Count   int     `json:"count"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Average float64 `json:"average"`
+	Median  float64 `json:"median"`
+	P75     float64 `json:"p75"`
+	P90     float64 `json:"p90"`
+	P95     float64 `json:"p95"`
+}
+
+// HistogramBucket is the number of values and visitors in a bucket of a histogram.
+// To is null for the last (open-ended) bucket.
+type HistogramBucket struct {
+	From          float64      `json:"from"`
+	To            null.Float64 `json:"to"`
+	Count         int          `json:"count"`
+	Visitors      int          `json:"visitors"`
+	RelativeCount float64      `db:"relative_count" json:"relative_count"`
+}
+
+// BreakdownRow is a row of statistics for a combination of dimension values.
+// The Dimensions are in the same order as requested, and the Metrics are keyed by name.
+type BreakdownRow struct {
+	Dimensions []string           `json:"dimensions"`
+	Metrics    map[string]float64 `json:"metrics"`
+}
+
+// BreakdownStats is the result type for statistics grouped by multiple dimensions.
+// The Totals are the metrics for all rows without limit and offset, and TotalRows is the number of rows for all pages.
+type BreakdownStats struct {
+	Dimensions []string           `json:"dimensions"`
+	Metrics    []string           `json:"metrics"`
+	Rows       []BreakdownRow     `json:"rows"`
+	Totals     map[string]float64 `json:"totals"`
+	TotalRows  int                `json:"total_rows"`
 }
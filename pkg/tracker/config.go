@@ -5,11 +5,14 @@ import (
 	"net"
 	"os"
 	"runtime"
+	"slices"
 	"time"
 
 	"github.com/pirsch-analytics/pirsch/v6/pkg/db"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker/geodb"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker/ip"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker/realtime"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker/session"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/util"
 )
@@ -19,6 +22,9 @@ const (
 	defaultWorkerTimeout    = time.Second * 5
 	maxWorkerTimeout        = time.Second * 60
 	defaultMaxPageViews     = uint16(200)
+
+	defaultEngagementMinDuration  = time.Second * 10
+	defaultEngagementMinPageViews = uint16(2)
 )
 
 // Config is the configuration for the Tracker.
@@ -34,7 +40,9 @@ type Config struct {
 	HeaderParser        []ip.HeaderParser
 	AllowedProxySubnets []net.IPNet
 	MaxPageViews        uint16
+	Engagement          Engagement
 	GeoDB               *geodb.GeoDB
+	Realtime            *realtime.Hub
 	IPFilter            []ip.Filter
 	LogIP               bool
 	Logger              *slog.Logger
@@ -75,7 +83,49 @@ func (config *Config) validate() {
 		config.MaxPageViews = defaultMaxPageViews
 	}
 
+	config.Engagement.validate()
+
 	if config.Logger == nil {
 		config.Logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
 	}
 }
+
+// Engagement defines when a session counts as engaged.
+// A session is engaged as soon as it matches one of the conditions and stays engaged afterward.
+type Engagement struct {
+	// MinDuration is the minimum session duration. Defaults to 10 seconds.
+	MinDuration time.Duration
+
+	// MinPageViews is the minimum number of page views. Defaults to 2.
+	MinPageViews uint16
+
+	// Events are the event names engaging a session, including non-interactive events.
+	// If empty, any interactive event engages the session.
+	Events []string
+}
+
+func (engagement *Engagement) validate() {
+	if engagement.MinDuration <= 0 {
+		engagement.MinDuration = defaultEngagementMinDuration
+	}
+
+	if engagement.MinPageViews == 0 {
+		engagement.MinPageViews = defaultEngagementMinPageViews
+	}
+}
+
+func (engagement *Engagement) engaged(session *model.Session, t eventType, eventName string, eventNonInteractive bool) bool {
+	if session.DurationSeconds >= uint32(engagement.MinDuration.Seconds()) || session.PageViews >= engagement.MinPageViews {
+		return true
+	}
+
+	if t != event {
+		return false
+	}
+
+	if len(engagement.Events) > 0 {
+		return slices.Contains(engagement.Events, eventName)
+	}
+
+	return !eventNonInteractive
+}
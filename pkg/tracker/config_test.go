@@ -18,6 +18,8 @@ func TestConfig_validate(t *testing.T) {
 	assert.Equal(t, defaultWorkerTimeout, cfg.WorkerTimeout)
 	assert.NotNil(t, cfg.SessionCache)
 	assert.NotNil(t, cfg.Logger)
+	assert.Equal(t, defaultEngagementMinDuration, cfg.Engagement.MinDuration)
+	assert.Equal(t, defaultEngagementMinPageViews, cfg.Engagement.MinPageViews)
 	cfg.WorkerTimeout = time.Second * 999
 	cfg.validate()
 	assert.Equal(t, maxWorkerTimeout, cfg.WorkerTimeout)
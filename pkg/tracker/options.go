@@ -43,6 +43,10 @@ type Options struct {
 	// This overrides Config.MaxPageViews for the Tracker.
 	MaxPageViews uint16
 
+	// Engagement optionally defines when a session counts as engaged.
+	// This overrides Config.Engagement for the Tracker.
+	Engagement *Engagement
+
 	// DisableBotFilter disables all bot filters if set to true.
 	DisableBotFilter bool
 }
@@ -0,0 +1,378 @@
+package realtime
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+)
+
+const (
+	defaultWindow     = time.Minute * 5
+	publishInterval   = time.Second
+	heartbeatInterval = time.Second * 30
+)
+
+type sessionKey struct {
+	visitorID uint64
+	sessionID uint32
+}
+
+type activeSession struct {
+	time         time.Time
+	path         string
+	title        string
+	countryCode  string
+	referrerName string
+	referrerIcon string
+}
+
+type client struct {
+	sessions    map[sessionKey]activeSession
+	subscribers map[chan model.RealtimeStats]struct{}
+	changed     bool
+}
+
+// Hub keeps a sliding window of active sessions per client in memory.
+// It's fed by the Tracker and publishes updated statistics to subscribers once per second at most,
+// so that live dashboards don't need to query the database.
+type Hub struct {
+	window  time.Duration
+	clients map[uint64]*client
+	m       sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+	stopped bool
+}
+
+// NewHub creates a new Hub for given window and starts publishing updates.
+// Sessions are considered active until they haven't been updated for the duration of the window. Defaults to 5 minutes.
+// Make sure to call Stop when you're done.
+func NewHub(window time.Duration) *Hub {
+	if window <= 0 {
+		window = defaultWindow
+	}
+
+	hub := &Hub{
+		window:  window,
+		clients: make(map[uint64]*client),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go hub.run()
+	return hub
+}
+
+// Update adds or updates the active session.
+// Sessions outside the window are ignored.
+func (hub *Hub) Update(session *model.Session) {
+	if session == nil || session.Time.Before(time.Now().Add(-hub.window)) {
+		return
+	}
+
+	referrerName := session.ReferrerName
+
+	if referrerName == "" {
+		referrerName = session.Referrer
+	}
+
+	hub.m.Lock()
+	defer hub.m.Unlock()
+	c := hub.getClient(session.ClientID)
+	c.sessions[sessionKey{session.VisitorID, session.SessionID}] = activeSession{
+		time:         session.Time,
+		path:         session.ExitPath,
+		title:        session.ExitTitle,
+		countryCode:  session.CountryCode,
+		referrerName: referrerName,
+		referrerIcon: session.ReferrerIcon,
+	}
+	c.changed = true
+}
+
+// Active returns the statistics for all active sessions of given client.
+func (hub *Hub) Active(clientID uint64) model.RealtimeStats {
+	hub.m.Lock()
+	defer hub.m.Unlock()
+	c := hub.clients[clientID]
+
+	if c == nil {
+		c = new(client)
+	}
+
+	return hub.stats(c, time.Now().UTC())
+}
+
+// Subscribe returns a channel receiving the statistics for given client whenever they change.
+// The current statistics are sent right away. Slow subscribers only receive the latest statistics.
+// The returned function must be called to unsubscribe. It closes the channel.
+func (hub *Hub) Subscribe(clientID uint64) (<-chan model.RealtimeStats, func()) {
+	updates := make(chan model.RealtimeStats, 1)
+	hub.m.Lock()
+	defer hub.m.Unlock()
+
+	if hub.stopped {
+		close(updates)
+		return updates, func() {}
+	}
+
+	c := hub.getClient(clientID)
+	c.subscribers[updates] = struct{}{}
+	updates <- hub.stats(c, time.Now().UTC())
+	var once sync.Once
+	return updates, func() {
+		once.Do(func() {
+			hub.m.Lock()
+			defer hub.m.Unlock()
+
+			if _, found := c.subscribers[updates]; found {
+				delete(c.subscribers, updates)
+				close(updates)
+			}
+		})
+	}
+}
+
+// ServeSSE streams the statistics for given client as Server-Sent Events until the request is canceled or the Hub is stopped.
+// Each update is sent as an "active" event containing the statistics as JSON.
+func (hub *Hub) ServeSSE(w http.ResponseWriter, r *http.Request, clientID uint64) {
+	flusher, ok := w.(http.Flusher)
+
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	updates, unsubscribe := hub.Subscribe(clientID)
+	defer unsubscribe()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+
+			flusher.Flush()
+		case stats, ok := <-updates:
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(stats)
+
+			if err != nil {
+				return
+			}
+
+			if _, err := fmt.Fprintf(w, "event: active\ndata: %s\n\n", data); err != nil {
+				return
+			}
+
+			flusher.Flush()
+		}
+	}
+}
+
+// Stop stops publishing updates and closes all subscriptions.
+func (hub *Hub) Stop() {
+	hub.m.Lock()
+
+	if hub.stopped {
+		hub.m.Unlock()
+		return
+	}
+
+	hub.stopped = true
+	close(hub.stop)
+	hub.m.Unlock()
+	<-hub.done
+	hub.m.Lock()
+	defer hub.m.Unlock()
+
+	for _, c := range hub.clients {
+		for updates := range c.subscribers {
+			close(updates)
+		}
+
+		clear(c.subscribers)
+	}
+}
+
+func (hub *Hub) run() {
+	defer close(hub.done)
+	ticker := time.NewTicker(publishInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-hub.stop:
+			return
+		case now := <-ticker.C:
+			hub.tick(now.UTC())
+		}
+	}
+}
+
+// tick removes sessions outside the window and publishes the statistics for all clients that have changed.
+func (hub *Hub) tick(now time.Time) {
+	hub.m.Lock()
+	defer hub.m.Unlock()
+	from := now.Add(-hub.window)
+
+	for clientID, c := range hub.clients {
+		for key, session := range c.sessions {
+			if session.time.Before(from) {
+				delete(c.sessions, key)
+				c.changed = true
+			}
+		}
+
+		if len(c.sessions) == 0 && len(c.subscribers) == 0 {
+			delete(hub.clients, clientID)
+			continue
+		}
+
+		if c.changed {
+			c.changed = false
+
+			if len(c.subscribers) > 0 {
+				stats := hub.stats(c, now)
+
+				for updates := range c.subscribers {
+					publish(updates, stats)
+				}
+			}
+		}
+	}
+}
+
+func (hub *Hub) getClient(clientID uint64) *client {
+	c := hub.clients[clientID]
+
+	if c == nil {
+		c = &client{
+			sessions:    make(map[sessionKey]activeSession),
+			subscribers: make(map[chan model.RealtimeStats]struct{}),
+		}
+		hub.clients[clientID] = c
+	}
+
+	return c
+}
+
+func (hub *Hub) stats(c *client, now time.Time) model.RealtimeStats {
+	type page struct {
+		path  string
+		title string
+	}
+
+	type referrer struct {
+		name string
+		icon string
+	}
+
+	visitors := make(map[uint64]struct{})
+	pages := make(map[page]map[uint64]struct{})
+	countries := make(map[string]map[uint64]struct{})
+	referrers := make(map[referrer]map[uint64]struct{})
+	from := now.Add(-hub.window)
+	sessions := 0
+
+	for key, session := range c.sessions {
+		if session.time.Before(from) {
+			continue
+		}
+
+		sessions++
+		visitors[key.visitorID] = struct{}{}
+		addVisitor(pages, page{session.path, session.title}, key.visitorID)
+		addVisitor(countries, session.countryCode, key.visitorID)
+		addVisitor(referrers, referrer{session.referrerName, session.referrerIcon}, key.visitorID)
+	}
+
+	stats := model.RealtimeStats{
+		Time:      now,
+		Visitors:  len(visitors),
+		Sessions:  sessions,
+		Pages:     make([]model.ActiveVisitorStats, 0, len(pages)),
+		Countries: make([]model.RealtimeCountryStats, 0, len(countries)),
+		Referrers: make([]model.RealtimeReferrerStats, 0, len(referrers)),
+	}
+
+	for p, v := range pages {
+		stats.Pages = append(stats.Pages, model.ActiveVisitorStats{
+			Path:     p.path,
+			Title:    p.title,
+			Visitors: len(v),
+		})
+	}
+
+	for countryCode, v := range countries {
+		stats.Countries = append(stats.Countries, model.RealtimeCountryStats{
+			CountryCode: countryCode,
+			Visitors:    len(v),
+		})
+	}
+
+	for r, v := range referrers {
+		stats.Referrers = append(stats.Referrers, model.RealtimeReferrerStats{
+			ReferrerName: r.name,
+			ReferrerIcon: r.icon,
+			Visitors:     len(v),
+		})
+	}
+
+	slices.SortFunc(stats.Pages, func(a, b model.ActiveVisitorStats) int {
+		return cmp.Or(b.Visitors-a.Visitors, cmp.Compare(a.Path, b.Path), cmp.Compare(a.Title, b.Title))
+	})
+	slices.SortFunc(stats.Countries, func(a, b model.RealtimeCountryStats) int {
+		return cmp.Or(b.Visitors-a.Visitors, cmp.Compare(a.CountryCode, b.CountryCode))
+	})
+	slices.SortFunc(stats.Referrers, func(a, b model.RealtimeReferrerStats) int {
+		return cmp.Or(b.Visitors-a.Visitors, cmp.Compare(a.ReferrerName, b.ReferrerName))
+	})
+	return stats
+}
+
+func addVisitor[K comparable](m map[K]map[uint64]struct{}, key K, visitorID uint64) {
+	if m[key] == nil {
+		m[key] = make(map[uint64]struct{})
+	}
+
+	m[key][visitorID] = struct{}{}
+}
+
+// publish sends the statistics without blocking, replacing a pending update the subscriber hasn't received yet.
+func publish(updates chan model.RealtimeStats, stats model.RealtimeStats) {
+	select {
+	case updates <- stats:
+		return
+	default:
+	}
+
+	select {
+	case <-updates:
+	default:
+	}
+
+	select {
+	case updates <- stats:
+	default:
+	}
+}
@@ -0,0 +1,156 @@
+package realtime
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub_Active(t *testing.T) {
+	hub := NewHub(time.Minute)
+	defer hub.Stop()
+	now := time.Now().UTC()
+	hub.Update(&model.Session{ClientID: 1, VisitorID: 1, SessionID: 1, Time: now, ExitPath: "/", ExitTitle: "Home", CountryCode: "de", ReferrerName: "Google"})
+	hub.Update(&model.Session{ClientID: 1, VisitorID: 2, SessionID: 2, Time: now, ExitPath: "/", ExitTitle: "Home", CountryCode: "de"})
+	hub.Update(&model.Session{ClientID: 1, VisitorID: 3, SessionID: 3, Time: now, ExitPath: "/foo", ExitTitle: "Foo", CountryCode: "gb", Referrer: "https://example.com"})
+	hub.Update(&model.Session{ClientID: 1, VisitorID: 3, SessionID: 4, Time: now, ExitPath: "/foo", ExitTitle: "Foo", CountryCode: "gb", Referrer: "https://example.com"})
+	hub.Update(&model.Session{ClientID: 1, VisitorID: 4, SessionID: 5, Time: now.Add(-time.Minute * 2), ExitPath: "/old"})
+	hub.Update(&model.Session{ClientID: 2, VisitorID: 5, SessionID: 6, Time: now, ExitPath: "/bar"})
+	stats := hub.Active(1)
+	assert.Equal(t, 3, stats.Visitors)
+	assert.Equal(t, 4, stats.Sessions)
+	assert.Len(t, stats.Pages, 2)
+	assert.Equal(t, "/", stats.Pages[0].Path)
+	assert.Equal(t, "Home", stats.Pages[0].Title)
+	assert.Equal(t, 2, stats.Pages[0].Visitors)
+	assert.Equal(t, "/foo", stats.Pages[1].Path)
+	assert.Equal(t, 1, stats.Pages[1].Visitors)
+	assert.Len(t, stats.Countries, 2)
+	assert.Equal(t, "de", stats.Countries[0].CountryCode)
+	assert.Equal(t, 2, stats.Countries[0].Visitors)
+	assert.Equal(t, "gb", stats.Countries[1].CountryCode)
+	assert.Equal(t, 1, stats.Countries[1].Visitors)
+	assert.Len(t, stats.Referrers, 3)
+	assert.Equal(t, "", stats.Referrers[0].ReferrerName)
+	assert.Equal(t, "Google", stats.Referrers[1].ReferrerName)
+	assert.Equal(t, "https://example.com", stats.Referrers[2].ReferrerName)
+
+	// the session moved on to another page
+	hub.Update(&model.Session{ClientID: 1, VisitorID: 2, SessionID: 2, Time: now.Add(time.Second), ExitPath: "/foo", ExitTitle: "Foo", CountryCode: "de"})
+	stats = hub.Active(1)
+	assert.Equal(t, 3, stats.Visitors)
+	assert.Equal(t, "/foo", stats.Pages[0].Path)
+	assert.Equal(t, 2, stats.Pages[0].Visitors)
+	assert.Equal(t, 1, hub.Active(2).Visitors)
+	stats = hub.Active(3)
+	assert.Zero(t, stats.Visitors)
+	assert.Empty(t, stats.Pages)
+	assert.NotNil(t, stats.Pages)
+
+	// sessions leave the window
+	hub.tick(now.Add(time.Minute + time.Millisecond*500))
+	stats = hub.Active(1)
+	assert.Equal(t, 1, stats.Visitors)
+	assert.Equal(t, 1, stats.Sessions)
+	hub.tick(now.Add(time.Minute * 2))
+	assert.Zero(t, hub.Active(1).Visitors)
+	hub.m.Lock()
+	assert.Empty(t, hub.clients)
+	hub.m.Unlock()
+}
+
+func TestHub_Subscribe(t *testing.T) {
+	hub := NewHub(time.Minute)
+	defer hub.Stop()
+	updates, unsubscribe := hub.Subscribe(1)
+	stats := <-updates
+	assert.Zero(t, stats.Visitors)
+	now := time.Now().UTC()
+	hub.Update(&model.Session{ClientID: 1, VisitorID: 1, SessionID: 1, Time: now, ExitPath: "/"})
+	hub.Update(&model.Session{ClientID: 2, VisitorID: 2, SessionID: 2, Time: now, ExitPath: "/"})
+	hub.tick(now)
+	stats = <-updates
+	assert.Equal(t, 1, stats.Visitors)
+
+	// nothing changed
+	hub.tick(now)
+
+	select {
+	case <-updates:
+		t.Fatal("unexpected update")
+	default:
+	}
+
+	// slow subscribers only receive the latest update
+	hub.Update(&model.Session{ClientID: 1, VisitorID: 3, SessionID: 3, Time: now, ExitPath: "/"})
+	hub.tick(now)
+	hub.Update(&model.Session{ClientID: 1, VisitorID: 4, SessionID: 4, Time: now, ExitPath: "/"})
+	hub.tick(now)
+	stats = <-updates
+	assert.Equal(t, 3, stats.Visitors)
+	unsubscribe()
+	unsubscribe()
+	_, ok := <-updates
+	assert.False(t, ok)
+
+	updates, _ = hub.Subscribe(1)
+	<-updates
+	hub.Stop()
+	_, ok = <-updates
+	assert.False(t, ok)
+	updates, _ = hub.Subscribe(1)
+	_, ok = <-updates
+	assert.False(t, ok)
+}
+
+func TestHub_ServeSSE(t *testing.T) {
+	hub := NewHub(time.Minute)
+	defer hub.Stop()
+	hub.Update(&model.Session{ClientID: 1, VisitorID: 1, SessionID: 1, Time: time.Now().UTC(), ExitPath: "/"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hub.ServeSSE(w, r, 1)
+	}))
+	defer server.Close()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "event: active\n", line)
+	line, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	data, found := strings.CutPrefix(strings.TrimSpace(line), "data: ")
+	assert.True(t, found)
+	var stats model.RealtimeStats
+	assert.NoError(t, json.Unmarshal([]byte(data), &stats))
+	assert.Equal(t, 1, stats.Visitors)
+	assert.Equal(t, "/", stats.Pages[0].Path)
+
+	// the next update is pushed by the hub within a second
+	hub.Update(&model.Session{ClientID: 1, VisitorID: 2, SessionID: 2, Time: time.Now().UTC(), ExitPath: "/foo"})
+	_, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	line, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "event: active\n", line)
+	line, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	data, _ = strings.CutPrefix(strings.TrimSpace(line), "data: ")
+	assert.NoError(t, json.Unmarshal([]byte(data), &stats))
+	assert.Equal(t, 2, stats.Visitors)
+}
@@ -105,7 +105,7 @@ func (tracker *Tracker) PageView(r *http.Request, clientID uint64, options Optio
 	}
 
 	if ignoreReason == "" {
-		session, cancelSession, timeOnPage := tracker.getSession(pageView, clientID, r, now, userAgent, ipAddress, false, options)
+		session, cancelSession, timeOnPage := tracker.getSession(pageView, clientID, r, now, userAgent, ipAddress, "", false, options)
 		var saveRequest *model.Request
 
 		if session != nil {
@@ -113,6 +113,7 @@ func (tracker *Tracker) PageView(r *http.Request, clientID uint64, options Optio
 				saveRequest = tracker.requestFromSession(session, clientID, ipAddress, userAgent.UserAgent, "")
 			}
 
+			tracker.updateRealtime(session)
 			tagKeys, tagValues := options.getTags()
 			pv := tracker.pageViewFromSession(session, timeOnPage, tagKeys, tagValues)
 			tracker.data <- data{
@@ -149,7 +150,7 @@ func (tracker *Tracker) Event(r *http.Request, clientID uint64, eventOptions Eve
 		}
 
 		if ignoreReason == "" {
-			session, cancelSession, timeOnPage := tracker.getSession(event, clientID, r, now, userAgent, ipAddress, eventOptions.NonInteractive, options)
+			session, cancelSession, timeOnPage := tracker.getSession(event, clientID, r, now, userAgent, ipAddress, eventOptions.Name, eventOptions.NonInteractive, options)
 			var saveRequest *model.Request
 
 			if session != nil {
@@ -157,6 +158,7 @@ func (tracker *Tracker) Event(r *http.Request, clientID uint64, eventOptions Eve
 					saveRequest = tracker.requestFromSession(session, clientID, ipAddress, userAgent.UserAgent, eventOptions.Name)
 				}
 
+				tracker.updateRealtime(session)
 				tagKeys, tagValues := options.getTags()
 				var pv *model.PageView
 
@@ -200,9 +202,10 @@ func (tracker *Tracker) ExtendSession(r *http.Request, clientID uint64, options
 			now = options.Time
 		}
 
-		session, cancelSession, _ := tracker.getSession(sessionUpdate, clientID, r, now, userAgent, ipAddress, false, options)
+		session, cancelSession, _ := tracker.getSession(sessionUpdate, clientID, r, now, userAgent, ipAddress, "", false, options)
 
 		if session != nil {
+			tracker.updateRealtime(session)
 			tracker.data <- data{
 				session:       session,
 				cancelSession: cancelSession,
@@ -230,7 +233,7 @@ func (tracker *Tracker) Accept(r *http.Request, clientID uint64, options Options
 	}
 
 	if ignoreReason == "" {
-		session, _, _ := tracker.getSession(pageView, clientID, r, now, userAgent, ipAddress, false, options)
+		session, _, _ := tracker.getSession(pageView, clientID, r, now, userAgent, ipAddress, "", false, options)
 		return session
 	}
 
@@ -561,7 +564,7 @@ func (tracker *Tracker) browserVersionBefore(version string, min int) bool {
 	return v < min
 }
 
-func (tracker *Tracker) getSession(t eventType, clientID uint64, r *http.Request, now time.Time, ua ua.UserAgent, ip string, eventNonInteractive bool, options Options) (*model.Session, *model.Session, uint32) {
+func (tracker *Tracker) getSession(t eventType, clientID uint64, r *http.Request, now time.Time, ua ua.UserAgent, ip, eventName string, eventNonInteractive bool, options Options) (*model.Session, *model.Session, uint32) {
 	fingerprint := tracker.fingerprint(tracker.config.Salt, ua.UserAgent, ip, now)
 	m := tracker.config.SessionCache.NewMutex(clientID, fingerprint)
 	m.Lock()
@@ -594,8 +597,16 @@ func (tracker *Tracker) getSession(t eventType, clientID uint64, r *http.Request
 	var timeOnPage uint32
 	var cancelSession *model.Session
 
+	engagement := tracker.config.Engagement
+
+	if options.Engagement != nil {
+		engagement = *options.Engagement
+		engagement.validate()
+	}
+
 	if session == nil || tracker.referrerOrCampaignChanged(r, session, options.Referrer, options.Hostname) {
 		session = tracker.newSession(clientID, r, fingerprint, now, ua, ip, options)
+		session.IsEngaged = engagement.engaged(session, t, eventName, eventNonInteractive)
 		tracker.config.SessionCache.Put(clientID, fingerprint, session)
 	} else {
 		if options.MaxPageViews > 0 && session.PageViews >= options.MaxPageViews ||
@@ -607,6 +618,7 @@ func (tracker *Tracker) getSession(t eventType, clientID uint64, r *http.Request
 		cancelSession = &sessionCopy
 		cancelSession.Sign = -1
 		timeOnPage = tracker.updateSession(t, r, session, now, options.Hostname, options.Path, options.Title, eventNonInteractive)
+		session.IsEngaged = session.IsEngaged || engagement.engaged(session, t, eventName, eventNonInteractive)
 		tracker.config.SessionCache.Put(clientID, fingerprint, session)
 	}
 
@@ -807,6 +819,12 @@ func (tracker *Tracker) getUTMMedium(r *http.Request, referrerName string) (stri
 	return medium, clickID
 }
 
+func (tracker *Tracker) updateRealtime(session *model.Session) {
+	if tracker.config.Realtime != nil {
+		tracker.config.Realtime.Update(session)
+	}
+}
+
 func (tracker *Tracker) fingerprint(salt, ua, ip string, now time.Time) uint64 {
 	var sb strings.Builder
 	sb.WriteString(ua)
@@ -16,6 +16,7 @@ import (
 	"github.com/pirsch-analytics/pirsch/v6/pkg/model"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker/geodb"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker/ip"
+	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker/realtime"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker/session"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/tracker/ua"
 	"github.com/pirsch-analytics/pirsch/v6/pkg/util"
@@ -1197,6 +1198,92 @@ func TestTracker_EventBounced(t *testing.T) {
 	assert.False(t, sessions[4].IsBounce)
 }
 
+func TestTracker_Engagement(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/blog/article", nil)
+	req.Header.Add("User-Agent", userAgent)
+	req.RemoteAddr = "81.2.69.142"
+	client := db.NewClientMock()
+	tracker := NewTracker(Config{
+		Store: client,
+	})
+	now := time.Now().UTC()
+	tracker.PageView(req, 123, Options{Time: now})
+	tracker.Flush()
+	sessions := client.GetSessions()
+	assert.Len(t, sessions, 1)
+	assert.True(t, sessions[0].IsBounce)
+	assert.False(t, sessions[0].IsEngaged)
+	tracker.ExtendSession(req, 123, Options{Time: now.Add(time.Second * 15)})
+	tracker.Flush()
+	sessions = client.GetSessions()
+	assert.Len(t, sessions, 3)
+	assert.True(t, sessions[2].IsBounce)
+	assert.True(t, sessions[2].IsEngaged)
+
+	client = db.NewClientMock()
+	tracker = NewTracker(Config{
+		Store: client,
+		Engagement: Engagement{
+			MinDuration:  time.Hour,
+			MinPageViews: 3,
+			Events:       []string{"Sign up"},
+		},
+	})
+	tracker.PageView(req, 123, Options{Time: now})
+	tracker.PageView(req, 123, Options{Path: "/pricing", Time: now.Add(time.Second)})
+	tracker.Event(req, 123, EventOptions{Name: "Click"}, Options{Time: now.Add(time.Second * 2)})
+	tracker.Flush()
+	sessions = client.GetSessions()
+	assert.Len(t, sessions, 5)
+	assert.False(t, sessions[4].IsBounce)
+	assert.False(t, sessions[4].IsEngaged)
+	tracker.Event(req, 123, EventOptions{Name: "Sign up", NonInteractive: true}, Options{Time: now.Add(time.Second * 3)})
+	tracker.Flush()
+	sessions = client.GetSessions()
+	assert.Len(t, sessions, 7)
+	assert.True(t, sessions[6].IsEngaged)
+
+	client = db.NewClientMock()
+	tracker = NewTracker(Config{
+		Store: client,
+	})
+	tracker.PageView(req, 123, Options{Time: now, Engagement: &Engagement{MinPageViews: 1}})
+	tracker.Flush()
+	sessions = client.GetSessions()
+	assert.Len(t, sessions, 1)
+	assert.True(t, sessions[0].IsBounce)
+	assert.True(t, sessions[0].IsEngaged)
+}
+
+func TestTracker_Realtime(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/", nil)
+	req.Header.Add("User-Agent", userAgent)
+	req.Header.Set("Referer", "https://google.com")
+	req.RemoteAddr = "81.2.69.142"
+	hub := realtime.NewHub(0)
+	defer hub.Stop()
+	client := db.NewClientMock()
+	tracker := NewTracker(Config{
+		Store:    client,
+		Realtime: hub,
+	})
+	assert.True(t, tracker.PageView(req, 123, Options{Title: "Home"}))
+	stats := hub.Active(123)
+	assert.Equal(t, 1, stats.Visitors)
+	assert.Len(t, stats.Pages, 1)
+	assert.Equal(t, "/", stats.Pages[0].Path)
+	assert.Equal(t, "Home", stats.Pages[0].Title)
+	assert.Len(t, stats.Referrers, 1)
+	assert.Equal(t, "Google", stats.Referrers[0].ReferrerName)
+	assert.True(t, tracker.Event(req, 123, EventOptions{Name: "Click"}, Options{Path: "/pricing", Title: "Pricing"}))
+	stats = hub.Active(123)
+	assert.Equal(t, 1, stats.Visitors)
+	assert.Equal(t, "/pricing", stats.Pages[0].Path)
+	assert.NotNil(t, tracker.Accept(req, 124, Options{}))
+	assert.Zero(t, hub.Active(124).Visitors)
+	tracker.Stop()
+}
+
 func TestTracker_ExtendSession(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "https://example.com/foo/bar?utm_source=Source&utm_campaign=Campaign&utm_medium=Medium&utm_content=Content&utm_term=Term", nil)
 	req.Header.Add("User-Agent", userAgent)